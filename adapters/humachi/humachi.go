@@ -2,6 +2,7 @@ package humachi
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
 	"net/http"
 	"net/url"
@@ -34,6 +35,22 @@ func (ctx *chiContext) Host() string {
 	return ctx.r.Host
 }
 
+func (ctx *chiContext) RemoteAddr() string {
+	return ctx.r.RemoteAddr
+}
+
+func (ctx *chiContext) TLS() *tls.ConnectionState {
+	return ctx.r.TLS
+}
+
+func (ctx *chiContext) Version() huma.ProtoVersion {
+	return huma.ProtoVersion{
+		Proto:      ctx.r.Proto,
+		ProtoMajor: ctx.r.ProtoMajor,
+		ProtoMinor: ctx.r.ProtoMinor,
+	}
+}
+
 func (ctx *chiContext) URL() url.URL {
 	return *ctx.r.URL
 }
@@ -66,6 +83,10 @@ func (ctx *chiContext) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(ctx.w, deadline)
 }
 
+func (ctx *chiContext) SetWriteDeadline(deadline time.Time) error {
+	return huma.SetWriteDeadline(ctx.w, deadline)
+}
+
 func (ctx *chiContext) SetStatus(code int) {
 	ctx.w.WriteHeader(code)
 }
@@ -99,3 +120,12 @@ func (a *chiAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func New(r chi.Router, config huma.Config) huma.API {
 	return huma.NewAPI(config, &chiAdapter{router: r})
 }
+
+// Unwrap returns the HTTP request & response writer of a context created by
+// this adapter, panicking for other contexts. See `huma.UnwrapContext`.
+func Unwrap(ctx huma.Context) (*http.Request, http.ResponseWriter) {
+	if c, ok := huma.UnwrapContext(ctx).(*chiContext); ok {
+		return c.r, c.w
+	}
+	panic("not a humachi context")
+}
@@ -2,6 +2,7 @@ package humafiber
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
 	"net/http"
 	"net/url"
@@ -37,6 +38,24 @@ func (ctx *fiberCtx) Host() string {
 	return ctx.orig.Hostname()
 }
 
+func (c *fiberCtx) RemoteAddr() string {
+	return c.orig.Context().RemoteAddr().String()
+}
+
+func (c *fiberCtx) TLS() *tls.ConnectionState {
+	return c.orig.Context().TLSConnectionState()
+}
+
+func (c *fiberCtx) Version() huma.ProtoVersion {
+	proto := string(c.orig.Request().Header.Protocol())
+	major, minor, _ := http.ParseHTTPVersion(proto)
+	return huma.ProtoVersion{
+		Proto:      proto,
+		ProtoMajor: major,
+		ProtoMinor: minor,
+	}
+}
+
 func (c *fiberCtx) URL() url.URL {
 	u, _ := url.Parse(string(c.orig.Request().RequestURI()))
 	return *u
@@ -73,7 +92,16 @@ func (c *fiberCtx) SetReadDeadline(deadline time.Time) error {
 	return c.orig.Context().Conn().SetReadDeadline(deadline)
 }
 
+func (c *fiberCtx) SetWriteDeadline(deadline time.Time) error {
+	return c.orig.Context().Conn().SetWriteDeadline(deadline)
+}
+
 func (c *fiberCtx) SetStatus(code int) {
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		// Fiber can't send interim responses like early hints, so only their
+		// headers are sent with the final response.
+		return
+	}
 	c.orig.Status(code)
 }
 
@@ -125,3 +153,12 @@ func (a *fiberAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func New(r *fiber.App, config huma.Config) huma.API {
 	return huma.NewAPI(config, &fiberAdapter{router: r})
 }
+
+// Unwrap returns the Fiber context of a context created by this adapter,
+// panicking for other contexts. See `huma.UnwrapContext`.
+func Unwrap(ctx huma.Context) *fiber.Ctx {
+	if c, ok := huma.UnwrapContext(ctx).(*fiberCtx); ok {
+		return c.orig
+	}
+	panic("not a humafiber context")
+}
@@ -2,6 +2,7 @@ package humagin
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
 	"net/http"
 	"net/url"
@@ -33,6 +34,22 @@ func (ctx *ginCtx) Host() string {
 	return ctx.orig.Request.Host
 }
 
+func (ctx *ginCtx) RemoteAddr() string {
+	return ctx.orig.Request.RemoteAddr
+}
+
+func (ctx *ginCtx) TLS() *tls.ConnectionState {
+	return ctx.orig.Request.TLS
+}
+
+func (ctx *ginCtx) Version() huma.ProtoVersion {
+	return huma.ProtoVersion{
+		Proto:      ctx.orig.Request.Proto,
+		ProtoMajor: ctx.orig.Request.ProtoMajor,
+		ProtoMinor: ctx.orig.Request.ProtoMinor,
+	}
+}
+
 func (c *ginCtx) URL() url.URL {
 	return *c.orig.Request.URL
 }
@@ -65,7 +82,19 @@ func (ctx *ginCtx) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(ctx.orig.Writer, deadline)
 }
 
+func (ctx *ginCtx) SetWriteDeadline(deadline time.Time) error {
+	return huma.SetWriteDeadline(ctx.orig.Writer, deadline)
+}
+
 func (c *ginCtx) SetStatus(code int) {
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		// Gin holds the status until the body is written, so interim responses
+		// like early hints are sent via the underlying writer.
+		if u, ok := c.orig.Writer.(interface{ Unwrap() http.ResponseWriter }); ok {
+			u.Unwrap().WriteHeader(code)
+		}
+		return
+	}
 	c.orig.Status(code)
 }
 
@@ -103,3 +132,12 @@ func (a *ginAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func New(r *gin.Engine, config huma.Config) huma.API {
 	return huma.NewAPI(config, &ginAdapter{router: r})
 }
+
+// Unwrap returns the Gin context of a context created by this adapter,
+// panicking for other contexts. See `huma.UnwrapContext`.
+func Unwrap(ctx huma.Context) *gin.Context {
+	if c, ok := huma.UnwrapContext(ctx).(*ginCtx); ok {
+		return c.orig
+	}
+	panic("not a humagin context")
+}
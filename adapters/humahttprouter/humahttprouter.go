@@ -2,6 +2,7 @@ package humahttprouter
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
 	"net/http"
 	"net/url"
@@ -36,6 +37,22 @@ func (ctx *httprouterContext) Host() string {
 	return ctx.r.Host
 }
 
+func (ctx *httprouterContext) RemoteAddr() string {
+	return ctx.r.RemoteAddr
+}
+
+func (ctx *httprouterContext) TLS() *tls.ConnectionState {
+	return ctx.r.TLS
+}
+
+func (ctx *httprouterContext) Version() huma.ProtoVersion {
+	return huma.ProtoVersion{
+		Proto:      ctx.r.Proto,
+		ProtoMajor: ctx.r.ProtoMajor,
+		ProtoMinor: ctx.r.ProtoMinor,
+	}
+}
+
 func (ctx *httprouterContext) URL() url.URL {
 	return *ctx.r.URL
 }
@@ -68,6 +85,10 @@ func (ctx *httprouterContext) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(ctx.w, deadline)
 }
 
+func (ctx *httprouterContext) SetWriteDeadline(deadline time.Time) error {
+	return huma.SetWriteDeadline(ctx.w, deadline)
+}
+
 func (ctx *httprouterContext) SetStatus(code int) {
 	ctx.w.WriteHeader(code)
 }
@@ -105,3 +126,12 @@ func (a *httprouterAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func New(r *httprouter.Router, config huma.Config) huma.API {
 	return huma.NewAPI(config, &httprouterAdapter{router: r})
 }
+
+// Unwrap returns the HTTP request & response writer of a context created by
+// this adapter, panicking for other contexts. See `huma.UnwrapContext`.
+func Unwrap(ctx huma.Context) (*http.Request, http.ResponseWriter) {
+	if c, ok := huma.UnwrapContext(ctx).(*httprouterContext); ok {
+		return c.r, c.w
+	}
+	panic("not a humahttprouter context")
+}
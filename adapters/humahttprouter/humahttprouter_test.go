@@ -70,3 +70,60 @@ func BenchmarkHumaHttprouter(b *testing.B) {
 		}
 	}
 }
+
+func TestCORSOptionsOperation(t *testing.T) {
+	r := httprouter.New()
+	config := huma.DefaultConfig("Test", "1.0.0")
+	config.CORS = &huma.CORSConfig{AllowOrigins: []string{"*"}}
+	app := New(r, config)
+
+	huma.Register(app, huma.Operation{
+		OperationID: "get-item",
+		Method:      http.MethodGet,
+		Path:        "/items/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	// Registering `OPTIONS` after the automatic preflight route must not
+	// register the path twice, which panics with httprouter.
+	var op *huma.Operation
+	huma.Register(app, huma.Operation{
+		OperationID: "options-item",
+		Method:      http.MethodOptions,
+		Path:        "/items/{id}",
+		Middlewares: []huma.Middleware{
+			func(ctx huma.Context, next func(huma.Context)) {
+				op = ctx.Operation()
+				next(ctx)
+			},
+		},
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct {
+		Allow string `header:"Allow"`
+	}, error) {
+		return &struct {
+			Allow string `header:"Allow"`
+		}{Allow: "GET, OPTIONS, " + input.ID}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodOptions, "/items/123", nil)
+	req.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, OPTIONS, 123" {
+		t.Errorf("expected the operation's Allow header, got %q", allow)
+	}
+	if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "*" {
+		t.Errorf("expected CORS headers, got %q", origin)
+	}
+	if op == nil || op.OperationID != "options-item" {
+		t.Errorf("expected the handler to see its own operation, got %v", op)
+	}
+}
@@ -2,6 +2,7 @@ package humagmux
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
 	"net/http"
 	"net/url"
@@ -34,6 +35,22 @@ func (ctx *gmuxContext) Host() string {
 	return ctx.r.Host
 }
 
+func (ctx *gmuxContext) RemoteAddr() string {
+	return ctx.r.RemoteAddr
+}
+
+func (ctx *gmuxContext) TLS() *tls.ConnectionState {
+	return ctx.r.TLS
+}
+
+func (ctx *gmuxContext) Version() huma.ProtoVersion {
+	return huma.ProtoVersion{
+		Proto:      ctx.r.Proto,
+		ProtoMajor: ctx.r.ProtoMajor,
+		ProtoMinor: ctx.r.ProtoMinor,
+	}
+}
+
 func (ctx *gmuxContext) URL() url.URL {
 	return *ctx.r.URL
 }
@@ -66,6 +83,10 @@ func (ctx *gmuxContext) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(ctx.w, deadline)
 }
 
+func (ctx *gmuxContext) SetWriteDeadline(deadline time.Time) error {
+	return huma.SetWriteDeadline(ctx.w, deadline)
+}
+
 func (ctx *gmuxContext) SetStatus(code int) {
 	ctx.w.WriteHeader(code)
 }
@@ -102,3 +123,12 @@ func (a *gMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func New(r *mux.Router, config huma.Config) huma.API {
 	return huma.NewAPI(config, &gMux{router: r})
 }
+
+// Unwrap returns the HTTP request & response writer of a context created by
+// this adapter, panicking for other contexts. See `huma.UnwrapContext`.
+func Unwrap(ctx huma.Context) (*http.Request, http.ResponseWriter) {
+	if c, ok := huma.UnwrapContext(ctx).(*gmuxContext); ok {
+		return c.r, c.w
+	}
+	panic("not a humamux context")
+}
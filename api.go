@@ -1,7 +1,9 @@
 package huma
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,7 +11,9 @@ import (
 	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2/negotiation"
@@ -33,8 +37,19 @@ type ResolverWithPath interface {
 	Resolve(ctx Context, prefix *PathBuffer) []error
 }
 
+// Validator runs custom validation rules on a request body type, or any type
+// within it, once the body has passed schema validation. The returned errors
+// are reported alongside any other validation errors. Locations of errors
+// which are `*ErrorDetail` are relative to the validated value, e.g. `end`
+// within `body.ranges[0]` becomes `body.ranges[0].end`, and other errors are
+// reported at the value's own location.
+type Validator interface {
+	ValidateHuma(ctx Context) []error
+}
+
 var resolverType = reflect.TypeOf((*Resolver)(nil)).Elem()
 var resolverWithPathType = reflect.TypeOf((*ResolverWithPath)(nil)).Elem()
+var validatorType = reflect.TypeOf((*Validator)(nil)).Elem()
 
 // Adapter is an interface that allows the API to be used with different HTTP
 // routers and frameworks. It is designed to work with the standard library
@@ -46,6 +61,14 @@ type Adapter interface {
 	ServeHTTP(http.ResponseWriter, *http.Request)
 }
 
+// ProtoVersion is the HTTP protocol version of a request, e.g. `HTTP/2.0`
+// with a major version of 2 and minor version of 0.
+type ProtoVersion struct {
+	Proto      string
+	ProtoMajor int
+	ProtoMinor int
+}
+
 // Context is the current request/response context. It provides a generic
 // interface to get request information and write responses.
 type Context interface {
@@ -53,6 +76,13 @@ type Context interface {
 	Context() context.Context
 	Method() string
 	Host() string
+	// RemoteAddr is the network address of the client, usually `IP:port`,
+	// without considering proxy headers like `X-Forwarded-For`.
+	RemoteAddr() string
+	// TLS is the state of the TLS connection, including any verified client
+	// certificates, or nil for requests which didn't use TLS.
+	TLS() *tls.ConnectionState
+	Version() ProtoVersion
 	URL() url.URL
 	Param(name string) string
 	Query(name string) string
@@ -60,6 +90,10 @@ type Context interface {
 	EachHeader(cb func(name, value string))
 	BodyReader() io.Reader
 	SetReadDeadline(time.Time) error
+	// SetWriteDeadline sets the deadline for writing the response, e.g. to
+	// give a streaming response more time, overriding any server-wide write
+	// timeout. A zero value means no deadline.
+	SetWriteDeadline(time.Time) error
 	SetStatus(code int)
 	SetHeader(name, value string)
 	AppendHeader(name, value string)
@@ -81,9 +115,45 @@ type Config struct {
 
 	// OpenAPIPath is the path to the OpenAPI spec without extension. If set
 	// to `/openapi` it will allow clients to get `/openapi.json` or
-	// `/openapi.yaml`, for example.
+	// `/openapi.yaml`, for example. An OpenAPI 3.0.3 version is also served at
+	// `/openapi-3.0.json` and `/openapi-3.0.yaml` for older tools. Set to an
+	// empty string to disable serving the spec.
 	OpenAPIPath string
-	DocsPath    string
+
+	// OpenAPIFilters serve filtered variants of the spec, e.g. for different
+	// consumers, keyed by name. A filter named `partner` with an `OpenAPIPath`
+	// of `/openapi` serves `/openapi-partner.json` and `/openapi-partner.yaml`
+	// with only the operations for which the filter returns true. See
+	// `OpenAPI.Filter` for details.
+	OpenAPIFilters map[string]func(op *Operation) bool
+
+	// OpenAPIRequestServer adds the server each request for the spec was
+	// sent to, like `https://api.example.com`, as the first of the spec's
+	// `Servers`, so the docs' "try it" consoles work no matter how the API is
+	// reached. See `TrustForwardedHeaders` for APIs behind a proxy.
+	OpenAPIRequestServer bool
+
+	// TrustForwardedHeaders uses the `Forwarded` or `X-Forwarded-Proto` &
+	// `X-Forwarded-Host` request headers for the scheme & host the request
+	// was sent to, e.g. for `OpenAPIRequestServer` & `$schema` links. Only
+	// enable it behind a proxy which sets or strips these headers, since
+	// otherwise clients can send any value. Without them, the scheme is
+	// `https` only for requests made over TLS.
+	TrustForwardedHeaders bool
+
+	// DocsPath is the path to the interactive API documentation page, which
+	// loads the spec from `OpenAPIPath`. Set to an empty string to disable
+	// serving the docs. Docs are also disabled when `OpenAPIPath` is empty.
+	DocsPath string
+
+	// DocsUI renders the documentation page served at `DocsPath`, e.g.
+	// `huma.SwaggerUI()` or a custom `huma.NewDocsTemplate(...)`. Defaults to
+	// `huma.StoplightElements()`.
+	DocsUI DocsUI
+
+	// SchemasPath is the path prefix used to serve individual JSON Schemas,
+	// e.g. `/schemas/MyModel.json`. Set to an empty string to disable serving
+	// schemas.
 	SchemasPath string
 
 	// Formats defines the supported request/response formats by content type or
@@ -96,7 +166,99 @@ type Config struct {
 	DefaultFormat string
 
 	// Transformers are a way to modify a response body before it is serialized.
+	// They run in order with a priority of zero. Use `huma.AddTransformer` to
+	// add transformers with another priority or only for some tags.
 	Transformers []Transformer
+
+	// Authenticator enforces the security requirements declared on each
+	// operation, or the API-wide `Security` default, before the handler runs.
+	// Requests which fail get a 401 or 403 error response. The built-in
+	// OpenAPI, docs, and schema endpoints are always public.
+	Authenticator Authenticator
+
+	// Compression enables response compression negotiated via the client's
+	// `Accept-Encoding` header for all operations. It is disabled if nil.
+	Compression *CompressionConfig
+
+	// Decompression enables decoding of request bodies sent with a
+	// `Content-Encoding` header like `gzip`, e.g. by mobile SDKs, with a limit
+	// on their decompressed size. It is disabled if nil.
+	Decompression *DecompressionConfig
+
+	// CORS enables Cross-Origin Resource Sharing for all operations, including
+	// automatic responses to preflight `OPTIONS` requests. It is disabled if
+	// nil.
+	CORS *CORSConfig
+
+	// AutoAllow answers `OPTIONS` requests with an `Allow` header listing the
+	// methods of the operations registered at each path, and requests for
+	// methods without an operation with a `405 Method Not Allowed` error using
+	// the error model & the same header, consistently across adapters rather
+	// than using each router's defaults.
+	AutoAllow bool
+
+	// DebugValidateResponses validates response bodies against the documented
+	// response schemas, sending a 500 error instead of any response which does
+	// not match. This catches drift between your Go types and the generated
+	// OpenAPI, but buffers responses and adds overhead, so it is meant for
+	// tests & development rather than production. Bodies are validated
+	// before transformers run.
+	DebugValidateResponses bool
+
+	// StripReadOnly sets `Operation.StripReadOnly` for every operation, so
+	// read-only properties sent by clients are silently dropped.
+	StripReadOnly bool
+
+	// DefaultErrorResponse sets `Operation.DefaultErrorResponse` for every
+	// operation, so errors are documented once as the `default` response
+	// rather than for each status code.
+	DefaultErrorResponse bool
+
+	// StrictTags makes `huma.Register` panic if an operation uses a tag which
+	// isn't declared in the OpenAPI `Tags`, e.g. via `OpenAPI.AddTag`. This
+	// catches typos and keeps every tag documented in large specs.
+	StrictTags bool
+
+	// MaxValidationErrors is the default `Operation.MaxValidationErrors` for
+	// operations which don't set their own limit.
+	MaxValidationErrors int
+
+	// Language selects the `MessageCatalogs` entry used for this API's
+	// validation error messages, e.g. `de`. Requests can select another
+	// catalog via their `Accept-Language` header. Defaults to
+	// `huma.DefaultLanguage`.
+	Language string
+
+	// NewError replaces the default RFC 7807 `ErrorModel` with your own error
+	// type for this API, e.g. an established company-wide error envelope. It
+	// is used for errors generated by Huma like validation failures, errors
+	// returned by handlers which were created by the `huma.ErrorXXX`
+	// constructors, and the documented error schema. If nil, the package-level
+	// `huma.NewError` is used.
+	NewError func(status int, msg string, errs ...error) StatusError
+
+	// JSONMarshal replaces `encoding/json` for writing responses in formats
+	// using `DefaultJSONFormat`, e.g. to use a faster compatible library like
+	// `github.com/bytedance/sonic` or `github.com/goccy/go-json`:
+	//
+	//	config.JSONMarshal = func(w io.Writer, v any) error {
+	//		return sonic.ConfigDefault.NewEncoder(w).Encode(v)
+	//	}
+	JSONMarshal func(w io.Writer, v any) error
+
+	// JSONUnmarshal replaces `json.Unmarshal` for parsing requests in formats
+	// using `DefaultJSONFormat`, e.g. `sonic.Unmarshal`. Setting it disables
+	// validating request bodies while decoding them, since that uses the
+	// `encoding/json` rules.
+	JSONUnmarshal func(data []byte, v any) error
+
+	// Recover converts panics in operation handlers into `500 Internal Server
+	// Error` responses using the configured error model, regardless of the
+	// router, so clients always get a consistent error body. The function is
+	// called with the recovered value and stack trace, e.g. to log them. It is
+	// disabled if nil, which is the default so panics reach the router's own
+	// recoverer. See `huma.DefaultPanicHandler`.
+	Recover PanicHandler
 }
 
 // API represents a Huma API wrapping a specific router.
@@ -131,6 +293,12 @@ type Format struct {
 
 	// Unmarshal a value into `v` from the given bytes (e.g. request body).
 	Unmarshal func(data []byte, v any) error
+
+	// JSON marks the format as JSON parsed with `encoding/json` rules. Its
+	// functions are replaced by `Config.JSONMarshal` & `Config.JSONUnmarshal`
+	// when those are set, and otherwise request bodies are validated while
+	// they are decoded for better performance.
+	JSON bool
 }
 
 type api struct {
@@ -138,7 +306,27 @@ type api struct {
 	adapter      Adapter
 	formats      map[string]Format
 	formatKeys   []string
-	transformers []Transformer
+	jsonFormats  map[string]bool
+	transformers *transformers
+	providers    map[reflect.Type]providerFunc
+}
+
+// baseAPI returns the API created by `NewAPI` which the given API wraps, e.g.
+// as a group, or nil if there is none. APIs wrapping another API from outside
+// this package provide it via an `Unwrap() huma.API` method.
+func baseAPI(a API) *api {
+	for {
+		switch v := a.(type) {
+		case *api:
+			return v
+		case *Group:
+			a = v.API
+		case interface{ Unwrap() API }:
+			a = v.Unwrap()
+		default:
+			return nil
+		}
+	}
 }
 
 func (r *api) Adapter() Adapter {
@@ -149,14 +337,41 @@ func (r *api) OpenAPI() *OpenAPI {
 	return r.config.OpenAPI
 }
 
-func (r *api) Unmarshal(contentType string, data []byte, v any) error {
-	// Handle e.g. `application/json; charset=utf-8` or `my/format+json`
+func (r *api) modifyOperation(op *Operation) {
+	if r.config.StripReadOnly {
+		op.StripReadOnly = true
+	}
+	if r.config.DefaultErrorResponse {
+		op.DefaultErrorResponse = true
+	}
+	if op.MaxValidationErrors == 0 {
+		op.MaxValidationErrors = r.config.MaxValidationErrors
+	}
+	if r.config.StrictTags && !op.Hidden {
+		for _, tag := range op.Tags {
+			if r.config.OpenAPI.tag(tag) == nil {
+				panic(fmt.Sprintf("operation %s: undeclared tag %s", op.OperationID, tag))
+			}
+		}
+	}
+}
+
+// formatKey returns the key used to look up the format for a request content
+// type, handling e.g. `application/json; charset=utf-8` or `my/format+json`.
+func formatKey(contentType string) string {
 	start := strings.IndexRune(contentType, '+') + 1
 	end := strings.IndexRune(contentType, ';')
 	if end == -1 {
 		end = len(contentType)
 	}
-	f, ok := r.formats[contentType[start:end]]
+	if start > end {
+		start = 0
+	}
+	return contentType[start:end]
+}
+
+func (r *api) Unmarshal(contentType string, data []byte, v any) error {
+	f, ok := r.formats[formatKey(contentType)]
 	if !ok {
 		return fmt.Errorf("unknown content type: %s", contentType)
 	}
@@ -178,13 +393,28 @@ func (a *api) Marshal(ctx Context, respKey string, ct string, v any) error {
 	// fmt.Println("marshaling", ct)
 	var err error
 
-	for _, t := range a.transformers {
-		v, err = t(ctx, respKey, v)
+	if a.config.DebugValidateResponses {
+		if vc := validateResponseContextOf(ctx); vc != nil {
+			vc.setBody(v)
+		}
+	}
+
+	for _, t := range a.transformers.chain(ctx.Operation()) {
+		v, err = t.Transform(ctx, respKey, v)
 		if err != nil {
 			return err
 		}
 	}
+	if _, ok := v.(noBody); ok {
+		return nil
+	}
+
+	return a.marshalFormat(ctx.BodyWriter(), ct, v)
+}
 
+// marshalFormat marshals the value using the format for the content type,
+// without running any transformers.
+func (a *api) marshalFormat(w io.Writer, ct string, v any) error {
 	f, ok := a.formats[ct]
 	if !ok {
 		start := strings.IndexRune(ct, '+') + 1
@@ -193,19 +423,95 @@ func (a *api) Marshal(ctx Context, respKey string, ct string, v any) error {
 	if !ok {
 		return fmt.Errorf("unknown content type: %s", ct)
 	}
-	return f.Marshal(ctx.BodyWriter(), v)
+	return f.Marshal(w, v)
 }
 
 func NewAPI(config Config, a Adapter) API {
+	if config.OpenAPI == nil {
+		config.OpenAPI = &OpenAPI{}
+	}
+
+	if config.Language != "" {
+		if _, ok := MessageCatalogs[config.Language]; !ok {
+			panic("no message catalog for language " + config.Language)
+		}
+	}
+
+	// Dispatching by method is closest to the router, so the other adapters
+	// see each operation as-is.
+	var allow *allowAdapter
+	if config.AutoAllow {
+		allow = newAllowAdapter(a)
+		a = allow
+	}
+
+	// Adapters wrapped first handle requests first. CORS headers must be added
+	// to every response, including auth & other errors from the adapters
+	// below, so the browser can read them.
+	var cors *corsAdapter
+	if config.CORS != nil {
+		cors = newCORSAdapter(a, *config.CORS)
+		a = cors
+	}
+
+	// Compression is outside of response validation, so the validator sees
+	// the uncompressed body.
+	if config.Compression != nil {
+		a = newCompressAdapter(a, *config.Compression)
+	}
+
+	var validateResponses *validateResponseAdapter
+	if config.DebugValidateResponses {
+		validateResponses = &validateResponseAdapter{Adapter: a}
+		a = validateResponses
+	}
+
+	var auth *authAdapter
+	if config.Authenticator != nil {
+		auth = &authAdapter{Adapter: a, authenticate: config.Authenticator}
+		a = auth
+		config.OpenAPI.OnAddOperation = append(config.OpenAPI.OnAddOperation, auth.documentErrors)
+	}
+
+	var decompress *decompressAdapter
+	if config.Decompression != nil {
+		decompress = newDecompressAdapter(a, *config.Decompression)
+		a = decompress
+	}
+
+	// Recover from panics closest to the handler, so the error response is
+	// written through the other adapters, e.g. to be compressed.
+	var rec *recoverAdapter
+	if config.Recover != nil {
+		rec = &recoverAdapter{Adapter: a, onPanic: config.Recover}
+		a = rec
+	}
+
 	newAPI := &api{
 		config:       config,
 		adapter:      a,
 		formats:      map[string]Format{},
-		transformers: config.Transformers,
+		jsonFormats:  map[string]bool{},
+		transformers: newTransformers(config.Transformers),
 	}
 
-	if config.OpenAPI == nil {
-		config.OpenAPI = &OpenAPI{}
+	if auth != nil {
+		auth.api = newAPI
+	}
+	if cors != nil {
+		cors.api = newAPI
+	}
+	if decompress != nil {
+		decompress.api = newAPI
+	}
+	if validateResponses != nil {
+		validateResponses.api = newAPI
+	}
+	if allow != nil {
+		allow.api = newAPI
+	}
+	if rec != nil {
+		rec.api = newAPI
 	}
 
 	if config.OpenAPI.OpenAPI == "" {
@@ -227,74 +533,127 @@ func NewAPI(config Config, a Adapter) API {
 		newAPI.formatKeys = append(newAPI.formatKeys, config.DefaultFormat)
 	}
 	for k, v := range config.Formats {
+		if v.JSON {
+			// Swap in the configured JSON library. Request bodies can only be
+			// validated while decoding when they use `encoding/json`, see
+			// `compileBodyDecoder`.
+			if config.JSONMarshal != nil {
+				v.Marshal = config.JSONMarshal
+			}
+			if config.JSONUnmarshal != nil {
+				v.Unmarshal = config.JSONUnmarshal
+			} else {
+				newAPI.jsonFormats[k] = true
+			}
+		}
 		newAPI.formats[k] = v
 		newAPI.formatKeys = append(newAPI.formatKeys, k)
 	}
 
+	// Built-in endpoints are always public, even if there is an API-wide
+	// security requirement.
+	public := []map[string][]string{}
+
 	if config.OpenAPIPath != "" {
-		var specJSON []byte
-		a.Handle(&Operation{
-			Method: http.MethodGet,
-			Path:   config.OpenAPIPath + ".json",
-		}, func(ctx Context) {
-			ctx.SetHeader("Content-Type", "application/vnd.oai.openapi+json")
-			if specJSON == nil {
-				specJSON, _ = json.Marshal(newAPI.OpenAPI())
-			}
-			ctx.BodyWriter().Write(specJSON)
+		// The spec may be modified until the server starts, so marshal it on
+		// the first request rather than right now.
+		serveSpec := func(path, contentType string, marshal func(o *OpenAPI) ([]byte, error)) {
+			var spec []byte
+			var once sync.Once
+			servers := &serverSpecs{}
+			a.Handle(&Operation{
+				Method:   http.MethodGet,
+				Path:     path,
+				Security: public,
+			}, func(ctx Context) {
+				var b []byte
+				if config.OpenAPIRequestServer {
+					url := requestServerURL(ctx, config.TrustForwardedHeaders)
+					b = servers.get(url, func() []byte {
+						b, _ := marshal(withServer(newAPI.OpenAPI(), url))
+						return b
+					})
+				} else {
+					once.Do(func() {
+						spec, _ = marshal(newAPI.OpenAPI())
+					})
+					b = spec
+				}
+				ctx.SetHeader("Content-Type", contentType)
+				ctx.BodyWriter().Write(b)
+			})
+		}
+		serveSpec(config.OpenAPIPath+".json", "application/vnd.oai.openapi+json", func(o *OpenAPI) ([]byte, error) {
+			return json.Marshal(o)
 		})
-		var specYAML []byte
-		a.Handle(&Operation{
-			Method: http.MethodGet,
-			Path:   config.OpenAPIPath + ".yaml",
-		}, func(ctx Context) {
-			ctx.SetHeader("Content-Type", "application/vnd.oai.openapi+yaml")
-			if specYAML == nil {
-				specYAML, _ = yaml.Marshal(newAPI.OpenAPI())
-			}
-			ctx.BodyWriter().Write(specYAML)
+		serveSpec(config.OpenAPIPath+".yaml", "application/vnd.oai.openapi+yaml", func(o *OpenAPI) ([]byte, error) {
+			return yaml.Marshal(o)
+		})
+		serveSpec(config.OpenAPIPath+"-3.0.json", "application/vnd.oai.openapi+json", func(o *OpenAPI) ([]byte, error) {
+			return o.Downgrade()
 		})
+		serveSpec(config.OpenAPIPath+"-3.0.yaml", "application/vnd.oai.openapi+yaml", func(o *OpenAPI) ([]byte, error) {
+			return o.DowngradeYAML()
+		})
+
+		names := make([]string, 0, len(config.OpenAPIFilters))
+		for name := range config.OpenAPIFilters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			keep := config.OpenAPIFilters[name]
+			serveSpec(config.OpenAPIPath+"-"+name+".json", "application/vnd.oai.openapi+json", func(o *OpenAPI) ([]byte, error) {
+				return json.Marshal(o.Filter(keep))
+			})
+			serveSpec(config.OpenAPIPath+"-"+name+".yaml", "application/vnd.oai.openapi+yaml", func(o *OpenAPI) ([]byte, error) {
+				return yaml.Marshal(o.Filter(keep))
+			})
+		}
 	}
 
-	if config.DocsPath != "" {
+	if config.DocsPath != "" && config.OpenAPIPath != "" {
+		docsUI := config.DocsUI
+		if docsUI == nil {
+			docsUI = StoplightElements()
+		}
+		data := DocsData{
+			Title:   "API Reference",
+			SpecURL: config.OpenAPIPath + ".yaml",
+		}
+		if config.Info != nil && config.Info.Title != "" {
+			data.Title = config.Info.Title + " Reference"
+		}
+		docs := &bytes.Buffer{}
+		if err := docsUI.Render(docs, data); err != nil {
+			panic(err)
+		}
 		a.Handle(&Operation{
-			Method: http.MethodGet,
-			Path:   config.DocsPath,
+			Method:   http.MethodGet,
+			Path:     config.DocsPath,
+			Security: public,
 		}, func(ctx Context) {
 			ctx.SetHeader("Content-Type", "text/html")
-			ctx.BodyWriter().Write([]byte(`<!doctype html>
-<html lang="en">
-  <head>
-    <meta charset="utf-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1, shrink-to-fit=no">
-    <title>Elements in HTML</title>
-    <!-- Embed elements Elements via Web Component -->
-    <script src="https://unpkg.com/@stoplight/elements/web-components.min.js"></script>
-    <link rel="stylesheet" href="https://unpkg.com/@stoplight/elements/styles.min.css">
-  </head>
-  <body>
-
-    <elements-api
-      apiDescriptionUrl="` + config.OpenAPIPath + `.yaml"
-      router="hash"
-      layout="sidebar"
-    />
-
-  </body>
-</html>`))
+			ctx.BodyWriter().Write(docs.Bytes())
 		})
 	}
 
 	if config.SchemasPath != "" {
 		a.Handle(&Operation{
-			Method: http.MethodGet,
-			Path:   config.SchemasPath + "/{schema}",
+			Method:   http.MethodGet,
+			Path:     config.SchemasPath + "/{schema}",
+			Security: public,
 		}, func(ctx Context) {
 			// Some routers dislike a path param+suffix, so we strip it here instead.
 			schema := strings.TrimSuffix(ctx.Param("schema"), ".json")
-			ctx.SetHeader("Content-Type", "application/json")
-			b, _ := json.Marshal(config.OpenAPI.Components.Schemas.Map()[schema])
-			b = rxSchema.ReplaceAll(b, []byte(config.SchemasPath+`/$1.json`))
+			registry := config.OpenAPI.Components.Schemas
+			s := registry.Map()[schema]
+			if s == nil {
+				WriteErr(newAPI, ctx, http.StatusNotFound, "schema not found")
+				return
+			}
+			b := schemaDocument(registry, s, schema)
+			ctx.SetHeader("Content-Type", "application/schema+json")
 			ctx.BodyWriter().Write(b)
 		})
 	}
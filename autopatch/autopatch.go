@@ -16,16 +16,7 @@ import (
 	jsonpatch "github.com/evanphx/json-patch/v5"
 )
 
-// jsonPatchOp describes an RFC 6902 JSON Patch operation. See also:
-// https://www.rfc-editor.org/rfc/rfc6902
-type jsonPatchOp struct {
-	Op    string      `json:"op" enum:"add,remove,replace,move,copy,test" doc:"Operation name"`
-	From  string      `json:"from,omitempty" doc:"JSON Pointer for the source of a move or copy"`
-	Path  string      `json:"path" doc:"JSON Pointer to the field being operated on, or the destination of a move/copy operation"`
-	Value interface{} `json:"value,omitempty" doc:"The value to set"`
-}
-
-var jsonPatchType = reflect.TypeOf([]jsonPatchOp{})
+var jsonPatchType = reflect.TypeOf(JSONPatch{})
 
 // AutoPatch generates HTTP PATCH operations for any resource which has a
 // GET & PUT but no pre-existing PATCH operation. Generated PATCH operations
@@ -80,7 +71,7 @@ func generatePatch(api huma.API, path *huma.PathItem) {
 					break
 				}
 			}
-			for status := range put.Errors {
+			for _, status := range put.Errors {
 				if status == code {
 					found = true
 					break
@@ -144,8 +135,15 @@ func generatePatch(api huma.API, path *huma.PathItem) {
 			return
 		}
 
+		// The GET & PUT share the same path & query params as the PATCH.
+		u := ctx.URL()
+		target := u.Path
+		if u.RawQuery != "" {
+			target += "?" + u.RawQuery
+		}
+
 		// Perform the get!
-		origReq, err := http.NewRequest(http.MethodGet, ctx.URL().Path, nil)
+		origReq, err := http.NewRequest(http.MethodGet, target, nil)
 		if err != nil {
 			huma.WriteErr(api, ctx, http.StatusInternalServerError, "Unable to get resource", err)
 			return
@@ -171,19 +169,14 @@ func generatePatch(api huma.API, path *huma.PathItem) {
 
 		// Accept JSON for the patches.
 		// TODO: could we accept other stuff here...?
-		ctx.SetHeader("Accept", "application/json")
-		ctx.SetHeader("Accept-Encoding", "")
+		origReq.Header.Set("Accept", "application/json")
 
 		origWriter := httptest.NewRecorder()
 		adapter.ServeHTTP(origWriter, origReq)
 
 		if origWriter.Code >= 300 {
 			// This represents an error on the GET side.
-			for key, values := range origWriter.Header() {
-				for _, value := range values {
-					ctx.SetHeader(key, value)
-				}
-			}
+			copyHeaders(ctx, origWriter.Header(), get.Responses)
 			ctx.SetStatus(origWriter.Code)
 			io.Copy(ctx.BodyWriter(), origWriter.Body)
 			return
@@ -235,7 +228,7 @@ func generatePatch(api huma.API, path *huma.PathItem) {
 			}
 		default:
 			// A content type we explicitly do not support was passed.
-			huma.WriteErr(api, ctx, http.StatusUnsupportedMediaType, "Content type should be one of application/merge-patch+json or application/json-patch+json", nil)
+			huma.WriteErr(api, ctx, http.StatusUnsupportedMediaType, "Content type should be one of application/merge-patch+json or application/json-patch+json")
 			return
 		}
 
@@ -245,13 +238,13 @@ func generatePatch(api huma.API, path *huma.PathItem) {
 		}
 
 		// Write the updated data back to the server!
-		putReq, err := http.NewRequest(http.MethodPut, op.Path, bytes.NewReader(patched))
+		putReq, err := http.NewRequest(http.MethodPut, target, bytes.NewReader(patched))
 		if err != nil {
 			huma.WriteErr(api, ctx, http.StatusInternalServerError, "Unable to put modified resource", err)
 			return
 		}
 		ctx.EachHeader(func(k, v string) {
-			if k == "Content-Type" || k == "Content-Length" {
+			if k == "Content-Type" || k == "Content-Length" || k == "Accept-Encoding" {
 				return
 			}
 			putReq.Header.Add(k, v)
@@ -276,12 +269,51 @@ func generatePatch(api huma.API, path *huma.PathItem) {
 
 		putWriter := httptest.NewRecorder()
 		adapter.ServeHTTP(putWriter, putReq)
-		for key, values := range putWriter.Header() {
-			for _, value := range values {
-				ctx.SetHeader(key, value)
-			}
-		}
+		copyHeaders(ctx, putWriter.Header(), put.Responses)
 		ctx.SetStatus(putWriter.Code)
 		io.Copy(ctx.BodyWriter(), putWriter.Body)
 	})
 }
+
+// patchHeaders are the undocumented response headers of the inner GET & PUT
+// requests which are copied to the PATCH response.
+var patchHeaders = map[string]bool{
+	"Content-Type":     true,
+	"Content-Language": true,
+	"Cache-Control":    true,
+	"Etag":             true,
+	"Last-Modified":    true,
+	"Location":         true,
+	"Retry-After":      true,
+	"Www-Authenticate": true,
+}
+
+// copyHeaders copies the response headers of an inner GET or PUT request which
+// the PATCH response needs. Others, like CORS or hop-by-hop headers, are set
+// by the adapters handling the PATCH itself, so copying them would result in
+// duplicate values.
+func copyHeaders(ctx huma.Context, headers http.Header, responses map[string]*huma.Response) {
+	documented := map[string]bool{}
+	for _, resp := range responses {
+		for name := range resp.Headers {
+			documented[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+
+	for key, values := range headers {
+		key = http.CanonicalHeaderKey(key)
+		if !patchHeaders[key] && !documented[key] {
+			continue
+		}
+		if key == "Vary" || strings.HasPrefix(key, "Access-Control-") {
+			continue
+		}
+		for i, value := range values {
+			if i == 0 {
+				ctx.SetHeader(key, value)
+			} else {
+				ctx.AppendHeader(key, value)
+			}
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package autopatch
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Thing struct {
+	Name  string   `json:"name" minLength:"1"`
+	Count int      `json:"count" minimum:"0"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+type ThingIDParam struct {
+	ID string `path:"id"`
+}
+
+func TestAutoPatch(t *testing.T) {
+	things := map[string]*Thing{
+		"abc": {Name: "ABC", Count: 1},
+	}
+
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *ThingIDParam) (*struct {
+		ETag string `header:"ETag"`
+		Body *Thing
+	}, error) {
+		thing := things[input.ID]
+		if thing == nil {
+			return nil, huma.Error404NotFound("not found")
+		}
+		return &struct {
+			ETag string `header:"ETag"`
+			Body *Thing
+		}{ETag: thing.Name, Body: thing}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ThingIDParam
+		IfMatch string `header:"If-Match"`
+		Body    Thing
+	}) (*struct {
+		Body *Thing
+	}, error) {
+		thing := things[input.ID]
+		if input.IfMatch != "" && input.IfMatch != thing.Name {
+			return nil, huma.Error412PreconditionFailed("etag mismatch")
+		}
+		things[input.ID] = &input.Body
+		return &struct{ Body *Thing }{Body: &input.Body}, nil
+	})
+
+	AutoPatch(api)
+
+	patch := api.OpenAPI().Paths["/things/{id}"].Patch
+	assert.NotNil(t, patch)
+	assert.Equal(t, "patch-thing", patch.OperationID)
+	assert.NotNil(t, patch.RequestBody.Content["application/merge-patch+json"])
+	assert.NotNil(t, patch.RequestBody.Content["application/json-patch+json"])
+
+	// Merge patch
+	resp := api.Patch("/things/abc",
+		"Content-Type: application/merge-patch+json",
+		strings.NewReader(`{"count": 5}`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, &Thing{Name: "ABC", Count: 5}, things["abc"])
+
+	// JSON Patch
+	resp = api.Patch("/things/abc",
+		"Content-Type: application/json-patch+json",
+		strings.NewReader(`[{"op": "add", "path": "/tags", "value": ["a"]}]`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, []string{"a"}, things["abc"].Tags)
+
+	// No changes
+	resp = api.Patch("/things/abc",
+		"Content-Type: application/merge-patch+json",
+		strings.NewReader(`{"count": 5}`))
+	assert.Equal(t, http.StatusNotModified, resp.Code, resp.Body.String())
+
+	// The patched resource is validated by the PUT operation.
+	resp = api.Patch("/things/abc",
+		"Content-Type: application/merge-patch+json",
+		strings.NewReader(`{"count": -1}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+
+	// Conditional requests are passed through to the PUT.
+	resp = api.Patch("/things/abc",
+		"Content-Type: application/merge-patch+json",
+		"If-Match: bad",
+		strings.NewReader(`{"count": 10}`))
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code, resp.Body.String())
+
+	// Missing resource
+	resp = api.Patch("/things/missing",
+		"Content-Type: application/merge-patch+json",
+		strings.NewReader(`{"count": 1}`))
+	assert.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	// Unsupported content type
+	resp = api.Patch("/things/abc",
+		"Content-Type: text/plain",
+		strings.NewReader(`count: 1`))
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code, resp.Body.String())
+}
+
+func TestAutoPatchHeaders(t *testing.T) {
+	thing := &Thing{Name: "ABC", Count: 1}
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.CORS = &huma.CORSConfig{AllowOrigins: []string{"https://example.com"}}
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *ThingIDParam) (*struct {
+		ETag string `header:"ETag"`
+		Body *Thing
+	}, error) {
+		return &struct {
+			ETag string `header:"ETag"`
+			Body *Thing
+		}{ETag: "get", Body: thing}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ThingIDParam
+		Body Thing
+	}) (*struct {
+		ETag    string `header:"ETag"`
+		Version string `header:"X-Version"`
+		Body    *Thing
+	}, error) {
+		thing = &input.Body
+		return &struct {
+			ETag    string `header:"ETag"`
+			Version string `header:"X-Version"`
+			Body    *Thing
+		}{ETag: "put", Version: "2", Body: thing}, nil
+	})
+
+	AutoPatch(api)
+
+	resp := api.Patch("/things/abc",
+		"Origin: https://example.com",
+		"Content-Type: application/merge-patch+json",
+		strings.NewReader(`{"count": 5}`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, []string{"https://example.com"}, resp.Header().Values("Access-Control-Allow-Origin"))
+	assert.Equal(t, []string{"Origin"}, resp.Header().Values("Vary"))
+	assert.Equal(t, []string{"put"}, resp.Header().Values("ETag"))
+	assert.Equal(t, "2", resp.Header().Get("X-Version"))
+	assert.Equal(t, []string{"application/json"}, resp.Header().Values("Content-Type"))
+}
+
+func TestJSONPatchBody(t *testing.T) {
+	thing := Thing{Name: "ABC", Count: 1, Tags: []string{"a"}}
+
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "patch-thing",
+		Method:      http.MethodPatch,
+		Path:        "/thing",
+	}, func(ctx context.Context, input *struct {
+		Body JSONPatch
+	}) (*struct{ Body Thing }, error) {
+		if err := input.Body.ApplyTo(&thing); err != nil {
+			return nil, huma.Error422UnprocessableEntity("unable to apply patch", err)
+		}
+		return &struct{ Body Thing }{Body: thing}, nil
+	})
+
+	body := api.OpenAPI().Paths["/thing"].Patch.RequestBody
+	assert.NotNil(t, body.Content["application/json-patch+json"])
+	assert.Nil(t, body.Content["application/json"])
+
+	resp := api.Patch("/thing",
+		"Content-Type: application/json-patch+json",
+		strings.NewReader(`[
+			{"op": "replace", "path": "/name", "value": "XYZ"},
+			{"op": "remove", "path": "/tags"},
+			{"op": "test", "path": "/count", "value": 1}
+		]`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, Thing{Name: "XYZ", Count: 1}, thing)
+
+	// Invalid operations are rejected before the handler runs.
+	resp = api.Patch("/thing",
+		"Content-Type: application/json-patch+json",
+		strings.NewReader(`[
+			{"op": "bad", "path": "/name"},
+			{"op": "move", "path": "name"}
+		]`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), "body[0].op")
+	assert.Contains(t, resp.Body.String(), "body[1].path")
+	assert.Contains(t, resp.Body.String(), "body[1].from")
+
+	// Failed tests leave the value unmodified.
+	resp = api.Patch("/thing",
+		"Content-Type: application/json-patch+json",
+		strings.NewReader(`[
+			{"op": "replace", "path": "/name", "value": "Changed"},
+			{"op": "test", "path": "/count", "value": 5}
+		]`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	assert.Equal(t, "XYZ", thing.Name)
+}
+
+func TestJSONPatchApply(t *testing.T) {
+	patch := JSONPatch{
+		{Op: "add", Path: "/a", Value: nil},
+		{Op: "copy", From: "/b", Path: "/c"},
+	}
+	result, err := patch.Apply([]byte(`{"b": 1}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"a": null, "b": 1, "c": 1}`, string(result))
+
+	_, err = JSONPatch{{Op: "move", Path: "/a"}}.Apply([]byte(`{}`))
+	assert.ErrorContains(t, err, "from is required for move")
+}
@@ -0,0 +1,139 @@
+package autopatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// JSONPatchOp describes an RFC 6902 JSON Patch operation. See also:
+// https://www.rfc-editor.org/rfc/rfc6902
+type JSONPatchOp struct {
+	Op    string `json:"op" enum:"add,remove,replace,move,copy,test" doc:"Operation name"`
+	From  string `json:"from,omitempty" doc:"JSON Pointer for the source of a move or copy"`
+	Path  string `json:"path" doc:"JSON Pointer to the field being operated on, or the destination of a move/copy operation"`
+	Value any    `json:"value,omitempty" doc:"The value to set"`
+}
+
+// Resolve validates the JSON Pointers used by the operation, which can't be
+// described by the schema alone.
+func (o *JSONPatchOp) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
+	return o.validate(prefix)
+}
+
+func (o *JSONPatchOp) validate(prefix *huma.PathBuffer) []error {
+	var errs []error
+	if o.Path != "" && !strings.HasPrefix(o.Path, "/") {
+		errs = append(errs, &huma.ErrorDetail{
+			Message:  "expected JSON Pointer starting with /",
+			Location: prefix.With("path"),
+			Value:    o.Path,
+		})
+	}
+	if o.Op == "move" || o.Op == "copy" {
+		if o.From == "" {
+			errs = append(errs, &huma.ErrorDetail{
+				Message:  "from is required for " + o.Op,
+				Location: prefix.With("from"),
+			})
+		} else if !strings.HasPrefix(o.From, "/") {
+			errs = append(errs, &huma.ErrorDetail{
+				Message:  "expected JSON Pointer starting with /",
+				Location: prefix.With("from"),
+				Value:    o.From,
+			})
+		}
+	}
+	return errs
+}
+
+// MarshalJSON always includes the value for operations which require one,
+// even if it is `null`.
+func (o JSONPatchOp) MarshalJSON() ([]byte, error) {
+	type plain JSONPatchOp
+	if o.Value == nil && (o.Op == "add" || o.Op == "replace" || o.Op == "test") {
+		return json.Marshal(struct {
+			plain
+			Value any `json:"value"`
+		}{plain: plain(o)})
+	}
+	return json.Marshal(plain(o))
+}
+
+// JSONPatch is an RFC 6902 JSON Patch document which can be used as an
+// operation's input `Body` to accept `application/json-patch+json` requests:
+//
+//	huma.Register(api, huma.Operation{
+//		OperationID: "patch-thing",
+//		Method:      http.MethodPatch,
+//		Path:        "/things/{id}",
+//	}, func(ctx context.Context, input *struct {
+//		ID   string `path:"id"`
+//		Body autopatch.JSONPatch
+//	}) (*struct{}, error) {
+//		thing := getThing(input.ID)
+//		if err := input.Body.ApplyTo(&thing); err != nil {
+//			return nil, huma.Error422UnprocessableEntity("unable to apply patch", err)
+//		}
+//		// ...
+//	})
+type JSONPatch []JSONPatchOp
+
+// ContentType documents the request body as `application/json-patch+json`.
+func (p JSONPatch) ContentType(string) string {
+	return "application/json-patch+json"
+}
+
+// Validate returns an error if any of the operations are invalid.
+func (p JSONPatch) Validate() error {
+	pb := huma.NewPathBuffer([]byte{}, 0)
+	for i := range p {
+		pb.Reset()
+		pb.PushIndex(i)
+		if errs := p[i].validate(pb); len(errs) > 0 {
+			return errs[0]
+		}
+	}
+	return nil
+}
+
+// Apply validates the patch and applies it to the JSON document, returning
+// the patched document. The original is not modified.
+func (p JSONPatch) Apply(doc []byte) ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonpatch.DecodePatch(b)
+	if err != nil {
+		return nil, err
+	}
+	return patch.Apply(doc)
+}
+
+// ApplyTo applies the patch to the value pointed to by `v` by round-tripping
+// it through JSON. The value is only modified if the patch succeeds.
+func (p JSONPatch) ApplyTo(v any) error {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	patched, err := p.Apply(doc)
+	if err != nil {
+		return err
+	}
+	// Decode into a new value so removed fields don't keep their old values.
+	rv := reflect.ValueOf(v).Elem()
+	nv := reflect.New(rv.Type())
+	if err := json.Unmarshal(patched, nv.Interface()); err != nil {
+		return err
+	}
+	rv.Set(nv.Elem())
+	return nil
+}
@@ -0,0 +1,41 @@
+// Package brotli adds Brotli (`br`) response compression & request body
+// decompression to Huma, keeping the Brotli library out of the core package.
+// Importing it registers the encoding in `huma.DefaultCompressors`, ahead of
+// gzip so that it is preferred when the client accepts both equally, and in
+// `huma.DefaultDecompressors`:
+//
+//	import _ "github.com/danielgtaylor/huma/v2/brotli"
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	config.Compression = &huma.CompressionConfig{}
+package brotli
+
+import (
+	"io"
+
+	"github.com/andybalholm/brotli"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Compressor compresses responses using Brotli, e.g. for a custom list of
+// `huma.CompressionConfig.Compressors`.
+var Compressor = huma.Compressor{
+	Encoding: "br",
+	New: func() huma.Encoder {
+		return brotli.NewWriterLevel(nil, brotli.DefaultCompression)
+	},
+}
+
+// Decompressor decompresses Brotli request bodies, e.g. for a custom list of
+// `huma.DecompressionConfig.Decompressors`.
+var Decompressor = huma.Decompressor{
+	Encoding: "br",
+	New: func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(brotli.NewReader(r)), nil
+	},
+}
+
+func init() {
+	huma.DefaultCompressors = append([]huma.Compressor{Compressor}, huma.DefaultCompressors...)
+	huma.DefaultDecompressors = append(huma.DefaultDecompressors, Decompressor)
+}
@@ -0,0 +1,68 @@
+package brotli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompression(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Compression = &huma.CompressionConfig{MinSize: 100}
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "compress",
+		Method:      http.MethodGet,
+		Path:        "/compress",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: strings.Repeat("a", 500)}, nil
+	})
+
+	// Brotli is preferred when the client accepts both equally.
+	resp := api.Get("/compress", "Accept-Encoding: gzip, br")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "br", resp.Header().Get("Content-Encoding"))
+
+	var body string
+	assert.NoError(t, json.NewDecoder(brotli.NewReader(resp.Body)).Decode(&body))
+	assert.Len(t, body, 500)
+
+	resp = api.Get("/compress", "Accept-Encoding: gzip")
+	assert.Equal(t, "gzip", resp.Header().Get("Content-Encoding"))
+}
+
+func TestDecompression(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Decompression = &huma.DecompressionConfig{}
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "decompress",
+		Method:      http.MethodPost,
+		Path:        "/decompress",
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: input.Body.Name}, nil
+	})
+
+	buf := &bytes.Buffer{}
+	w := brotli.NewWriter(buf)
+	w.Write([]byte(`{"name": "br"}`))
+	w.Close()
+
+	resp := api.Post("/decompress", "Content-Type: application/json", "Content-Encoding: br", buf)
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, `"br"`+"\n", resp.Body.String())
+}
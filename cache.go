@@ -0,0 +1,93 @@
+package huma
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicy describes how clients & shared caches like CDNs may cache an
+// operation's successful responses, sent as the `Cache-Control` header. See
+// `Operation.Cache`.
+//
+//	huma.Register(api, huma.Operation{
+//		OperationID: "get-thing",
+//		Method:      http.MethodGet,
+//		Path:        "/things/{thing-id}",
+//		Cache:       &huma.CachePolicy{Public: true, MaxAge: 5 * time.Minute},
+//	}, handler)
+type CachePolicy struct {
+	// MaxAge is how long responses may be cached.
+	MaxAge time.Duration
+
+	// SharedMaxAge overrides `MaxAge` for shared caches like CDNs.
+	SharedMaxAge time.Duration
+
+	// StaleWhileRevalidate is how long stale responses may be used while
+	// they are revalidated in the background.
+	StaleWhileRevalidate time.Duration
+
+	// Public lets shared caches store responses, even for authenticated
+	// requests.
+	Public bool
+
+	// Private only lets the client's own cache store responses, e.g. for
+	// user-specific data.
+	Private bool
+
+	// NoCache requires caches to revalidate responses before using them.
+	NoCache bool
+
+	// NoStore prevents caching responses at all, e.g. for sensitive data.
+	NoStore bool
+
+	// Immutable indicates responses will never change while fresh.
+	Immutable bool
+}
+
+// String returns the `Cache-Control` header value for the policy, like
+// `public, max-age=300`.
+func (p *CachePolicy) String() string {
+	directives := []string{}
+	if p.Public {
+		directives = append(directives, "public")
+	}
+	if p.Private {
+		directives = append(directives, "private")
+	}
+	if p.NoCache {
+		directives = append(directives, "no-cache")
+	}
+	if p.NoStore {
+		directives = append(directives, "no-store")
+	}
+	for _, d := range []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"max-age", p.MaxAge},
+		{"s-maxage", p.SharedMaxAge},
+		{"stale-while-revalidate", p.StaleWhileRevalidate},
+	} {
+		if d.duration > 0 {
+			directives = append(directives, d.name+"="+strconv.Itoa(int(d.duration.Seconds())))
+		}
+	}
+	if p.Immutable {
+		directives = append(directives, "immutable")
+	}
+	return strings.Join(directives, ", ")
+}
+
+// validate returns a description of the first conflicting setting, if any.
+func (p *CachePolicy) validate() string {
+	switch {
+	case p.Public && p.Private:
+		return "cannot be both public and private"
+	case p.NoStore && (p.MaxAge > 0 || p.SharedMaxAge > 0 || p.StaleWhileRevalidate > 0 || p.Immutable):
+		return "no-store cannot be combined with caching durations or immutable"
+	case p.String() == "":
+		return "sets no directives"
+	}
+	return ""
+}
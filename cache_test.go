@@ -0,0 +1,84 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachePolicyString(t *testing.T) {
+	for expected, policy := range map[string]CachePolicy{
+		"public, max-age=300":                           {Public: true, MaxAge: 5 * time.Minute},
+		"private, no-cache":                             {Private: true, NoCache: true},
+		"no-store":                                      {NoStore: true},
+		"max-age=60, s-maxage=3600, immutable":          {MaxAge: time.Minute, SharedMaxAge: time.Hour, Immutable: true},
+		"public, max-age=10, stale-while-revalidate=30": {Public: true, MaxAge: 10 * time.Second, StaleWhileRevalidate: 30 * time.Second},
+	} {
+		assert.Equal(t, expected, policy.String())
+	}
+}
+
+func TestCachePolicy(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Register(app, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+		Cache:       &CachePolicy{Public: true, MaxAge: time.Minute},
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{ Body string }, error) {
+		if input.ID == "missing" {
+			return nil, Error404NotFound("no thing")
+		}
+		return &struct{ Body string }{input.ID}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/things/a", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+
+	// Errors are not cached.
+	req, _ = http.NewRequest(http.MethodGet, "/things/missing", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+
+	op := app.OpenAPI().Paths["/things/{id}"].Get
+	assert.Equal(t, "public, max-age=60", op.Extensions["x-cache-control"])
+	assert.Contains(t, op.Responses["200"].Headers, "Cache-Control")
+
+	assert.PanicsWithValue(t, "operation bad: cache policy cannot be both public and private", func() {
+		Register(app, Operation{
+			OperationID: "bad",
+			Method:      http.MethodGet,
+			Path:        "/bad",
+			Cache:       &CachePolicy{Public: true, Private: true},
+		}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+
+	assert.PanicsWithValue(t, "operation conflict: cache policy conflicts with the Cache-Control output header", func() {
+		Register(app, Operation{
+			OperationID: "conflict",
+			Method:      http.MethodGet,
+			Path:        "/conflict",
+			Cache:       &CachePolicy{NoStore: true},
+		}, func(ctx context.Context, input *struct{}) (*struct {
+			CacheControl string `header:"Cache-Control"`
+		}, error) {
+			return nil, nil
+		})
+	})
+}
@@ -0,0 +1,170 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Call calls a registered operation of a Huma API at the base URL, like `Do`
+// using the operation's method & path, and reuses the operation's schemas to
+// validate both sides of the call. The input's params & body are validated
+// before sending, so invalid requests fail fast without a round trip, and the
+// response body is validated against the schema documented for its status.
+// This makes it useful for service-to-service calls and contract tests.
+//
+//	op := api.OpenAPI().FindOperation("get-thing")
+//	out, err := huma.Call[GetThingInput, GetThingOutput](ctx, "https://api.example.com", op, &GetThingInput{ID: "abc"})
+//
+// The operation must have been registered via `Register` with the same input
+// & output types. Invalid inputs return a 422 `StatusError` and invalid
+// responses a 502 `StatusError` with the validation errors. Other error
+// responses are returned like with `Do`.
+func Call[I, O any](ctx context.Context, baseURL string, op *Operation, input *I) (*O, error) {
+	return CallWith[I, O](ctx, NewClient(baseURL), op, input)
+}
+
+// CallWith calls a registered operation like `Call`, using the client to send
+// the request, e.g. to set headers for authentication.
+func CallWith[I, O any](ctx context.Context, c *Client, op *Operation, input *I) (*O, error) {
+	var out O
+	v := reflect.ValueOf(&out).Elem()
+	if op.inputType == nil || op.registry == nil {
+		return nil, fmt.Errorf("operation %s was not registered via huma.Register", op.OperationID)
+	}
+	if it := reflect.TypeOf(input).Elem(); it != op.inputType || v.Type() != op.outputType {
+		return nil, fmt.Errorf("operation %s takes %s and returns %s, not %s and %s", op.OperationID, op.inputType, op.outputType, it, v.Type())
+	}
+	if err := checkClientOutput(v.Type()); err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(ctx, op.Method, op.Path, reflect.ValueOf(input))
+	if err != nil {
+		return nil, err
+	}
+	if errs := validateCallRequest(op, req); len(errs) > 0 {
+		return nil, NewError(http.StatusUnprocessableEntity, "validation failed", errs...)
+	}
+
+	resp, body, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if errs := validateCallResponse(op, resp, body); len(errs) > 0 {
+		return nil, NewError(http.StatusBadGateway, "response validation failed", errs...)
+	}
+
+	if err := decodeClientResponse(resp, body, v); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// callParams caches the params of each operation, parsed with the
+// operation's schemas.
+var callParams sync.Map
+
+// validateCallRequest validates the request against the operation's params &
+// body schema by parsing it in the same way as the server.
+func validateCallRequest(op *Operation, req *clientRequest) []error {
+	cached, ok := callParams.Load(op)
+	if !ok {
+		cached, _ = callParams.LoadOrStore(op, findParams(op.registry, &Operation{}, op.inputType))
+	}
+	params := cached.(*findResult[*paramFieldInfo])
+
+	deps := validatePool.Get().(*validateDeps)
+	defer func() {
+		deps.reset()
+		validatePool.Put(deps)
+	}()
+	pb := deps.pb
+	res := deps.res
+	res.Limit = op.MaxValidationErrors
+
+	if !op.SkipValidateParams {
+		query := req.URL.Query()
+		for _, entry := range params.Paths {
+			p := entry.Value
+			f := reflect.New(p.Type).Elem()
+			pb.Reset()
+			pb.Push(p.Loc)
+			pb.Push(p.Name)
+			if p.parseQuery != nil {
+				p.parseQuery(f, query, pb, res, true)
+				continue
+			}
+
+			var value string
+			switch p.Loc {
+			case "path":
+				value = req.pathParams[p.Name]
+			case "query":
+				value = query.Get(p.Name)
+			case "header":
+				value = strings.Join(req.Header.Values(p.Name), ",")
+			}
+			if value == "" && p.Default != "" {
+				value = p.Default
+			}
+			if p.Loc == "path" && value == "" {
+				res.Add(pb, "", message("requiredPathParam"))
+				continue
+			}
+			if value != "" {
+				p.parse(f, value, pb, res, true)
+			}
+		}
+	}
+
+	if !op.SkipValidateBody && req.body != nil && op.RequestBody != nil {
+		if mt := op.RequestBody.Content[req.Header.Get("Content-Type")]; mt != nil && mt.Schema != nil {
+			var parsed any
+			if err := json.Unmarshal(req.body, &parsed); err == nil {
+				pb.Reset()
+				pb.Push("body")
+				Validate(op.registry, mt.Schema, pb, ModeWriteToServer, parsed, res)
+			}
+		}
+	}
+
+	if len(res.Errors) == 0 {
+		return nil
+	}
+	// The result is reused once returned to the pool.
+	return append([]error(nil), res.Errors...)
+}
+
+// validateCallResponse validates a JSON response body against the schema
+// documented for its status.
+func validateCallResponse(op *Operation, resp *http.Response, body []byte) []error {
+	ct := resp.Header.Get("Content-Type")
+	schema := responseSchema(op.Responses, resp.StatusCode, ct)
+	if schema == nil || len(body) == 0 {
+		return nil
+	}
+
+	deps := validatePool.Get().(*validateDeps)
+	defer func() {
+		deps.reset()
+		validatePool.Put(deps)
+	}()
+	pb := deps.pb
+	res := deps.res
+
+	var parsed any
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return []error{&ErrorDetail{Location: "body", Message: err.Error()}}
+	}
+	pb.Push("body")
+	Validate(op.registry, schema, pb, ModeReadFromServer, parsed, res)
+	if len(res.Errors) == 0 {
+		return nil
+	}
+	return append([]error(nil), res.Errors...)
+}
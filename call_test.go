@@ -0,0 +1,123 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type CallThingInput struct {
+	ID    string `path:"id" minLength:"3"`
+	Limit int    `query:"limit" maximum:"10"`
+	Body  struct {
+		Name string `json:"name" maxLength:"10"`
+	}
+}
+
+type CallThingOutput struct {
+	Body struct {
+		ID    string `json:"id"`
+		Count int    `json:"count" minimum:"0"`
+	}
+}
+
+func TestCall(t *testing.T) {
+	api, server := newClientTestAPI(t)
+
+	calls := 0
+	Register(api, Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *CallThingInput) (*CallThingOutput, error) {
+		calls++
+		out := &CallThingOutput{}
+		out.Body.ID = input.ID
+		out.Body.Count = input.Limit
+		if input.Body.Name == "broken" {
+			// Violates the documented response schema.
+			out.Body.Count = -1
+		}
+		return out, nil
+	})
+
+	op := api.OpenAPI().FindOperation("put-thing")
+	assert.NotNil(t, op)
+	assert.Nil(t, api.OpenAPI().FindOperation("missing"))
+	ctx := context.Background()
+
+	input := &CallThingInput{ID: "abc", Limit: 5}
+	input.Body.Name = "Thing"
+	out, err := Call[CallThingInput, CallThingOutput](ctx, server.URL, op, input)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", out.Body.ID)
+	assert.Equal(t, 5, out.Body.Count)
+	assert.Equal(t, 1, calls)
+
+	// Invalid params & bodies fail without sending the request.
+	input = &CallThingInput{ID: "a", Limit: 20}
+	input.Body.Name = "This name is far too long"
+	_, err = Call[CallThingInput, CallThingOutput](ctx, server.URL, op, input)
+	var model *ErrorModel
+	if assert.ErrorAs(t, err, &model) {
+		assert.Equal(t, http.StatusUnprocessableEntity, model.Status)
+		locations := []string{}
+		for _, detail := range model.Errors {
+			locations = append(locations, detail.Location)
+		}
+		assert.ElementsMatch(t, []string{"path.id", "query.limit", "body.name"}, locations)
+	}
+	assert.Equal(t, 1, calls)
+
+	// Responses which don't match their schema are rejected.
+	input = &CallThingInput{ID: "abc"}
+	input.Body.Name = "broken"
+	_, err = Call[CallThingInput, CallThingOutput](ctx, server.URL, op, input)
+	if assert.ErrorAs(t, err, &model) {
+		assert.Equal(t, http.StatusBadGateway, model.Status)
+		assert.Equal(t, "body.count", model.Errors[0].Location)
+	}
+	assert.Equal(t, 2, calls)
+
+	// Headers can be sent using a client.
+	client := NewClient(server.URL)
+	client.Header.Set("Authorization", "Bearer abc")
+	input = &CallThingInput{ID: "def"}
+	out, err = CallWith[CallThingInput, CallThingOutput](ctx, client, op, input)
+	assert.NoError(t, err)
+	assert.Equal(t, "def", out.Body.ID)
+}
+
+func TestCallErrors(t *testing.T) {
+	api, server := newClientTestAPI(t)
+	Register(api, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, Error404NotFound("thing not found")
+	})
+	op := api.OpenAPI().FindOperation("get-thing")
+	ctx := context.Background()
+
+	// Error responses are validated against the error model & returned.
+	_, err := Call[struct {
+		ID string `path:"id"`
+	}, struct{}](ctx, server.URL, op, &struct {
+		ID string `path:"id"`
+	}{ID: "abc"})
+	var se StatusError
+	if assert.ErrorAs(t, err, &se) {
+		assert.Equal(t, http.StatusNotFound, se.GetStatus())
+	}
+
+	_, err = Call[CallThingInput, struct{}](ctx, server.URL, op, &CallThingInput{})
+	assert.ErrorContains(t, err, "operation get-thing takes")
+
+	_, err = Call[CallThingInput, struct{}](ctx, server.URL, &Operation{OperationID: "manual"}, &CallThingInput{})
+	assert.ErrorContains(t, err, "was not registered")
+}
@@ -2,15 +2,20 @@ package huma
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"reflect"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/danielgtaylor/casing"
+	"github.com/goccy/go-yaml"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -47,6 +52,29 @@ type Hooks interface {
 	OnStop(func())
 }
 
+// ServerOptions are common options for running an HTTP service, which can be
+// embedded in your own options struct:
+//
+//	type Options struct {
+//		huma.ServerOptions
+//		DatabaseURL string `doc:"Database connection string"`
+//	}
+//
+//	cli := huma.NewCLI(func(hooks huma.Hooks, opts *Options) {
+//		server := &http.Server{Addr: opts.Addr(), Handler: router}
+//		huma.Serve(hooks, server, opts.ShutdownTimeout, nil)
+//	})
+type ServerOptions struct {
+	Host            string        `doc:"Hostname to listen on."`
+	Port            int           `doc:"Port to listen on." short:"p" default:"8888"`
+	ShutdownTimeout time.Duration `doc:"Time to wait for in-flight requests on shutdown." default:"10s"`
+}
+
+// Addr returns the `host:port` address to listen on.
+func (o ServerOptions) Addr() string {
+	return fmt.Sprintf("%s:%d", o.Host, o.Port)
+}
+
 type contextKey string
 
 var optionsKey contextKey = "huma/cli/options"
@@ -86,6 +114,10 @@ func (c *cli[Options]) Run() {
 			for _, i := range opt.path {
 				f = f.Field(i)
 			}
+			if opt.typ == durationType {
+				f.Set(reflect.ValueOf(c.cfg.GetDuration(opt.name)))
+				continue
+			}
 			switch opt.typ.Kind() {
 			case reflect.String:
 				f.Set(reflect.ValueOf(c.cfg.GetString(opt.name)))
@@ -123,6 +155,88 @@ func (c *cli[O]) OnStop(fn func()) {
 	c.stop = fn
 }
 
+// Serve sets the `OnStart` and `OnStop` hooks to run the HTTP server, whose
+// handler can be any router or `API.Adapter()`. On shutdown, in-flight
+// requests are given up to `shutdownTimeout` to complete before the server is
+// closed. If the server fails, e.g. because its address is already in use,
+// `onError` is called with the error and the CLI stops. If `onError` is nil,
+// the error is printed to stderr.
+func Serve(hooks Hooks, server *http.Server, shutdownTimeout time.Duration, onError func(error)) {
+	hooks.OnStart(func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			if onError == nil {
+				fmt.Fprintln(os.Stderr, err)
+				return
+			}
+			onError(err)
+		}
+	})
+
+	hooks.OnStop(func() {
+		ctx := context.Background()
+		if shutdownTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, shutdownTimeout)
+			defer cancel()
+		}
+		if err := server.Shutdown(ctx); err != nil {
+			// Deadline exceeded, so forcibly close remaining connections.
+			server.Close()
+		}
+	})
+}
+
+// NewOpenAPICommand creates an `openapi` command which prints the spec for the
+// API returned by `getAPI` to stdout without starting the server, e.g. for
+// generating clients in CI. The API is usually created in the `NewCLI`
+// callback, which runs before the command, so the spec is exactly what the
+// server would serve at `/openapi.json`. Use `--yaml` for YAML output and
+// `--downgrade` for OpenAPI 3.0.3.
+//
+//	var api huma.API
+//	cli := huma.NewCLI(func(hooks huma.Hooks, opts *Options) {
+//		api = humachi.New(router, config)
+//		// ...
+//	})
+//	cli.Root().AddCommand(huma.NewOpenAPICommand(func() huma.API { return api }))
+func NewOpenAPICommand(getAPI func() API) *cobra.Command {
+	var asYAML, downgrade bool
+	cmd := &cobra.Command{
+		Use:   "openapi",
+		Short: "Print the OpenAPI spec",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oapi := getAPI().OpenAPI()
+
+			var b []byte
+			var err error
+			switch {
+			case asYAML && downgrade:
+				b, err = oapi.DowngradeYAML()
+			case asYAML:
+				b, err = yaml.Marshal(oapi)
+			case downgrade:
+				b, err = oapi.Downgrade()
+			default:
+				b, err = json.MarshalIndent(oapi, "", "  ")
+			}
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			out.Write(b)
+			if len(b) > 0 && b[len(b)-1] != '\n' {
+				out.Write([]byte("\n"))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asYAML, "yaml", false, "Output YAML instead of JSON")
+	cmd.Flags().BoolVar(&downgrade, "downgrade", false, "Output OpenAPI 3.0.3 instead of 3.1")
+	return cmd
+}
+
 func (c *cli[O]) setupOptions(flags *pflag.FlagSet, t reflect.Type, path []int) {
 	var err error
 	for i := 0; i < t.NumField(); i++ {
@@ -150,6 +264,19 @@ func (c *cli[O]) setupOptions(flags *pflag.FlagSet, t reflect.Type, path []int)
 		}
 
 		c.optInfo = append(c.optInfo, option{name, field.Type, currentPath})
+		if field.Type == durationType {
+			var def time.Duration
+			if d := field.Tag.Get("default"); d != "" {
+				def, err = time.ParseDuration(d)
+				if err != nil {
+					panic(err)
+				}
+			}
+			c.cfg.SetDefault(name, def)
+			flags.DurationP(name, field.Tag.Get("short"), def, field.Tag.Get("doc"))
+			c.cfg.BindPFlag(name, flags.Lookup(name))
+			continue
+		}
 		switch field.Type.Kind() {
 		case reflect.String:
 			c.cfg.SetDefault(name, field.Tag.Get("default"))
@@ -214,6 +341,7 @@ func NewCLI[O any](onParsed func(Hooks, *O)) CLI {
 		// Handle graceful shutdown.
 		quit := make(chan os.Signal, 1)
 		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(quit)
 
 		select {
 		case <-done:
@@ -2,10 +2,14 @@ package huma
 
 import (
 	"bytes"
+	"context"
+	"net"
+	"net/http"
 	"syscall"
 	"testing"
 	"time"
 
+	"github.com/go-chi/chi"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 )
@@ -136,6 +140,98 @@ func TestCLIShutdown(t *testing.T) {
 	assert.True(t, started)
 }
 
+func TestCLIServe(t *testing.T) {
+	type Options struct {
+		ServerOptions
+	}
+
+	addr := make(chan string, 1)
+	cli := NewCLI(func(hooks Hooks, options *Options) {
+		assert.Equal(t, 5*time.Second, options.ShutdownTimeout)
+		server := &http.Server{
+			Addr: options.Addr(),
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}),
+			BaseContext: func(l net.Listener) context.Context {
+				// The port is picked by the OS, so read it back.
+				addr <- l.Addr().String()
+				return context.Background()
+			},
+		}
+		Serve(hooks, server, options.ShutdownTimeout, func(err error) {
+			t.Error(err)
+		})
+	})
+
+	go func() {
+		resp, err := http.Get("http://" + <-addr)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+			resp.Body.Close()
+		}
+		syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+	}()
+
+	cli.Root().SetArgs([]string{"--host", "127.0.0.1", "--port", "0", "--shutdown-timeout", "5s"})
+	cli.Run()
+}
+
+func TestCLIServeError(t *testing.T) {
+	type Options struct {
+		ServerOptions
+	}
+
+	// The address is already in use, so the server fails to start.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer l.Close()
+
+	var serveErr error
+	cli := NewCLI(func(hooks Hooks, options *Options) {
+		server := &http.Server{Addr: l.Addr().String()}
+		Serve(hooks, server, options.ShutdownTimeout, func(err error) {
+			serveErr = err
+		})
+	})
+
+	cli.Root().SetArgs([]string{})
+	cli.Run()
+	assert.Error(t, serveErr)
+}
+
+func TestCLIOpenAPICommand(t *testing.T) {
+	type Options struct{}
+
+	for _, item := range []struct {
+		args     []string
+		contains string
+	}{
+		{[]string{"openapi"}, `"openapi": "3.1.0"`},
+		{[]string{"openapi", "--yaml"}, "openapi: 3.1.0"},
+		{[]string{"openapi", "--downgrade"}, `"openapi":"3.0.3"`},
+		{[]string{"openapi", "--yaml", "--downgrade"}, "openapi: 3.0.3"},
+	} {
+		var api API
+		cli := NewCLI(func(hooks Hooks, options *Options) {
+			api = NewTestAdapter(chi.NewRouter(), DefaultConfig("Test API", "1.0.0"))
+			hooks.OnStart(func() {
+				t.Fatal("server should not start")
+			})
+		})
+		cli.Root().AddCommand(NewOpenAPICommand(func() API { return api }))
+
+		buf := bytes.NewBuffer(nil)
+		cli.Root().SetOut(buf)
+		cli.Root().SetArgs(item.args)
+		cli.Run()
+		assert.Contains(t, buf.String(), item.contains)
+		assert.Contains(t, buf.String(), "Test API")
+	}
+}
+
 func TestCLIBadType(t *testing.T) {
 	type Options struct {
 		Debug []struct{}
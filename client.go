@@ -0,0 +1,529 @@
+package huma
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client calls the operations of a Huma API using the same input & output
+// structs as their handlers, so params are sent and responses are decoded
+// exactly as the server expects. Use it with `Do` & `DoRaw`, or generate a
+// typed client for an API with `GenerateClient`.
+//
+//	client := huma.NewClient("https://api.example.com")
+//	out, err := huma.Do[GetThingInput, GetThingOutput](ctx, client, http.MethodGet, "/things/{id}", &GetThingInput{ID: "abc"})
+type Client struct {
+	// BaseURL is the URL of the API, like `https://api.example.com/v1`, which
+	// operation paths are appended to.
+	BaseURL string
+
+	// HTTPClient sends the requests. Defaults to `http.DefaultClient`.
+	HTTPClient *http.Client
+
+	// Header is sent with every request, e.g. for authentication. Headers set
+	// by the input struct take precedence.
+	Header http.Header
+}
+
+// NewClient returns a client for the API at the base URL.
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Header:  http.Header{},
+	}
+}
+
+// Do calls the operation at the method & path, like `/things/{id}`, with the
+// input and decodes the response into a new output struct. Input fields with
+// `path`, `query`, and `header` tags are sent as params using the same styles
+// & formats as the server parses, and the `Body` or `RawBody` field is sent
+// as the request body. Params are sent whenever they are set, including zero
+// values like `false` & `0`, so the server sees the same values as the input.
+// Unset `Optional` params, nil slices & maps, and empty strings, which the
+// server treats as missing, are not sent so the server's defaults apply.
+//
+// A `Status` output field is set to the response status code, header fields
+// are set from the response headers, and the `Body` field or the field for
+// the response's status, like `status:"409"`, is unmarshaled from the
+// response body. Other error responses are returned as a `StatusError` of the
+// type created by `NewError`. Use `DoRaw` for streamed responses.
+func Do[I, O any](ctx context.Context, c *Client, method, path string, input *I) (*O, error) {
+	var out O
+	v := reflect.ValueOf(&out).Elem()
+	if err := checkClientOutput(v.Type()); err != nil {
+		return nil, err
+	}
+	req, err := c.newRequest(ctx, method, path, reflect.ValueOf(input))
+	if err != nil {
+		return nil, err
+	}
+	resp, body, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeClientResponse(resp, body, v); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DoRaw calls the operation like `Do`, but returns the successful response
+// as-is, e.g. for operations which stream their response. The caller must
+// close the response body. Error responses are returned as a `StatusError`.
+func DoRaw[I any](ctx context.Context, c *Client, method, path string, input *I) (*http.Response, error) {
+	req, err := c.newRequest(ctx, method, path, reflect.ValueOf(input))
+	if err != nil {
+		return nil, err
+	}
+	return c.sendRaw(req)
+}
+
+// clientRequest is a request built from an input struct.
+type clientRequest struct {
+	*http.Request
+
+	// pathParams are the path param values by name.
+	pathParams map[string]string
+
+	// body is the JSON request body, if any.
+	body []byte
+}
+
+// newRequest builds the request for the input, which is a pointer to the
+// input struct and may be nil.
+func (c *Client) newRequest(ctx context.Context, method, path string, input reflect.Value) (*clientRequest, error) {
+	t := input.Type().Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("input must be a struct, got %s", t)
+	}
+	info := clientInputInfo(t)
+	v := reflect.New(t).Elem()
+	if !input.IsNil() {
+		v = input.Elem()
+	}
+
+	r := &clientRequest{pathParams: map[string]string{}}
+	query := url.Values{}
+	header := http.Header{}
+	var paramErr error
+	info.params.EveryField(v, func(f reflect.Value, p *paramFieldInfo) {
+		if paramErr != nil {
+			return
+		}
+		if optionalElem(f.Type()) != nil {
+			if !f.Field(1).Bool() {
+				return
+			}
+			f = f.Field(0)
+		} else if p.Loc != "path" && isNil(f) {
+			// Unset, so the server's default is used.
+			return
+		}
+
+		switch {
+		case p.Style == "deepObject":
+			addDeepObject(query, p.Name, f)
+		case p.Explode:
+			for i := 0; i < f.Len(); i++ {
+				query.Add(p.Name, formatParam(f.Index(i), p))
+			}
+		default:
+			value := formatParam(f, p)
+			if value == "" && p.Loc != "path" {
+				// The server treats empty values as missing.
+				return
+			}
+			switch p.Loc {
+			case "path":
+				placeholder := "{" + p.Name + "}"
+				if !strings.Contains(path, placeholder) {
+					paramErr = fmt.Errorf("path %s has no param %s", path, p.Name)
+					return
+				}
+				path = strings.ReplaceAll(path, placeholder, url.PathEscape(value))
+				r.pathParams[p.Name] = value
+			case "query":
+				query.Set(p.Name, value)
+			case "header":
+				header.Set(p.Name, value)
+			}
+		}
+	})
+	if paramErr != nil {
+		return nil, paramErr
+	}
+
+	var body io.Reader
+	contentType := ""
+	if info.bodyIndex != -1 {
+		f := v.Field(info.bodyIndex)
+		if (f.Kind() != reflect.Pointer && f.Kind() != reflect.Interface) || !f.IsNil() {
+			if b, ok := f.Interface().([]byte); ok {
+				body = bytes.NewReader(b)
+			} else {
+				b, err := json.Marshal(f.Interface())
+				if err != nil {
+					return nil, fmt.Errorf("unable to encode request body: %w", err)
+				}
+				body = bytes.NewReader(b)
+				r.body = b
+			}
+			contentType = info.contentType
+		}
+	}
+	if body == nil && info.rawBodyIndex != -1 {
+		switch raw := v.Field(info.rawBodyIndex).Interface().(type) {
+		case []byte:
+			if raw != nil {
+				body = bytes.NewReader(raw)
+			}
+		case io.Reader:
+			body = raw
+		}
+		if body != nil {
+			contentType = "application/octet-stream"
+		}
+	}
+
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.Header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	r.Request = req
+	return r, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// sendRaw sends the request, returning error responses as a `StatusError`.
+func (c *Client) sendRaw(req *clientRequest) (*http.Response, error) {
+	resp, err := c.httpClient().Do(req.Request)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, decodeClientError(resp.StatusCode, body)
+	}
+	return resp, nil
+}
+
+// send sends the request and reads the whole response body.
+func (c *Client) send(req *clientRequest) (*http.Response, []byte, error) {
+	resp, err := c.httpClient().Do(req.Request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
+}
+
+// checkClientOutput returns an error if responses can't be decoded into the
+// output type.
+func checkClientOutput(t reflect.Type) error {
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("output must be a struct, got %s", t)
+	}
+	if clientOutputInfo(t).bodyFunc {
+		return fmt.Errorf("output %s has a streamed body, use DoRaw instead", t)
+	}
+	return nil
+}
+
+// decodeClientResponse decodes the response into the output struct `v`, or
+// returns the error for error responses.
+func decodeClientResponse(resp *http.Response, body []byte, v reflect.Value) error {
+	info := clientOutputInfo(v.Type())
+	bodyIndex := info.bodyIndex
+	for _, alt := range info.altBodies {
+		if alt.status == resp.StatusCode {
+			bodyIndex = alt.index
+		}
+	}
+	if bodyIndex == info.bodyIndex && resp.StatusCode >= 400 {
+		return decodeClientError(resp.StatusCode, body)
+	}
+
+	if info.statusIndex != -1 {
+		v.Field(info.statusIndex).SetInt(int64(resp.StatusCode))
+	}
+	var headerErr error
+	info.headers.Every(v, func(f reflect.Value, h *headerInfo) {
+		if value := resp.Header.Get(h.Name); value != "" && headerErr == nil {
+			if err := setClientHeader(f, value, h.TimeFormat); err != nil {
+				headerErr = fmt.Errorf("unable to decode response header %s: %w", h.Name, err)
+			}
+		}
+	})
+	if headerErr != nil {
+		return headerErr
+	}
+
+	if bodyIndex != -1 && len(body) > 0 {
+		f := v.Field(bodyIndex)
+		if b, ok := f.Addr().Interface().(*[]byte); ok {
+			*b = body
+		} else if err := json.Unmarshal(body, f.Addr().Interface()); err != nil {
+			return fmt.Errorf("unable to decode response body: %w", err)
+		}
+	}
+	return nil
+}
+
+// clientInput describes how to send an input struct.
+type clientInput struct {
+	params       *findResult[*paramFieldInfo]
+	bodyIndex    int
+	rawBodyIndex int
+	contentType  string
+}
+
+var clientInputs sync.Map
+
+// clientInputInfo returns how to send the input struct type, finding its
+// params in the same way as `Register` so both sides always agree.
+func clientInputInfo(t reflect.Type) *clientInput {
+	if info, ok := clientInputs.Load(t); ok {
+		return info.(*clientInput)
+	}
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	info := &clientInput{
+		params:       findParams(registry, &Operation{}, t),
+		bodyIndex:    -1,
+		rawBodyIndex: -1,
+	}
+	if f, ok := t.FieldByName("Body"); ok {
+		info.bodyIndex = f.Index[0]
+		info.contentType = "application/json"
+		if ctf, ok := reflect.New(f.Type).Interface().(ContentTypeFilter); ok {
+			info.contentType = ctf.ContentType(info.contentType)
+		}
+	}
+	if f, ok := t.FieldByName("RawBody"); ok {
+		info.rawBodyIndex = f.Index[0]
+	}
+	clientInputs.Store(t, info)
+	return info
+}
+
+// clientOutput describes how to decode a response into an output struct.
+type clientOutput struct {
+	headers     *findResult[*headerInfo]
+	statusIndex int
+	bodyIndex   int
+	bodyFunc    bool
+	altBodies   []statusBody
+}
+
+var clientOutputs sync.Map
+
+// clientOutputInfo returns how to decode a response into the output struct
+// type, finding its fields in the same way as `Register`.
+func clientOutputInfo(t reflect.Type) *clientOutput {
+	if info, ok := clientOutputs.Load(t); ok {
+		return info.(*clientOutput)
+	}
+	info := &clientOutput{
+		statusIndex: -1,
+		bodyIndex:   -1,
+		altBodies:   findStatusBodies(t),
+	}
+	altNames := make([]string, len(info.altBodies))
+	for i, alt := range info.altBodies {
+		altNames[i] = t.Field(alt.index).Name
+	}
+	info.headers = findHeaders(t, altNames...)
+	if f, ok := t.FieldByName("Status"); ok && f.Type.Kind() == reflect.Int {
+		info.statusIndex = f.Index[0]
+	}
+	if f, ok := t.FieldByName("Body"); ok {
+		info.bodyIndex = f.Index[0]
+		info.bodyFunc = f.Type.Kind() == reflect.Func
+	}
+	clientOutputs.Store(t, info)
+	return info
+}
+
+// formatParam formats a single param value as the server parses it.
+func formatParam(f reflect.Value, p *paramFieldInfo) string {
+	t := f.Type()
+	if t == timeType {
+		timeFormat := p.TimeFormat
+		if timeFormat == "" {
+			timeFormat = time.RFC3339Nano
+			if p.Loc == "header" {
+				timeFormat = http.TimeFormat
+			}
+		}
+		return f.Interface().(time.Time).Format(timeFormat)
+	}
+	if t == durationType && p.Schema != nil && p.Schema.Format == "duration" {
+		return FormatDuration(time.Duration(f.Int()))
+	}
+	if m, ok := f.Interface().(encoding.TextMarshaler); ok {
+		b, _ := m.MarshalText()
+		return string(b)
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		return f.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(f.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(f.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(f.Float(), 'f', -1, t.Bits())
+	case reflect.Bool:
+		return strconv.FormatBool(f.Bool())
+	case reflect.Slice:
+		items := make([]string, f.Len())
+		for i := range items {
+			items[i] = formatParam(f.Index(i), p)
+		}
+		return strings.Join(items, listDelimiters[p.Style])
+	}
+	return fmt.Sprintf("%v", f.Interface())
+}
+
+// addDeepObject adds the properties of a struct or map `deepObject` param,
+// like `?filter[name]=foo`.
+func addDeepObject(query url.Values, name string, f reflect.Value) {
+	if f.Kind() == reflect.Map {
+		iter := f.MapRange()
+		for iter.Next() {
+			query.Set(name+"["+iter.Key().String()+"]", formatParam(iter.Value(), &paramFieldInfo{Loc: "query"}))
+		}
+		return
+	}
+	t := f.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || isNil(f.Field(i)) {
+			continue
+		}
+		key := sf.Name
+		if j := sf.Tag.Get("json"); j != "" {
+			key = strings.Split(j, ",")[0]
+		}
+		if key == "-" {
+			continue
+		}
+		if value := formatParam(f.Field(i), &paramFieldInfo{Loc: "query", TimeFormat: sf.Tag.Get("timeFormat")}); value != "" {
+			query.Set(name+"["+key+"]", value)
+		}
+	}
+}
+
+// isNil returns whether the value is a nil pointer, interface, slice or map.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	}
+	return false
+}
+
+// setClientHeader parses a response header value into the output field.
+func setClientHeader(f reflect.Value, value, timeFormat string) error {
+	if f.Type() == timeType {
+		t, err := time.Parse(timeFormat, value)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if u, ok := f.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		f.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		f.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(v)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(v)
+	default:
+		return fmt.Errorf("unsupported header type %s", f.Type())
+	}
+	return nil
+}
+
+// decodeClientError decodes an error response into the error type created by
+// `NewError`, falling back to an error with the body as its message.
+func decodeClientError(status int, body []byte) error {
+	t := reflect.TypeOf(NewError(status, ""))
+	if t.Kind() == reflect.Pointer {
+		v := reflect.New(t.Elem())
+		if json.Unmarshal(body, v.Interface()) == nil {
+			if se, ok := v.Interface().(StatusError); ok && se.GetStatus() == status {
+				return se
+			}
+		}
+	}
+	msg := strings.TrimSpace(string(body))
+	if msg == "" {
+		msg = http.StatusText(status)
+	}
+	return NewError(status, msg)
+}
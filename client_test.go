@@ -0,0 +1,266 @@
+package huma
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type ClientThing struct {
+	ID    string   `json:"id"`
+	Name  string   `json:"name" maxLength:"10"`
+	Tags  []string `json:"tags,omitempty"`
+	Count int      `json:"count,omitempty"`
+}
+
+type ClientFilter struct {
+	Name string `json:"name,omitempty"`
+	Min  int    `json:"min,omitempty"`
+}
+
+type ClientGetThingInput struct {
+	ID      string            `path:"id"`
+	Verbose bool              `query:"verbose"`
+	Limit   Optional[int]     `query:"limit"`
+	IDs     []string          `query:"ids"`
+	Colors  []string          `query:"color,explode"`
+	Filter  ClientFilter      `query:"filter,deepObject"`
+	Since   time.Time         `query:"since"`
+	Every   time.Duration     `query:"every" format:"duration"`
+	Tenant  string            `header:"X-Tenant"`
+	Langs   []string          `header:"X-Langs"`
+	Labels  map[string]string `query:"labels,deepObject"`
+}
+
+type ClientGetThingOutput struct {
+	Status   int
+	ETag     string    `header:"ETag"`
+	Modified time.Time `header:"Last-Modified"`
+	Body     ClientThing
+}
+
+type ClientCreateThingInput struct {
+	Body ClientThing
+}
+
+type ClientCreateThingOutput struct {
+	Location string `header:"Location"`
+	Body     ClientThing
+	Conflict *ClientThing `status:"409"`
+}
+
+func newClientTestAPI(t *testing.T) (API, *httptest.Server) {
+	r := chi.NewRouter()
+	api := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func TestClient(t *testing.T) {
+	api, server := newClientTestAPI(t)
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var received *ClientGetThingInput
+	Register(api, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *ClientGetThingInput) (*ClientGetThingOutput, error) {
+		received = input
+		if input.ID == "missing" {
+			return nil, Error404NotFound("thing not found")
+		}
+		return &ClientGetThingOutput{
+			ETag:     "abc",
+			Modified: modified,
+			Body:     ClientThing{ID: input.ID, Name: "Thing"},
+		}, nil
+	})
+
+	Register(api, Operation{
+		OperationID:   "create-thing",
+		Method:        http.MethodPost,
+		Path:          "/things",
+		DefaultStatus: http.StatusCreated,
+	}, func(ctx context.Context, input *ClientCreateThingInput) (*ClientCreateThingOutput, error) {
+		if input.Body.ID == "dupe" {
+			return &ClientCreateThingOutput{Conflict: &ClientThing{ID: "dupe", Name: "Existing"}}, nil
+		}
+		return &ClientCreateThingOutput{Location: "/things/" + input.Body.ID, Body: input.Body}, nil
+	})
+
+	client := NewClient(server.URL)
+	client.Header.Set("X-Tenant", "default")
+	ctx := context.Background()
+
+	since := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	out, err := Do[ClientGetThingInput, ClientGetThingOutput](ctx, client, http.MethodGet, "/things/{id}", &ClientGetThingInput{
+		ID:      "a b",
+		Verbose: true,
+		Limit:   NewOptional(0),
+		IDs:     []string{"1", "2"},
+		Colors:  []string{"red", "blue"},
+		Filter:  ClientFilter{Name: "foo", Min: 3},
+		Since:   since,
+		Every:   90 * time.Minute,
+		Tenant:  "acme",
+		Langs:   []string{"en", "de"},
+		Labels:  map[string]string{"env": "prod"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, out.Status)
+	assert.Equal(t, "abc", out.ETag)
+	assert.Equal(t, modified, out.Modified.UTC())
+	assert.Equal(t, ClientThing{ID: "a b", Name: "Thing"}, out.Body)
+
+	// The server parsed everything the client sent.
+	assert.Equal(t, "a b", received.ID)
+	assert.True(t, received.Verbose)
+	assert.Equal(t, NewOptional(0), received.Limit)
+	assert.Equal(t, []string{"1", "2"}, received.IDs)
+	assert.Equal(t, []string{"red", "blue"}, received.Colors)
+	assert.Equal(t, ClientFilter{Name: "foo", Min: 3}, received.Filter)
+	assert.True(t, since.Equal(received.Since))
+	assert.Equal(t, 90*time.Minute, received.Every)
+	assert.Equal(t, "acme", received.Tenant)
+	assert.Equal(t, []string{"en", "de"}, received.Langs)
+	assert.Equal(t, map[string]string{"env": "prod"}, received.Labels)
+
+	// Unset params are not sent, and the client's headers are used.
+	_, err = Do[ClientGetThingInput, ClientGetThingOutput](ctx, client, http.MethodGet, "/things/{id}", &ClientGetThingInput{ID: "b"})
+	assert.NoError(t, err)
+	assert.False(t, received.Limit.Set)
+	assert.Equal(t, "default", received.Tenant)
+
+	// Errors are decoded into the error model.
+	_, err = Do[ClientGetThingInput, ClientGetThingOutput](ctx, client, http.MethodGet, "/things/{id}", &ClientGetThingInput{ID: "missing"})
+	var model *ErrorModel
+	assert.ErrorAs(t, err, &model)
+	assert.Equal(t, http.StatusNotFound, model.Status)
+	assert.Equal(t, "thing not found", model.Detail)
+
+	created, err := Do[ClientCreateThingInput, ClientCreateThingOutput](ctx, client, http.MethodPost, "/things", &ClientCreateThingInput{
+		Body: ClientThing{ID: "c", Name: "New"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "/things/c", created.Location)
+	assert.Equal(t, ClientThing{ID: "c", Name: "New"}, created.Body)
+	assert.Nil(t, created.Conflict)
+
+	// Bodies for other statuses are decoded into their fields.
+	created, err = Do[ClientCreateThingInput, ClientCreateThingOutput](ctx, client, http.MethodPost, "/things", &ClientCreateThingInput{
+		Body: ClientThing{ID: "dupe", Name: "New"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &ClientThing{ID: "dupe", Name: "Existing"}, created.Conflict)
+
+	// Validation errors from the server are returned with their details.
+	_, err = Do[ClientCreateThingInput, ClientCreateThingOutput](ctx, client, http.MethodPost, "/things", &ClientCreateThingInput{
+		Body: ClientThing{ID: "d", Name: "This name is far too long"},
+	})
+	assert.ErrorAs(t, err, &model)
+	assert.Equal(t, http.StatusUnprocessableEntity, model.Status)
+	assert.Len(t, model.Errors, 1)
+	assert.Equal(t, "body.name", model.Errors[0].Location)
+}
+
+func TestClientZeroParams(t *testing.T) {
+	api, server := newClientTestAPI(t)
+
+	type Input struct {
+		Enabled bool   `query:"enabled" default:"true"`
+		Count   int    `query:"count" default:"10"`
+		Level   int    `header:"X-Level" default:"3"`
+		Name    string `query:"name" default:"anonymous"`
+	}
+
+	var received *Input
+	Register(api, Operation{
+		OperationID: "zero",
+		Method:      http.MethodGet,
+		Path:        "/zero",
+	}, func(ctx context.Context, input *Input) (*struct{}, error) {
+		received = input
+		return nil, nil
+	})
+
+	// Zero values are sent rather than replaced by the server's defaults,
+	// except empty strings which the server treats as missing.
+	_, err := Do[Input, struct{}](context.Background(), NewClient(server.URL), http.MethodGet, "/zero", &Input{})
+	assert.NoError(t, err)
+	assert.Equal(t, &Input{Enabled: false, Count: 0, Level: 0, Name: "anonymous"}, received)
+}
+
+func TestClientRaw(t *testing.T) {
+	api, server := newClientTestAPI(t)
+
+	Register(api, Operation{
+		OperationID: "stream",
+		Method:      http.MethodGet,
+		Path:        "/stream",
+	}, func(ctx context.Context, input *struct {
+		Fail bool `query:"fail"`
+	}) (*StreamResponse, error) {
+		if input.Fail {
+			return nil, Error503ServiceUnavailable("try again later")
+		}
+		return &StreamResponse{Body: func(ctx Context) {
+			ctx.SetHeader("Content-Type", "text/plain")
+			ctx.BodyWriter().Write([]byte("hello"))
+		}}, nil
+	})
+
+	Register(api, Operation{
+		OperationID: "upload",
+		Method:      http.MethodPut,
+		Path:        "/upload",
+	}, func(ctx context.Context, input *struct {
+		RawBody []byte
+	}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: string(input.RawBody)}, nil
+	})
+
+	client := NewClient(server.URL + "/")
+	ctx := context.Background()
+
+	_, err := Do[struct{}, StreamResponse](ctx, client, http.MethodGet, "/stream", nil)
+	assert.ErrorContains(t, err, "use DoRaw")
+
+	resp, err := DoRaw[struct{}](ctx, client, http.MethodGet, "/stream", nil)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "hello", string(b))
+
+	_, err = DoRaw(ctx, client, http.MethodGet, "/stream", &struct {
+		Fail bool `query:"fail"`
+	}{Fail: true})
+	var se StatusError
+	assert.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusServiceUnavailable, se.GetStatus())
+
+	out, err := Do[struct{ RawBody []byte }, struct{ Body string }](ctx, client, http.MethodPut, "/upload", &struct{ RawBody []byte }{RawBody: []byte("raw data")})
+	assert.NoError(t, err)
+	assert.Equal(t, "raw data", out.Body)
+}
+
+func TestClientErrorFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := Do[struct{}, struct{}](context.Background(), NewClient(server.URL), http.MethodGet, "/", nil)
+	var se StatusError
+	assert.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusBadGateway, se.GetStatus())
+	assert.True(t, strings.Contains(err.Error(), "upstream unavailable"))
+}
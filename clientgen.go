@@ -0,0 +1,332 @@
+package huma
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/casing"
+)
+
+// humaPkgPath is the import path of this package.
+var humaPkgPath = reflect.TypeOf(Operation{}).PkgPath()
+
+// rxQualifiedName matches the package paths within type names, like
+// `github.com/example/things.Thing` in `Page[github.com/example/things.Thing]`.
+var rxQualifiedName = regexp.MustCompile(`((?:[\w.-]+/)*[\w-]+)\.(\w+)`)
+
+var rxMajorVersion = regexp.MustCompile(`^v\d+$`)
+
+// GenerateClient generates the Go source of a typed client for the API's
+// operations, in a package with the given name. Each operation is a method
+// named after its operation ID which takes the operation's input struct and
+// returns its output struct, so the client always uses the same types as the
+// handlers. Named input & output types are imported from their packages,
+// while anonymous structs are declared in the generated code. Hidden
+// operations are not included. See `Client` for how requests are sent.
+//
+//	src, err := huma.GenerateClient(api, "thingsclient")
+//	os.WriteFile("thingsclient/client.go", src, 0o644)
+//
+// Input & output types must be exported from an importable package, so an
+// error is returned for unexported types or those in `package main`.
+func GenerateClient(api API, pkg string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+
+	g := &clientGenerator{
+		imports: map[string]string{"context": "context", humaPkgPath: "huma"},
+		aliases: map[string]string{"context": "context", "huma": humaPkgPath},
+	}
+	oapi := api.OpenAPI()
+
+	paths := make([]string, 0, len(oapi.Paths))
+	for p := range oapi.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	methods := &bytes.Buffer{}
+	types := &bytes.Buffer{}
+	names := map[string]string{"NewClient": "", "Client": ""}
+	for _, p := range paths {
+		for _, op := range oapi.Paths[p].operations() {
+			if op.inputType == nil || op.outputType == nil {
+				// Not registered via Huma, e.g. documented manually.
+				continue
+			}
+			name := casing.Camel(op.OperationID)
+			if !token.IsIdentifier(name) || !token.IsExported(name) {
+				return nil, fmt.Errorf("operation %s: cannot generate a method name from the operation ID", op.OperationID)
+			}
+			if existing, ok := names[name]; ok {
+				return nil, fmt.Errorf("operation %s: method name %s is already used by %s", op.OperationID, name, existing)
+			}
+			names[name] = op.OperationID
+
+			input, err := g.structType(types, op.inputType, name, "Input")
+			if err != nil {
+				return nil, fmt.Errorf("operation %s: %w", op.OperationID, err)
+			}
+			output, err := g.structType(types, op.outputType, name, "Output")
+			if err != nil {
+				return nil, fmt.Errorf("operation %s: %w", op.OperationID, err)
+			}
+			for _, typeName := range []string{name + "Input", name + "Output"} {
+				if _, ok := names[typeName]; !ok {
+					names[typeName] = op.OperationID
+				}
+			}
+
+			fmt.Fprintf(methods, "\n// %s calls %s %s.\n", name, op.Method, op.Path)
+			if op.Summary != "" {
+				fmt.Fprintf(methods, "//\n// %s\n", strings.ReplaceAll(op.Summary, "\n", " "))
+			}
+			if op.Deprecated {
+				fmt.Fprintf(methods, "//\n// Deprecated: the operation is deprecated.\n")
+			}
+			if f, ok := op.outputType.FieldByName("Body"); ok && f.Type.Kind() == reflect.Func {
+				fmt.Fprintf(methods, "//\n// The streamed response is returned as-is and its body must be closed.\n")
+				http, _ := g.importAlias("net/http")
+				fmt.Fprintf(methods, "func (c *Client) %s(ctx context.Context, input *%s) (*%s.Response, error) {\n", name, input, http)
+				fmt.Fprintf(methods, "\treturn huma.DoRaw[%s](ctx, c.Client, %q, %q, input)\n}\n", input, op.Method, op.Path)
+			} else {
+				fmt.Fprintf(methods, "func (c *Client) %s(ctx context.Context, input *%s) (*%s, error) {\n", name, input, output)
+				fmt.Fprintf(methods, "\treturn huma.Do[%s, %s](ctx, c.Client, %q, %q, input)\n}\n", input, output, op.Method, op.Path)
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by huma.GenerateClient. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "// Package %s is a client for %s.\n", pkg, oapi.Info.Title)
+	fmt.Fprintf(buf, "package %s\n\nimport (\n", pkg)
+	importPaths := make([]string, 0, len(g.imports))
+	for p := range g.imports {
+		importPaths = append(importPaths, p)
+	}
+	sort.Slice(importPaths, func(i, j int) bool {
+		// Standard library packages come first, like `goimports`.
+		if isStdPkg(importPaths[i]) != isStdPkg(importPaths[j]) {
+			return isStdPkg(importPaths[i])
+		}
+		return importPaths[i] < importPaths[j]
+	})
+	for i, p := range importPaths {
+		if i > 0 && isStdPkg(importPaths[i-1]) && !isStdPkg(p) {
+			buf.WriteString("\n")
+		}
+		if alias := g.imports[p]; alias != path.Base(p) {
+			fmt.Fprintf(buf, "\t%s %q\n", alias, p)
+		} else {
+			fmt.Fprintf(buf, "\t%q\n", p)
+		}
+	}
+	fmt.Fprintf(buf, ")\n\n")
+	fmt.Fprintf(buf, "// Client calls the operations of %s.\ntype Client struct {\n\t*huma.Client\n}\n\n", oapi.Info.Title)
+	fmt.Fprintf(buf, "// NewClient returns a client for the API at the base URL.\nfunc NewClient(baseURL string) *Client {\n\treturn &Client{huma.NewClient(baseURL)}\n}\n")
+	buf.Write(types.Bytes())
+	buf.Write(methods.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated client: %w", err)
+	}
+	return src, nil
+}
+
+// clientGenerator writes Go types, tracking the packages they import.
+type clientGenerator struct {
+	// imports maps import paths to their aliases.
+	imports map[string]string
+
+	// aliases maps aliases to their import paths.
+	aliases map[string]string
+}
+
+// structType returns the Go type of an input or output struct. Anonymous
+// structs are declared as a new type named after the operation's method, like
+// `GetThingInput`.
+func (g *clientGenerator) structType(types *bytes.Buffer, t reflect.Type, method, kind string) (string, error) {
+	if t.Name() != "" {
+		return g.typeName(t)
+	}
+	def, err := g.typeExpr(t)
+	if err != nil {
+		return "", err
+	}
+	name := method + kind
+	fmt.Fprintf(types, "\n// %s is the %s of %s.\ntype %s %s\n", name, strings.ToLower(kind), method, name, def)
+	return name, nil
+}
+
+// typeExpr returns the Go source for the type.
+func (g *clientGenerator) typeExpr(t reflect.Type) (string, error) {
+	if t.Name() != "" {
+		return g.typeName(t)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := g.typeExpr(t.Elem())
+		return "*" + elem, err
+	case reflect.Slice:
+		elem, err := g.typeExpr(t.Elem())
+		return "[]" + elem, err
+	case reflect.Array:
+		elem, err := g.typeExpr(t.Elem())
+		return "[" + strconv.Itoa(t.Len()) + "]" + elem, err
+	case reflect.Map:
+		key, err := g.typeExpr(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := g.typeExpr(t.Elem())
+		return "map[" + key + "]" + elem, err
+	case reflect.Interface:
+		if t.NumMethod() > 0 {
+			return "", fmt.Errorf("unsupported interface type %s", t)
+		}
+		return "any", nil
+	case reflect.Func:
+		params := make([]string, t.NumIn())
+		for i := range params {
+			p, err := g.typeExpr(t.In(i))
+			if err != nil {
+				return "", err
+			}
+			params[i] = p
+		}
+		results := make([]string, t.NumOut())
+		for i := range results {
+			r, err := g.typeExpr(t.Out(i))
+			if err != nil {
+				return "", err
+			}
+			results[i] = r
+		}
+		fn := "func(" + strings.Join(params, ", ") + ")"
+		switch len(results) {
+		case 0:
+		case 1:
+			fn += " " + results[0]
+		default:
+			fn += " (" + strings.Join(results, ", ") + ")"
+		}
+		return fn, nil
+	case reflect.Struct:
+		fields := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("inject") != "" {
+				// Neither is sent by clients.
+				continue
+			}
+			ft, err := g.typeExpr(f.Type)
+			if err != nil {
+				return "", fmt.Errorf("field %s: %w", f.Name, err)
+			}
+			field := f.Name + " " + ft
+			if f.Anonymous {
+				field = ft
+			}
+			if tag := string(f.Tag); strings.Contains(tag, "`") {
+				field += " " + strconv.Quote(tag)
+			} else if tag != "" {
+				field += " `" + tag + "`"
+			}
+			fields = append(fields, field)
+		}
+		if len(fields) == 0 {
+			return "struct{}", nil
+		}
+		return "struct {\n" + strings.Join(fields, "\n") + "\n}", nil
+	}
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+// typeName returns the qualified name of a named type, importing its package
+// and those of any type arguments.
+func (g *clientGenerator) typeName(t reflect.Type) (string, error) {
+	name := t.Name()
+	if t.PkgPath() == "" {
+		// Predeclared, like `string` or `error`.
+		return name, nil
+	}
+	if strings.Contains(name, "·") {
+		return "", fmt.Errorf("type %s is declared within a function", name)
+	}
+	base, args, generic := strings.Cut(name, "[")
+	if !token.IsExported(base) {
+		return "", fmt.Errorf("type %s is not exported", t)
+	}
+	alias, err := g.importAlias(t.PkgPath())
+	if err != nil {
+		return "", err
+	}
+	if !generic {
+		return alias + "." + name, nil
+	}
+
+	// Type arguments use their package paths, like
+	// `Page[github.com/example/things.Thing]`.
+	for _, m := range rxQualifiedName.FindAllStringSubmatch(args, -1) {
+		if !token.IsExported(m[2]) {
+			return "", fmt.Errorf("type %s in %s is not exported", m[0], name)
+		}
+		if _, err := g.importAlias(m[1]); err != nil {
+			return "", err
+		}
+	}
+	args = rxQualifiedName.ReplaceAllStringFunc(args, func(s string) string {
+		m := rxQualifiedName.FindStringSubmatch(s)
+		return g.imports[m[1]] + "." + m[2]
+	})
+	return alias + "." + base + "[" + args, nil
+}
+
+// importAlias imports the package and returns its alias, which is the last
+// element of its path (skipping major versions like `v2`), made unique.
+func (g *clientGenerator) importAlias(pkgPath string) (string, error) {
+	if alias, ok := g.imports[pkgPath]; ok {
+		return alias, nil
+	}
+	if pkgPath == "main" {
+		return "", fmt.Errorf("package main cannot be imported")
+	}
+	parts := strings.Split(pkgPath, "/")
+	base := parts[len(parts)-1]
+	if rxMajorVersion.MatchString(base) && len(parts) > 1 {
+		base = parts[len(parts)-2]
+	}
+	base = strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return -1
+	}, base)
+	if !token.IsIdentifier(base) {
+		base = "pkg" + base
+	}
+	alias := base
+	for i := 2; g.aliases[alias] != ""; i++ {
+		alias = base + strconv.Itoa(i)
+	}
+	g.imports[pkgPath] = alias
+	g.aliases[alias] = pkgPath
+	return alias, nil
+}
+
+// isStdPkg returns whether the import path is in the standard library, which
+// has no domain name in its first element.
+func isStdPkg(pkgPath string) bool {
+	return !strings.Contains(strings.Split(pkgPath, "/")[0], ".")
+}
@@ -0,0 +1,137 @@
+package huma
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateClient(t *testing.T) {
+	app := NewTestAdapter(chi.NewRouter(), DefaultConfig("Test API", "1.0.0"))
+
+	Register(app, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+		Summary:     "Get a thing",
+	}, func(ctx context.Context, input *ClientGetThingInput) (*ClientGetThingOutput, error) {
+		return nil, nil
+	})
+	Register(app, Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+		Deprecated:  true,
+	}, func(ctx context.Context, input *struct {
+		Cursor Optional[string] `query:"cursor" doc:"Page cursor"`
+		Tags   []string         `query:"tags"`
+	}) (*struct {
+		Link string `header:"Link"`
+		Body []ClientThing
+	}, error) {
+		return nil, nil
+	})
+	Register(app, Operation{
+		OperationID: "update-thing",
+		Method:      http.MethodPatch,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID   string `path:"id"`
+		Body map[string]Optional[ClientThing]
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+	Register(app, Operation{
+		OperationID: "stream-things",
+		Method:      http.MethodGet,
+		Path:        "/things/stream",
+	}, func(ctx context.Context, input *struct{}) (*StreamResponse, error) {
+		return nil, nil
+	})
+	Register(app, Operation{
+		OperationID: "hidden",
+		Method:      http.MethodGet,
+		Path:        "/hidden",
+		Hidden:      true,
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	src, err := GenerateClient(app, "thingsclient")
+	assert.NoError(t, err)
+
+	code := string(src)
+	_, err = parser.ParseFile(token.NewFileSet(), "client.go", src, parser.AllErrors)
+	assert.NoError(t, err, code)
+
+	assert.Contains(t, code, "// Code generated by huma.GenerateClient. DO NOT EDIT.")
+	assert.Contains(t, code, "package thingsclient")
+	assert.Contains(t, code, `huma "github.com/danielgtaylor/huma/v2"`)
+	assert.Contains(t, code, "func NewClient(baseURL string) *Client {")
+
+	assert.Contains(t, code, "// GetThing calls GET /things/{id}.\n//\n// Get a thing\n")
+	assert.Contains(t, code, "func (c *Client) GetThing(ctx context.Context, input *huma.ClientGetThingInput) (*huma.ClientGetThingOutput, error) {")
+	assert.Contains(t, code, `return huma.Do[huma.ClientGetThingInput, huma.ClientGetThingOutput](ctx, c.Client, "GET", "/things/{id}", input)`)
+
+	// Anonymous structs are declared with the same fields & tags.
+	assert.Contains(t, code, "type ListThingsInput struct {")
+	assert.Contains(t, code, "Cursor huma.Optional[string] `query:\"cursor\" doc:\"Page cursor\"`")
+	assert.Contains(t, code, "Body []huma.ClientThing")
+	assert.Contains(t, code, "// Deprecated: the operation is deprecated.")
+	assert.Contains(t, code, "func (c *Client) ListThings(ctx context.Context, input *ListThingsInput) (*ListThingsOutput, error) {")
+
+	// Type arguments are qualified by their package alias.
+	assert.Contains(t, code, "Body map[string]huma.Optional[huma.ClientThing]")
+
+	// Streamed responses are returned as-is.
+	assert.Contains(t, code, `"net/http"`)
+	assert.Contains(t, code, "func (c *Client) StreamThings(ctx context.Context, input *StreamThingsInput) (*http.Response, error) {")
+	assert.Contains(t, code, `return huma.DoRaw[StreamThingsInput](ctx, c.Client, "GET", "/things/stream", input)`)
+
+	assert.NotContains(t, code, "Hidden")
+}
+
+func TestGenerateClientErrors(t *testing.T) {
+	_, err := GenerateClient(NewTestAdapter(chi.NewRouter(), DefaultConfig("Test API", "1.0.0")), "not-valid")
+	assert.ErrorContains(t, err, "invalid package name")
+
+	type localOutput struct {
+		Body string
+	}
+	app := NewTestAdapter(chi.NewRouter(), DefaultConfig("Test API", "1.0.0"))
+	Register(app, Operation{
+		OperationID: "local",
+		Method:      http.MethodGet,
+		Path:        "/local",
+	}, func(ctx context.Context, input *struct{}) (*localOutput, error) {
+		return nil, nil
+	})
+	_, err = GenerateClient(app, "client")
+	assert.ErrorContains(t, err, "operation local: type huma.localOutput is not exported")
+}
+
+func TestClientGeneratorImports(t *testing.T) {
+	g := &clientGenerator{imports: map[string]string{}, aliases: map[string]string{}}
+	for _, item := range []struct {
+		path  string
+		alias string
+	}{
+		{"github.com/example/things", "things"},
+		{"github.com/example/api/v2", "api"},
+		{"github.com/other/things", "things2"},
+		{"github.com/example/go-widgets", "gowidgets"},
+		{"github.com/example/things", "things"},
+	} {
+		alias, err := g.importAlias(item.path)
+		assert.NoError(t, err)
+		assert.Equal(t, item.alias, alias, item.path)
+	}
+
+	_, err := g.importAlias("main")
+	assert.Error(t, err)
+}
@@ -0,0 +1,249 @@
+package huma
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2/negotiation"
+)
+
+// Encoder is a streaming compression writer like `*gzip.Writer`. Encoders are
+// pooled and reused, so `Reset` is called before each use.
+type Encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Compressor describes a supported response compression encoding.
+type Compressor struct {
+	// Encoding is the name used in the `Accept-Encoding` and
+	// `Content-Encoding` headers, e.g. `gzip` or `br`.
+	Encoding string
+
+	// New creates a new encoder instance.
+	New func() Encoder
+}
+
+// DefaultCompressors supports gzip. Importing the `brotli` package adds
+// Brotli, which is preferred when the client accepts both equally.
+var DefaultCompressors = []Compressor{
+	{"gzip", func() Encoder { return gzip.NewWriter(nil) }},
+}
+
+// DefaultCompressibleTypes are the response content types which are
+// compressed by default. Binary formats like CBOR or images are already
+// compact and are not included.
+var DefaultCompressibleTypes = []string{
+	"application/json",
+	"+json",
+	"application/yaml",
+	"+yaml",
+	"application/xml",
+	"+xml",
+	"text/plain",
+	"text/html",
+	"text/csv",
+	"text/css",
+	"text/javascript",
+}
+
+// CompressionConfig controls opt-in response compression, negotiated with the
+// client via the `Accept-Encoding` header. It works with any adapter by
+// wrapping the `huma.Context` body writer. See `Config.Compression`.
+type CompressionConfig struct {
+	// MinSize is the minimum response body size in bytes before compression
+	// is used. Smaller responses are sent uncompressed. Defaults to 1024.
+	MinSize int
+
+	// ContentTypes is an allowlist of response content types which may be
+	// compressed. Entries ending in `/` match a prefix like `text/` while
+	// entries starting with `+` match a structured syntax suffix like `+json`.
+	// Defaults to `DefaultCompressibleTypes`.
+	ContentTypes []string
+
+	// Compressors lists the supported encodings in order of server preference.
+	// Defaults to `DefaultCompressors`.
+	Compressors []Compressor
+}
+
+type compressAdapter struct {
+	Adapter
+	config    CompressionConfig
+	encodings []string
+	pools     map[string]*sync.Pool
+}
+
+func newCompressAdapter(a Adapter, config CompressionConfig) *compressAdapter {
+	if config.MinSize == 0 {
+		config.MinSize = 1024
+	}
+	if config.ContentTypes == nil {
+		config.ContentTypes = DefaultCompressibleTypes
+	}
+	if config.Compressors == nil {
+		config.Compressors = DefaultCompressors
+	}
+
+	ca := &compressAdapter{
+		Adapter: a,
+		config:  config,
+		pools:   make(map[string]*sync.Pool, len(config.Compressors)),
+	}
+	for _, c := range config.Compressors {
+		newEncoder := c.New
+		ca.encodings = append(ca.encodings, c.Encoding)
+		ca.pools[c.Encoding] = &sync.Pool{
+			New: func() any {
+				return newEncoder()
+			},
+		}
+	}
+	return ca
+}
+
+func (a *compressAdapter) compressible(ct string) bool {
+	if i := strings.IndexRune(ct, ';'); i != -1 {
+		ct = ct[:i]
+	}
+	for _, allowed := range a.config.ContentTypes {
+		switch {
+		case strings.HasSuffix(allowed, "/"):
+			if strings.HasPrefix(ct, allowed) {
+				return true
+			}
+		case strings.HasPrefix(allowed, "+"):
+			if strings.HasSuffix(ct, allowed) {
+				return true
+			}
+		case ct == allowed:
+			return true
+		}
+	}
+	return false
+}
+
+func (a *compressAdapter) Handle(op *Operation, handler func(Context)) {
+	a.Adapter.Handle(op, func(ctx Context) {
+		cc := &compressContext{
+			humaContext: ctx,
+			adapter:     a,
+			encoding:    negotiation.SelectQValueFast(ctx.Header("Accept-Encoding"), a.encodings),
+		}
+		handler(cc)
+		cc.close()
+	})
+}
+
+// humaContext lets `Context` be embedded without the field name shadowing its
+// `Context()` method.
+type humaContext = Context
+
+// compressContext delays writing the response status and headers until
+// enough of the body has been written to decide whether to compress it.
+type compressContext struct {
+	humaContext
+	adapter  *compressAdapter
+	encoding string
+	status   int
+	ct       string
+	skip     bool
+	decided  bool
+	buf      *bytes.Buffer
+	enc      Encoder
+	out      io.Writer
+}
+
+// Unwrap returns the wrapped context, see `UnwrapContext`.
+func (c *compressContext) Unwrap() Context {
+	return c.humaContext
+}
+
+func (c *compressContext) SetStatus(code int) {
+	if c.decided || isInterim(code) {
+		c.humaContext.SetStatus(code)
+		return
+	}
+	c.status = code
+}
+
+func (c *compressContext) SetHeader(name, value string) {
+	switch {
+	case strings.EqualFold(name, "Content-Type"):
+		c.ct = value
+	case strings.EqualFold(name, "Content-Encoding"), strings.EqualFold(name, "Content-Length"):
+		// The handler is controlling the encoding or size, so leave it alone.
+		c.skip = true
+	}
+	c.humaContext.SetHeader(name, value)
+}
+
+func (c *compressContext) BodyWriter() io.Writer {
+	return c
+}
+
+// decide whether to compress, then write the delayed status code.
+func (c *compressContext) decide(compress bool) {
+	c.decided = true
+	if !c.skip && c.adapter.compressible(c.ct) {
+		c.humaContext.AppendHeader("Vary", "Accept-Encoding")
+		if compress && c.encoding != "" {
+			c.humaContext.SetHeader("Content-Encoding", c.encoding)
+			c.enc = c.adapter.pools[c.encoding].Get().(Encoder)
+			c.enc.Reset(c.humaContext.BodyWriter())
+		}
+	}
+	if c.status != 0 {
+		c.humaContext.SetStatus(c.status)
+	}
+	c.out = c.humaContext.BodyWriter()
+	if c.enc != nil {
+		c.out = c.enc
+	}
+	if c.buf != nil {
+		c.out.Write(c.buf.Bytes())
+		c.buf.Reset()
+		bufPool.Put(c.buf)
+		c.buf = nil
+	}
+}
+
+func (c *compressContext) Write(p []byte) (int, error) {
+	if !c.decided {
+		if c.buf == nil {
+			c.buf = bufPool.Get().(*bytes.Buffer)
+		}
+		if c.buf.Len()+len(p) < c.adapter.config.MinSize {
+			return c.buf.Write(p)
+		}
+		c.decide(true)
+	}
+	return c.out.Write(p)
+}
+
+// Flush any buffered data to the client. Streaming responses are compressed
+// as soon as they are flushed regardless of size.
+func (c *compressContext) Flush() {
+	if !c.decided {
+		c.decide(true)
+	}
+	if c.enc != nil {
+		c.enc.Flush()
+	}
+	Flush(c.humaContext)
+}
+
+func (c *compressContext) close() {
+	if !c.decided {
+		// Never reached the minimum size, so send the response as-is.
+		c.decide(false)
+	}
+	if c.enc != nil {
+		c.enc.Close()
+		c.adapter.pools[c.encoding].Put(c.enc)
+		c.enc = nil
+	}
+}
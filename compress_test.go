@@ -0,0 +1,73 @@
+package huma
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompression(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Compression = &CompressionConfig{MinSize: 100}
+	app := NewTestAdapter(r, config)
+	Register(app, Operation{
+		OperationID: "compress",
+		Method:      http.MethodGet,
+		Path:        "/compress",
+	}, func(ctx context.Context, input *struct {
+		Size int `query:"size"`
+	}) (*struct {
+		Status int
+		Body   string
+	}, error) {
+		return &struct {
+			Status int
+			Body   string
+		}{Status: http.StatusCreated, Body: strings.Repeat("a", input.Size)}, nil
+	})
+
+	for _, item := range []struct {
+		name     string
+		size     int
+		accept   string
+		encoding string
+	}{
+		{"small", 10, "gzip", ""},
+		{"gzip", 500, "gzip", "gzip"},
+		{"br", 500, "gzip, br", "gzip"},
+		{"unsupported", 500, "deflate", ""},
+		{"not acceptable", 500, "gzip;q=0, identity", ""},
+		{"weighted", 500, "deflate;q=1, gzip;q=0.5", "gzip"},
+		{"none", 500, "", ""},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/compress?size=%d", item.size), nil)
+			if item.accept != "" {
+				req.Header.Set("Accept-Encoding", item.accept)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusCreated, w.Code)
+			assert.Equal(t, item.encoding, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+			var reader io.Reader = w.Body
+			if item.encoding == "gzip" {
+				reader, _ = gzip.NewReader(w.Body)
+			}
+			var body string
+			assert.NoError(t, json.NewDecoder(reader).Decode(&body))
+			assert.Len(t, body, item.size)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 // trimETag removes the quotes and `W/` prefix for incoming ETag values to
 // make comparisons easier.
 func trimETag(value string) string {
+	value = strings.TrimSpace(value)
 	if strings.HasPrefix(value, "W/") && len(value) > 2 {
 		value = value[2:]
 	}
@@ -88,6 +89,11 @@ func (p *Params) PreconditionFailed(etag string, modified time.Time) huma.Status
 			}
 		}
 
+		// The `*` is a special case meaning to match any existing value.
+		if !found && len(p.IfMatch) == 1 && trimETag(p.IfMatch[0]) == "*" && etag != "" {
+			found = true
+		}
+
 		if !found {
 			// We did not match the expected resource, abort!
 			if p.isWrite {
@@ -101,7 +107,12 @@ func (p *Params) PreconditionFailed(etag string, modified time.Time) huma.Status
 		}
 	}
 
-	if !p.IfModifiedSince.IsZero() && !modified.After(p.IfModifiedSince) {
+	// HTTP dates only have second precision, so ignore anything smaller.
+	modified = modified.Truncate(time.Second)
+
+	// Dates are ignored when the equivalent ETag header is present, see
+	// https://www.rfc-editor.org/rfc/rfc9110#section-13.2.2.
+	if len(p.IfNoneMatch) == 0 && !p.IfModifiedSince.IsZero() && !modified.After(p.IfModifiedSince) {
 		// Resource was modified *before* the date that was passed, abort!
 		if p.isWrite {
 			errors = append(errors, &huma.ErrorDetail{
@@ -113,7 +124,7 @@ func (p *Params) PreconditionFailed(etag string, modified time.Time) huma.Status
 		failed = true
 	}
 
-	if !p.IfUnmodifiedSince.IsZero() && modified.After(p.IfUnmodifiedSince) {
+	if len(p.IfMatch) == 0 && !p.IfUnmodifiedSince.IsZero() && modified.After(p.IfUnmodifiedSince) {
 		// Resource was modified *after* the date that was passed, abort!
 		if p.isWrite {
 			errors = append(errors, &huma.ErrorDetail{
@@ -134,7 +145,7 @@ func (p *Params) PreconditionFailed(etag string, modified time.Time) huma.Status
 			)
 		}
 
-		return huma.Status304NotModied()
+		return huma.Status304NotModified()
 	}
 
 	return nil
@@ -1,11 +1,13 @@
 package conditional
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/humatest"
 	"github.com/stretchr/testify/assert"
 )
@@ -175,3 +177,77 @@ func TestIfUnmodifiedSince(t *testing.T) {
 	assert.Error(t, perr)
 	assert.Equal(t, http.StatusPreconditionFailed, perr.GetStatus())
 }
+
+func TestIfMatchAny(t *testing.T) {
+	p := Params{}
+
+	r, _ := http.NewRequest(http.MethodPut, "https://example.com/resource", nil)
+	w := httptest.NewRecorder()
+	ctx := humatest.NewContext(nil, r, w)
+
+	p.IfMatch = []string{"*"}
+	p.Resolve(ctx)
+	assert.NoError(t, p.PreconditionFailed("abc123", time.Time{}))
+
+	err := p.PreconditionFailed("", time.Time{})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, err.GetStatus())
+}
+
+func TestPrecedence(t *testing.T) {
+	now, err := time.Parse(time.RFC3339, "2021-01-01T12:00:00Z")
+	assert.NoError(t, err)
+
+	after, err := time.Parse(time.RFC3339, "2022-01-01T12:00:00Z")
+	assert.NoError(t, err)
+
+	r, _ := http.NewRequest(http.MethodGet, "https://example.com/resource", nil)
+	w := httptest.NewRecorder()
+	ctx := humatest.NewContext(nil, r, w)
+
+	// If-None-Match takes precedence over If-Modified-Since.
+	p := Params{IfNoneMatch: []string{`"abc123"`}, IfModifiedSince: after}
+	p.Resolve(ctx)
+	assert.NoError(t, p.PreconditionFailed("def456", now))
+
+	// If-Match takes precedence over If-Unmodified-Since.
+	p = Params{IfMatch: []string{`"abc123"`}, IfUnmodifiedSince: now}
+	p.Resolve(ctx)
+	assert.NoError(t, p.PreconditionFailed("abc123", after))
+
+	// Sub-second precision is ignored since HTTP dates can't represent it.
+	p = Params{IfModifiedSince: now}
+	p.Resolve(ctx)
+	assert.Error(t, p.PreconditionFailed("", now.Add(500*time.Millisecond)))
+}
+
+func TestConditionalRequest(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-resource",
+		Method:      http.MethodGet,
+		Path:        "/resource",
+	}, func(ctx context.Context, input *struct {
+		Params
+	}) (*struct{}, error) {
+		if err := input.PreconditionFailed("abc123", time.Time{}); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+
+	params := api.OpenAPI().Paths["/resource"].Get.Parameters
+	names := []string{}
+	for _, param := range params {
+		names = append(names, param.Name)
+	}
+	assert.ElementsMatch(t, []string{"If-Match", "If-None-Match", "If-Modified-Since", "If-Unmodified-Since"}, names)
+
+	// Lists of ETags may contain whitespace after the comma separator.
+	resp := api.Get("/resource", `If-None-Match: "def456", "abc123"`)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+
+	resp = api.Get("/resource", `If-None-Match: "def456"`)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+}
@@ -0,0 +1,266 @@
+// Package connect serves the operations of a Huma API as unary RPCs using the
+// Connect protocol's JSON encoding, so one registration serves both REST &
+// OpenAPI clients and Connect RPC clients. See https://connectrpc.com/docs/protocol.
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/casing"
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/internal/schemalink"
+)
+
+// Error codes of the Connect protocol.
+const (
+	CodeCanceled           = "canceled"
+	CodeUnknown            = "unknown"
+	CodeInvalidArgument    = "invalid_argument"
+	CodeDeadlineExceeded   = "deadline_exceeded"
+	CodeNotFound           = "not_found"
+	CodeAlreadyExists      = "already_exists"
+	CodePermissionDenied   = "permission_denied"
+	CodeResourceExhausted  = "resource_exhausted"
+	CodeFailedPrecondition = "failed_precondition"
+	CodeAborted            = "aborted"
+	CodeOutOfRange         = "out_of_range"
+	CodeUnimplemented      = "unimplemented"
+	CodeInternal           = "internal"
+	CodeUnavailable        = "unavailable"
+	CodeDataLoss           = "data_loss"
+	CodeUnauthenticated    = "unauthenticated"
+)
+
+// codeStatus maps error codes to the HTTP status sent with them.
+var codeStatus = map[string]int{
+	CodeCanceled:           499,
+	CodeUnknown:            http.StatusInternalServerError,
+	CodeInvalidArgument:    http.StatusBadRequest,
+	CodeDeadlineExceeded:   http.StatusGatewayTimeout,
+	CodeNotFound:           http.StatusNotFound,
+	CodeAlreadyExists:      http.StatusConflict,
+	CodePermissionDenied:   http.StatusForbidden,
+	CodeResourceExhausted:  http.StatusTooManyRequests,
+	CodeFailedPrecondition: http.StatusBadRequest,
+	CodeAborted:            http.StatusConflict,
+	CodeOutOfRange:         http.StatusBadRequest,
+	CodeUnimplemented:      http.StatusNotImplemented,
+	CodeInternal:           http.StatusInternalServerError,
+	CodeUnavailable:        http.StatusServiceUnavailable,
+	CodeDataLoss:           http.StatusInternalServerError,
+	CodeUnauthenticated:    http.StatusUnauthorized,
+}
+
+// StatusCodes maps the HTTP status of an operation's error response to the
+// Connect error code sent to RPC clients. Other client errors use
+// `failed_precondition` and server errors `internal`.
+var StatusCodes = map[int]string{
+	http.StatusBadRequest:            CodeInvalidArgument,
+	http.StatusUnauthorized:          CodeUnauthenticated,
+	http.StatusForbidden:             CodePermissionDenied,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeAlreadyExists,
+	http.StatusPreconditionFailed:    CodeFailedPrecondition,
+	http.StatusRequestEntityTooLarge: CodeResourceExhausted,
+	http.StatusUnprocessableEntity:   CodeInvalidArgument,
+	http.StatusTooManyRequests:       CodeResourceExhausted,
+	499:                              CodeCanceled,
+	http.StatusNotImplemented:        CodeUnimplemented,
+	http.StatusServiceUnavailable:    CodeUnavailable,
+	http.StatusGatewayTimeout:        CodeDeadlineExceeded,
+}
+
+// Error is a Connect error response.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// MethodName returns the RPC method name of an operation, which is its
+// operation ID in CamelCase, e.g. `GetThing` for `get-thing`.
+func MethodName(op *huma.Operation) string {
+	return casing.Camel(op.OperationID, casing.Identity)
+}
+
+// Register serves every operation of the API with an operation ID as a unary
+// RPC method of the service, so call it once all operations are registered:
+//
+//	connect.Register(api, "things.v1.ThingService")
+//
+// Each operation is served via `POST /{service}/{method}`, with the method
+// named by `MethodName`. Requests must use the JSON encoding, and the fields
+// of the request message are the operation's params & body as described by
+// `huma.Invoke`, e.g. `{"thing-id": "abc", "name": "Thing"}`. Successful
+// responses send the operation's body without its `$schema` link, or an empty
+// message if it has none, along with its headers. Error responses are sent as Connect errors, with a
+// code set by `StatusCodes`.
+//
+// The RPC routes are not documented in the OpenAPI. Panics if the service
+// name is invalid or two operations have the same method name.
+func Register(api huma.API, service string) {
+	if service == "" || strings.ContainsAny(service, "/ ") {
+		panic(fmt.Sprintf("invalid service name %q", service))
+	}
+
+	oapi := api.OpenAPI()
+	paths := make([]string, 0, len(oapi.Paths))
+	for path := range oapi.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	methods := map[string]string{}
+	for _, path := range paths {
+		item := oapi.Paths[path]
+		for _, op := range []*huma.Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head, item.Patch, item.Trace} {
+			if op == nil || op.OperationID == "" {
+				continue
+			}
+			method := MethodName(op)
+			if existing, ok := methods[method]; ok {
+				panic(fmt.Sprintf("operations %s and %s have the same method name %s", existing, op.OperationID, method))
+			}
+			methods[method] = op.OperationID
+
+			op := op
+			api.Adapter().Handle(&huma.Operation{
+				OperationID: op.OperationID + "-connect",
+				Method:      http.MethodPost,
+				Path:        "/" + service + "/" + method,
+			}, func(ctx huma.Context) {
+				serve(api, op, ctx)
+			})
+		}
+	}
+}
+
+// serve calls the operation with the request message & writes the response.
+func serve(api huma.API, op *huma.Operation, ctx huma.Context) {
+	ct, _, _ := mime.ParseMediaType(ctx.Header("Content-Type"))
+	if ct != "application/json" {
+		ctx.SetHeader("Accept-Post", "application/json")
+		ctx.SetStatus(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	c := ctx.Context()
+	if ms := ctx.Header("Connect-Timeout-Ms"); ms != "" {
+		timeout, err := strconv.ParseInt(ms, 10, 64)
+		if err != nil || timeout < 0 {
+			writeError(ctx, http.Header{}, &Error{Code: CodeInvalidArgument, Message: "invalid Connect-Timeout-Ms header"})
+			return
+		}
+		var cancel context.CancelFunc
+		c, cancel = context.WithTimeout(c, time.Duration(timeout)*time.Millisecond)
+		defer cancel()
+	}
+
+	args := map[string]any{}
+	dec := json.NewDecoder(ctx.BodyReader())
+	dec.UseNumber()
+	if err := dec.Decode(&args); err != nil && !errors.Is(err, io.EOF) {
+		writeError(ctx, http.Header{}, &Error{Code: CodeInvalidArgument, Message: "invalid request message: " + err.Error()})
+		return
+	}
+
+	header := http.Header{}
+	ctx.EachHeader(func(name, value string) {
+		switch strings.ToLower(name) {
+		case "content-type", "content-length", "content-encoding", "accept-encoding", "connect-protocol-version", "connect-timeout-ms":
+			return
+		}
+		header.Add(name, value)
+	})
+	header.Set("Accept", "application/json")
+
+	resp, err := huma.Invoke(c, api, op, header, args)
+	if err != nil {
+		writeError(ctx, http.Header{}, errorFromBody(http.StatusBadRequest, marshalError(err)))
+		return
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if c.Err() == context.DeadlineExceeded {
+		writeError(ctx, http.Header{}, &Error{Code: CodeDeadlineExceeded, Message: "deadline exceeded"})
+		return
+	}
+	if resp.StatusCode >= 300 {
+		writeError(ctx, resp.Header, errorFromBody(resp.StatusCode, body))
+		return
+	}
+
+	copyHeaders(ctx, resp.Header)
+	ctx.SetHeader("Content-Type", "application/json")
+	ctx.SetStatus(http.StatusOK)
+	if len(body) == 0 {
+		body = []byte("{}")
+	}
+	ctx.BodyWriter().Write(schemalink.Strip(body))
+}
+
+// copyHeaders sends the operation's response headers, except those
+// describing its body.
+func copyHeaders(ctx huma.Context, header http.Header) {
+	for name, values := range header {
+		switch name {
+		case "Content-Type", "Content-Length", "Content-Encoding", "Link":
+			continue
+		}
+		for _, v := range values {
+			ctx.AppendHeader(name, v)
+		}
+	}
+}
+
+func marshalError(err error) []byte {
+	b, _ := json.Marshal(err)
+	return b
+}
+
+// errorFromBody converts an error response to a Connect error, using the
+// detail of Huma's error model as the message.
+func errorFromBody(status int, body []byte) *Error {
+	code, ok := StatusCodes[status]
+	if !ok {
+		code = CodeUnknown
+		if status >= 400 && status < 500 {
+			code = CodeFailedPrecondition
+		} else if status >= 500 {
+			code = CodeInternal
+		}
+	}
+
+	e := &Error{Code: code}
+	var model huma.ErrorModel
+	if json.Unmarshal(body, &model) == nil {
+		e.Message = model.Detail
+		if e.Message == "" {
+			e.Message = model.Title
+		}
+		for _, detail := range model.Errors {
+			e.Message += "; " + detail.Error()
+		}
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(status)
+	}
+	return e
+}
+
+// writeError sends a Connect error response.
+func writeError(ctx huma.Context, header http.Header, e *Error) {
+	copyHeaders(ctx, header)
+	ctx.SetHeader("Content-Type", "application/json")
+	ctx.SetStatus(codeStatus[e.Code])
+	b, _ := json.Marshal(e)
+	ctx.BodyWriter().Write(b)
+}
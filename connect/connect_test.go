@@ -0,0 +1,151 @@
+package connect
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Thing struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name" minLength:"2"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+func register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{thing-id}",
+	}, func(ctx context.Context, input *struct {
+		ID     string   `path:"thing-id"`
+		Fields []string `query:"fields"`
+		Trace  string   `header:"X-Trace"`
+	}) (*struct {
+		Version string `header:"X-Version"`
+		Body    Thing
+	}, error) {
+		if input.ID == "missing" {
+			return nil, huma.Error404NotFound("thing not found")
+		}
+		resp := &struct {
+			Version string `header:"X-Version"`
+			Body    Thing
+		}{Version: "1"}
+		resp.Body = Thing{ID: input.ID, Name: input.Trace, Tags: input.Fields}
+		return resp, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{thing-id}",
+	}, func(ctx context.Context, input *struct {
+		ID   string `path:"thing-id"`
+		Body struct {
+			Name string `json:"name" minLength:"2"`
+		}
+	}) (*struct{ Body Thing }, error) {
+		return &struct{ Body Thing }{Body: Thing{ID: input.ID, Name: input.Body.Name}}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-thing",
+		Method:      http.MethodDelete,
+		Path:        "/things/{thing-id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"thing-id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+}
+
+func TestConnect(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	register(api)
+	Register(api, "things.v1.ThingService")
+
+	resp := api.Post("/things.v1.ThingService/GetThing",
+		"Content-Type: application/json",
+		"X-Trace: traced",
+		strings.NewReader(`{"thing-id": "abc", "fields": ["a", "b"]}`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "1", resp.Header().Get("X-Version"))
+	assert.JSONEq(t, `{"id": "abc", "name": "traced", "tags": ["a", "b"]}`, resp.Body.String())
+
+	// Object bodies are flattened into the request message.
+	resp = api.Post("/things.v1.ThingService/PutThing",
+		"Content-Type: application/json",
+		strings.NewReader(`{"thing-id": "abc", "name": "Thing"}`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.JSONEq(t, `{"id": "abc", "name": "Thing"}`, resp.Body.String())
+
+	// Responses without a body send an empty message.
+	resp = api.Post("/things.v1.ThingService/DeleteThing",
+		"Content-Type: application/json",
+		strings.NewReader(`{"thing-id": "abc"}`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.JSONEq(t, `{}`, resp.Body.String())
+
+	// The RPC routes are not documented.
+	assert.Len(t, api.OpenAPI().Paths, 1)
+}
+
+func TestConnectErrors(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	register(api)
+	Register(api, "things.v1.ThingService")
+
+	resp := api.Post("/things.v1.ThingService/GetThing",
+		"Content-Type: application/json",
+		strings.NewReader(`{"thing-id": "missing"}`))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.JSONEq(t, `{"code": "not_found", "message": "thing not found"}`, resp.Body.String())
+
+	resp = api.Post("/things.v1.ThingService/PutThing",
+		"Content-Type: application/json",
+		strings.NewReader(`{"thing-id": "abc", "name": "a"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"code":"invalid_argument"`)
+	assert.Contains(t, resp.Body.String(), `body.name`)
+
+	resp = api.Post("/things.v1.ThingService/DeleteThing",
+		"Content-Type: application/json",
+		strings.NewReader(`{"thing-id": "abc", "extra": true}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), `unknown argument (extra: true)`)
+
+	resp = api.Post("/things.v1.ThingService/DeleteThing",
+		"Content-Type: application/json",
+		strings.NewReader(`{`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), `invalid request message`)
+
+	resp = api.Post("/things.v1.ThingService/DeleteThing",
+		"Content-Type: application/proto",
+		strings.NewReader(``))
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Accept-Post"))
+
+	resp = api.Post("/things.v1.ThingService/DeleteThing",
+		"Content-Type: application/json",
+		"Connect-Timeout-Ms: soon",
+		strings.NewReader(`{"thing-id": "abc"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	assert.Panics(t, func() {
+		Register(api, "bad/service")
+	})
+}
+
+func TestErrorFromBody(t *testing.T) {
+	assert.Equal(t, &Error{Code: CodeFailedPrecondition, Message: "Payment Required"}, errorFromBody(http.StatusPaymentRequired, nil))
+	assert.Equal(t, &Error{Code: CodeInternal, Message: "Bad Gateway"}, errorFromBody(http.StatusBadGateway, nil))
+	assert.Equal(t, &Error{Code: CodeUnknown, Message: "Found"}, errorFromBody(http.StatusFound, nil))
+}
@@ -0,0 +1,247 @@
+package huma
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// CORSConfig enables Cross-Origin Resource Sharing for all operations, so
+// that browser apps on other origins can call the API. Preflight `OPTIONS`
+// requests are answered automatically with the methods and headers used by
+// the operations registered at each path. It works with any adapter. See
+// `Config.CORS`.
+type CORSConfig struct {
+	// AllowOrigins lists the origins, like `https://example.com`, which may
+	// call the API. Use `*` to allow any origin. If empty, the origins of the
+	// absolute URLs in the OpenAPI `Servers` list are allowed.
+	AllowOrigins []string
+
+	// AllowHeaders lists additional request headers clients may send. Header
+	// parameters, `Content-Type` for operations with a request body, and
+	// headers used by security schemes are always allowed.
+	AllowHeaders []string
+
+	// ExposeHeaders lists additional response headers clients may read.
+	// Response headers documented by operations are always exposed.
+	ExposeHeaders []string
+
+	// AllowCredentials lets clients send cookies and HTTP auth credentials.
+	// It can't be used with the `*` origin, since any site could then make
+	// requests with the user's credentials.
+	AllowCredentials bool
+
+	// MaxAge is how long clients may cache the result of a preflight request.
+	// If zero, the `Access-Control-Max-Age` header is not sent.
+	MaxAge time.Duration
+}
+
+// corsPath tracks the operations registered at a single path.
+type corsPath struct {
+	methods   []string
+	headers   []string
+	preflight bool
+
+	// options handles `OPTIONS` requests instead of the automatic preflight
+	// response, for operations registered after it.
+	options   func(Context)
+	optionsOp *Operation
+}
+
+type corsAdapter struct {
+	Adapter
+	api     API
+	config  CORSConfig
+	paths   map[string]*corsPath
+	once    sync.Once
+	any     bool
+	origins map[string]bool
+}
+
+func newCORSAdapter(a Adapter, config CORSConfig) *corsAdapter {
+	if config.AllowCredentials && slices.Contains(config.AllowOrigins, "*") {
+		panic("CORS AllowCredentials can't be used with the * origin")
+	}
+	return &corsAdapter{
+		Adapter: a,
+		config:  config,
+		paths:   map[string]*corsPath{},
+	}
+}
+
+// allowed returns whether the origin may call the API. The spec may be
+// modified until the server starts, so derive the origins on first use.
+func (a *corsAdapter) allowed(origin string) bool {
+	a.once.Do(func() {
+		a.origins = map[string]bool{}
+		origins := a.config.AllowOrigins
+		if len(origins) == 0 {
+			for _, server := range a.api.OpenAPI().Servers {
+				if u, err := url.Parse(server.URL); err == nil && u.Scheme != "" && u.Host != "" {
+					origins = append(origins, u.Scheme+"://"+u.Host)
+				}
+			}
+		}
+		for _, o := range origins {
+			if o == "*" {
+				a.any = true
+			}
+			a.origins[strings.ToLower(strings.TrimSuffix(o, "/"))] = true
+		}
+	})
+	return a.any || a.origins[strings.ToLower(origin)]
+}
+
+// requestHeaders returns the request headers an operation may use.
+func (a *corsAdapter) requestHeaders(op *Operation) []string {
+	headers := []string{}
+	for _, p := range op.Parameters {
+		if p.In == "header" {
+			headers = append(headers, p.Name)
+		}
+	}
+	if op.RequestBody != nil {
+		headers = append(headers, "Content-Type")
+	}
+
+	oapi := a.api.OpenAPI()
+	security := op.Security
+	if security == nil {
+		security = oapi.Security
+	}
+	for _, requirement := range security {
+		for name := range requirement {
+			var scheme *SecurityScheme
+			if oapi.Components != nil {
+				scheme = oapi.Components.SecuritySchemes[name]
+			}
+			switch {
+			case scheme == nil:
+			case scheme.Type == "apiKey" && scheme.In == "header":
+				headers = append(headers, scheme.Name)
+			case scheme.Type == "http", scheme.Type == "oauth2", scheme.Type == "openIdConnect":
+				headers = append(headers, "Authorization")
+			}
+		}
+	}
+	return headers
+}
+
+// joinUnique merges header or method names, removing duplicates.
+func joinUnique(lists ...[]string) string {
+	seen := map[string]bool{}
+	values := []string{}
+	for _, list := range lists {
+		for _, v := range list {
+			key := http.CanonicalHeaderKey(v)
+			if !seen[key] {
+				seen[key] = true
+				values = append(values, v)
+			}
+		}
+	}
+	sort.Strings(values)
+	return strings.Join(values, ", ")
+}
+
+// Handle registers the operation, answering preflight requests for its path
+// unless the path has its own `OPTIONS` operation. Routers only see a single
+// `OPTIONS` handler for each path, which dispatches to an `OPTIONS` operation
+// registered after the automatic preflight response, so the two never
+// conflict.
+func (a *corsAdapter) Handle(op *Operation, handler func(Context)) {
+	p := a.paths[op.Path]
+	if p == nil {
+		p = &corsPath{}
+		a.paths[op.Path] = p
+	}
+
+	if op.Method != http.MethodOptions {
+		p.methods = append(p.methods, op.Method)
+		p.headers = append(p.headers, a.requestHeaders(op)...)
+	}
+
+	exposed := []string{}
+	for _, resp := range op.Responses {
+		for name := range resp.Headers {
+			exposed = append(exposed, name)
+		}
+	}
+	expose := joinUnique(exposed, a.config.ExposeHeaders)
+
+	wrapped := func(ctx Context) {
+		origin := ctx.Header("Origin")
+		ctx.AppendHeader("Vary", "Origin")
+		if origin != "" && a.allowed(origin) {
+			a.setOrigin(ctx, origin)
+			if expose != "" {
+				ctx.SetHeader("Access-Control-Expose-Headers", expose)
+			}
+		}
+		handler(ctx)
+	}
+
+	if op.Method == http.MethodOptions {
+		if p.preflight {
+			// The automatic preflight route is already registered, so use the
+			// operation from there.
+			p.options = wrapped
+			p.optionsOp = op
+			return
+		}
+		// The operation handles its own `OPTIONS` requests.
+		p.preflight = true
+	} else if !p.preflight {
+		p.preflight = true
+		a.Adapter.Handle(&Operation{
+			Method:   http.MethodOptions,
+			Path:     op.Path,
+			Security: []map[string][]string{},
+		}, func(ctx Context) {
+			if p.options != nil {
+				p.options(&allowContext{humaContext: ctx, op: p.optionsOp})
+				return
+			}
+			a.handlePreflight(ctx, p)
+		})
+	}
+
+	a.Adapter.Handle(op, wrapped)
+}
+
+func (a *corsAdapter) setOrigin(ctx Context, origin string) {
+	if a.any {
+		ctx.SetHeader("Access-Control-Allow-Origin", "*")
+	} else {
+		ctx.SetHeader("Access-Control-Allow-Origin", origin)
+	}
+	if a.config.AllowCredentials {
+		ctx.SetHeader("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (a *corsAdapter) handlePreflight(ctx Context, p *corsPath) {
+	ctx.AppendHeader("Vary", "Origin")
+	ctx.AppendHeader("Vary", "Access-Control-Request-Method")
+	ctx.AppendHeader("Vary", "Access-Control-Request-Headers")
+
+	origin := ctx.Header("Origin")
+	if origin != "" && a.allowed(origin) {
+		a.setOrigin(ctx, origin)
+		ctx.SetHeader("Access-Control-Allow-Methods", joinUnique(p.methods))
+		if headers := joinUnique(p.headers, a.config.AllowHeaders); headers != "" {
+			ctx.SetHeader("Access-Control-Allow-Headers", headers)
+		}
+		if a.config.MaxAge > 0 {
+			ctx.SetHeader("Access-Control-Max-Age", strconv.Itoa(int(a.config.MaxAge.Seconds())))
+		}
+	}
+	ctx.SetHeader("Allow", joinUnique(append([]string{http.MethodOptions}, p.methods...)))
+	ctx.SetStatus(http.StatusNoContent)
+}
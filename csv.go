@@ -0,0 +1,214 @@
+package huma
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultCSVFormat is an optional CSV formatter that can be set in the API's
+// `Config.Formats` map, e.g. for export endpoints. It writes a slice of
+// structs as a header row followed by a row for each item, or a single struct
+// as one row, with the columns named and ordered like the JSON fields. Values
+// like objects & arrays which don't fit in a cell are encoded as JSON. It
+// can't be used for request bodies.
+//
+//	config.Formats["text/csv"] = huma.DefaultCSVFormat
+//	config.Formats["csv"] = huma.DefaultCSVFormat
+var DefaultCSVFormat = Format{
+	Marshal: csvMarshal,
+	Unmarshal: func(data []byte, v any) error {
+		return errors.New("csv request bodies are not supported")
+	},
+}
+
+// csvColumn is a CSV column for a struct field.
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+var csvColumnCache sync.Map
+
+// csvColumns returns the columns for the struct type, using the same names
+// and embedding rules as `encoding/json`.
+func csvColumns(t reflect.Type) []csvColumn {
+	if cached, ok := csvColumnCache.Load(t); ok {
+		return cached.([]csvColumn)
+	}
+	columns := appendCSVColumns(nil, t, nil)
+	csvColumnCache.Store(t, columns)
+	return columns
+}
+
+func appendCSVColumns(columns []csvColumn, t reflect.Type, index []int) []csvColumn {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		idx := append(append([]int{}, index...), i)
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// Fields of embedded structs are promoted.
+				columns = appendCSVColumns(columns, ft, idx)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		columns = append(columns, csvColumn{name: name, index: idx})
+	}
+	return columns
+}
+
+func csvMarshal(w io.Writer, v any) error {
+	rv := reflect.ValueOf(unlinkValue(v))
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	rows := rv
+	if rv.Kind() == reflect.Struct {
+		rows = reflect.New(reflect.SliceOf(rv.Type())).Elem()
+		rows = reflect.Append(rows, rv)
+	}
+	if rows.Kind() != reflect.Slice && rows.Kind() != reflect.Array {
+		return fmt.Errorf("csv: unsupported type %s", rv.Type())
+	}
+	et := rows.Type().Elem()
+	for et.Kind() == reflect.Pointer {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return fmt.Errorf("csv: unsupported type %s", rv.Type())
+	}
+
+	columns := csvColumns(et)
+	record := make([]string, len(columns))
+	for i, col := range columns {
+		record[i] = col.name
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+	for i := 0; i < rows.Len(); i++ {
+		row := reflect.Indirect(rows.Index(i))
+		for j, col := range columns {
+			record[j] = ""
+			if !row.IsValid() {
+				continue
+			}
+			f, err := row.FieldByIndexErr(col.index)
+			if err != nil {
+				// Nil embedded struct pointer.
+				continue
+			}
+			if record[j], err = csvCell(f); err != nil {
+				return err
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvCell formats a value for a single CSV cell. Nil values are empty.
+func csvCell(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return "", err
+	}
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		// Types which marshal themselves as strings, like `huma.Nullable[string]`.
+		return s, nil
+	}
+	return string(b), nil
+}
+
+// DocumentCSV documents a `text/csv` response for operations whose successful
+// responses are a JSON array of objects, so clients know they can request it
+// using the `Accept` header. Add it to the OpenAPI's `OnAddOperation` hooks,
+// along with `DefaultCSVFormat` to the API's formats, before registering
+// operations.
+func DocumentCSV(oapi *OpenAPI, op *Operation) {
+	resolve := func(s *Schema) *Schema {
+		if s != nil && s.Ref != "" && oapi.Components != nil && oapi.Components.Schemas != nil {
+			return oapi.Components.Schemas.SchemaFromRef(s.Ref)
+		}
+		return s
+	}
+
+	for status, resp := range op.Responses {
+		if !strings.HasPrefix(status, "2") || resp.Content == nil || resp.Content["text/csv"] != nil {
+			continue
+		}
+		mt := resp.Content["application/json"]
+		if mt == nil {
+			continue
+		}
+		s := resolve(mt.Schema)
+		if s == nil || s.Type != TypeArray || s.Items == nil {
+			continue
+		}
+		if items := resolve(s.Items); items == nil || items.Type != TypeObject {
+			continue
+		}
+		resp.Content["text/csv"] = &MediaType{
+			Schema: &Schema{
+				Type:        TypeString,
+				Description: "A header row with the name of each field, followed by a row for each item.",
+			},
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package huma
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type csvBase struct {
+	ID string `json:"id"`
+}
+
+type csvItem struct {
+	csvBase
+	Name    string            `json:"name"`
+	Price   float64           `json:"price"`
+	Created time.Time         `json:"created"`
+	Tags    []string          `json:"tags,omitempty"`
+	Note    *string           `json:"note"`
+	Meta    map[string]string `json:"meta"`
+	Secret  string            `json:"-"`
+	hidden  string
+}
+
+func TestCSV(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Formats["text/csv"] = DefaultCSVFormat
+	config.Formats["csv"] = DefaultCSVFormat
+	config.OpenAPI.OnAddOperation = append(config.OpenAPI.OnAddOperation, DocumentCSV)
+	api := NewTestAdapter(r, config)
+
+	note := "has, comma"
+	Register(api, Operation{
+		OperationID: "export",
+		Method:      http.MethodGet,
+		Path:        "/export",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body []*csvItem }, error) {
+		created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		return &struct{ Body []*csvItem }{Body: []*csvItem{
+			{csvBase: csvBase{ID: "a"}, Name: "Apple", Price: 1.5, Created: created, Tags: []string{"fruit", "red"}, Meta: map[string]string{"k": "v"}},
+			{csvBase: csvBase{ID: "b"}, Name: "Banana", Price: 2, Created: created, Note: &note},
+			nil,
+		}}, nil
+	})
+
+	Register(api, Operation{
+		OperationID: "get-item",
+		Method:      http.MethodGet,
+		Path:        "/item",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body csvItem }, error) {
+		return nil, Error404NotFound("no item")
+	})
+
+	// Only array responses document CSV.
+	assert.Contains(t, api.OpenAPI().Paths["/export"].Get.Responses["200"].Content, "text/csv")
+	assert.NotContains(t, api.OpenAPI().Paths["/item"].Get.Responses["200"].Content, "text/csv")
+
+	req, _ := http.NewRequest(http.MethodGet, "/export", nil)
+	req.Header.Set("Accept", "text/csv")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, `id,name,price,created,tags,note,meta
+a,Apple,1.5,2024-01-02T03:04:05Z,"[""fruit"",""red""]",,"{""k"":""v""}"
+b,Banana,2,2024-01-02T03:04:05Z,,"has, comma",
+,,,,,,
+`, w.Body.String())
+
+	// JSON is still the default.
+	req, _ = http.NewRequest(http.MethodGet, "/export", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	// Single structs like errors are written as one row.
+	req, _ = http.NewRequest(http.MethodGet, "/item", nil)
+	req.Header.Set("Accept", "text/csv")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "title,status,detail")
+	assert.Contains(t, w.Body.String(), "Not Found,404,no item")
+}
+
+func TestCSVUnsupported(t *testing.T) {
+	assert.Error(t, DefaultCSVFormat.Marshal(io.Discard, []int{1, 2}))
+	assert.Error(t, DefaultCSVFormat.Marshal(io.Discard, "foo"))
+	assert.Error(t, DefaultCSVFormat.Unmarshal([]byte("a,b"), &[]csvItem{}))
+}
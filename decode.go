@@ -0,0 +1,812 @@
+package huma
+
+import (
+	"encoding"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+type decodeKind int
+
+const (
+	decodeBool decodeKind = iota
+	decodeString
+	decodeInt
+	decodeUint
+	decodeFloat
+	decodeSlice
+	decodeMap
+	decodeStruct
+	decodePtr
+	decodeTime
+)
+
+// decodeField is a struct field populated from a JSON object property.
+type decodeField struct {
+	id       int
+	index    []int
+	node     *decodeNode
+	required bool
+}
+
+// decodeNode validates & decodes a single JSON value into a Go type.
+type decodeNode struct {
+	kind   decodeKind
+	typ    reflect.Type
+	schema *Schema
+	elem   *decodeNode
+
+	// Struct fields by property name, and how many are required.
+	fields   map[string]*decodeField
+	required int
+}
+
+// bodyDecoder validates a JSON request body while decoding it directly into
+// the input body type, avoiding the round trip through `any` used by
+// `Validate` followed by a second unmarshal. Only the happy path is handled:
+// for invalid or unusual input it reports failure and the caller falls back
+// to the regular path, which generates the exhaustive validation errors.
+type bodyDecoder struct {
+	root *decodeNode
+}
+
+// compileBodyDecoder returns a decoder for the schema and type, or nil if the
+// combination is not supported, e.g. because the type implements custom
+// unmarshaling or the schema uses features which need the regular path. When
+// `stripReadOnly` is set, bodies containing read-only properties use the
+// regular path so the properties can be removed.
+func compileBodyDecoder(r Registry, s *Schema, t reflect.Type, stripReadOnly bool) *bodyDecoder {
+	c := &decodeCompiler{r: r, seen: map[decodeKey]*decodeNode{}, stripReadOnly: stripReadOnly}
+	root := c.compile(s, t)
+	if root == nil || c.failed {
+		return nil
+	}
+	return &bodyDecoder{root: root}
+}
+
+type decodeKey struct {
+	schema *Schema
+	typ    reflect.Type
+}
+
+type decodeCompiler struct {
+	r             Registry
+	seen          map[decodeKey]*decodeNode
+	failed        bool
+	stripReadOnly bool
+}
+
+func (c *decodeCompiler) compile(s *Schema, t reflect.Type) *decodeNode {
+	if s == nil {
+		return nil
+	}
+	for s.Ref != "" {
+		if s = c.r.SchemaFromRef(s.Ref); s == nil {
+			return nil
+		}
+	}
+
+	if t.Kind() == reflect.Ptr {
+		elem := c.compile(s, t.Elem())
+		if elem == nil {
+			return nil
+		}
+		return &decodeNode{kind: decodePtr, typ: t, schema: s, elem: elem}
+	}
+
+	key := decodeKey{s, t}
+	if n := c.seen[key]; n != nil {
+		// Recursive types reuse the node being compiled.
+		return n
+	}
+
+	if t == timeType {
+		if s.Type != TypeString {
+			return nil
+		}
+		return &decodeNode{kind: decodeTime, typ: t, schema: s}
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return nil
+	}
+	if s.UniqueItems || s.Nullable || s.Type == "" || s.PatternProperties != nil || s.PropertyNames != nil || s.DependentRequired != nil || s.comparisons != nil {
+		return nil
+	}
+	for _, e := range s.Enum {
+		switch e.(type) {
+		case bool, string, float64:
+		default:
+			// Anything else can't match a decoded value.
+			return nil
+		}
+	}
+
+	switch s.constValue.(type) {
+	case nil, bool, string, float64:
+	default:
+		// Composite constants need the regular path.
+		return nil
+	}
+
+	n := &decodeNode{typ: t, schema: s}
+	switch t.Kind() {
+	case reflect.Bool:
+		if s.Type != TypeBoolean {
+			return nil
+		}
+		n.kind = decodeBool
+	case reflect.String:
+		if s.Type != TypeString {
+			return nil
+		}
+		n.kind = decodeString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s.Type != TypeInteger && s.Type != TypeNumber {
+			return nil
+		}
+		n.kind = decodeInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s.Type != TypeInteger && s.Type != TypeNumber {
+			return nil
+		}
+		n.kind = decodeUint
+	case reflect.Float32, reflect.Float64:
+		if s.Type != TypeInteger && s.Type != TypeNumber {
+			return nil
+		}
+		n.kind = decodeFloat
+	case reflect.Slice:
+		if s.Type != TypeArray || t.Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		n.kind = decodeSlice
+		c.seen[key] = n
+		if n.elem = c.compile(s.Items, t.Elem()); n.elem == nil {
+			c.failed = true
+			return nil
+		}
+	case reflect.Map:
+		addl, ok := s.AdditionalProperties.(*Schema)
+		if s.Type != TypeObject || t.Key().Kind() != reflect.String || len(s.Properties) > 0 || !ok {
+			return nil
+		}
+		if reflect.PointerTo(t.Key()).Implements(textUnmarshalerType) {
+			return nil
+		}
+		n.kind = decodeMap
+		c.seen[key] = n
+		if n.elem = c.compile(addl, t.Elem()); n.elem == nil {
+			c.failed = true
+			return nil
+		}
+	case reflect.Struct:
+		if s.Type != TypeObject || len(s.Properties) == 0 {
+			return nil
+		}
+		if _, ok := s.AdditionalProperties.(*Schema); ok {
+			return nil
+		}
+		n.kind = decodeStruct
+		c.seen[key] = n
+		if !c.compileStruct(n, s, t) {
+			c.failed = true
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	return n
+}
+
+// compileStruct maps the schema properties to the struct's fields, which
+// must match the way both `SchemaFromType` and `encoding/json` see them.
+func (c *decodeCompiler) compileStruct(n *decodeNode, s *Schema, t reflect.Type) bool {
+	fields := map[string][]int{}
+	for _, f := range jsonFields(t) {
+		// Fields within embedded pointers may need allocating, so they are left
+		// to the regular path.
+		ft := t
+		for _, i := range f.index[:len(f.index)-1] {
+			if ft = ft.Field(i).Type; ft.Kind() == reflect.Pointer {
+				return false
+			}
+		}
+		fields[f.name] = f.index
+	}
+
+	n.fields = map[string]*decodeField{}
+	for name, ps := range s.Properties {
+		for ps.Ref != "" {
+			ps = c.r.SchemaFromRef(ps.Ref)
+		}
+		required := s.requiredMap[name] && !ps.ReadOnly
+		index, ok := fields[name]
+		if ok && ps.ReadOnly && c.stripReadOnly {
+			// Treated as unknown so it's left to the regular path.
+			continue
+		}
+		if !ok {
+			// Documented but never decoded, so sending it needs the regular path.
+			if required {
+				return false
+			}
+			continue
+		}
+		node := c.compile(s.Properties[name], t.FieldByIndex(index).Type)
+		if node == nil {
+			return false
+		}
+		n.fields[name] = &decodeField{id: len(n.fields), index: index, node: node, required: required}
+		if required {
+			n.required++
+		}
+	}
+	// Duplicate properties are tracked using a bit mask.
+	return len(n.fields) <= 64
+}
+
+var decodePool = sync.Pool{
+	New: func() any {
+		return &decodeState{}
+	},
+}
+
+// Decode validates the JSON body and decodes it into `v`, which must be a
+// settable zero value of the compiled type. It returns false and leaves `v`
+// zeroed if the body is invalid or needs the regular path.
+func (d *bodyDecoder) Decode(body []byte, v reflect.Value) bool {
+	st := decodePool.Get().(*decodeState)
+	defer func() {
+		st.data = nil
+		st.i = 0
+		st.scratch = st.scratch[:0]
+		decodePool.Put(st)
+	}()
+	st.data = body
+
+	st.skipSpace()
+	ok := st.value(d.root, v)
+	if ok {
+		st.skipSpace()
+		ok = st.i == len(st.data)
+	}
+	if !ok {
+		v.Set(reflect.Zero(d.root.typ))
+	}
+	return ok
+}
+
+type decodeState struct {
+	data    []byte
+	i       int
+	scratch []byte
+	res     ValidateResult
+	pb      PathBuffer
+}
+
+func (st *decodeState) skipSpace() {
+	for st.i < len(st.data) {
+		switch st.data[st.i] {
+		case ' ', '\t', '\n', '\r':
+			st.i++
+		default:
+			return
+		}
+	}
+}
+
+// literal consumes the given keyword, e.g. `true`.
+func (st *decodeState) literal(s string) bool {
+	if len(st.data)-st.i < len(s) || string(st.data[st.i:st.i+len(s)]) != s {
+		return false
+	}
+	st.i += len(s)
+	return true
+}
+
+// str parses a JSON string, returning its unescaped bytes. The result may
+// point into the input or the scratch buffer, so it is only valid until the
+// next call.
+func (st *decodeState) str() ([]byte, bool) {
+	if st.i >= len(st.data) || st.data[st.i] != '"' {
+		return nil, false
+	}
+	st.i++
+	start := st.i
+	for st.i < len(st.data) {
+		c := st.data[st.i]
+		switch {
+		case c == '"':
+			st.i++
+			return st.data[start : st.i-1], true
+		case c == '\\':
+			return st.strEscaped(start)
+		case c < 0x20:
+			return nil, false
+		case c < utf8.RuneSelf:
+			st.i++
+		default:
+			r, size := utf8.DecodeRune(st.data[st.i:])
+			if r == utf8.RuneError && size == 1 {
+				// Invalid UTF-8 would be replaced, so leave it to the regular path.
+				return nil, false
+			}
+			st.i += size
+		}
+	}
+	return nil, false
+}
+
+func (st *decodeState) strEscaped(start int) ([]byte, bool) {
+	buf := append(st.scratch[:0], st.data[start:st.i]...)
+	for st.i < len(st.data) {
+		c := st.data[st.i]
+		switch {
+		case c == '"':
+			st.i++
+			st.scratch = buf
+			return buf, true
+		case c == '\\':
+			if st.i+1 >= len(st.data) {
+				return nil, false
+			}
+			st.i += 2
+			switch st.data[st.i-1] {
+			case '"', '\\', '/':
+				buf = append(buf, st.data[st.i-1])
+			case 'b':
+				buf = append(buf, '\b')
+			case 'f':
+				buf = append(buf, '\f')
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'u':
+				r, ok := st.hex4()
+				if !ok {
+					return nil, false
+				}
+				if utf16.IsSurrogate(r) {
+					if !st.literal("\\u") {
+						return nil, false
+					}
+					r2, ok := st.hex4()
+					if !ok {
+						return nil, false
+					}
+					if r = utf16.DecodeRune(r, r2); r == utf8.RuneError {
+						return nil, false
+					}
+				}
+				buf = utf8.AppendRune(buf, r)
+			default:
+				return nil, false
+			}
+		case c < 0x20:
+			return nil, false
+		case c < utf8.RuneSelf:
+			buf = append(buf, c)
+			st.i++
+		default:
+			r, size := utf8.DecodeRune(st.data[st.i:])
+			if r == utf8.RuneError && size == 1 {
+				return nil, false
+			}
+			buf = append(buf, st.data[st.i:st.i+size]...)
+			st.i += size
+		}
+	}
+	return nil, false
+}
+
+func (st *decodeState) hex4() (rune, bool) {
+	if len(st.data)-st.i < 4 {
+		return 0, false
+	}
+	var r rune
+	for _, c := range st.data[st.i : st.i+4] {
+		switch {
+		case '0' <= c && c <= '9':
+			c = c - '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return 0, false
+		}
+		r = r*16 + rune(c)
+	}
+	st.i += 4
+	return r, true
+}
+
+// number parses a JSON number, returning its text and whether it is an
+// integer without a fraction or exponent.
+func (st *decodeState) number() ([]byte, bool, bool) {
+	start := st.i
+	integer := true
+	if st.i < len(st.data) && st.data[st.i] == '-' {
+		st.i++
+	}
+	switch {
+	case st.i < len(st.data) && st.data[st.i] == '0':
+		st.i++
+	case st.i < len(st.data) && '1' <= st.data[st.i] && st.data[st.i] <= '9':
+		for st.i < len(st.data) && '0' <= st.data[st.i] && st.data[st.i] <= '9' {
+			st.i++
+		}
+	default:
+		return nil, false, false
+	}
+	if st.i < len(st.data) && st.data[st.i] == '.' {
+		integer = false
+		st.i++
+		if !st.digits() {
+			return nil, false, false
+		}
+	}
+	if st.i < len(st.data) && (st.data[st.i] == 'e' || st.data[st.i] == 'E') {
+		integer = false
+		st.i++
+		if st.i < len(st.data) && (st.data[st.i] == '+' || st.data[st.i] == '-') {
+			st.i++
+		}
+		if !st.digits() {
+			return nil, false, false
+		}
+	}
+	return st.data[start:st.i], integer, true
+}
+
+func (st *decodeState) digits() bool {
+	start := st.i
+	for st.i < len(st.data) && '0' <= st.data[st.i] && st.data[st.i] <= '9' {
+		st.i++
+	}
+	return st.i > start
+}
+
+// value decodes the next JSON value into `v`, which must be a zero value of
+// the node's type.
+func (st *decodeState) value(n *decodeNode, v reflect.Value) bool {
+	s := n.schema
+	switch n.kind {
+	case decodePtr:
+		p := reflect.New(n.typ.Elem())
+		if !st.value(n.elem, p.Elem()) {
+			return false
+		}
+		v.Set(p)
+		return true
+	case decodeBool:
+		var b bool
+		switch {
+		case st.literal("true"):
+			b = true
+		case st.literal("false"):
+		default:
+			return false
+		}
+		if len(s.Enum) > 0 && !enumContains(s.Enum, b) {
+			return false
+		}
+		if !constOK(s, b) {
+			return false
+		}
+		v.SetBool(b)
+		return true
+	case decodeString:
+		b, ok := st.str()
+		if !ok || !st.validString(s, b) {
+			return false
+		}
+		v.SetString(string(b))
+		return true
+	case decodeTime:
+		b, ok := st.str()
+		if !ok || !st.validString(s, b) {
+			return false
+		}
+		var t time.Time
+		if err := t.UnmarshalText(b); err != nil {
+			return false
+		}
+		v.Set(reflect.ValueOf(t))
+		return true
+	case decodeInt, decodeUint, decodeFloat:
+		text, integer, ok := st.number()
+		if !ok {
+			return false
+		}
+		return st.setNumber(n, v, text, integer)
+	case decodeSlice:
+		return st.slice(n, v)
+	case decodeMap:
+		return st.object(n, v)
+	case decodeStruct:
+		return st.object(n, v)
+	}
+	return false
+}
+
+func (st *decodeState) validString(s *Schema, b []byte) bool {
+	if s.MinLength != nil && len(b) < *s.MinLength {
+		return false
+	}
+	if s.MaxLength != nil && len(b) > *s.MaxLength {
+		return false
+	}
+	if s.patternRe != nil && !s.patternRe.Match(b) {
+		return false
+	}
+	if s.ContentEncoding == "base64" && !rxBase64.Match(b) {
+		return false
+	}
+	if s.constValue != nil {
+		if c, ok := s.constValue.(string); !ok || c != string(b) {
+			return false
+		}
+	}
+	if s.Format != "" || len(s.Enum) > 0 {
+		str := string(b)
+		if len(s.Enum) > 0 && !enumContains(s.Enum, str) {
+			return false
+		}
+		if s.Format != "" {
+			validateFormat(&st.pb, str, s, &st.res)
+			if len(st.res.Errors) > 0 {
+				st.res.Reset()
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// setNumber parses the number for the node's type, rejecting anything which
+// `encoding/json` would fail to decode, and validates it like `Validate` does
+// using its float64 value.
+func (st *decodeState) setNumber(n *decodeNode, v reflect.Value, text []byte, integer bool) bool {
+	var num float64
+	switch n.kind {
+	case decodeInt:
+		if !integer {
+			return false
+		}
+		i, err := strconv.ParseInt(string(text), 10, 64)
+		if err != nil || v.OverflowInt(i) {
+			return false
+		}
+		v.SetInt(i)
+		num = float64(i)
+	case decodeUint:
+		if !integer || text[0] == '-' {
+			return false
+		}
+		u, err := strconv.ParseUint(string(text), 10, 64)
+		if err != nil || v.OverflowUint(u) {
+			return false
+		}
+		v.SetUint(u)
+		num = float64(u)
+	case decodeFloat:
+		f, err := strconv.ParseFloat(string(text), 64)
+		if err != nil {
+			return false
+		}
+		if v.Kind() == reflect.Float32 {
+			f32, err := strconv.ParseFloat(string(text), 32)
+			if err != nil {
+				return false
+			}
+			v.SetFloat(f32)
+		} else {
+			v.SetFloat(f)
+		}
+		num = f
+	}
+
+	s := n.schema
+	if s.Type == TypeInteger {
+		if num != math.Trunc(num) {
+			return false
+		}
+		if s.integerRangeMsg(num) != "" {
+			return false
+		}
+	}
+	if s.Minimum != nil && num < *s.Minimum {
+		return false
+	}
+	if s.ExclusiveMinimum != nil && num <= *s.ExclusiveMinimum {
+		return false
+	}
+	if s.Maximum != nil && num > *s.Maximum {
+		return false
+	}
+	if s.ExclusiveMaximum != nil && num >= *s.ExclusiveMaximum {
+		return false
+	}
+	if s.MultipleOf != nil && math.Mod(num, *s.MultipleOf) != 0 {
+		return false
+	}
+	if len(s.Enum) > 0 && !enumContains(s.Enum, num) {
+		return false
+	}
+	return constOK(s, num)
+}
+
+func (st *decodeState) slice(n *decodeNode, v reflect.Value) bool {
+	if st.i >= len(st.data) || st.data[st.i] != '[' {
+		return false
+	}
+	st.i++
+	s := n.schema
+	sv := reflect.MakeSlice(n.typ, 0, 0)
+	st.skipSpace()
+	if st.i < len(st.data) && st.data[st.i] == ']' {
+		st.i++
+	} else {
+		for {
+			st.skipSpace()
+			item := reflect.New(n.typ.Elem()).Elem()
+			if !st.value(n.elem, item) {
+				return false
+			}
+			sv = reflect.Append(sv, item)
+			st.skipSpace()
+			if st.i >= len(st.data) {
+				return false
+			}
+			if st.data[st.i] == ']' {
+				st.i++
+				break
+			}
+			if st.data[st.i] != ',' {
+				return false
+			}
+			st.i++
+		}
+	}
+	if s.MinItems != nil && sv.Len() < *s.MinItems {
+		return false
+	}
+	if s.MaxItems != nil && sv.Len() > *s.MaxItems {
+		return false
+	}
+	v.Set(sv)
+	return true
+}
+
+// object decodes a JSON object into a map or struct.
+func (st *decodeState) object(n *decodeNode, v reflect.Value) bool {
+	if st.i >= len(st.data) || st.data[st.i] != '{' {
+		return false
+	}
+	st.i++
+	s := n.schema
+
+	isMap := n.kind == decodeMap
+	if isMap {
+		v.Set(reflect.MakeMap(n.typ))
+	}
+
+	count := 0
+	required := 0
+	var seen uint64
+	st.skipSpace()
+	if st.i < len(st.data) && st.data[st.i] == '}' {
+		st.i++
+	} else {
+		for {
+			st.skipSpace()
+			key, ok := st.str()
+			if !ok {
+				return false
+			}
+			st.skipSpace()
+			if st.i >= len(st.data) || st.data[st.i] != ':' {
+				return false
+			}
+			st.i++
+			st.skipSpace()
+			count++
+
+			if isMap {
+				k := reflect.ValueOf(string(key)).Convert(n.typ.Key())
+				if v.MapIndex(k).IsValid() {
+					// Duplicate keys are left to the regular path.
+					return false
+				}
+				item := reflect.New(n.typ.Elem()).Elem()
+				if !st.value(n.elem, item) {
+					return false
+				}
+				v.SetMapIndex(k, item)
+			} else {
+				f := n.fields[string(key)]
+				if f == nil {
+					// Unknown or undecoded property, or a case-insensitive match.
+					return false
+				}
+				if seen&(1<<f.id) != 0 {
+					// Duplicate keys are left to the regular path.
+					return false
+				}
+				seen |= 1 << f.id
+				if st.literal("null") {
+					// Treated as missing, see `Validate`.
+					if f.required {
+						return false
+					}
+				} else {
+					if !st.value(f.node, v.FieldByIndex(f.index)) {
+						return false
+					}
+					if f.required {
+						required++
+					}
+				}
+			}
+
+			st.skipSpace()
+			if st.i >= len(st.data) {
+				return false
+			}
+			if st.data[st.i] == '}' {
+				st.i++
+				break
+			}
+			if st.data[st.i] != ',' {
+				return false
+			}
+			st.i++
+		}
+	}
+
+	if required < n.required {
+		return false
+	}
+	if s.MinProperties != nil && count < *s.MinProperties {
+		return false
+	}
+	if s.MaxProperties != nil && count > *s.MaxProperties {
+		return false
+	}
+	return true
+}
+
+// constOK returns whether the decoded scalar matches the schema's constant,
+// if it has one.
+func constOK[T bool | float64](s *Schema, v T) bool {
+	if s.constValue == nil {
+		return true
+	}
+	c, ok := s.constValue.(T)
+	return ok && c == v
+}
+
+func enumContains(enum []any, v any) bool {
+	for _, e := range enum {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
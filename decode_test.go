@@ -0,0 +1,245 @@
+package huma
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type DecodeEmbedded struct {
+	Embedded string `json:"embedded,omitempty"`
+}
+
+type DecodeTree struct {
+	Name     string        `json:"name" minLength:"1"`
+	Children []*DecodeTree `json:"children,omitempty"`
+}
+
+type DecodeInput struct {
+	DecodeEmbedded
+	Name    string            `json:"name" maxLength:"10"`
+	Color   string            `json:"color,omitempty" enum:"red,green"`
+	Count   int               `json:"count,omitempty" minimum:"1"`
+	Small   int8              `json:"small,omitempty"`
+	Uint    uint              `json:"uint,omitempty"`
+	Ratio   float32           `json:"ratio,omitempty" exclusiveMaximum:"1"`
+	Enabled *bool             `json:"enabled,omitempty"`
+	Tags    []string          `json:"tags,omitempty" maxItems:"2"`
+	Labels  map[string]int    `json:"labels,omitempty"`
+	Created time.Time         `json:"created,omitempty"`
+	Email   string            `json:"email,omitempty" format:"email"`
+	ID      string            `json:"id,omitempty" readOnly:"true"`
+	Tree    *DecodeTree       `json:"tree,omitempty"`
+	Nested  []map[string]bool `json:"nested,omitempty"`
+	Kind    string            `json:"kind,omitempty" const:"thing"`
+	Version int               `json:"version,omitempty" const:"2"`
+	Offset  int64             `json:"offset,omitempty" format:"int32"`
+}
+
+func TestBodyDecoder(t *testing.T) {
+	for _, item := range []struct {
+		name string
+		body string
+		ok   bool
+	}{
+		{"minimal", `{"name": "foo"}`, true},
+		{"full", `{
+			"embedded": "e",
+			"name": "foo",
+			"color": "red",
+			"count": 5,
+			"small": -12,
+			"uint": 7,
+			"ratio": 0.5,
+			"enabled": false,
+			"tags": ["a", "b"],
+			"labels": {"x": 1, "y": 2},
+			"created": "2023-01-01T12:00:00Z",
+			"email": "alice@example.com",
+			"id": "abc",
+			"tree": {"name": "root", "children": [{"name": "leaf"}]},
+			"nested": [{"a": true}, {}],
+			"kind": "thing",
+			"version": 2,
+			"offset": -5
+		}`, true},
+		{"escapes", `{"name": "\"\\é\n😀"}`, true},
+		{"surrogate pair", `{"name": "\ud83d\ude00"}`, true},
+		{"unicode", `{"name": "héllo"}`, true},
+		{"null optional", `{"name": "foo", "tags": null, "enabled": null}`, true},
+		{"empty array", `{"name": "foo", "tags": []}`, true},
+		{"whitespace", " \n{ \"name\" : \"foo\" } \t", true},
+		{"missing required", `{}`, false},
+		{"null required", `{"name": null}`, false},
+		{"too long", `{"name": "12345678901"}`, false},
+		{"bad enum", `{"name": "foo", "color": "blue"}`, false},
+		{"minimum", `{"name": "foo", "count": 0}`, false},
+		{"float for int", `{"name": "foo", "count": 1.5}`, false},
+		{"exponent for int", `{"name": "foo", "count": 1e2}`, false},
+		{"overflow", `{"name": "foo", "small": 300}`, false},
+		{"negative uint", `{"name": "foo", "uint": -1}`, false},
+		{"format range", `{"name": "foo", "offset": 2147483648}`, false},
+		{"exclusive maximum", `{"name": "foo", "ratio": 1}`, false},
+		{"max items", `{"name": "foo", "tags": ["a", "b", "c"]}`, false},
+		{"bad map value", `{"name": "foo", "labels": {"x": "y"}}`, false},
+		{"null map value", `{"name": "foo", "labels": {"x": null}}`, false},
+		{"bad time", `{"name": "foo", "created": "yesterday"}`, false},
+		{"bad format", `{"name": "foo", "email": "nope"}`, false},
+		{"bad const", `{"name": "foo", "kind": "other"}`, false},
+		{"bad numeric const", `{"name": "foo", "version": 1}`, false},
+		{"recursive", `{"name": "foo", "tree": {"name": "root", "children": [{"name": ""}]}}`, false},
+		{"unknown property", `{"name": "foo", "other": 1}`, false},
+		{"case insensitive", `{"NAME": "foo"}`, false},
+		{"duplicate", `{"name": "foo", "name": "bar"}`, false},
+		{"duplicate map key", `{"name": "foo", "labels": {"x": 1, "x": 2}}`, false},
+		{"wrong type", `{"name": 1}`, false},
+		{"null body", `null`, false},
+		{"trailing data", `{"name": "foo"} {}`, false},
+		{"truncated", `{"name": "foo"`, false},
+		{"trailing comma", `{"name": "foo",}`, false},
+		{"bad escape", `{"name": "\x"}`, false},
+		{"lone surrogate", `{"name": "\ud83d"}`, false},
+		{"invalid utf-8", "{\"name\": \"\xff\"}", false},
+		{"leading zero", `{"name": "foo", "count": 01}`, false},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+			typ := reflect.TypeOf(DecodeInput{})
+			s := registry.Schema(typ, true, "DecodeInput")
+			dec := compileBodyDecoder(registry, s, typ, false)
+			if !assert.NotNil(t, dec) {
+				return
+			}
+
+			var decoded DecodeInput
+			ok := dec.Decode([]byte(item.body), reflect.ValueOf(&decoded).Elem())
+			assert.Equal(t, item.ok, ok)
+			if !ok {
+				assert.Equal(t, DecodeInput{}, decoded, "modified on failure")
+				return
+			}
+
+			// The result must match the regular validate & unmarshal path.
+			var parsed any
+			assert.NoError(t, json.Unmarshal([]byte(item.body), &parsed))
+			res := &ValidateResult{}
+			Validate(registry, s, NewPathBuffer([]byte{}, 0), ModeWriteToServer, parsed, res)
+			assert.Empty(t, res.Errors)
+
+			var expected DecodeInput
+			assert.NoError(t, json.Unmarshal([]byte(item.body), &expected))
+			assert.Equal(t, expected, decoded)
+		})
+	}
+}
+
+func TestBodyDecoderValidateTests(t *testing.T) {
+	// The decoder must never accept input which `Validate` rejects.
+	for _, test := range validateTests {
+		if test.panic != "" || test.mode != ModeWriteToServer {
+			continue
+		}
+		t.Run(test.name, func(t *testing.T) {
+			registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+			s := registry.Schema(test.typ, false, "TestInput")
+			dec := compileBodyDecoder(registry, s, test.typ, false)
+			if dec == nil {
+				return
+			}
+			body, err := json.Marshal(test.input)
+			if err != nil {
+				return
+			}
+			v := reflect.New(test.typ).Elem()
+			if dec.Decode(body, v) {
+				assert.Empty(t, test.errs)
+			}
+		})
+	}
+}
+
+func TestBodyDecoderUnsupported(t *testing.T) {
+	for _, typ := range []reflect.Type{
+		reflect.TypeOf(struct {
+			Value any `json:"value"`
+		}{}),
+		reflect.TypeOf(struct {
+			Value json.RawMessage `json:"value"`
+		}{}),
+		reflect.TypeOf(struct {
+			Value []byte `json:"value"`
+		}{}),
+		reflect.TypeOf(struct {
+			Value []string `json:"value" uniqueItems:"true"`
+		}{}),
+		reflect.TypeOf(struct {
+			*DecodeEmbedded
+		}{}),
+		reflect.TypeOf(struct {
+			Value []string `json:"value" const:"a,b"`
+		}{}),
+	} {
+		registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+		s := registry.Schema(typ, false, "")
+		assert.Nil(t, compileBodyDecoder(registry, s, typ, false), typ.String())
+	}
+}
+
+func TestBodyDecoderShadowing(t *testing.T) {
+	// The outer field shadows the embedded one, like with `encoding/json`.
+	type Input struct {
+		DecodeEmbedded
+		Embedded int `json:"embedded"`
+	}
+	typ := reflect.TypeOf(Input{})
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	s := registry.Schema(typ, false, "")
+	dec := compileBodyDecoder(registry, s, typ, false)
+	if !assert.NotNil(t, dec) {
+		return
+	}
+
+	body := []byte(`{"embedded": 5}`)
+	v := reflect.New(typ).Elem()
+	assert.True(t, dec.Decode(body, v))
+
+	var expected Input
+	assert.NoError(t, json.Unmarshal(body, &expected))
+	assert.Equal(t, expected, v.Interface())
+}
+
+func BenchmarkBodyDecoder(b *testing.B) {
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	typ := reflect.TypeOf(DecodeInput{})
+	s := registry.Schema(typ, true, "DecodeInput")
+	dec := compileBodyDecoder(registry, s, typ, false)
+	body := []byte(`{"name": "foo", "color": "red", "count": 5, "tags": ["a", "b"], "labels": {"x": 1}, "tree": {"name": "root"}}`)
+
+	b.Run("compiled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v DecodeInput
+			if !dec.Decode(body, reflect.ValueOf(&v).Elem()) {
+				b.Fatal("decode failed")
+			}
+		}
+	})
+
+	b.Run("regular", func(b *testing.B) {
+		pb := NewPathBuffer([]byte{}, 0)
+		res := &ValidateResult{}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var parsed any
+			json.Unmarshal(body, &parsed)
+			pb.Reset()
+			res.Reset()
+			Validate(registry, s, pb, ModeWriteToServer, parsed, res)
+			var v DecodeInput
+			json.Unmarshal(body, &v)
+		}
+	})
+}
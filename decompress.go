@@ -0,0 +1,204 @@
+package huma
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Decompressor describes a supported request body encoding.
+type Decompressor struct {
+	// Encoding is the name used in the `Content-Encoding` header, e.g. `gzip`.
+	Encoding string
+
+	// New creates a reader which decompresses the body.
+	New func(r io.Reader) (io.ReadCloser, error)
+}
+
+// DefaultDecompressors supports gzip and deflate (zlib) request bodies.
+// Importing the `brotli` package adds Brotli.
+var DefaultDecompressors = []Decompressor{
+	{"gzip", func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }},
+	{"deflate", func(r io.Reader) (io.ReadCloser, error) { return zlib.NewReader(r) }},
+}
+
+// DecompressionConfig controls opt-in decompression of request bodies sent
+// with a `Content-Encoding` header. Bodies are decompressed as they are read,
+// before validation, and handlers see the decoded body without the
+// `Content-Encoding` & `Content-Length` headers. See `Config.Decompression`.
+type DecompressionConfig struct {
+	// MaxBytes is the maximum size of a decompressed body, protecting against
+	// decompression bombs where a small body expands to use all available
+	// memory. Larger bodies get a 413 error. Operations' `MaxBodyBytes` still
+	// apply to the decompressed body. Defaults to 10MB.
+	MaxBytes int64
+
+	// Decompressors lists the supported encodings. Requests using other
+	// encodings get a 415 error listing the supported ones in the
+	// `Accept-Encoding` header. Defaults to `DefaultDecompressors`.
+	Decompressors []Decompressor
+}
+
+type decompressAdapter struct {
+	Adapter
+	api           API
+	config        DecompressionConfig
+	decompressors map[string]Decompressor
+	accept        string
+}
+
+func newDecompressAdapter(a Adapter, config DecompressionConfig) *decompressAdapter {
+	if config.MaxBytes == 0 {
+		config.MaxBytes = 10 * 1024 * 1024
+	}
+	if config.Decompressors == nil {
+		config.Decompressors = DefaultDecompressors
+	}
+
+	da := &decompressAdapter{
+		Adapter:       a,
+		config:        config,
+		decompressors: make(map[string]Decompressor, len(config.Decompressors)),
+	}
+	encodings := make([]string, 0, len(config.Decompressors))
+	for _, d := range config.Decompressors {
+		da.decompressors[strings.ToLower(d.Encoding)] = d
+		encodings = append(encodings, d.Encoding)
+	}
+	da.accept = strings.Join(encodings, ", ")
+	return da
+}
+
+func (a *decompressAdapter) Handle(op *Operation, handler func(Context)) {
+	a.Adapter.Handle(op, func(ctx Context) {
+		header := ctx.Header("Content-Encoding")
+		if header == "" {
+			handler(ctx)
+			return
+		}
+
+		// Encodings are listed in the order they were applied, so are removed
+		// in reverse order.
+		chain := []Decompressor{}
+		for _, encoding := range strings.Split(header, ",") {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			if encoding == "" || encoding == "identity" {
+				continue
+			}
+			d, ok := a.decompressors[encoding]
+			if !ok {
+				ctx.SetHeader("Accept-Encoding", a.accept)
+				WriteErr(a.api, ctx, http.StatusUnsupportedMediaType, fmt.Sprintf("unsupported content encoding %s", encoding))
+				return
+			}
+			chain = append([]Decompressor{d}, chain...)
+		}
+		if len(chain) == 0 {
+			handler(ctx)
+			return
+		}
+
+		dc := &decompressContext{
+			humaContext: ctx,
+			reader: &decompressReader{
+				body:  ctx.BodyReader(),
+				chain: chain,
+				limit: a.config.MaxBytes,
+			},
+		}
+		defer dc.reader.Close()
+		handler(dc)
+	})
+}
+
+// decompressContext provides the decompressed request body, hiding the
+// headers which describe the compressed body.
+type decompressContext struct {
+	humaContext
+	reader *decompressReader
+}
+
+// Unwrap returns the wrapped context, see `UnwrapContext`.
+func (c *decompressContext) Unwrap() Context {
+	return c.humaContext
+}
+
+func (c *decompressContext) Header(name string) string {
+	if strings.EqualFold(name, "Content-Encoding") || strings.EqualFold(name, "Content-Length") {
+		return ""
+	}
+	return c.humaContext.Header(name)
+}
+
+func (c *decompressContext) EachHeader(cb func(name, value string)) {
+	c.humaContext.EachHeader(func(name, value string) {
+		if strings.EqualFold(name, "Content-Encoding") || strings.EqualFold(name, "Content-Length") {
+			return
+		}
+		cb(name, value)
+	})
+}
+
+func (c *decompressContext) BodyReader() io.Reader {
+	return c.reader
+}
+
+func (c *decompressContext) Flush() error {
+	return Flush(c.humaContext)
+}
+
+// decompressReader lazily decompresses the body on first read, so invalid
+// bodies are reported while reading them like other read errors. Errors are
+// returned as a `StatusError` with a 400 or 413 status.
+type decompressReader struct {
+	body    io.Reader
+	chain   []Decompressor
+	limit   int64
+	readers []io.ReadCloser
+	r       io.Reader
+	n       int64
+	err     error
+}
+
+func (d *decompressReader) Read(p []byte) (int, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+	if d.r == nil {
+		d.r = d.body
+		for _, dec := range d.chain {
+			rc, err := dec.New(d.r)
+			if err != nil {
+				d.err = NewError(http.StatusBadRequest, "invalid compressed request body", err)
+				return 0, d.err
+			}
+			d.readers = append(d.readers, rc)
+			d.r = rc
+		}
+	}
+
+	n, err := d.r.Read(p)
+	d.n += int64(n)
+	if d.n > d.limit {
+		n -= int(d.n - d.limit)
+		d.err = NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("decompressed request body is too large limit=%d bytes", d.limit))
+		return n, d.err
+	}
+	if err != nil && err != io.EOF {
+		d.err = NewError(http.StatusBadRequest, "invalid compressed request body", err)
+		return n, d.err
+	}
+	return n, err
+}
+
+// Close the decompressors.
+func (d *decompressReader) Close() error {
+	for _, rc := range d.readers {
+		rc.Close()
+	}
+	d.readers = nil
+	return nil
+}
@@ -0,0 +1,114 @@
+package huma
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func compressBody(encoding, body string) []byte {
+	buf := &bytes.Buffer{}
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(buf)
+	case "deflate":
+		w = zlib.NewWriter(buf)
+	}
+	w.Write([]byte(body))
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestDecompression(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Decompression = &DecompressionConfig{MaxBytes: 1000}
+	app := NewTestAdapter(r, config)
+	Register(app, Operation{
+		OperationID:  "decompress",
+		Method:       http.MethodPost,
+		Path:         "/decompress",
+		MaxBodyBytes: 500,
+	}, func(ctx context.Context, input *struct {
+		Encoding string `header:"Content-Encoding"`
+		Body     struct {
+			Name string `json:"name" maxLength:"5"`
+			Pad  string `json:"pad,omitempty"`
+		}
+	}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: input.Body.Name + input.Encoding}, nil
+	})
+
+	for _, item := range []struct {
+		name     string
+		encoding string
+		body     []byte
+		status   int
+		resp     string
+	}{
+		{"identity", "", []byte(`{"name": "plain"}`), http.StatusOK, `"plain"`},
+		{"gzip", "gzip", compressBody("gzip", `{"name": "gzip"}`), http.StatusOK, `"gzip"`},
+		{"deflate", "deflate", compressBody("deflate", `{"name": "zlib"}`), http.StatusOK, `"zlib"`},
+		{"chain", "gzip, deflate", compressBody("deflate", string(compressBody("gzip", `{"name": "both"}`))), http.StatusOK, `"both"`},
+		{"validated", "gzip", compressBody("gzip", `{"name": "too long"}`), http.StatusUnprocessableEntity, ""},
+		{"corrupt", "gzip", []byte(`{"name": "plain"}`), http.StatusBadRequest, ""},
+		{"unsupported", "zstd", []byte(`{}`), http.StatusUnsupportedMediaType, ""},
+		{"operation limit", "gzip", compressBody("gzip", `{"name": "a", "pad": "`+strings.Repeat("a", 600)+`"}`), http.StatusRequestEntityTooLarge, ""},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/decompress", bytes.NewReader(item.body))
+			req.Header.Set("Content-Type", "application/json")
+			if item.encoding != "" {
+				req.Header.Set("Content-Encoding", item.encoding)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, item.status, w.Code, w.Body.String())
+			if item.resp != "" {
+				// The handler doesn't see the encoding header.
+				assert.Equal(t, item.resp, strings.TrimSpace(w.Body.String()))
+			}
+			if item.status == http.StatusUnsupportedMediaType {
+				assert.Equal(t, "gzip, deflate", w.Header().Get("Accept-Encoding"))
+			}
+		})
+	}
+}
+
+func TestDecompressionBomb(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Decompression = &DecompressionConfig{MaxBytes: 1000}
+	app := NewTestAdapter(r, config)
+	Register(app, Operation{
+		OperationID:  "upload",
+		Method:       http.MethodPost,
+		Path:         "/upload",
+		MaxBodyBytes: -1,
+	}, func(ctx context.Context, input *struct {
+		RawBody []byte
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	// A tiny body which expands well past the limit.
+	body := compressBody("gzip", strings.Repeat("a", 100000))
+	assert.Less(t, len(body), 1000)
+
+	req, _ := http.NewRequest(http.MethodPost, "/upload", bytes.NewReader(body))
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "limit=1000")
+}
@@ -10,10 +10,13 @@ import (
 // DefaultJSONFormat is the default JSON formatter that can be set in the API's
 // `Config.Formats` map.
 var DefaultJSONFormat = Format{
-	Marshal: func(w io.Writer, v any) error {
-		return json.NewEncoder(w).Encode(v)
-	},
+	Marshal:   jsonMarshal,
 	Unmarshal: json.Unmarshal,
+	JSON:      true,
+}
+
+func jsonMarshal(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
 }
 
 var cborEncMode, _ = cbor.EncOptions{
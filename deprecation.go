@@ -0,0 +1,63 @@
+package huma
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// deprecationHeaders returns the headers sent with every response of a
+// deprecated operation, or nil if the operation isn't deprecated. Panics if
+// the deprecation details are set for an operation which isn't deprecated.
+func deprecationHeaders(op *Operation) http.Header {
+	if !op.Deprecated {
+		if !op.DeprecatedSince.IsZero() || !op.Sunset.IsZero() || op.SuccessorVersion != "" {
+			panic(fmt.Sprintf("operation %s: deprecation details require the operation to be deprecated", op.OperationID))
+		}
+		return nil
+	}
+	if !op.DeprecatedSince.IsZero() && !op.Sunset.IsZero() && op.Sunset.Before(op.DeprecatedSince) {
+		panic(fmt.Sprintf("operation %s: sunset must not be before the deprecation", op.OperationID))
+	}
+
+	headers := http.Header{}
+	if op.DeprecatedSince.IsZero() {
+		headers.Set("Deprecation", "true")
+	} else {
+		headers.Set("Deprecation", "@"+strconv.FormatInt(op.DeprecatedSince.Unix(), 10))
+	}
+	if !op.Sunset.IsZero() {
+		headers.Set("Sunset", op.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if op.SuccessorVersion != "" {
+		headers.Set("Link", "<"+op.SuccessorVersion+">; rel=\"successor-version\"")
+	}
+	return headers
+}
+
+// documentDeprecation documents the deprecation headers for each of the
+// operation's responses, unless a response already documents them.
+func documentDeprecation(op *Operation, headers http.Header) {
+	descriptions := map[string]string{
+		"Deprecation": "When the operation was deprecated, as a Unix timestamp prefixed with `@`, or `true`.",
+		"Sunset":      "When the operation will stop working.",
+		"Link":        "Link to the successor version of the operation.",
+	}
+	for _, resp := range op.Responses {
+		if resp.Ref != "" {
+			continue
+		}
+		for name := range headers {
+			if resp.Headers[name] != nil {
+				continue
+			}
+			if resp.Headers == nil {
+				resp.Headers = map[string]*Param{}
+			}
+			resp.Headers[name] = &Header{
+				Description: descriptions[name],
+				Schema:      &Schema{Type: TypeString, Examples: []any{headers.Get(name)}},
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecationHeaders(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	type Output struct {
+		Body string
+	}
+
+	Register(app, Operation{
+		OperationID:      "get-thing-v1",
+		Method:           http.MethodGet,
+		Path:             "/v1/things/{id}",
+		Deprecated:       true,
+		DeprecatedSince:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:           time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
+		SuccessorVersion: "https://example.com/v2/things",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id" maxLength:"5"`
+	}) (*Output, error) {
+		return &Output{Body: "hello"}, nil
+	})
+	Register(app, Operation{
+		OperationID: "list-things-v1",
+		Method:      http.MethodGet,
+		Path:        "/v1/things",
+		Deprecated:  true,
+	}, func(ctx context.Context, input *struct{}) (*Output, error) {
+		return &Output{Body: "hello"}, nil
+	})
+	Register(app, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/v2/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*Output, error) {
+		return &Output{Body: "hello"}, nil
+	})
+
+	for _, item := range []struct {
+		name        string
+		path        string
+		status      int
+		deprecation string
+		sunset      string
+		link        string
+	}{
+		{"details", "/v1/things/123", http.StatusOK, "@1704067200", "Tue, 31 Dec 2024 23:59:59 GMT", `<https://example.com/v2/things>; rel="successor-version"`},
+		{"error", "/v1/things/toolong", http.StatusUnprocessableEntity, "@1704067200", "Tue, 31 Dec 2024 23:59:59 GMT", `<https://example.com/v2/things>; rel="successor-version"`},
+		{"no details", "/v1/things", http.StatusOK, "true", "", ""},
+		{"not deprecated", "/v2/things/123", http.StatusOK, "", "", ""},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, item.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, item.status, w.Code, w.Body.String())
+			assert.Equal(t, item.deprecation, w.Header().Get("Deprecation"))
+			assert.Equal(t, item.sunset, w.Header().Get("Sunset"))
+			assert.Equal(t, item.link, w.Header().Get("Link"))
+		})
+	}
+
+	op := app.OpenAPI().Paths["/v1/things/{id}"].Get
+	for status, resp := range op.Responses {
+		assert.NotNil(t, resp.Headers["Deprecation"], status)
+		assert.NotNil(t, resp.Headers["Sunset"], status)
+		assert.NotNil(t, resp.Headers["Link"], status)
+	}
+	assert.Equal(t, []any{"@1704067200"}, op.Responses["200"].Headers["Deprecation"].Schema.Examples)
+
+	op = app.OpenAPI().Paths["/v1/things"].Get
+	assert.NotNil(t, op.Responses["200"].Headers["Deprecation"])
+	assert.Nil(t, op.Responses["200"].Headers["Sunset"])
+
+	assert.Nil(t, app.OpenAPI().Paths["/v2/things/{id}"].Get.Responses["200"].Headers["Deprecation"])
+}
+
+func TestDeprecationInvalid(t *testing.T) {
+	app := NewTestAdapter(chi.NewRouter(), DefaultConfig("Test API", "1.0.0"))
+
+	assert.PanicsWithValue(t, "operation sunset: deprecation details require the operation to be deprecated", func() {
+		Register(app, Operation{
+			OperationID: "sunset",
+			Method:      http.MethodGet,
+			Path:        "/sunset",
+			Sunset:      time.Now(),
+		}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+
+	assert.PanicsWithValue(t, "operation early: sunset must not be before the deprecation", func() {
+		Register(app, Operation{
+			OperationID:     "early",
+			Method:          http.MethodGet,
+			Path:            "/early",
+			Deprecated:      true,
+			DeprecatedSince: time.Now(),
+			Sunset:          time.Now().Add(-time.Hour),
+		}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
@@ -0,0 +1,133 @@
+package huma
+
+import (
+	"html/template"
+	"io"
+)
+
+// DocsData is passed to a `DocsUI` when rendering the documentation page.
+type DocsData struct {
+	// Title of the page, usually the API title from the OpenAPI info.
+	Title string
+
+	// SpecURL is the URL of the OpenAPI spec, e.g. `/openapi.yaml`.
+	SpecURL string
+
+	// AssetsURL is the base URL for JavaScript & CSS assets used by the page.
+	AssetsURL string
+}
+
+// DocsUI renders an interactive documentation page for the API. See
+// `Config.DocsUI`.
+type DocsUI interface {
+	Render(w io.Writer, data DocsData) error
+}
+
+// DocsTemplate is a `DocsUI` rendered from an HTML template, which is passed
+// `DocsData`. Set `AssetsURL` to serve assets from your own server rather
+// than a public CDN, which works with a stricter Content Security Policy.
+type DocsTemplate struct {
+	Template  *template.Template
+	AssetsURL string
+}
+
+// NewDocsTemplate creates a `DocsUI` from a custom HTML template string. It
+// panics if the template is invalid.
+func NewDocsTemplate(tmpl string, assetsURL string) *DocsTemplate {
+	return &DocsTemplate{
+		Template:  template.Must(template.New("docs").Parse(tmpl)),
+		AssetsURL: assetsURL,
+	}
+}
+
+func (d *DocsTemplate) Render(w io.Writer, data DocsData) error {
+	if data.AssetsURL == "" {
+		data.AssetsURL = d.AssetsURL
+	}
+	return d.Template.Execute(w, data)
+}
+
+// StoplightElements renders docs using Stoplight Elements. This is the
+// default. Assets are `web-components.min.js` and `styles.min.css`.
+// https://stoplight.io/open-source/elements
+func StoplightElements() *DocsTemplate {
+	return NewDocsTemplate(`<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1, shrink-to-fit=no">
+    <title>{{.Title}}</title>
+    <script src="{{.AssetsURL}}/web-components.min.js"></script>
+    <link rel="stylesheet" href="{{.AssetsURL}}/styles.min.css">
+  </head>
+  <body>
+    <elements-api
+      apiDescriptionUrl="{{.SpecURL}}"
+      router="hash"
+      layout="sidebar"
+    />
+  </body>
+</html>`, "https://unpkg.com/@stoplight/elements")
+}
+
+// SwaggerUI renders docs using Swagger UI. Assets are `swagger-ui.css` and
+// `swagger-ui-bundle.js`. The page uses a small inline script to start
+// Swagger UI, so use a custom template if your Content Security Policy
+// forbids inline scripts.
+// https://swagger.io/tools/swagger-ui/
+func SwaggerUI() *DocsTemplate {
+	return NewDocsTemplate(`<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1, shrink-to-fit=no">
+    <title>{{.Title}}</title>
+    <link rel="stylesheet" href="{{.AssetsURL}}/swagger-ui.css">
+  </head>
+  <body>
+    <div id="swagger-ui"></div>
+    <script src="{{.AssetsURL}}/swagger-ui-bundle.js"></script>
+    <script>
+      window.ui = SwaggerUIBundle({
+        url: "{{.SpecURL}}",
+        dom_id: "#swagger-ui",
+      });
+    </script>
+  </body>
+</html>`, "https://unpkg.com/swagger-ui-dist@5")
+}
+
+// Redoc renders docs using Redoc. The asset is `redoc.standalone.js`.
+// https://redocly.com/redoc/
+func Redoc() *DocsTemplate {
+	return NewDocsTemplate(`<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1, shrink-to-fit=no">
+    <title>{{.Title}}</title>
+  </head>
+  <body>
+    <redoc spec-url="{{.SpecURL}}"></redoc>
+    <script src="{{.AssetsURL}}/redoc.standalone.js"></script>
+  </body>
+</html>`, "https://unpkg.com/redoc@2/bundles")
+}
+
+// Scalar renders docs using the Scalar API reference. The asset is
+// `standalone.js`.
+// https://github.com/scalar/scalar
+func Scalar() *DocsTemplate {
+	return NewDocsTemplate(`<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1, shrink-to-fit=no">
+    <title>{{.Title}}</title>
+  </head>
+  <body>
+    <script id="api-reference" data-url="{{.SpecURL}}"></script>
+    <script src="{{.AssetsURL}}/standalone.js"></script>
+  </body>
+</html>`, "https://cdn.jsdelivr.net/npm/@scalar/api-reference/dist/browser")
+}
@@ -0,0 +1,204 @@
+package huma
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/goccy/go-yaml"
+)
+
+// unsupported30 lists JSON Schema keywords which have no OpenAPI 3.0
+// equivalent and are dropped when downgrading.
+var unsupported30 = []string{
+	"$schema", "$id", "$comment", "contentMediaType", "contentSchema",
+	"patternProperties", "propertyNames", "dependentRequired",
+	"dependentSchemas", "unevaluatedProperties", "unevaluatedItems",
+	"prefixItems", "contains", "minContains", "maxContains", "if", "then",
+	"else",
+}
+
+// Downgrade converts this OpenAPI 3.1 spec to OpenAPI 3.0.3 and returns it as
+// JSON. This is useful for tools and gateways which do not yet support 3.1.
+// Schemas are converted where possible (e.g. `type: [T, "null"]` becomes
+// `nullable: true`, numeric `exclusiveMinimum` becomes a boolean) and
+// keywords without a 3.0 equivalent are removed.
+func (o *OpenAPI) Downgrade() ([]byte, error) {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var v map[string]any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	downgradeSpec(v)
+
+	return json.Marshal(v)
+}
+
+// DowngradeYAML converts this OpenAPI 3.1 spec to OpenAPI 3.0.3 and returns it
+// as YAML. See `Downgrade` for details.
+func (o *OpenAPI) DowngradeYAML() ([]byte, error) {
+	b, err := o.Downgrade()
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(b)
+}
+
+func downgradeSpec(v map[string]any) {
+	v["openapi"] = "3.0.3"
+	delete(v, "jsonSchemaDialect")
+	delete(v, "webhooks")
+
+	if info, ok := v["info"].(map[string]any); ok {
+		delete(info, "summary")
+		if license, ok := info["license"].(map[string]any); ok {
+			delete(license, "identifier")
+		}
+	}
+
+	if _, ok := v["paths"]; !ok {
+		// Paths are required in OpenAPI 3.0.
+		v["paths"] = map[string]any{}
+	}
+
+	if components, ok := v["components"].(map[string]any); ok {
+		delete(components, "pathItems")
+		if schemas, ok := components["schemas"].(map[string]any); ok {
+			for _, s := range schemas {
+				downgradeSchema(s)
+			}
+		}
+		for _, k := range []string{"parameters", "responses", "headers", "requestBodies"} {
+			downgradeSchemaRefs(components[k])
+		}
+	}
+
+	downgradeSchemaRefs(v)
+}
+
+// downgradeSchemaRefs finds and downgrades schemas in parameters, headers,
+// and media types, which all use the `schema` key.
+func downgradeSchemaRefs(v any) {
+	switch tv := v.(type) {
+	case map[string]any:
+		for k, item := range tv {
+			switch {
+			case k == "schema":
+				downgradeSchema(item)
+			case k == "example", k == "examples", k == "default", k == "components":
+				// Arbitrary user data or already handled above.
+			case len(k) > 2 && k[:2] == "x-":
+				// Extensions can contain anything.
+			default:
+				downgradeSchemaRefs(item)
+			}
+		}
+	case []any:
+		for _, item := range tv {
+			downgradeSchemaRefs(item)
+		}
+	}
+}
+
+func downgradeSchema(v any) {
+	s, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+
+	if types, ok := s["type"].([]any); ok {
+		nonNull := []any{}
+		for _, t := range types {
+			if t == "null" {
+				s["nullable"] = true
+			} else {
+				nonNull = append(nonNull, t)
+			}
+		}
+		if len(nonNull) == 1 {
+			s["type"] = nonNull[0]
+		} else {
+			// Multiple types can't be represented, so allow anything.
+			delete(s, "type")
+		}
+	} else if s["type"] == "null" {
+		s["nullable"] = true
+		delete(s, "type")
+	}
+
+	if examples, ok := s["examples"].([]any); ok {
+		if len(examples) > 0 {
+			s["example"] = examples[0]
+		}
+		delete(s, "examples")
+	}
+
+	if c, ok := s["const"]; ok {
+		s["enum"] = []any{c}
+		delete(s, "const")
+	}
+
+	downgradeExclusive(s, "exclusiveMinimum", "minimum", func(exclusive, inclusive float64) bool {
+		return exclusive >= inclusive
+	})
+	downgradeExclusive(s, "exclusiveMaximum", "maximum", func(exclusive, inclusive float64) bool {
+		return exclusive <= inclusive
+	})
+
+	if s["contentEncoding"] == "base64" {
+		s["format"] = "byte"
+	}
+	delete(s, "contentEncoding")
+
+	for _, k := range unsupported30 {
+		delete(s, k)
+	}
+
+	for _, k := range []string{"properties", "$defs"} {
+		if props, ok := s[k].(map[string]any); ok {
+			for _, p := range props {
+				downgradeSchema(p)
+			}
+		}
+	}
+	for _, k := range []string{"items", "additionalProperties", "not"} {
+		downgradeSchema(s[k])
+	}
+	for _, k := range []string{"allOf", "anyOf", "oneOf"} {
+		if list, ok := s[k].([]any); ok {
+			for _, item := range list {
+				downgradeSchema(item)
+			}
+		}
+	}
+}
+
+// downgradeExclusive converts a numeric 3.1 `exclusiveMinimum` or
+// `exclusiveMaximum` into the 3.0 boolean form. The `stricter` function
+// returns whether the exclusive value is at least as strict as any existing
+// inclusive value.
+func downgradeExclusive(s map[string]any, exclusiveKey, inclusiveKey string, stricter func(exclusive, inclusive float64) bool) {
+	n, ok := s[exclusiveKey].(json.Number)
+	if !ok {
+		return
+	}
+	delete(s, exclusiveKey)
+
+	if existing, ok := s[inclusiveKey].(json.Number); ok {
+		e, _ := n.Float64()
+		i, _ := existing.Float64()
+		if !stricter(e, i) {
+			// The existing inclusive bound is stricter, so keep it as-is.
+			return
+		}
+	}
+
+	s[inclusiveKey] = n
+	s[exclusiveKey] = true
+}
@@ -0,0 +1,177 @@
+package huma
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDowngrade(t *testing.T) {
+	min := 1.0
+	exclusiveMin := 0.0
+	exclusiveMax := 100.0
+
+	oapi := &OpenAPI{
+		OpenAPI:           "3.1.0",
+		Info:              &Info{Title: "Test API", Version: "1.0.0"},
+		JSONSchemaDialect: "removed",
+		Components: &Components{
+			Schemas: NewMapRegistry("#/components/schemas/", DefaultSchemaNamer),
+		},
+		Paths: map[string]*PathItem{
+			"/test": {
+				Get: &Operation{
+					Parameters: []*Param{
+						{
+							Name: "q",
+							In:   "query",
+							Schema: &Schema{
+								Type:             "integer",
+								Minimum:          &min,
+								ExclusiveMinimum: &exclusiveMin,
+								ExclusiveMaximum: &exclusiveMax,
+								Examples:         []any{5},
+							},
+						},
+					},
+					Responses: map[string]*Response{
+						"200": {
+							Description: "OK",
+							Content: map[string]*MediaType{
+								"application/json": {
+									Schema: &Schema{
+										Type: "object",
+										Properties: map[string]*Schema{
+											"const": {
+												ContentEncoding: "base64",
+												Extensions: map[string]any{
+													"type":  []any{"string", "null"},
+													"const": "foo",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := oapi.Downgrade()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"openapi": "3.0.3",
+		"info": {"title": "Test API", "version": "1.0.0"},
+		"components": {"schemas": {}},
+		"paths": {
+			"/test": {
+				"get": {
+					"parameters": [
+						{
+							"name": "q",
+							"in": "query",
+							"schema": {
+								"type": "integer",
+								"minimum": 1,
+								"maximum": 100,
+								"exclusiveMaximum": true,
+								"example": 5
+							}
+						}
+					],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {
+								"application/json": {
+									"schema": {
+										"type": "object",
+										"properties": {
+											"const": {
+												"type": "string",
+												"nullable": true,
+												"format": "byte",
+												"enum": ["foo"]
+											}
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}`, string(b))
+
+	y, err := oapi.DowngradeYAML()
+	assert.NoError(t, err)
+	assert.Contains(t, string(y), "openapi: 3.0.3")
+}
+
+func TestDowngradeComponents(t *testing.T) {
+	nullable := func() *Schema {
+		return &Schema{Type: "string", Extensions: map[string]any{"type": []any{"string", "null"}}}
+	}
+	oapi := &OpenAPI{
+		OpenAPI: "3.1.0",
+		Info:    &Info{Title: "Test API", Version: "1.0.0"},
+		Components: &Components{
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "Error",
+					Headers:     map[string]*Header{"X-Reason": {Schema: nullable()}},
+					Content:     map[string]*MediaType{"application/json": {Schema: nullable()}},
+				},
+			},
+			Headers: map[string]*Header{
+				"X-Trace": {Schema: nullable()},
+			},
+			RequestBodies: map[string]*RequestBody{
+				"Thing": {Content: map[string]*MediaType{"application/json": {Schema: nullable()}}},
+			},
+		},
+	}
+
+	b, err := oapi.Downgrade()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"openapi": "3.0.3",
+		"info": {"title": "Test API", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"responses": {
+				"Error": {
+					"description": "Error",
+					"headers": {"X-Reason": {"schema": {"type": "string", "nullable": true}}},
+					"content": {"application/json": {"schema": {"type": "string", "nullable": true}}}
+				}
+			},
+			"headers": {
+				"X-Trace": {"schema": {"type": "string", "nullable": true}}
+			},
+			"requestBodies": {
+				"Thing": {"content": {"application/json": {"schema": {"type": "string", "nullable": true}}}}
+			}
+		}
+	}`, string(b))
+}
+
+func TestDowngradeEndpoints(t *testing.T) {
+	r := chi.NewRouter()
+	NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	for _, path := range []string{"/openapi-3.0.json", "/openapi-3.0.yaml"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Contains(t, w.Body.String(), "3.0.3")
+	}
+}
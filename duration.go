@@ -0,0 +1,116 @@
+package huma
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDuration is returned when a duration string can't be parsed.
+var ErrInvalidDuration = errors.New("invalid ISO 8601 duration")
+
+// ParseDuration parses an ISO 8601 duration like `PT1H30M` or `P1DT12H` into
+// a `time.Duration`. Days are 24 hours and weeks are 7 days. Years and months
+// are rejected since their length varies. The smallest unit may have a
+// decimal fraction, e.g. `PT0.5S`, and a leading `-` negates the duration.
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	neg := false
+	if strings.HasPrefix(s, "-") {
+		neg = true
+		s = s[1:]
+	}
+	if len(s) < 3 || s[0] != 'P' {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, orig)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	for s != "" {
+		if s[0] == 'T' {
+			if inTime || len(s) == 1 {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, orig)
+			}
+			inTime = true
+			s = s[1:]
+			continue
+		}
+
+		i := 0
+		for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.' || s[i] == ',') {
+			i++
+		}
+		if i == 0 || i == len(s) {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, orig)
+		}
+		num, err := strconv.ParseFloat(strings.Replace(s[:i], ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, orig)
+		}
+
+		var unit time.Duration
+		switch {
+		case !inTime && s[i] == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && s[i] == 'D':
+			unit = 24 * time.Hour
+		case inTime && s[i] == 'H':
+			unit = time.Hour
+		case inTime && s[i] == 'M':
+			unit = time.Minute
+		case inTime && s[i] == 'S':
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, orig)
+		}
+		d += time.Duration(num * float64(unit))
+		s = s[i+1:]
+	}
+
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// FormatDuration formats a `time.Duration` as an ISO 8601 duration using
+// hours, minutes and seconds, e.g. `PT1H30M` or `PT0.5S`.
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	b := make([]byte, 0, 24)
+	if d < 0 {
+		b = append(b, '-')
+		d = -d
+	}
+	b = append(b, 'P', 'T')
+	if h := d / time.Hour; h > 0 {
+		b = strconv.AppendInt(b, int64(h), 10)
+		b = append(b, 'H')
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		b = strconv.AppendInt(b, int64(m), 10)
+		b = append(b, 'M')
+		d -= m * time.Minute
+	}
+	if d > 0 {
+		b = strconv.AppendFloat(b, d.Seconds(), 'f', -1, 64)
+		b = append(b, 'S')
+	}
+	return string(b)
+}
+
+// parseParamDuration parses an ISO 8601 duration, falling back to Go's
+// duration syntax like `1h30m` which is convenient in query strings.
+func parseParamDuration(value string) (time.Duration, error) {
+	if d, err := ParseDuration(value); err == nil {
+		return d, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,43 @@
+package huma
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	for _, item := range []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"PT0S", 0},
+		{"PT1H30M", 90 * time.Minute},
+		{"PT0.5S", 500 * time.Millisecond},
+		{"PT1,5S", 1500 * time.Millisecond},
+		{"P1DT12H", 36 * time.Hour},
+		{"P2W", 14 * 24 * time.Hour},
+		{"-PT5M", -5 * time.Minute},
+	} {
+		d, err := ParseDuration(item.input)
+		assert.NoError(t, err, item.input)
+		assert.Equal(t, item.expected, d, item.input)
+	}
+
+	for _, input := range []string{"", "P", "PT", "1H", "PT1D", "P1H", "P1Y", "P1M", "PTH", "PT1", "P1DT"} {
+		_, err := ParseDuration(input)
+		assert.ErrorIs(t, err, ErrInvalidDuration, input)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for _, d := range []time.Duration{0, time.Second, 90 * time.Minute, 36*time.Hour + 1500*time.Millisecond, -5 * time.Minute} {
+		s := FormatDuration(d)
+		parsed, err := ParseDuration(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, d, parsed, s)
+	}
+	assert.Equal(t, "PT1H30M", FormatDuration(90*time.Minute))
+	assert.Equal(t, "PT0.5S", FormatDuration(500*time.Millisecond))
+}
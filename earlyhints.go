@@ -0,0 +1,26 @@
+package huma
+
+import "net/http"
+
+// WriteEarlyHints sends a `103 Early Hints` interim response with a `Link`
+// header for each link, e.g. `</style.css>; rel=preload; as=style`, so
+// clients can start fetching resources while the response is still being
+// prepared. Call it from middleware, resolvers or a streaming response body
+// before the final status is set, or use `Operation.EarlyHints` to send the
+// same links for every request.
+//
+// The links are also sent with the final response. Adapters which can't send
+// interim responses, like Fiber, only send them with the final response.
+func WriteEarlyHints(ctx Context, links ...string) {
+	for _, link := range links {
+		ctx.AppendHeader("Link", link)
+	}
+	ctx.SetStatus(http.StatusEarlyHints)
+}
+
+// isInterim returns whether the status is for an informational interim
+// response, which is sent before the final response rather than replacing
+// it.
+func isInterim(status int) bool {
+	return status >= 100 && status < 200 && status != http.StatusSwitchingProtocols
+}
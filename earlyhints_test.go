@@ -0,0 +1,62 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEarlyHints(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Compression = &CompressionConfig{}
+	api := NewTestAdapter(r, config)
+
+	Register(api, Operation{
+		OperationID: "page",
+		Method:      http.MethodGet,
+		Path:        "/page",
+		EarlyHints:  []string{"</style.css>; rel=preload; as=style"},
+		Middlewares: []Middleware{func(ctx Context, next func(Context)) {
+			WriteEarlyHints(ctx, "</script.js>; rel=preload; as=script")
+			next(ctx)
+		}},
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: "hello"}, nil
+	})
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	interim := []int{}
+	links := [][]string{}
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			interim = append(interim, code)
+			links = append(links, header.Values("Link"))
+			return nil
+		},
+	}
+	req, _ := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL+"/page", nil)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []int{http.StatusEarlyHints, http.StatusEarlyHints}, interim)
+	// Middleware runs before the operation's hints are sent.
+	assert.Equal(t, []string{"</script.js>; rel=preload; as=script"}, links[0])
+	assert.Equal(t, []string{
+		"</script.js>; rel=preload; as=script",
+		"</style.css>; rel=preload; as=style",
+	}, links[1])
+
+	// The links are also sent with the final response.
+	assert.Len(t, resp.Header.Values("Link"), 2)
+}
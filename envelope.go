@@ -0,0 +1,124 @@
+package huma
+
+import (
+	"strings"
+)
+
+// Envelope is a standard wrapper for successful response bodies, used by
+// `EnvelopeTransformer`.
+type Envelope struct {
+	Data  any               `json:"data"`
+	Meta  map[string]any    `json:"meta,omitempty"`
+	Links map[string]string `json:"links,omitempty"`
+}
+
+// EnvelopeMetaProvider is implemented by response bodies which add metadata
+// to their envelope, like pagination info.
+//
+//	type ThingList []Thing
+//
+//	func (l ThingList) EnvelopeMeta() map[string]any {
+//		return map[string]any{"count": len(l)}
+//	}
+type EnvelopeMetaProvider interface {
+	EnvelopeMeta() map[string]any
+}
+
+// EnvelopeLinksProvider is implemented by response bodies which add links to
+// their envelope, like the `next` page.
+type EnvelopeLinksProvider interface {
+	EnvelopeLinks() map[string]string
+}
+
+// EnvelopeTransformer wraps successful response bodies in an `Envelope` like
+// `{"data": ..., "meta": {...}, "links": {...}}`, with the request ID and any
+// metadata & links provided by the body. See `NewEnvelopeTransformer`.
+type EnvelopeTransformer struct {
+	// RequestIDHeader is the request header whose value is added to the
+	// metadata as `requestId`, if set. Defaults to `X-Request-ID`.
+	RequestIDHeader string
+}
+
+// NewEnvelopeTransformer creates a new envelope transformer. Its
+// `OnAddOperation` method must be added to the OpenAPI's `OnAddOperation`
+// hooks to document the envelope, and its `Transform` method to the API's
+// transformers. Both must run before the other hooks & transformers so that
+// they see the original response bodies:
+//
+//	envelope := huma.NewEnvelopeTransformer()
+//	config.OnAddOperation = append([]huma.AddOpFunc{envelope.OnAddOperation}, config.OnAddOperation...)
+//	config.Transformers = append([]huma.Transformer{envelope.Transform}, config.Transformers...)
+func NewEnvelopeTransformer() *EnvelopeTransformer {
+	return &EnvelopeTransformer{
+		RequestIDHeader: "X-Request-ID",
+	}
+}
+
+// OnAddOperation wraps the schemas of the operation's successful responses in
+// the envelope.
+func (t *EnvelopeTransformer) OnAddOperation(oapi *OpenAPI, op *Operation) {
+	for status, resp := range op.Responses {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		for _, content := range resp.Content {
+			if content == nil || content.Schema == nil {
+				continue
+			}
+			content.Schema = envelopeSchema(content.Schema)
+		}
+	}
+}
+
+// envelopeSchema returns the schema of an envelope around `data`.
+func envelopeSchema(data *Schema) *Schema {
+	s := &Schema{
+		Type: TypeObject,
+		Properties: map[string]*Schema{
+			"data": data,
+			"meta": {
+				Type:        TypeObject,
+				Description: "Response metadata, like pagination info.",
+				Properties: map[string]*Schema{
+					"requestId": {Type: TypeString, Description: "The ID of the request."},
+				},
+				AdditionalProperties: true,
+			},
+			"links": {
+				Type:                 TypeObject,
+				Description:          "Related resources by relation, like the next page.",
+				AdditionalProperties: &Schema{Type: TypeString, Format: "uri-reference"},
+			},
+		},
+		Required:             []string{"data"},
+		AdditionalProperties: false,
+	}
+	for _, prop := range []*Schema{s.Properties["meta"], s.Properties["links"], s} {
+		prop.PrecomputeMessages()
+	}
+	return s
+}
+
+// Transform wraps successful response bodies in an `Envelope`.
+func (t *EnvelopeTransformer) Transform(ctx Context, status string, v any) (any, error) {
+	if !strings.HasPrefix(status, "2") {
+		return v, nil
+	}
+
+	env := &Envelope{Data: v}
+	if p, ok := v.(EnvelopeMetaProvider); ok {
+		env.Meta = p.EnvelopeMeta()
+	}
+	if p, ok := v.(EnvelopeLinksProvider); ok {
+		env.Links = p.EnvelopeLinks()
+	}
+	if t.RequestIDHeader != "" {
+		if id := ctx.Header(t.RequestIDHeader); id != "" {
+			if env.Meta == nil {
+				env.Meta = map[string]any{}
+			}
+			env.Meta["requestId"] = id
+		}
+	}
+	return env, nil
+}
@@ -0,0 +1,87 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type EnvelopeThing struct {
+	ID string `json:"id"`
+}
+
+type EnvelopeThingList []EnvelopeThing
+
+func (l EnvelopeThingList) EnvelopeMeta() map[string]any {
+	return map[string]any{"count": len(l)}
+}
+
+func (l EnvelopeThingList) EnvelopeLinks() map[string]string {
+	return map[string]string{"next": "/things?cursor=" + l[len(l)-1].ID}
+}
+
+func TestEnvelopeTransformer(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	envelope := NewEnvelopeTransformer()
+	config.OnAddOperation = append([]AddOpFunc{envelope.OnAddOperation}, config.OnAddOperation...)
+	config.Transformers = append([]Transformer{envelope.Transform}, config.Transformers...)
+	config.DebugValidateResponses = true
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body EnvelopeThingList }, error) {
+		return &struct{ Body EnvelopeThingList }{EnvelopeThingList{{ID: "a"}, {ID: "b"}}}, nil
+	})
+	Register(app, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{ Body EnvelopeThing }, error) {
+		if input.ID != "a" {
+			return nil, Error404NotFound("no thing")
+		}
+		return &struct{ Body EnvelopeThing }{EnvelopeThing{ID: "a"}}, nil
+	})
+
+	for _, item := range []struct {
+		url       string
+		requestID string
+		status    int
+		body      string
+	}{
+		{"/things", "req1", http.StatusOK, `{
+			"data": [{"id": "a"}, {"id": "b"}],
+			"meta": {"count": 2, "requestId": "req1"},
+			"links": {"next": "/things?cursor=b"}
+		}`},
+		{"/things/a", "", http.StatusOK, `{"data": {"id": "a"}}`},
+		{"/things/b", "req2", http.StatusNotFound, `{"$schema": "http:///schemas/ErrorModel.json", "title": "Not Found", "status": 404, "detail": "no thing"}`},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, item.url, nil)
+		if item.requestID != "" {
+			req.Header.Set("X-Request-ID", item.requestID)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, item.status, w.Code, item.url)
+		assert.JSONEq(t, item.body, w.Body.String(), item.url)
+	}
+
+	op := app.OpenAPI().Paths["/things/{id}"].Get
+	schema := op.Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, TypeObject, schema.Type)
+	assert.Equal(t, []string{"data"}, schema.Required)
+	assert.Equal(t, "#/components/schemas/EnvelopeThing", schema.Properties["data"].Ref)
+	assert.Contains(t, schema.Properties["meta"].Properties, "requestId")
+	assert.Empty(t, op.Responses["default"].Content["application/problem+json"].Schema.Properties)
+}
@@ -1,9 +1,14 @@
 package huma
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
+
+	"github.com/fxamacker/cbor/v2"
 )
 
 // ErrorDetailer returns error details for responses & debugging.
@@ -26,6 +31,11 @@ type ErrorDetail struct {
 	// the client didn't send extra whitespace or help when the client
 	// did not log an outgoing request.
 	Value any `json:"value,omitempty" doc:"The value at the given location"`
+
+	// Extensions are additional members, like an error code, which are
+	// serialized alongside the fields above. Names which conflict with those
+	// fields are ignored. Document them via `ErrorDetailExtensions`.
+	Extensions map[string]any `json:"-"`
 }
 
 // Error returns the error message / satisfies the `error` interface.
@@ -64,14 +74,176 @@ type ErrorModel struct {
 	// Errors provides an optional mechanism of passing additional error details
 	// as a list.
 	Errors []*ErrorDetail `json:"errors,omitempty" doc:"Optional list of individual error details"`
+
+	// Extensions are additional RFC 7807 problem members, like an error code,
+	// trace ID, or link to docs, which are serialized alongside the fields
+	// above. Names which conflict with those fields are ignored. Document them
+	// via `ErrorModelExtensions`.
+	Extensions map[string]any `json:"-"`
+}
+
+// ErrorModelExtensions documents the extension members which may be present
+// in `ErrorModel.Extensions`, by name. They are added to the generated error
+// schema, which always allows undocumented extension members. Set this before
+// registering operations.
+var ErrorModelExtensions = map[string]*Schema{}
+
+// ErrorDetailExtensions documents the extension members which may be present
+// in `ErrorDetail.Extensions`, see `ErrorModelExtensions`.
+var ErrorDetailExtensions = map[string]*Schema{}
+
+var errorModelMembers = []string{"type", "title", "status", "detail", "instance", "errors"}
+var errorDetailMembers = []string{"message", "location", "value"}
+
+// extendSchema allows extension members in an error schema and documents the
+// given ones.
+func extendSchema(s *Schema, extensions map[string]*Schema) *Schema {
+	s.AdditionalProperties = true
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if s.Properties[name] == nil {
+			s.Properties[name] = extensions[name]
+			s.propertyNames = append(s.propertyNames, name)
+		}
+	}
+	return s
+}
+
+// filterExtensions returns a copy of the extension members without any which
+// conflict with the given member names.
+func filterExtensions(extensions map[string]any, members []string) map[string]any {
+	ext := make(map[string]any, len(extensions))
+	for k, v := range extensions {
+		ext[k] = v
+	}
+	for _, k := range members {
+		delete(ext, k)
+	}
+	return ext
+}
+
+// marshalExtended marshals `v` as a JSON object and appends the extension
+// members to it, skipping any which conflict with the given member names.
+func marshalExtended(v any, extensions map[string]any, members []string) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extensions) == 0 {
+		return b, err
+	}
+	ext := filterExtensions(extensions, members)
+	if len(ext) == 0 {
+		return b, nil
+	}
+	eb, err := json.Marshal(ext)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > 2 {
+		b[len(b)-1] = ','
+		return append(b, eb[1:]...), nil
+	}
+	return eb, nil
+}
+
+// unmarshalExtensions returns the members of the JSON object which are not
+// in the given list of member names, or nil if there are none.
+func unmarshalExtensions(data []byte, members []string) (map[string]any, error) {
+	var all map[string]any
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for _, k := range members {
+		delete(all, k)
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+	return all, nil
+}
+
+// cborExtended marshals `v` as a CBOR map including the extension members,
+// skipping any which conflict with the given member names.
+func cborExtended(v any, extensions map[string]any, members []string) ([]byte, error) {
+	b, err := cborEncMode.Marshal(v)
+	if err != nil || len(extensions) == 0 {
+		return b, err
+	}
+	var m map[string]any
+	if err := cbor.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for k, v := range filterExtensions(extensions, members) {
+		m[k] = v
+	}
+	return cborEncMode.Marshal(m)
+}
+
+// TransformSchema documents `ErrorDetailExtensions`.
+func (e *ErrorDetail) TransformSchema(r Registry, s *Schema) *Schema {
+	return extendSchema(s, ErrorDetailExtensions)
+}
+
+// MarshalJSON includes any extension members.
+func (e ErrorDetail) MarshalJSON() ([]byte, error) {
+	type plain ErrorDetail
+	return marshalExtended(plain(e), e.Extensions, errorDetailMembers)
+}
+
+// MarshalCBOR includes any extension members.
+func (e ErrorDetail) MarshalCBOR() ([]byte, error) {
+	type plain ErrorDetail
+	return cborExtended(plain(e), e.Extensions, errorDetailMembers)
+}
+
+// UnmarshalJSON collects unknown members into `Extensions`.
+func (e *ErrorDetail) UnmarshalJSON(data []byte) error {
+	type plain ErrorDetail
+	if err := json.Unmarshal(data, (*plain)(e)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data, errorDetailMembers)
+	e.Extensions = ext
+	return err
 }
 
 func (e *ErrorModel) Error() string {
 	return e.Detail
 }
 
+// TransformSchema documents `ErrorModelExtensions`.
+func (e *ErrorModel) TransformSchema(r Registry, s *Schema) *Schema {
+	return extendSchema(s, ErrorModelExtensions)
+}
+
+// MarshalJSON includes any extension members.
+func (e ErrorModel) MarshalJSON() ([]byte, error) {
+	type plain ErrorModel
+	return marshalExtended(plain(e), e.Extensions, errorModelMembers)
+}
+
+// MarshalCBOR includes any extension members.
+func (e ErrorModel) MarshalCBOR() ([]byte, error) {
+	type plain ErrorModel
+	return cborExtended(plain(e), e.Extensions, errorModelMembers)
+}
+
+// UnmarshalJSON collects unknown members into `Extensions`.
+func (e *ErrorModel) UnmarshalJSON(data []byte) error {
+	type plain ErrorModel
+	if err := json.Unmarshal(data, (*plain)(e)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(data, errorModelMembers)
+	e.Extensions = ext
+	return err
+}
+
 func (e *ErrorModel) Add(err error) {
-	if converted, ok := err.(ErrorDetailer); ok {
+	var converted ErrorDetailer
+	if errors.As(err, &converted) {
 		e.Errors = append(e.Errors, converted.ErrorDetail())
 		return
 	}
@@ -96,13 +268,16 @@ func (e *ErrorModel) ContentType(ct string) string {
 // ContentTypeFilter allows you to override the content type for responses,
 // allowing you to return a different content type like
 // `application/problem+json` after using the `application/json` marshaller.
-// This should be implemented by the response body struct.
+// This should be implemented by the response body struct. Input body types
+// may also implement it to document a more specific request content type.
 type ContentTypeFilter interface {
 	ContentType(string) string
 }
 
 // StatusError is an error that has an HTTP status code. When returned from
-// an operation handler, this sets the response status code.
+// an operation handler, this sets the response status code. Status errors may
+// be wrapped, e.g. via `fmt.Errorf("...: %w", err)`, and are found using
+// `errors.As`.
 type StatusError interface {
 	GetStatus() int
 	Error() string
@@ -111,15 +286,20 @@ type StatusError interface {
 // Ensure the default error model satisfies these interfaces.
 var _ StatusError = (*ErrorModel)(nil)
 var _ ContentTypeFilter = (*ErrorModel)(nil)
+var _ SchemaTransformer = (*ErrorModel)(nil)
 
 // NewError creates a new instance of an error model with the given status code,
 // message, and errors. If the error implements the `ErrorDetailer` interface,
 // the error details will be used. Otherwise, the error message will be used.
-// Replace this function to use your own error type.
+// Replace this function to use your own error type for all errors, including
+// the `huma.ErrorXXX` constructors and the errors Huma generates itself, like
+// validation failures. Use `Config.NewError` instead to change the error type
+// of a single API.
 var NewError = func(status int, msg string, errs ...error) StatusError {
 	details := make([]*ErrorDetail, len(errs))
 	for i := 0; i < len(errs); i++ {
-		if converted, ok := errs[i].(ErrorDetailer); ok {
+		var converted ErrorDetailer
+		if errors.As(errs[i], &converted) {
 			details[i] = converted.ErrorDetail()
 		} else {
 			details[i] = &ErrorDetail{Message: errs[i].Error()}
@@ -137,24 +317,63 @@ var NewError = func(status int, msg string, errs ...error) StatusError {
 // configured error type and with the given status code and message. It is
 // marshaled using the API's content negotiation methods.
 func WriteErr(api API, ctx Context, status int, msg string, errs ...error) {
-	var err any = NewError(status, msg, errs...)
+	writeStatusErr(api, ctx, newAPIError(api, status, msg, errs...))
+}
+
+// apiErrorConstructor returns the API's own error constructor, or nil if it
+// uses `NewError`.
+func apiErrorConstructor(api API) func(status int, msg string, errs ...error) StatusError {
+	if r := baseAPI(api); r != nil {
+		return r.config.NewError
+	}
+	return nil
+}
+
+// newAPIError creates an error using the API's error type, see `NewError`.
+func newAPIError(api API, status int, msg string, errs ...error) StatusError {
+	if newError := apiErrorConstructor(api); newError != nil {
+		return newError(status, msg, errs...)
+	}
+	return NewError(status, msg, errs...)
+}
 
+// writeStatusErr writes an existing error response, see `WriteErr`. Errors
+// using the default `ErrorModel`, e.g. from the `huma.ErrorXXX` constructors,
+// are converted to the API's own error type.
+func writeStatusErr(api API, ctx Context, err StatusError) {
+	if em, ok := err.(*ErrorModel); ok {
+		if newError := apiErrorConstructor(api); newError != nil {
+			errs := make([]error, len(em.Errors))
+			for i, detail := range em.Errors {
+				errs[i] = detail
+			}
+			err = newError(em.Status, em.Detail, errs...)
+		}
+	}
+
+	status := err.GetStatus()
 	ct, _ := api.Negotiate(ctx.Header("Accept"))
 	if ctf, ok := err.(ContentTypeFilter); ok {
 		ct = ctf.ContentType(ct)
 	}
 
 	ctx.SetHeader("Content-Type", ct)
-	ctx.SetStatus(status)
-	api.Marshal(ctx, strconv.Itoa(status), ct, err)
+	marshalWithStatus(api, ctx, status, strconv.Itoa(status), ct, err)
 }
 
-// Status304NotModied returns a 304. This is not really an error, but provides
-// a way to send non-default responses.
-func Status304NotModied() StatusError {
+// Status304NotModified returns a 304. This is not really an error, but
+// provides a way to send non-default responses.
+func Status304NotModified() StatusError {
 	return NewError(http.StatusNotModified, "")
 }
 
+// Status304NotModied returns a 304.
+//
+// Deprecated: use `Status304NotModified` instead.
+func Status304NotModied() StatusError {
+	return Status304NotModified()
+}
+
 // Error400BadRequest returns a 400.
 func Error400BadRequest(msg string, errs ...error) StatusError {
 	return NewError(http.StatusBadRequest, msg, errs...)
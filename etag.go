@@ -0,0 +1,86 @@
+package huma
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// noBody is returned by transformers to send the response without a body,
+// like a `304 Not Modified`.
+type noBody struct{}
+
+// ETagTransform is a transform which sets a weak `ETag` header on successful
+// `GET` & `HEAD` responses by hashing the response body, and sends a `304 Not
+// Modified` without a body if it matches the request's `If-None-Match`
+// header. This saves bandwidth without changing handlers, though the body is
+// still generated and marshalled an extra time to hash it. Operations which
+// document their own `ETag` response header are skipped. Use
+// `DocumentETags` to document the headers.
+func ETagTransform(ctx Context, status string, v any) (any, error) {
+	if status != "200" || (ctx.Method() != http.MethodGet && ctx.Method() != http.MethodHead) {
+		return v, nil
+	}
+	if op := ctx.Operation(); op != nil {
+		if resp := op.Responses[status]; resp != nil && resp.Headers["ETag"] != nil && resp.Headers["ETag"] != etagHeader {
+			// The handler sets its own ETag.
+			return v, nil
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v, nil
+	}
+	sum := sha256.Sum256(b)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	ctx.SetHeader("ETag", etag)
+
+	if ifNoneMatch := ctx.Header("If-None-Match"); ifNoneMatch != "" {
+		for _, value := range strings.Split(ifNoneMatch, ",") {
+			value = strings.TrimSpace(value)
+			if value == "*" || strings.TrimPrefix(value, "W/") == etag[2:] {
+				ctx.SetStatus(http.StatusNotModified)
+				return noBody{}, nil
+			}
+		}
+	}
+	return v, nil
+}
+
+// etagHeader documents the `ETag` header set by `ETagTransform`.
+var etagHeader = &Header{
+	Description: "Hash of the response body, for conditional requests via If-None-Match.",
+	Schema:      &Schema{Type: TypeString},
+}
+
+// DocumentETags documents the `If-None-Match` request header and the `ETag`
+// response header & `304 Not Modified` response used by `ETagTransform` for
+// `GET` operations. Add it to the OpenAPI's `OnAddOperation` hooks before
+// registering operations.
+func DocumentETags(oapi *OpenAPI, op *Operation) {
+	resp := op.Responses["200"]
+	if op.Method != http.MethodGet || resp == nil || len(resp.Content) == 0 || resp.Headers["ETag"] != nil {
+		return
+	}
+	if resp.Headers == nil {
+		resp.Headers = map[string]*Header{}
+	}
+	resp.Headers["ETag"] = etagHeader
+	if op.Responses["304"] == nil {
+		op.Responses["304"] = &Response{Description: http.StatusText(http.StatusNotModified)}
+	}
+	for _, p := range op.Parameters {
+		if p.In == "header" && strings.EqualFold(p.Name, "If-None-Match") {
+			return
+		}
+	}
+	op.Parameters = append(op.Parameters, &Param{
+		Name:        "If-None-Match",
+		In:          "header",
+		Description: "Send a 304 Not Modified without a body if the response's ETag matches one of these.",
+		Schema:      &Schema{Type: TypeString},
+	})
+}
@@ -0,0 +1,79 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETagTransform(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.OnAddOperation = append(config.OnAddOperation, DocumentETags)
+	config.Transformers = append(config.Transformers, ETagTransform)
+	app := NewTestAdapter(r, config)
+
+	message := "hello"
+	Register(app, Operation{
+		OperationID: "get-message",
+		Method:      http.MethodGet,
+		Path:        "/message",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{message}, nil
+	})
+	Register(app, Operation{
+		OperationID: "get-custom",
+		Method:      http.MethodGet,
+		Path:        "/custom",
+	}, func(ctx context.Context, input *struct{}) (*struct {
+		ETag string `header:"ETag"`
+		Body string
+	}, error) {
+		return &struct {
+			ETag string `header:"ETag"`
+			Body string
+		}{`"custom"`, message}, nil
+	})
+
+	get := func(url, ifNoneMatch string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/message", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+	assert.JSONEq(t, `"hello"`, w.Body.String())
+
+	for _, ifNoneMatch := range []string{etag, etag[2:], `"other", ` + etag, "*"} {
+		w = get("/message", ifNoneMatch)
+		assert.Equal(t, http.StatusNotModified, w.Code, ifNoneMatch)
+		assert.Empty(t, w.Body.String(), ifNoneMatch)
+		assert.Equal(t, etag, w.Result().Header.Get("ETag"), ifNoneMatch)
+	}
+
+	// Changes to the body change the ETag.
+	message = "changed"
+	w = get("/message", etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	// Handlers can set their own ETag.
+	w = get("/custom", "")
+	assert.Equal(t, `"custom"`, w.Header().Get("ETag"))
+
+	op := app.OpenAPI().Paths["/message"].Get
+	assert.Contains(t, op.Responses["200"].Headers, "ETag")
+	assert.Contains(t, op.Responses, "304")
+	assert.Equal(t, "If-None-Match", op.Parameters[0].Name)
+}
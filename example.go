@@ -0,0 +1,41 @@
+package huma
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// addExamples documents the examples for the media type. The Go values are
+// converted to their JSON representation and validated against the media
+// type's schema, panicking if they are invalid.
+func addExamples(registry Registry, opID string, mt *MediaType, mode ValidateMode, examples map[string]any) {
+	if mt.Examples == nil {
+		mt.Examples = map[string]*Example{}
+	}
+	for name, example := range examples {
+		b, err := json.Marshal(example)
+		if err != nil {
+			panic(fmt.Sprintf("operation %s: example %s: %v", opID, name, err))
+		}
+		var v any
+		if err := json.Unmarshal(b, &v); err != nil {
+			panic(fmt.Sprintf("operation %s: example %s: %v", opID, name, err))
+		}
+
+		if mt.Schema != nil {
+			pb := NewPathBuffer([]byte{}, 0)
+			res := &ValidateResult{}
+			Validate(registry, mt.Schema, pb, mode, v, res)
+			if len(res.Errors) > 0 {
+				msgs := make([]string, len(res.Errors))
+				for i, err := range res.Errors {
+					msgs[i] = err.Error()
+				}
+				panic(fmt.Sprintf("operation %s: example %s does not match schema: %s", opID, name, strings.Join(msgs, ", ")))
+			}
+		}
+
+		mt.Examples[name] = &Example{Value: v}
+	}
+}
@@ -0,0 +1,68 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type ExampleThing struct {
+	ID    string `json:"id" readOnly:"true"`
+	Name  string `json:"name" maxLength:"10"`
+	Count int    `json:"count,omitempty" minimum:"0"`
+}
+
+func TestExamples(t *testing.T) {
+	app := NewTestAdapter(chi.NewRouter(), DefaultConfig("Test API", "1.0.0"))
+
+	handler := func(ctx context.Context, input *struct {
+		Body ExampleThing
+	}) (*struct{ Body ExampleThing }, error) {
+		return nil, nil
+	}
+
+	Register(app, Operation{
+		OperationID: "create-thing",
+		Method:      http.MethodPost,
+		Path:        "/things",
+		RequestExamples: map[string]any{
+			"simple": ExampleThing{Name: "foo"},
+		},
+		ResponseExamples: map[int]map[string]any{
+			http.StatusOK: {
+				"created": ExampleThing{ID: "abc123", Name: "foo", Count: 2},
+			},
+		},
+	}, handler)
+
+	op := app.OpenAPI().Paths["/things"].Post
+	assert.Equal(t, map[string]any{"id": "", "name": "foo"}, op.RequestBody.Content["application/json"].Examples["simple"].Value)
+	assert.Equal(t, map[string]any{"id": "abc123", "name": "foo", "count": 2.0}, op.Responses["200"].Content["application/json"].Examples["created"].Value)
+
+	assert.PanicsWithValue(t, "operation bad-example: example long does not match schema: expected length <= 10 (name: toolong-name)", func() {
+		Register(app, Operation{
+			OperationID: "bad-example",
+			Method:      http.MethodPut,
+			Path:        "/things",
+			RequestExamples: map[string]any{
+				"long": ExampleThing{Name: "toolong-name"},
+			},
+		}, handler)
+	})
+
+	assert.PanicsWithValue(t, "operation no-body: response 204 examples need a response body", func() {
+		Register(app, Operation{
+			OperationID: "no-body",
+			Method:      http.MethodDelete,
+			Path:        "/things",
+			ResponseExamples: map[int]map[string]any{
+				http.StatusNoContent: {"empty": nil},
+			},
+		}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
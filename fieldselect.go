@@ -0,0 +1,299 @@
+package huma
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/danielgtaylor/shorthand/v2"
+)
+
+// FieldSelectTransform is a transform that lets clients select which fields
+// of a response body to send over the wire, providing a GraphQL-like way to
+// reduce response sizes. Fields are selected via the `Fields` header or
+// `fields` query param as a comma-separated list of JSON property names, with
+// nested properties selected via `{...}` or dots. Arrays select the fields
+// from each item, e.g.
+//
+//	Fields: id,name,owner{name,email},items.id
+//
+// Unknown fields are ignored. Only the selected fields are visited and the
+// rest of the body is left untouched. Use `DocumentFieldSelect` to document
+// the header & query param in the OpenAPI.
+//
+// Selections starting with `{` or using `[`, `:`, `|`, `*`, `..` or `.{` are
+// queries using the shorthand syntax, e.g. `{id, name: owner.name}` or
+// `items[].{id}`, which are run against the JSON representation of the body.
+//
+// Fields tagged `sensitive` are removed or masked like `RedactTransform` does,
+// unless the operation sets `SkipRedaction`, since the selected fields lose
+// their tags and can't be redacted by later transformers.
+func FieldSelectTransform(ctx Context, status string, v any) (any, error) {
+	fields := ctx.Header("Fields")
+	if fields == "" {
+		fields = ctx.Query("fields")
+	}
+	if fields == "" || v == nil {
+		return v, nil
+	}
+	if _, ok := v.(noBody); ok {
+		return v, nil
+	}
+	redact := true
+	if op := ctx.Operation(); op != nil && op.SkipRedaction {
+		redact = false
+	}
+	if isShorthandQuery(fields) {
+		if redact {
+			v = Redact(v)
+		}
+		var tmp any
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		json.Unmarshal(b, &tmp)
+		result, _, err := shorthand.GetPath(fields, tmp, shorthand.GetOptions{})
+		return result, err
+	}
+	return selectFields(reflect.ValueOf(v), parseFieldSelection(fields), redact), nil
+}
+
+// isShorthandQuery returns whether the field selection uses the shorthand
+// query syntax rather than a list of fields.
+func isShorthandQuery(fields string) bool {
+	return strings.HasPrefix(strings.TrimSpace(fields), "{") ||
+		strings.ContainsAny(fields, "[]:|*") ||
+		strings.Contains(fields, "..") ||
+		strings.Contains(fields, ".{")
+}
+
+// DocumentFieldSelect documents the `Fields` header & `fields` query param
+// used by `FieldSelectTransform` for operations with a successful response
+// body. Add it to the OpenAPI's `OnAddOperation` hooks before registering
+// operations.
+func DocumentFieldSelect(oapi *OpenAPI, op *Operation) {
+	hasBody := false
+	for status, resp := range op.Responses {
+		if strings.HasPrefix(status, "2") && len(resp.Content) > 0 {
+			hasBody = true
+		}
+	}
+	if !hasBody {
+		return
+	}
+	description := "Comma-separated response body fields to return, with nested fields in braces, e.g. `id,name,owner{name}`. All fields are returned by default."
+	for _, p := range []*Param{
+		{Name: "fields", In: "query", Description: description, Schema: &Schema{Type: TypeString}},
+		{Name: "Fields", In: "header", Description: description, Schema: &Schema{Type: TypeString}},
+	} {
+		exists := false
+		for _, existing := range op.Parameters {
+			if strings.EqualFold(existing.Name, p.Name) && existing.In == p.In {
+				exists = true
+			}
+		}
+		if !exists {
+			op.Parameters = append(op.Parameters, p)
+		}
+	}
+}
+
+// fieldSelection maps selected field names to the fields selected within
+// them, or nil to select the whole value.
+type fieldSelection map[string]fieldSelection
+
+// parseFieldSelection parses a field selection like `id,owner{name},items.id`.
+// Parsing is lenient, so e.g. an unclosed brace is closed at the end.
+func parseFieldSelection(s string) fieldSelection {
+	sel, _ := parseFieldList(s, 0)
+	return sel
+}
+
+// parseFieldList parses comma-separated fields starting at index `i` until a
+// closing brace or the end, and returns the index after it.
+func parseFieldList(s string, i int) (fieldSelection, int) {
+	sel := fieldSelection{}
+	for i < len(s) {
+		switch s[i] {
+		case '}':
+			return sel, i + 1
+		case ',', ' ':
+			i++
+			continue
+		}
+		var name string
+		var sub fieldSelection
+		name, sub, i = parseField(s, i)
+		if name != "" {
+			sel.add(name, sub)
+		}
+	}
+	return sel, i
+}
+
+// parseField parses a single field with any nested selection.
+func parseField(s string, i int) (string, fieldSelection, int) {
+	start := i
+	for i < len(s) && !strings.ContainsRune(",{}. ", rune(s[i])) {
+		i++
+	}
+	name := s[start:i]
+	var sub fieldSelection
+	if i < len(s) {
+		switch s[i] {
+		case '.':
+			var subName string
+			var subSel fieldSelection
+			subName, subSel, i = parseField(s, i+1)
+			if subName != "" {
+				sub = fieldSelection{subName: subSel}
+			}
+		case '{':
+			sub, i = parseFieldList(s, i+1)
+		}
+	}
+	return name, sub, i
+}
+
+// add selects the field, merging any nested selections. Selecting the whole
+// value takes precedence over selecting some of its fields.
+func (sel fieldSelection) add(name string, sub fieldSelection) {
+	existing, ok := sel[name]
+	switch {
+	case !ok:
+		sel[name] = sub
+	case existing == nil || sub == nil:
+		sel[name] = nil
+	default:
+		for k, v := range sub {
+			existing.add(k, v)
+		}
+	}
+}
+
+// selectFields returns the selected fields of the value as maps & slices,
+// keeping unselected values as-is so they are marshalled normally. If
+// `redact` is set, sensitive fields are removed or masked.
+func selectFields(v reflect.Value, sel fieldSelection, redact bool) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	t := v.Type()
+	if pt := reflect.PointerTo(t); pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType) {
+		// Custom marshaling, so select from the JSON representation instead.
+		if v.CanAddr() {
+			v = v.Addr()
+		}
+		var tmp any
+		b, _ := json.Marshal(v.Interface())
+		json.Unmarshal(b, &tmp)
+		return selectFields(reflect.ValueOf(tmp), sel, redact)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		out := map[string]any{}
+		selectStructFields(v, sel, redact, out)
+		return out
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		if v.IsNil() {
+			return nil
+		}
+		out := map[string]any{}
+		for name, sub := range sel {
+			if mv := v.MapIndex(reflect.ValueOf(name).Convert(t.Key())); mv.IsValid() {
+				out[name] = selectValue(mv, sub, redact)
+			}
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Bytes are a base64 string, so there are no fields.
+			return v.Interface()
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = selectFields(v.Index(i), sel, redact)
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// selectValue returns the whole value if `sel` is nil, otherwise the selected
+// fields from it.
+func selectValue(v reflect.Value, sel fieldSelection, redact bool) any {
+	if sel == nil {
+		if redact {
+			return Redact(v.Interface())
+		}
+		return v.Interface()
+	}
+	return selectFields(v, sel, redact)
+}
+
+// selectStructFields adds the selected fields of the struct to `out`, using
+// the same names and embedding rules as `encoding/json`.
+func selectStructFields(v reflect.Value, sel fieldSelection, redact bool, out map[string]any) {
+	t := v.Type()
+	for _, f := range jsonFields(t) {
+		sub, ok := sel[f.name]
+		if !ok {
+			continue
+		}
+		sensitive := ""
+		if redact {
+			sensitive = t.FieldByIndex(f.index).Tag.Get("sensitive")
+		}
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// Within a nil embedded struct pointer.
+			continue
+		}
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		switch sensitive {
+		case "":
+			out[f.name] = selectValue(fv, sub, redact)
+		case "mask":
+			if fv.Kind() == reflect.String {
+				out[f.name] = RedactedMask
+			}
+		}
+	}
+}
+
+// isEmptyValue reports whether `encoding/json` treats the value as empty for
+// `omitempty`.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
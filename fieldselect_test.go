@@ -0,0 +1,159 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type FieldSelectOwner struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type FieldSelectBase struct {
+	ID string `json:"id"`
+}
+
+type FieldSelectItem struct {
+	FieldSelectBase
+	Tags    []string          `json:"tags"`
+	Owner   *FieldSelectOwner `json:"owner,omitempty"`
+	Created time.Time         `json:"created"`
+	Meta    map[string]any    `json:"meta,omitempty"`
+	Count   Optional[int]     `json:"count"`
+	secret  string
+}
+
+func TestParseFieldSelection(t *testing.T) {
+	for input, expected := range map[string]fieldSelection{
+		"":                     {},
+		"id":                   {"id": nil},
+		"id, name":             {"id": nil, "name": nil},
+		"owner{name,email}":    {"owner": {"name": nil, "email": nil}},
+		"owner.name":           {"owner": {"name": nil}},
+		"a.b.c,a.d":            {"a": {"b": {"c": nil}, "d": nil}},
+		"owner.name,owner":     {"owner": nil},
+		"items{owner{name}},x": {"items": {"owner": {"name": nil}}, "x": nil},
+		"owner{name":           {"owner": {"name": nil}},
+		",,}ignored":           {},
+	} {
+		assert.Equal(t, expected, parseFieldSelection(input), input)
+	}
+}
+
+type FieldSelectNamed struct {
+	Name string
+}
+
+type FieldSelectLabeled struct {
+	Name  string
+	Label string `json:"label"`
+}
+
+type FieldSelectShadow struct {
+	// Shadows the embedded field, even though it is declared first.
+	ID string `json:"id"`
+	FieldSelectBase
+	// Conflicting fields at the same depth are dropped.
+	FieldSelectNamed
+	FieldSelectLabeled
+}
+
+func TestSelectStructFieldsShadowing(t *testing.T) {
+	v := FieldSelectShadow{
+		ID:                 "outer",
+		FieldSelectBase:    FieldSelectBase{ID: "inner"},
+		FieldSelectNamed:   FieldSelectNamed{Name: "a"},
+		FieldSelectLabeled: FieldSelectLabeled{Name: "b", Label: "c"},
+	}
+	assert.Equal(t, map[string]any{"id": "outer", "label": "c"}, selectFields(reflect.ValueOf(v), fieldSelection{"id": nil, "Name": nil, "label": nil}, true))
+
+	// Same as `encoding/json`.
+	b, _ := json.Marshal(v)
+	assert.JSONEq(t, `{"id": "outer", "label": "c"}`, string(b))
+}
+
+func TestFieldSelectTransform(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.OnAddOperation = append(config.OnAddOperation, DocumentFieldSelect)
+	config.Transformers = append(config.Transformers, FieldSelectTransform)
+	app := NewTestAdapter(r, config)
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	items := []FieldSelectItem{
+		{
+			FieldSelectBase: FieldSelectBase{ID: "a"},
+			Tags:            []string{"x"},
+			Owner:           &FieldSelectOwner{Name: "alice", Email: "alice@example.com"},
+			Created:         created,
+			Meta:            map[string]any{"color": "red", "size": 1},
+			Count:           NewOptional(5),
+			secret:          "hidden",
+		},
+		{FieldSelectBase: FieldSelectBase{ID: "b"}, Created: created},
+	}
+
+	Register(app, Operation{
+		OperationID: "list-items",
+		Method:      http.MethodGet,
+		Path:        "/items",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body []FieldSelectItem }, error) {
+		return &struct{ Body []FieldSelectItem }{items}, nil
+	})
+	Register(app, Operation{
+		OperationID: "get-item",
+		Method:      http.MethodGet,
+		Path:        "/items/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{ Body FieldSelectItem }, error) {
+		if input.ID != "a" {
+			return nil, Error404NotFound("no item")
+		}
+		return &struct{ Body FieldSelectItem }{items[0]}, nil
+	})
+
+	for _, item := range []struct {
+		url    string
+		header string
+		body   string
+	}{
+		{"/items", "id", `[{"id": "a"}, {"id": "b"}]`},
+		{"/items?fields=id,owner.name", "", `[{"id": "a", "owner": {"name": "alice"}}, {"id": "b"}]`},
+		{"/items", "owner{email},tags,created", `[
+			{"owner": {"email": "alice@example.com"}, "tags": ["x"], "created": "2024-01-02T03:04:05Z"},
+			{"tags": null, "created": "2024-01-02T03:04:05Z"}
+		]`},
+		{"/items/a", "id,meta{color},count,secret,missing", `{"id": "a", "meta": {"color": "red"}, "count": 5}`},
+		{"/items/a", "$schema,owner", `{"$schema": "http:///schemas/FieldSelectItem.json", "owner": {"name": "alice", "email": "alice@example.com"}}`},
+		{"/items/b", "status,detail", `{"status": 404, "detail": "no item"}`},
+		// Shorthand queries.
+		{"/items/a", "{id, name: owner.name}", `{"id": "a", "name": "alice"}`},
+		{"/items", "[].{id}", `[{"id": "a"}, {"id": "b"}]`},
+		{"/items", "[].id", `["a", "b"]`},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, item.url, nil)
+		if item.header != "" {
+			req.Header.Set("Fields", item.header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.JSONEq(t, item.body, w.Body.String(), item.url+" "+item.header)
+	}
+
+	params := app.OpenAPI().Paths["/items/{id}"].Get.Parameters
+	assert.Len(t, params, 3)
+	assert.Equal(t, "fields", params[1].Name)
+	assert.Equal(t, "query", params[1].In)
+	assert.Equal(t, "Fields", params[2].Name)
+	assert.Equal(t, "header", params[2].In)
+}
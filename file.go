@@ -0,0 +1,320 @@
+package huma
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File is content to serve with `NewFileResponse`.
+type File struct {
+	// Name is the file name, used to detect the content type from its
+	// extension and for the `Content-Disposition` header.
+	Name string
+
+	// ContentType overrides the content type detected from the name or
+	// content.
+	ContentType string
+
+	// ModTime is when the content was last modified, sent as the
+	// `Last-Modified` header if set.
+	ModTime time.Time
+
+	// ETag identifies this version of the content. If empty, one is
+	// generated from the size & modification time when it is set.
+	ETag string
+
+	// Attachment makes browsers download the content rather than display it.
+	Attachment bool
+
+	// Content is the content to serve, which is closed afterward if it is an
+	// `io.Closer`.
+	Content io.ReadSeeker
+}
+
+// FileResponse is a response which serves a file or other seekable content,
+// with support for conditional & range requests. It is documented as a
+// binary `application/octet-stream` response. See `NewFileResponse`.
+type FileResponse struct {
+	Body func(ctx Context)
+}
+
+var fileResponseType = reflect.TypeOf(FileResponse{})
+
+// NewFileResponse creates a response serving the file. The content type is
+// detected from the name's extension or by sniffing the content, and the
+// `ETag` & `Last-Modified` headers are used to answer conditional requests
+// with `304 Not Modified`. A single byte range can be requested via the
+// `Range` header, e.g. to resume downloads.
+//
+//	func handler(ctx context.Context, input *struct{}) (*huma.FileResponse, error) {
+//		f, err := os.Open("report.pdf")
+//		if err != nil {
+//			return nil, huma.Error404NotFound("no report")
+//		}
+//		info, _ := f.Stat()
+//		return huma.NewFileResponse(huma.File{
+//			Name:       "report.pdf",
+//			ModTime:    info.ModTime(),
+//			Attachment: true,
+//			Content:    f,
+//		}), nil
+//	}
+func NewFileResponse(file File) *FileResponse {
+	return &FileResponse{
+		Body: func(ctx Context) {
+			if c, ok := file.Content.(io.Closer); ok {
+				defer c.Close()
+			}
+			serveFile(ctx, file)
+		},
+	}
+}
+
+// NewFSFileResponse creates a response serving the named file from the
+// file system, or returns a 404 error if it doesn't exist. See
+// `NewFileResponse`.
+func NewFSFileResponse(fsys fs.FS, name string) (*FileResponse, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, Error404NotFound("file not found")
+		}
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	rs, ok := f.(io.ReadSeeker)
+	if !ok || info.IsDir() {
+		f.Close()
+		return nil, Error404NotFound("file not found")
+	}
+	return NewFileResponse(File{
+		Name:    path.Base(name),
+		ModTime: info.ModTime(),
+		Content: rs,
+	}), nil
+}
+
+func serveFile(ctx Context, file File) {
+	size, err := file.Content.Seek(0, io.SeekEnd)
+	if err == nil {
+		_, err = file.Content.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		ctx.SetStatus(http.StatusInternalServerError)
+		return
+	}
+
+	ct := file.ContentType
+	if ct == "" {
+		ct = mime.TypeByExtension(path.Ext(file.Name))
+	}
+	if ct == "" {
+		var buf [512]byte
+		n, _ := io.ReadFull(file.Content, buf[:])
+		ct = http.DetectContentType(buf[:n])
+		if _, err := file.Content.Seek(0, io.SeekStart); err != nil {
+			ctx.SetStatus(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	modTime := file.ModTime.Truncate(time.Second)
+	etag := file.ETag
+	if etag == "" && !modTime.IsZero() {
+		etag = fmt.Sprintf(`"%x-%x"`, modTime.Unix(), size)
+	}
+
+	ctx.SetHeader("Accept-Ranges", "bytes")
+	if etag != "" {
+		ctx.SetHeader("ETag", etag)
+	}
+	if !modTime.IsZero() {
+		ctx.SetHeader("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(ctx, etag, modTime) {
+		ctx.SetStatus(http.StatusNotModified)
+		return
+	}
+
+	ctx.SetHeader("Content-Type", ct)
+	if file.Name != "" {
+		disposition := "inline"
+		if file.Attachment {
+			disposition = "attachment"
+		}
+		ctx.SetHeader("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": file.Name}))
+	}
+
+	start, length := int64(0), size
+	if rangeHeader := ctx.Header("Range"); rangeHeader != "" && rangeApplies(ctx.Header("If-Range"), etag, modTime) {
+		var ok bool
+		if start, length, ok = parseRange(rangeHeader, size); !ok {
+			ctx.SetHeader("Content-Range", "bytes */"+strconv.FormatInt(size, 10))
+			ctx.SetStatus(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		if length != size {
+			if _, err := file.Content.Seek(start, io.SeekStart); err != nil {
+				ctx.SetStatus(http.StatusInternalServerError)
+				return
+			}
+			ctx.SetHeader("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, size))
+			ctx.SetHeader("Content-Length", strconv.FormatInt(length, 10))
+			ctx.SetStatus(http.StatusPartialContent)
+			io.CopyN(ctx.BodyWriter(), file.Content, length)
+			return
+		}
+	}
+
+	ctx.SetHeader("Content-Length", strconv.FormatInt(size, 10))
+	ctx.SetStatus(http.StatusOK)
+	if ctx.Method() != http.MethodHead {
+		io.Copy(ctx.BodyWriter(), file.Content)
+	}
+}
+
+// notModified returns whether the request's conditional headers match the
+// current version of the file.
+func notModified(ctx Context, etag string, modTime time.Time) bool {
+	if ctx.Method() != http.MethodGet && ctx.Method() != http.MethodHead {
+		return false
+	}
+	if ifNoneMatch := ctx.Header("If-None-Match"); ifNoneMatch != "" {
+		for _, value := range strings.Split(ifNoneMatch, ",") {
+			value = strings.TrimSpace(value)
+			if value == "*" || (etag != "" && strings.TrimPrefix(value, "W/") == strings.TrimPrefix(etag, "W/")) {
+				return true
+			}
+		}
+		return false
+	}
+	if ims, err := http.ParseTime(ctx.Header("If-Modified-Since")); err == nil && !modTime.IsZero() {
+		return !modTime.After(ims)
+	}
+	return false
+}
+
+// rangeApplies returns whether a range request should be served given the
+// `If-Range` header, which must match the current version of the file.
+func rangeApplies(ifRange, etag string, modTime time.Time) bool {
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) {
+		// Ranges require a strong ETag match.
+		return etag != "" && !strings.HasPrefix(etag, "W/") && ifRange == etag
+	}
+	t, err := http.ParseTime(ifRange)
+	return err == nil && !modTime.IsZero() && modTime.Equal(t)
+}
+
+// parseRange parses a single byte range like `bytes=0-99`, `bytes=100-`, or
+// `bytes=-100`, returning the start & length. Multiple ranges are ignored by
+// returning the whole content.
+func parseRange(header string, size int64) (int64, int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok {
+		return 0, 0, false
+	}
+	if strings.Contains(spec, ",") {
+		return 0, size, true
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, 0, false
+	}
+	if first == "" {
+		// The last N bytes.
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, false
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, size > 0
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, false
+	}
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+			return 0, 0, false
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	return start, end - start + 1, true
+}
+
+// documentFileResponse documents the binary content, headers, and statuses
+// sent by a `FileResponse`.
+func documentFileResponse(op *Operation) {
+	binary := func() map[string]*MediaType {
+		return map[string]*MediaType{
+			"application/octet-stream": {Schema: &Schema{Type: TypeString, Format: "binary"}},
+		}
+	}
+	header := func(description string) *Header {
+		return &Header{Description: description, Schema: &Schema{Type: TypeString}}
+	}
+
+	resp := op.Responses[strconv.Itoa(op.DefaultStatus)]
+	if resp.Content == nil {
+		resp.Content = binary()
+	}
+	for name, description := range map[string]string{
+		"Content-Disposition": "Whether to display or download the file, and its name.",
+		"Last-Modified":       "When the file was last modified.",
+		"ETag":                "Identifies this version of the file.",
+		"Accept-Ranges":       "Byte ranges of the file can be requested.",
+	} {
+		if resp.Headers[name] == nil {
+			resp.Headers[name] = header(description)
+		}
+	}
+
+	if op.Responses["206"] == nil {
+		op.Responses["206"] = &Response{
+			Description: http.StatusText(http.StatusPartialContent),
+			Headers: map[string]*Header{
+				"Content-Range": header("The byte range sent, like `bytes 0-99/1000`."),
+			},
+			Content: binary(),
+		}
+	}
+	for _, code := range []int{http.StatusNotModified, http.StatusRequestedRangeNotSatisfiable} {
+		if status := strconv.Itoa(code); op.Responses[status] == nil {
+			op.Responses[status] = &Response{Description: http.StatusText(code)}
+		}
+	}
+
+	for _, p := range op.Parameters {
+		if p.In == "header" && strings.EqualFold(p.Name, "Range") {
+			return
+		}
+	}
+	op.Parameters = append(op.Parameters, &Param{
+		Name:        "Range",
+		In:          "header",
+		Description: "A single byte range of the file to get, like `bytes=0-99`.",
+		Schema:      &Schema{Type: TypeString},
+	})
+}
@@ -0,0 +1,126 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileResponse(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"files/notes.txt": {Data: []byte("0123456789"), ModTime: modified},
+	}
+
+	Register(app, Operation{
+		OperationID: "get-report",
+		Method:      http.MethodGet,
+		Path:        "/report",
+	}, func(ctx context.Context, input *struct{}) (*FileResponse, error) {
+		return NewFileResponse(File{
+			Name:       "report.pdf",
+			ModTime:    modified,
+			Attachment: true,
+			Content:    strings.NewReader("%PDF-1.4 data"),
+		}), nil
+	})
+	Register(app, Operation{
+		OperationID: "get-file",
+		Method:      http.MethodGet,
+		Path:        "/files/{name}",
+	}, func(ctx context.Context, input *struct {
+		Name string `path:"name"`
+	}) (*FileResponse, error) {
+		return NewFSFileResponse(fsys, "files/"+input.Name)
+	})
+
+	etag := `"65937d25-a"`
+	lastModified := "Tue, 02 Jan 2024 03:04:05 GMT"
+
+	for _, item := range []struct {
+		name    string
+		url     string
+		headers map[string]string
+		status  int
+		body    string
+		expect  map[string]string
+	}{
+		{"attachment", "/report", nil, http.StatusOK, "%PDF-1.4 data", map[string]string{
+			"Content-Type":        "application/pdf",
+			"Content-Disposition": `attachment; filename=report.pdf`,
+			"Content-Length":      "13",
+		}},
+		{"full", "/files/notes.txt", nil, http.StatusOK, "0123456789", map[string]string{
+			"Content-Type":        "text/plain; charset=utf-8",
+			"Content-Disposition": `inline; filename=notes.txt`,
+			"ETag":                etag,
+			"Last-Modified":       lastModified,
+			"Accept-Ranges":       "bytes",
+		}},
+		{"range", "/files/notes.txt", map[string]string{"Range": "bytes=2-4"}, http.StatusPartialContent, "234", map[string]string{
+			"Content-Range":  "bytes 2-4/10",
+			"Content-Length": "3",
+		}},
+		{"open range", "/files/notes.txt", map[string]string{"Range": "bytes=7-"}, http.StatusPartialContent, "789", nil},
+		{"suffix range", "/files/notes.txt", map[string]string{"Range": "bytes=-2"}, http.StatusPartialContent, "89", nil},
+		{"multiple ranges", "/files/notes.txt", map[string]string{"Range": "bytes=0-1,4-5"}, http.StatusOK, "0123456789", nil},
+		{"unsatisfiable", "/files/notes.txt", map[string]string{"Range": "bytes=20-"}, http.StatusRequestedRangeNotSatisfiable, "", map[string]string{
+			"Content-Range": "bytes */10",
+		}},
+		{"if-range match", "/files/notes.txt", map[string]string{"Range": "bytes=0-0", "If-Range": etag}, http.StatusPartialContent, "0", nil},
+		{"if-range mismatch", "/files/notes.txt", map[string]string{"Range": "bytes=0-0", "If-Range": `"old"`}, http.StatusOK, "0123456789", nil},
+		{"if-none-match", "/files/notes.txt", map[string]string{"If-None-Match": etag}, http.StatusNotModified, "", nil},
+		{"if-modified-since", "/files/notes.txt", map[string]string{"If-Modified-Since": lastModified}, http.StatusNotModified, "", nil},
+		{"modified", "/files/notes.txt", map[string]string{"If-Modified-Since": "Mon, 01 Jan 2024 00:00:00 GMT"}, http.StatusOK, "0123456789", nil},
+		{"missing", "/files/missing.txt", nil, http.StatusNotFound, "", nil},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, item.url, nil)
+		for k, v := range item.headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, item.status, w.Code, item.name)
+		if item.status != http.StatusNotFound {
+			assert.Equal(t, item.body, w.Body.String(), item.name)
+		}
+		for k, v := range item.expect {
+			assert.Equal(t, v, w.Header().Get(k), item.name+" "+k)
+		}
+	}
+
+	op := app.OpenAPI().Paths["/files/{name}"].Get
+	assert.Equal(t, "binary", op.Responses["200"].Content["application/octet-stream"].Schema.Format)
+	assert.Contains(t, op.Responses["200"].Headers, "ETag")
+	assert.Contains(t, op.Responses, "206")
+	assert.Contains(t, op.Responses, "304")
+	assert.Equal(t, "Range", op.Parameters[len(op.Parameters)-1].Name)
+}
+
+func TestParseRange(t *testing.T) {
+	for header, expected := range map[string][3]any{
+		"bytes=0-9":    {int64(0), int64(10), true},
+		"bytes=0-100":  {int64(0), int64(10), true},
+		"bytes=5-":     {int64(5), int64(5), true},
+		"bytes=-3":     {int64(7), int64(3), true},
+		"bytes=-30":    {int64(0), int64(10), true},
+		"bytes=10-":    {int64(0), int64(0), false},
+		"bytes=5-2":    {int64(0), int64(0), false},
+		"bytes=a-b":    {int64(0), int64(0), false},
+		"items=0-1":    {int64(0), int64(0), false},
+		"bytes=0-1,3-": {int64(0), int64(10), true},
+	} {
+		start, length, ok := parseRange(header, 10)
+		assert.Equal(t, expected, [3]any{start, length, ok}, header)
+	}
+}
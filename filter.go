@@ -0,0 +1,153 @@
+package huma
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+var refPattern = regexp.MustCompile(`"\$ref":\s*"([^"]+)"`)
+
+// Filter returns a copy of this spec with only the operations for which
+// `keep` returns true, e.g. to serve tailored docs to different consumers
+// from one set of registrations. Paths, tags, and component schemas &
+// parameters which are no longer used are removed. The copy shares everything
+// else with this spec, so it should not be modified.
+//
+//	partner := api.OpenAPI().Filter(func(op *huma.Operation) bool {
+//		return slices.Contains(op.Tags, "partner")
+//	})
+func (o *OpenAPI) Filter(keep func(op *Operation) bool) *OpenAPI {
+	f := *o
+	f.Paths = filterPaths(o.Paths, keep)
+	f.Webhooks = filterPaths(o.Webhooks, keep)
+
+	if o.Tags != nil {
+		used := map[string]bool{}
+		for _, paths := range []map[string]*PathItem{f.Paths, f.Webhooks} {
+			for _, item := range paths {
+				for _, op := range item.operations() {
+					for _, tag := range op.Tags {
+						used[tag] = true
+					}
+				}
+			}
+		}
+		f.Tags = nil
+		for _, tag := range o.Tags {
+			if used[tag.Name] {
+				f.Tags = append(f.Tags, tag)
+			}
+		}
+	}
+
+	if o.Components == nil {
+		return &f
+	}
+	c := *o.Components
+	f.Components = &c
+
+	if c.Schemas != nil {
+		// Schemas are found by following refs, so start without any.
+		c.Schemas = &filteredRegistry{Registry: o.Components.Schemas, schemas: map[string]*Schema{}}
+	}
+
+	if o.Components.Parameters != nil {
+		c.Parameters = nil
+		refs := specRefs(&f)
+		c.Parameters = map[string]*Param{}
+		for name, p := range o.Components.Parameters {
+			if refs["#/components/parameters/"+name] {
+				c.Parameters[name] = p
+			}
+		}
+	}
+
+	if c.Schemas != nil {
+		all := o.Components.Schemas.Map()
+		kept := c.Schemas.Map()
+		queue := []string{}
+		for ref := range specRefs(&f) {
+			queue = append(queue, ref)
+		}
+		for len(queue) > 0 {
+			ref := queue[0]
+			queue = queue[1:]
+			name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+			if !ok || kept[name] != nil || all[name] == nil {
+				continue
+			}
+			kept[name] = all[name]
+			b, _ := json.Marshal(all[name])
+			for ref := range findRefs(b) {
+				queue = append(queue, ref)
+			}
+		}
+	}
+
+	return &f
+}
+
+// filterPaths returns the path items with only the operations to keep. Items
+// without any operations left are removed.
+func filterPaths(paths map[string]*PathItem, keep func(op *Operation) bool) map[string]*PathItem {
+	if paths == nil {
+		return nil
+	}
+	filtered := map[string]*PathItem{}
+	for path, item := range paths {
+		fi := *item
+		for _, op := range []**Operation{&fi.Get, &fi.Put, &fi.Post, &fi.Delete, &fi.Options, &fi.Head, &fi.Patch, &fi.Trace} {
+			if *op != nil && !keep(*op) {
+				*op = nil
+			}
+		}
+		if len(fi.operations()) > 0 {
+			filtered[path] = &fi
+		}
+	}
+	return filtered
+}
+
+// operations returns the path item's operations.
+func (p *PathItem) operations() []*Operation {
+	ops := []*Operation{}
+	for _, op := range []*Operation{p.Get, p.Put, p.Post, p.Delete, p.Options, p.Head, p.Patch, p.Trace} {
+		if op != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// specRefs returns the set of `$ref` values used in the spec.
+func specRefs(o *OpenAPI) map[string]bool {
+	b, _ := json.Marshal(o)
+	return findRefs(b)
+}
+
+func findRefs(b []byte) map[string]bool {
+	refs := map[string]bool{}
+	for _, m := range refPattern.FindAllSubmatch(b, -1) {
+		refs[string(m[1])] = true
+	}
+	return refs
+}
+
+// filteredRegistry is a registry which only marshals some of its schemas.
+type filteredRegistry struct {
+	Registry
+	schemas map[string]*Schema
+}
+
+func (r *filteredRegistry) Map() map[string]*Schema {
+	return r.schemas
+}
+
+func (r *filteredRegistry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.schemas)
+}
+
+func (r *filteredRegistry) MarshalYAML() (interface{}, error) {
+	return r.schemas, nil
+}
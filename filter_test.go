@@ -0,0 +1,118 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slices"
+)
+
+type FilterOwner struct {
+	Name string `json:"name"`
+}
+
+type FilterPublicItem struct {
+	ID    string       `json:"id"`
+	Owner *FilterOwner `json:"owner"`
+}
+
+type FilterInternalItem struct {
+	Secret string `json:"secret"`
+}
+
+type FilterPaging struct {
+	Cursor string `query:"cursor"`
+}
+
+func TestFilter(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Tags = []*Tag{{Name: "public"}, {Name: "internal"}}
+	config.OpenAPIFilters = map[string]func(op *Operation) bool{
+		"public": func(op *Operation) bool {
+			return slices.Contains(op.Tags, "public")
+		},
+	}
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "list-items",
+		Method:      http.MethodGet,
+		Path:        "/items",
+		Tags:        []string{"public"},
+	}, func(ctx context.Context, input *struct {
+		FilterPaging
+	}) (*struct{ Body []FilterPublicItem }, error) {
+		return nil, nil
+	})
+	Register(app, Operation{
+		OperationID: "create-item",
+		Method:      http.MethodPost,
+		Path:        "/items",
+		Tags:        []string{"internal"},
+	}, func(ctx context.Context, input *struct {
+		Body FilterInternalItem
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+	Register(app, Operation{
+		OperationID: "get-secret",
+		Method:      http.MethodGet,
+		Path:        "/secrets/{id}",
+		Tags:        []string{"internal"},
+	}, func(ctx context.Context, input *struct {
+		FilterPaging
+		ID string `path:"id"`
+	}) (*struct{ Body FilterInternalItem }, error) {
+		return nil, nil
+	})
+
+	oapi := app.OpenAPI()
+	filtered := oapi.Filter(func(op *Operation) bool {
+		return slices.Contains(op.Tags, "public")
+	})
+
+	assert.Len(t, filtered.Paths, 1)
+	assert.NotNil(t, filtered.Paths["/items"].Get)
+	assert.Nil(t, filtered.Paths["/items"].Post)
+	assert.Equal(t, []*Tag{{Name: "public"}}, filtered.Tags)
+	assert.Contains(t, filtered.Components.Schemas.Map(), "FilterPublicItem")
+	assert.Contains(t, filtered.Components.Schemas.Map(), "FilterOwner", "nested refs are kept")
+	assert.NotContains(t, filtered.Components.Schemas.Map(), "FilterInternalItem")
+	assert.Contains(t, filtered.Components.Parameters, "FilterPagingCursor")
+
+	// The original spec is unchanged.
+	assert.Len(t, oapi.Paths, 2)
+	assert.NotNil(t, oapi.Paths["/items"].Post)
+	assert.Len(t, oapi.Tags, 2)
+	assert.Contains(t, oapi.Components.Schemas.Map(), "FilterInternalItem")
+
+	req, _ := http.NewRequest(http.MethodGet, "/openapi-public.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/vnd.oai.openapi+json", w.Header().Get("Content-Type"))
+
+	var spec map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Len(t, spec["paths"], 1)
+	assert.NotContains(t, w.Body.String(), "FilterInternalItem")
+	assert.NotContains(t, w.Body.String(), "secrets")
+
+	req, _ = http.NewRequest(http.MethodGet, "/openapi-public.yaml", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "list-items")
+	assert.NotContains(t, w.Body.String(), "create-item")
+
+	req, _ = http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), "FilterInternalItem")
+}
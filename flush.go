@@ -0,0 +1,54 @@
+package huma
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ErrFlushUnsupported is returned by `Flush` when the adapter can't send
+// partial responses, e.g. routers which buffer the whole response before
+// sending it. It wraps `http.ErrNotSupported`.
+var ErrFlushUnsupported = fmt.Errorf("flush: %w", http.ErrNotSupported)
+
+// Flusher is an optional interface for contexts which control how buffered
+// response data is sent to the client, e.g. contexts which wrap another one
+// to transform the response. Other contexts are flushed via their body
+// writer, see `Flush`.
+type Flusher interface {
+	Flush() error
+}
+
+// Flush sends any buffered response data, including the status & headers if
+// not yet sent, to the client. Use it from streaming response bodies like
+// server-sent events, long polling or progress reports so the client sees
+// partial output as soon as it's written:
+//
+//	for progress := range updates {
+//		fmt.Fprintf(ctx.BodyWriter(), "%d%%\n", progress)
+//		if err := huma.Flush(ctx); err != nil {
+//			return
+//		}
+//	}
+//
+// Returns `ErrFlushUnsupported` if neither the context (via `Flusher`) nor its
+// body writer (or a writer it wraps via `Unwrap() http.ResponseWriter`)
+// implements `http.Flusher`.
+func Flush(ctx Context) error {
+	if f, ok := ctx.(Flusher); ok {
+		return f.Flush()
+	}
+	w := ctx.BodyWriter()
+	for {
+		switch t := w.(type) {
+		case interface{ FlushError() error }:
+			return t.FlushError()
+		case http.Flusher:
+			t.Flush()
+			return nil
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return ErrFlushUnsupported
+		}
+	}
+}
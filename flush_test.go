@@ -0,0 +1,77 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type unwrapWriter struct {
+	http.ResponseWriter
+}
+
+func (w unwrapWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func TestFlush(t *testing.T) {
+	for _, item := range []struct {
+		name   string
+		config func(*Config)
+	}{
+		{"plain", func(c *Config) {}},
+		{"compressed", func(c *Config) { c.Compression = &CompressionConfig{MinSize: 1000} }},
+		{"validated", func(c *Config) { c.DebugValidateResponses = true }},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			config := DefaultConfig("Test API", "1.0.0")
+			item.config(&config)
+			api := NewTestAdapter(r, config)
+
+			w := httptest.NewRecorder()
+			flushed := false
+			Register(api, Operation{
+				OperationID: "stream",
+				Method:      http.MethodGet,
+				Path:        "/stream",
+			}, func(ctx context.Context, input *struct{}) (*StreamResponse, error) {
+				return &StreamResponse{Body: func(ctx Context) {
+					ctx.SetHeader("Content-Type", "text/plain")
+					ctx.SetStatus(http.StatusAccepted)
+					ctx.BodyWriter().Write([]byte("partial"))
+					assert.NoError(t, Flush(ctx))
+
+					// The status & partial body were sent before the body completed.
+					flushed = w.Flushed
+					assert.Equal(t, http.StatusAccepted, w.Code)
+					assert.Equal(t, "partial", w.Body.String())
+					ctx.BodyWriter().Write([]byte(" done"))
+				}}, nil
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/stream", nil)
+			req.Header.Set("Accept-Encoding", "identity")
+			r.ServeHTTP(w, req)
+			assert.True(t, flushed)
+			assert.Equal(t, "partial done", strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
+func TestFlushUnsupported(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+
+	w := httptest.NewRecorder()
+	assert.NoError(t, Flush(&testContext{r: r, w: unwrapWriter{w}}))
+	assert.True(t, w.Flushed)
+
+	err := Flush(&testContext{r: r, w: struct{ http.ResponseWriter }{w}})
+	assert.ErrorIs(t, err, ErrFlushUnsupported)
+	assert.ErrorIs(t, err, http.ErrNotSupported)
+}
@@ -0,0 +1,596 @@
+// Package graphql serves the operations of a Huma API as a GraphQL schema,
+// with queries for `GET` operations & mutations for the others, so clients can
+// batch fetches into a single request. It is similar to Huma v1's GraphQL
+// support, but resolves fields by calling operations via `huma.Invoke`.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/danielgtaylor/casing"
+	"github.com/danielgtaylor/huma/v2"
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// JSON is the scalar type of values without a GraphQL equivalent, like maps
+// or schemas without a type, which are sent as-is.
+var JSON = gql.NewScalar(gql.ScalarConfig{
+	Name:        "JSON",
+	Description: "Any JSON value.",
+	Serialize: func(value any) any {
+		return value
+	},
+	ParseValue: func(value any) any {
+		return value
+	},
+	ParseLiteral: parseLiteral,
+})
+
+// parseLiteral converts a literal from a query to a JSON value.
+func parseLiteral(value ast.Value) any {
+	switch v := value.(type) {
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.IntValue:
+		return json.Number(v.Value)
+	case *ast.FloatValue:
+		return json.Number(v.Value)
+	case *ast.EnumValue:
+		return v.Value
+	case *ast.ListValue:
+		items := make([]any, len(v.Values))
+		for i, item := range v.Values {
+			items[i] = parseLiteral(item)
+		}
+		return items
+	case *ast.ObjectValue:
+		obj := make(map[string]any, len(v.Fields))
+		for _, f := range v.Fields {
+			obj[f.Name.Value] = parseLiteral(f.Value)
+		}
+		return obj
+	}
+	return nil
+}
+
+// headerKey is the context key of the incoming request's headers, which are
+// sent with each call.
+type headerKey struct{}
+
+// Register serves a GraphQL schema built from every operation of the API with
+// an operation ID via `POST` requests to the path, so call it once all
+// operations are registered:
+//
+//	graphql.Register(api, "/graphql")
+//
+// Each `GET` operation with a JSON response body is a query field & each
+// other operation is a mutation field, named by the operation ID in
+// `lowerCamelCase`, e.g. `getThing` for `get-thing`. Arguments are the
+// operation's params & either the properties of an object body or a `body`
+// argument, as described by `huma.Invoke`, and are also named in
+// `lowerCamelCase`. Types are generated from the response & request body
+// schemas, with `Input` appended to the names of input types. Mutations of
+// operations without a response body return `true`.
+//
+// Fields are resolved by calling their operations concurrently with the
+// headers of the request, so validation, middleware & errors behave the same
+// as for REST clients. Error responses are returned as GraphQL errors with
+// the error's detail as their message.
+//
+// The endpoint is not documented in the OpenAPI. Panics if the path is empty
+// or the schema is invalid, e.g. when two operation IDs have the same field
+// name.
+func Register(api huma.API, path string) {
+	if path == "" {
+		panic("graphql path must not be empty")
+	}
+
+	schema, err := newSchema(api)
+	if err != nil {
+		panic(err)
+	}
+
+	api.Adapter().Handle(&huma.Operation{
+		OperationID: "graphql",
+		Method:      http.MethodPost,
+		Path:        path,
+	}, func(ctx huma.Context) {
+		var req struct {
+			Query         string         `json:"query"`
+			OperationName string         `json:"operationName"`
+			Variables     map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(ctx.BodyReader()).Decode(&req); err != nil || req.Query == "" {
+			msg := "expected a JSON request with a query"
+			if err != nil {
+				msg += ": " + err.Error()
+			}
+			writeJSON(ctx, http.StatusBadRequest, map[string]any{
+				"errors": []map[string]any{{"message": msg}},
+			})
+			return
+		}
+
+		header := http.Header{}
+		ctx.EachHeader(func(name, value string) {
+			switch strings.ToLower(name) {
+			case "content-type", "content-length", "content-encoding", "accept-encoding":
+				return
+			}
+			header.Add(name, value)
+		})
+		header.Set("Accept", "application/json")
+
+		result := gql.Do(gql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			OperationName:  req.OperationName,
+			VariableValues: req.Variables,
+			Context:        context.WithValue(ctx.Context(), headerKey{}, header),
+		})
+		writeJSON(ctx, http.StatusOK, result)
+	})
+}
+
+func writeJSON(ctx huma.Context, status int, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Errorf("unable to marshal GraphQL response: %w", err))
+	}
+	ctx.SetHeader("Content-Type", "application/json")
+	ctx.SetStatus(status)
+	ctx.BodyWriter().Write(b)
+}
+
+// newSchema builds the GraphQL schema of the API's operations.
+func newSchema(api huma.API) (gql.Schema, error) {
+	b := &builder{
+		api:     api,
+		oapi:    api.OpenAPI(),
+		outputs: map[string]gql.Output{},
+		inputs:  map[string]gql.Input{},
+	}
+
+	paths := make([]string, 0, len(b.oapi.Paths))
+	for p := range b.oapi.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	queries := gql.Fields{}
+	mutations := gql.Fields{}
+	for _, p := range paths {
+		item := b.oapi.Paths[p]
+		for _, op := range []*huma.Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+			if op == nil || op.OperationID == "" {
+				continue
+			}
+			fields := mutations
+			if op.Method == http.MethodGet {
+				fields = queries
+			}
+			name := fieldName(op.OperationID)
+			if _, ok := fields[name]; ok {
+				return gql.Schema{}, fmt.Errorf("operation %s has the same field name %s as another operation", op.OperationID, name)
+			}
+			if f := b.operation(op); f != nil {
+				fields[name] = f
+			}
+		}
+	}
+
+	config := gql.SchemaConfig{
+		Query: gql.NewObject(gql.ObjectConfig{Name: "Query", Fields: queries}),
+	}
+	if len(mutations) > 0 {
+		config.Mutation = gql.NewObject(gql.ObjectConfig{Name: "Mutation", Fields: mutations})
+	}
+	return gql.NewSchema(config)
+}
+
+// fieldName converts a name to a valid GraphQL field or argument name in
+// `lowerCamelCase`.
+func fieldName(name string) string {
+	return validName(casing.LowerCamel(name))
+}
+
+// typeName converts a name to a valid GraphQL type name in `CamelCase`.
+func typeName(name string) string {
+	return validName(casing.Camel(name, casing.Identity))
+}
+
+// validName removes characters which can't be used in GraphQL names.
+func validName(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	name = sb.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// argument maps a GraphQL argument to the operation argument it is sent as.
+type argument struct {
+	name   string
+	schema *huma.Schema
+}
+
+// builder converts the API's schemas to GraphQL types, which are named after
+// their schema refs, or the path to them for inline schemas.
+type builder struct {
+	api     huma.API
+	oapi    *huma.OpenAPI
+	outputs map[string]gql.Output
+	inputs  map[string]gql.Input
+}
+
+// deref follows a schema ref, returning the referenced schema & its type
+// name, if any.
+func (b *builder) deref(s *huma.Schema) (*huma.Schema, string) {
+	name := ""
+	for s != nil && s.Ref != "" {
+		name = typeName(path.Base(s.Ref))
+		if b.oapi.Components == nil || b.oapi.Components.Schemas == nil {
+			return nil, name
+		}
+		s = b.oapi.Components.Schemas.SchemaFromRef(s.Ref)
+	}
+	return s, name
+}
+
+// sortedProperties returns the object's property names which can be used as
+// GraphQL fields.
+func sortedProperties(s *huma.Schema, input bool) []string {
+	names := make([]string, 0, len(s.Properties))
+	for name, prop := range s.Properties {
+		if strings.HasPrefix(name, "$") || (input && prop != nil && prop.ReadOnly) || (!input && prop != nil && prop.WriteOnly) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// output returns the GraphQL type of a response schema.
+func (b *builder) output(s *huma.Schema, name string) gql.Output {
+	s, refName := b.deref(s)
+	if refName != "" {
+		name = refName
+	}
+	if s == nil {
+		return JSON
+	}
+	switch s.Type {
+	case huma.TypeBoolean:
+		return gql.Boolean
+	case huma.TypeInteger:
+		return gql.Int
+	case huma.TypeNumber:
+		return gql.Float
+	case huma.TypeString:
+		return gql.String
+	case huma.TypeArray:
+		return gql.NewList(b.output(s.Items, name+"Item"))
+	case huma.TypeObject:
+		if len(s.Properties) == 0 {
+			return JSON
+		}
+		if t, ok := b.outputs[name]; ok {
+			return t
+		}
+		// Fields are created lazily to allow for recursive types.
+		obj := gql.NewObject(gql.ObjectConfig{
+			Name:        name,
+			Description: s.Description,
+			Fields: gql.FieldsThunk(func() gql.Fields {
+				fields := gql.Fields{}
+				for _, prop := range sortedProperties(s, false) {
+					prop := prop
+					ps := s.Properties[prop]
+					f := &gql.Field{
+						Type: b.output(ps, name+typeName(prop)),
+						Resolve: func(p gql.ResolveParams) (any, error) {
+							if m, ok := p.Source.(map[string]any); ok {
+								return m[prop], nil
+							}
+							return nil, nil
+						},
+					}
+					if ps != nil {
+						f.Description = ps.Description
+						if ps.Deprecated {
+							f.DeprecationReason = "Deprecated"
+						}
+					}
+					fields[fieldName(prop)] = f
+				}
+				return fields
+			}),
+		})
+		b.outputs[name] = obj
+		return obj
+	}
+	return JSON
+}
+
+// input returns the GraphQL type of a request schema.
+func (b *builder) input(s *huma.Schema, name string) gql.Input {
+	s, refName := b.deref(s)
+	if refName != "" {
+		name = refName
+	}
+	if s == nil {
+		return JSON
+	}
+	switch s.Type {
+	case huma.TypeBoolean:
+		return gql.Boolean
+	case huma.TypeInteger:
+		return gql.Int
+	case huma.TypeNumber:
+		return gql.Float
+	case huma.TypeString:
+		return gql.String
+	case huma.TypeArray:
+		return gql.NewList(b.input(s.Items, name+"Item"))
+	case huma.TypeObject:
+		if len(s.Properties) == 0 {
+			return JSON
+		}
+		if t, ok := b.inputs[name]; ok {
+			return t
+		}
+		obj := gql.NewInputObject(gql.InputObjectConfig{
+			Name:        name + "Input",
+			Description: s.Description,
+			Fields: gql.InputObjectConfigFieldMapThunk(func() gql.InputObjectConfigFieldMap {
+				fields := gql.InputObjectConfigFieldMap{}
+				for _, prop := range sortedProperties(s, true) {
+					ps := s.Properties[prop]
+					f := &gql.InputObjectFieldConfig{
+						Type: b.required(b.input(ps, name+typeName(prop)), s, prop),
+					}
+					if ps != nil {
+						f.Description = ps.Description
+					}
+					fields[fieldName(prop)] = f
+				}
+				return fields
+			}),
+		})
+		b.inputs[name] = obj
+		return obj
+	}
+	return JSON
+}
+
+// required makes the input type of a property non-null if the object
+// requires it.
+func (b *builder) required(t gql.Input, s *huma.Schema, prop string) gql.Input {
+	for _, r := range s.Required {
+		if r == prop {
+			if ps, _ := b.deref(s.Properties[prop]); ps != nil && ps.Nullable {
+				return t
+			}
+			return gql.NewNonNull(t)
+		}
+	}
+	return t
+}
+
+// argValue converts an argument value using the GraphQL names of object
+// properties back to the value expected by the schema.
+func (b *builder) argValue(s *huma.Schema, v any) any {
+	s, _ = b.deref(s)
+	if s == nil {
+		return v
+	}
+	switch value := v.(type) {
+	case []any:
+		items := make([]any, len(value))
+		for i, item := range value {
+			items[i] = b.argValue(s.Items, item)
+		}
+		return items
+	case map[string]any:
+		if len(s.Properties) == 0 {
+			return value
+		}
+		obj := make(map[string]any, len(value))
+		for _, prop := range sortedProperties(s, true) {
+			if item, ok := value[fieldName(prop)]; ok {
+				obj[prop] = b.argValue(s.Properties[prop], item)
+			}
+		}
+		return obj
+	}
+	return v
+}
+
+// jsonSchema returns the schema of the first JSON content.
+func jsonSchema(content map[string]*huma.MediaType) *huma.Schema {
+	types := make([]string, 0, len(content))
+	for ct := range content {
+		types = append(types, ct)
+	}
+	sort.Strings(types)
+	for _, ct := range types {
+		if strings.HasSuffix(ct, "json") && content[ct] != nil {
+			return content[ct].Schema
+		}
+	}
+	return nil
+}
+
+// operation returns the GraphQL field calling the operation, or nil if it
+// has no JSON response body & can't be a query.
+func (b *builder) operation(op *huma.Operation) *gql.Field {
+	name := typeName(op.OperationID)
+
+	var out gql.Output
+	var outSchema *huma.Schema
+	statuses := make([]string, 0, len(op.Responses))
+	for status := range op.Responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		if resp := op.Responses[status]; resp != nil && strings.HasPrefix(status, "2") {
+			if outSchema = jsonSchema(resp.Content); outSchema != nil {
+				break
+			}
+		}
+	}
+	if outSchema != nil {
+		out = b.output(outSchema, name+"Response")
+	} else if op.Method != http.MethodGet {
+		out = gql.Boolean
+	} else {
+		return nil
+	}
+
+	args := gql.FieldConfigArgument{}
+	argNames := map[string]argument{}
+	for _, p := range op.Parameters {
+		if p != nil && p.Name == "" && p.Ref != "" && b.oapi.Components != nil {
+			p = b.oapi.Components.Parameters[path.Base(p.Ref)]
+		}
+		if p == nil {
+			continue
+		}
+		t := b.input(p.Schema, name+typeName(p.Name))
+		if p.Required {
+			t = gql.NewNonNull(t)
+		}
+		argName := fieldName(p.Name)
+		args[argName] = &gql.ArgumentConfig{Type: t, Description: p.Description}
+		argNames[argName] = argument{name: p.Name, schema: p.Schema}
+	}
+
+	if body := op.RequestBody; body != nil {
+		if body.Ref != "" && b.oapi.Components != nil {
+			body = b.oapi.Components.RequestBodies[path.Base(body.Ref)]
+		}
+		if s := jsonSchema(body.Content); s != nil {
+			inputName := name + "Request"
+			if ds, _ := b.deref(s); ds != nil && ds.Type == huma.TypeObject {
+				// Properties of object bodies are arguments like with
+				// `huma.Invoke`.
+				for _, prop := range sortedProperties(ds, true) {
+					argName := fieldName(prop)
+					if _, ok := args[argName]; ok {
+						continue
+					}
+					args[argName] = &gql.ArgumentConfig{
+						Type:        b.required(b.input(ds.Properties[prop], inputName+typeName(prop)), ds, prop),
+						Description: ds.Properties[prop].Description,
+					}
+					argNames[argName] = argument{name: prop, schema: ds.Properties[prop]}
+				}
+			} else {
+				t := b.input(s, inputName)
+				if body.Required {
+					t = gql.NewNonNull(t)
+				}
+				args["body"] = &gql.ArgumentConfig{Type: t}
+				argNames["body"] = argument{name: "body", schema: s}
+			}
+		}
+	}
+
+	return &gql.Field{
+		Type:        out,
+		Description: op.Summary,
+		Args:        args,
+		Resolve: func(p gql.ResolveParams) (any, error) {
+			callArgs := make(map[string]any, len(p.Args))
+			for argName, v := range p.Args {
+				if arg, ok := argNames[argName]; ok {
+					callArgs[arg.name] = b.argValue(arg.schema, v)
+				}
+			}
+			header, _ := p.Context.Value(headerKey{}).(http.Header)
+
+			if op.Method != http.MethodGet {
+				// Mutations run one after the other.
+				return b.call(p.Context, op, header, callArgs, outSchema != nil)
+			}
+
+			// Queries run concurrently, returning a thunk which is resolved
+			// once all the fields at this level have started.
+			type result struct {
+				value any
+				err   error
+			}
+			done := make(chan result, 1)
+			go func() {
+				v, err := b.call(p.Context, op, header, callArgs, true)
+				done <- result{v, err}
+			}()
+			return func() (any, error) {
+				r := <-done
+				return r.value, r.err
+			}, nil
+		},
+	}
+}
+
+// call invokes the operation & returns its decoded response body, or true
+// if it has none.
+func (b *builder) call(ctx context.Context, op *huma.Operation, header http.Header, args map[string]any, hasBody bool) (any, error) {
+	resp, err := huma.Invoke(ctx, b.api, op, header, args)
+	if err != nil {
+		return nil, err
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return nil, errorFromBody(resp.StatusCode, body)
+	}
+	if !hasBody {
+		return true, nil
+	}
+	if len(body) == 0 {
+		return nil, nil
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// errorFromBody converts an error response to an error whose message is the
+// detail of Huma's error model.
+func errorFromBody(status int, body []byte) error {
+	msg := ""
+	var model huma.ErrorModel
+	if json.Unmarshal(body, &model) == nil {
+		msg = model.Detail
+		if msg == "" {
+			msg = model.Title
+		}
+		for _, detail := range model.Errors {
+			msg += "; " + detail.Error()
+		}
+	}
+	if msg == "" {
+		msg = http.StatusText(status)
+	}
+	return fmt.Errorf("%s", msg)
+}
@@ -0,0 +1,179 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Owner struct {
+	Name string `json:"name"`
+}
+
+type Thing struct {
+	ID     string            `json:"id"`
+	Name   string            `json:"name"`
+	Rating float64           `json:"rating"`
+	Tags   []string          `json:"tags"`
+	Owner  *Owner            `json:"owner,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func newAPI(t *testing.T) humatest.TestAPI {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{thing-id}",
+		Summary:     "Get a thing",
+	}, func(ctx context.Context, input *struct {
+		ID   string `path:"thing-id" maxLength:"5"`
+		User string `header:"X-User"`
+	}) (*struct{ Body Thing }, error) {
+		if input.ID == "none" {
+			return nil, huma.Error404NotFound("thing not found")
+		}
+		return &struct{ Body Thing }{Body: Thing{
+			ID:     input.ID,
+			Name:   input.User,
+			Rating: 4.5,
+			Tags:   []string{"a", "b"},
+			Owner:  &Owner{Name: "owner"},
+			Labels: map[string]string{"env": "test"},
+		}}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		Limit int `query:"limit" default:"2"`
+	}) (*struct{ Body []Thing }, error) {
+		things := []Thing{}
+		for i := 0; i < input.Limit; i++ {
+			things = append(things, Thing{ID: string(rune('a' + i))})
+		}
+		return &struct{ Body []Thing }{Body: things}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{thing-id}",
+	}, func(ctx context.Context, input *struct {
+		ID   string `path:"thing-id"`
+		Body struct {
+			Name  string `json:"name" minLength:"2"`
+			Owner *Owner `json:"owner,omitempty"`
+		}
+	}) (*struct{ Body Thing }, error) {
+		return &struct{ Body Thing }{Body: Thing{ID: input.ID, Name: input.Body.Name, Owner: input.Body.Owner}}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-thing",
+		Method:      http.MethodDelete,
+		Path:        "/things/{thing-id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"thing-id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	Register(api, "/graphql")
+	return api
+}
+
+func query(api humatest.TestAPI, q string, args ...any) map[string]any {
+	resp := api.Post("/graphql", append(args, map[string]any{"query": q})...)
+	var result map[string]any
+	json.Unmarshal(resp.Body.Bytes(), &result)
+	return result
+}
+
+func TestGraphQLQuery(t *testing.T) {
+	api := newAPI(t)
+
+	result := query(api, `{
+		getThing(thingId: "abc") { id name rating tags owner { name } labels }
+		other: getThing(thingId: "def") { id }
+		listThings(limit: 3) { id }
+	}`, "X-User: daniel")
+	assert.Nil(t, result["errors"])
+	b, _ := json.Marshal(result["data"])
+	assert.JSONEq(t, `{
+		"getThing": {
+			"id": "abc",
+			"name": "daniel",
+			"rating": 4.5,
+			"tags": ["a", "b"],
+			"owner": {"name": "owner"},
+			"labels": {"env": "test"}
+		},
+		"other": {"id": "def"},
+		"listThings": [{"id": "a"}, {"id": "b"}, {"id": "c"}]
+	}`, string(b))
+
+	// The endpoint isn't documented.
+	assert.Len(t, api.OpenAPI().Paths, 2)
+}
+
+func TestGraphQLMutation(t *testing.T) {
+	api := newAPI(t)
+
+	result := query(api, `mutation {
+		putThing(thingId: "abc", name: "Thing", owner: {name: "me"}) { id name owner { name } }
+		deleteThing(thingId: "abc")
+	}`)
+	assert.Nil(t, result["errors"])
+	b, _ := json.Marshal(result["data"])
+	assert.JSONEq(t, `{
+		"putThing": {"id": "abc", "name": "Thing", "owner": {"name": "me"}},
+		"deleteThing": true
+	}`, string(b))
+
+	// Variables are supported.
+	resp := api.Post("/graphql", map[string]any{
+		"query":     `mutation Put($id: String!, $owner: OwnerInput) { putThing(thingId: $id, name: "Thing", owner: $owner) { owner { name } } }`,
+		"variables": map[string]any{"id": "abc", "owner": map[string]any{"name": "var"}},
+	})
+	assert.Contains(t, resp.Body.String(), `"owner":{"name":"var"}`)
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	api := newAPI(t)
+
+	result := query(api, `{ getThing(thingId: "none") { id } }`)
+	assert.Contains(t, result["errors"].([]any)[0].(map[string]any)["message"], "thing not found")
+
+	result = query(api, `{ getThing(thingId: "too-long") { id } }`)
+	assert.Contains(t, result["errors"].([]any)[0].(map[string]any)["message"], "path.thing-id")
+
+	result = query(api, `mutation { putThing(thingId: "abc", name: "a") { id } }`)
+	assert.Contains(t, result["errors"].([]any)[0].(map[string]any)["message"], "body.name")
+
+	// Unknown fields are rejected by the schema.
+	result = query(api, `{ getThing(thingId: "abc") { missing } }`)
+	assert.NotEmpty(t, result["errors"])
+
+	resp := api.Post("/graphql", strings.NewReader(`{`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	assert.Panics(t, func() {
+		Register(api, "")
+	})
+}
+
+func TestFieldName(t *testing.T) {
+	assert.Equal(t, "getThing", fieldName("get-thing"))
+	assert.Equal(t, "_200", fieldName("200"))
+	assert.Equal(t, "ThingsV1", typeName("things.v1"))
+}
@@ -0,0 +1,66 @@
+package huma
+
+// Group is an API which registers operations with a shared path prefix and
+// metadata, making it easy to organize large services. Pass it to
+// `huma.Register` like any other API. Groups can be nested, in which case
+// the outer group's prefix comes first and its middlewares run first.
+//
+//	v1 := huma.NewGroup(api, "/v1")
+//	v1.Tags = []string{"v1"}
+//	v1.Security = []map[string][]string{{"bearer": {}}}
+//
+//	// Registers `GET /v1/things` with the tag & security requirement.
+//	huma.Register(v1, huma.Operation{
+//		OperationID: "list-things",
+//		Method:      http.MethodGet,
+//		Path:        "/things",
+//	}, handler)
+type Group struct {
+	API
+
+	// Prefix is prepended to the path of each operation.
+	Prefix string
+
+	// Tags are added to each operation.
+	Tags []string
+
+	// Security is used for operations which don't set their own security
+	// requirements.
+	Security []map[string][]string
+
+	// Middlewares run before each operation's own middlewares.
+	Middlewares []Middleware
+
+	// Transformers run before each operation's own transformers.
+	Transformers []Transformer
+}
+
+// NewGroup creates a new group of operations under the path prefix.
+func NewGroup(api API, prefix string) *Group {
+	return &Group{API: api, Prefix: prefix}
+}
+
+// operationModifier is implemented by APIs which modify operations before
+// they are registered, like groups.
+type operationModifier interface {
+	modifyOperation(op *Operation)
+}
+
+func (g *Group) modifyOperation(op *Operation) {
+	op.Path = g.Prefix + op.Path
+	if len(g.Tags) > 0 {
+		op.Tags = append(append([]string{}, g.Tags...), op.Tags...)
+	}
+	if op.Security == nil && g.Security != nil {
+		op.Security = g.Security
+	}
+	if len(g.Middlewares) > 0 {
+		op.Middlewares = append(append([]Middleware{}, g.Middlewares...), op.Middlewares...)
+	}
+	if len(g.Transformers) > 0 {
+		op.Transformers = append(append([]Transformer{}, g.Transformers...), op.Transformers...)
+	}
+	if m, ok := g.API.(operationModifier); ok {
+		m.modifyOperation(op)
+	}
+}
@@ -0,0 +1,117 @@
+package huma
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// HTMLTemplateNamer is implemented by response bodies which choose the name
+// of the template used to render them as HTML, see `HTMLRenderer`.
+type HTMLTemplateNamer interface {
+	HTMLTemplate() string
+}
+
+// htmlFallback renders bodies without a template, like errors, as JSON.
+var htmlFallback = template.Must(template.New("fallback").Parse(`<!doctype html>
+<html>
+<head><meta charset="utf-8"></head>
+<body><pre>{{.}}</pre></body>
+</html>
+`))
+
+// HTMLRenderer renders response bodies as HTML pages using `html/template`,
+// so the same operations can serve admin pages or other hybrid UIs to
+// browsers while programmatic clients keep getting JSON. The body is passed
+// to its template as-is, so templates use the same typed model as the JSON
+// response.
+//
+// Templates are looked up by the name of the body's Go type, e.g.
+// `ThingList`, either as-is or with an `.html` extension like the names of
+// templates parsed from files. Bodies can implement `HTMLTemplateNamer` to
+// use another template. Other bodies, like errors, are rendered as indented
+// JSON within a minimal page.
+//
+//	html := huma.NewHTMLRenderer(template.Must(template.ParseFS(files, "templates/*.html")))
+//	config.Formats["text/html"] = html.Format()
+//	config.OpenAPI.OnAddOperation = append(config.OpenAPI.OnAddOperation, html.OnAddOperation)
+type HTMLRenderer struct {
+	templates *template.Template
+}
+
+// NewHTMLRenderer creates a renderer for the given templates. Use its
+// `Format` for the `text/html` content type, and add its `OnAddOperation`
+// method to the OpenAPI's `OnAddOperation` hooks to document the HTML
+// responses.
+func NewHTMLRenderer(templates *template.Template) *HTMLRenderer {
+	return &HTMLRenderer{templates: templates}
+}
+
+// Format returns the format which renders responses. It can't be used for
+// request bodies.
+func (r *HTMLRenderer) Format() Format {
+	return Format{
+		Marshal: r.Marshal,
+		Unmarshal: func(data []byte, v any) error {
+			return errors.New("html request bodies are not supported")
+		},
+	}
+}
+
+// lookup returns the template for the body type, or nil if there is none.
+func (r *HTMLRenderer) lookup(t reflect.Type, v any) *template.Template {
+	name := ""
+	if n, ok := v.(HTMLTemplateNamer); ok {
+		name = n.HTMLTemplate()
+	} else {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		name = t.Name()
+	}
+	if name == "" {
+		return nil
+	}
+	if tmpl := r.templates.Lookup(name); tmpl != nil {
+		return tmpl
+	}
+	return r.templates.Lookup(name + ".html")
+}
+
+// Marshal renders the value using its template.
+func (r *HTMLRenderer) Marshal(w io.Writer, v any) error {
+	v = unlinkValue(v)
+	if v != nil {
+		if tmpl := r.lookup(reflect.TypeOf(v), v); tmpl != nil {
+			return tmpl.Execute(w, v)
+		}
+	}
+
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return htmlFallback.Execute(w, string(b))
+}
+
+// OnAddOperation documents a `text/html` response for operations whose
+// body type has a template.
+func (r *HTMLRenderer) OnAddOperation(oapi *OpenAPI, op *Operation) {
+	if op.outputType == nil {
+		return
+	}
+	f, ok := op.outputType.FieldByName("Body")
+	if !ok || r.lookup(f.Type, reflect.New(f.Type).Interface()) == nil {
+		return
+	}
+	resp := op.Responses[strconv.Itoa(op.DefaultStatus)]
+	if resp == nil || resp.Content == nil || resp.Content["application/json"] == nil || resp.Content["text/html"] != nil {
+		return
+	}
+	resp.Content["text/html"] = &MediaType{
+		Schema: &Schema{Type: TypeString},
+	}
+}
@@ -0,0 +1,110 @@
+package huma
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type HTMLThing struct {
+	Name string `json:"name"`
+}
+
+type HTMLThingList []HTMLThing
+
+type htmlNamed struct {
+	Name string `json:"name"`
+}
+
+func (htmlNamed) HTMLTemplate() string {
+	return "custom"
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	templates := template.Must(template.New("HTMLThing").Parse(`<h1>{{.Name}}</h1>`))
+	template.Must(templates.New("HTMLThingList.html").Parse(`<ul>{{range .}}<li>{{.Name}}</li>{{end}}</ul>`))
+	template.Must(templates.New("custom").Parse(`<p>{{.Name}}</p>`))
+	html := NewHTMLRenderer(templates)
+
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Formats["text/html"] = html.Format()
+	config.OpenAPI.OnAddOperation = append(config.OpenAPI.OnAddOperation, html.OnAddOperation)
+	api := NewTestAdapter(r, config)
+
+	Register(api, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{name}",
+	}, func(ctx context.Context, input *struct {
+		Name string `path:"name"`
+	}) (*struct{ Body *HTMLThing }, error) {
+		if input.Name == "missing" {
+			return nil, Error404NotFound("no <thing>")
+		}
+		return &struct{ Body *HTMLThing }{Body: &HTMLThing{Name: "<" + input.Name + ">"}}, nil
+	})
+
+	Register(api, Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body HTMLThingList }, error) {
+		return &struct{ Body HTMLThingList }{Body: HTMLThingList{{Name: "a"}, {Name: "b"}}}, nil
+	})
+
+	Register(api, Operation{
+		OperationID: "get-named",
+		Method:      http.MethodGet,
+		Path:        "/named",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body htmlNamed }, error) {
+		return &struct{ Body htmlNamed }{Body: htmlNamed{Name: "named"}}, nil
+	})
+
+	Register(api, Operation{
+		OperationID: "get-other",
+		Method:      http.MethodGet,
+		Path:        "/other",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body map[string]int }, error) {
+		return &struct{ Body map[string]int }{Body: map[string]int{"a": 1}}, nil
+	})
+
+	paths := api.OpenAPI().Paths
+	assert.Contains(t, paths["/things/{name}"].Get.Responses["200"].Content, "text/html")
+	assert.Contains(t, paths["/things"].Get.Responses["200"].Content, "text/html")
+	assert.Contains(t, paths["/named"].Get.Responses["200"].Content, "text/html")
+	assert.NotContains(t, paths["/other"].Get.Responses["200"].Content, "text/html")
+
+	for _, item := range []struct {
+		path   string
+		accept string
+		status int
+		ct     string
+		body   string
+	}{
+		{"/things/foo", "text/html", http.StatusOK, "text/html", "<h1>&lt;foo&gt;</h1>"},
+		{"/things/foo", "application/json", http.StatusOK, "application/json", `"name":"\u003cfoo\u003e"`},
+		{"/things/foo", "", http.StatusOK, "application/json", `"name":"\u003cfoo\u003e"`},
+		{"/things", "text/html,application/xhtml+xml,*/*;q=0.8", http.StatusOK, "text/html", "<ul><li>a</li><li>b</li></ul>"},
+		{"/named", "text/html", http.StatusOK, "text/html", "<p>named</p>"},
+		{"/other", "text/html", http.StatusOK, "text/html", "<pre>{\n  &#34;a&#34;: 1\n}</pre>"},
+		{"/things/missing", "text/html", http.StatusNotFound, "text/html", "&#34;detail&#34;: &#34;no \\u003cthing\\u003e&#34;"},
+	} {
+		t.Run(item.path+" "+item.accept, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, item.path, nil)
+			if item.accept != "" {
+				req.Header.Set("Accept", item.accept)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, item.status, w.Code)
+			assert.Equal(t, item.ct, w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), item.body)
+		})
+	}
+}
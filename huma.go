@@ -3,16 +3,20 @@ package huma
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/danielgtaylor/casing"
 	"golang.org/x/exp/slices"
 )
 
@@ -20,6 +24,8 @@ var errDeadlineUnsupported = fmt.Errorf("%w", http.ErrNotSupported)
 
 var bodyCallbackType = reflect.TypeOf(func(Context) {})
 
+var readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
+
 // SetReadDeadline is a utility to set the read deadline on a response writer,
 // if possible. If not, it will not incur any allocations (unlike the stdlib
 // `http.ResponseController`).
@@ -37,6 +43,23 @@ func SetReadDeadline(w http.ResponseWriter, deadline time.Time) error {
 	}
 }
 
+// SetWriteDeadline is a utility to set the write deadline on a response
+// writer, if possible. If not, it will not incur any allocations (unlike the
+// stdlib `http.ResponseController`).
+func SetWriteDeadline(w http.ResponseWriter, deadline time.Time) error {
+	rw := w
+	for {
+		switch t := rw.(type) {
+		case interface{ SetWriteDeadline(time.Time) error }:
+			return t.SetWriteDeadline(deadline)
+		case interface{ Unwrap() http.ResponseWriter }:
+			rw = t.Unwrap()
+		default:
+			return errDeadlineUnsupported
+		}
+	}
+}
+
 // StreamResponse is a response that streams data to the client. The body
 // function will be called once the response headers have been written and
 // the body writer is ready to be written to.
@@ -50,7 +73,12 @@ type paramFieldInfo struct {
 	Loc        string
 	Default    string
 	TimeFormat string
+	Style      string
+	Explode    bool
 	Schema     *Schema
+	parse      paramParser
+	parseQuery queryParser
+	param      *Param
 }
 
 func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*paramFieldInfo] {
@@ -66,7 +94,13 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 
 		var example any
 		if e := f.Tag.Get("example"); e != "" {
-			example = jsonTagValue(f, f.Type, f.Tag.Get("example"))
+			if isDurationString(f) {
+				example = e
+			} else if elem := optionalElem(f.Type); elem != nil {
+				example = jsonTagValue(f, elem, e)
+			} else {
+				example = jsonTagValue(f, f.Type, e)
+			}
 		}
 
 		if def := f.Tag.Get("default"); def != "" {
@@ -82,9 +116,21 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 		} else if q := f.Tag.Get("query"); q != "" {
 			pfi.Loc = "query"
 			name = q
+			if n, options, ok := strings.Cut(q, ","); ok {
+				name = n
+				pfi.Name = name
+				parseQueryOptions(pfi, options)
+			} else if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() != reflect.Uint8 {
+				// Arrays are comma-separated by default.
+				pfi.Style = "form"
+			}
 		} else if h := f.Tag.Get("header"); h != "" {
 			pfi.Loc = "header"
 			name = h
+			if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() != reflect.Uint8 {
+				// Arrays are comma-separated and may be sent as repeated headers.
+				pfi.Style = "simple"
+			}
 		} else {
 			return nil
 		}
@@ -99,20 +145,73 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 			pfi.TimeFormat = timeFormat
 		}
 
+		if pfi.Style != "" {
+			paramItemConstraints(pfi.Schema)
+		}
+
+		if pfi.parseQuery = newQueryParser(registry, pfi); pfi.parseQuery == nil {
+			pfi.parse = newParamParser(registry, pfi)
+		}
+
 		if f.Tag.Get("hidden") == "" {
 			// Document the parameter if not hidden.
-			op.Parameters = append(op.Parameters, &Param{
+			param := &Param{
 				Name:     name,
 				In:       pfi.Loc,
 				Required: required,
 				Schema:   pfi.Schema,
 				Example:  example,
-			})
+			}
+			if pfi.Style != "" {
+				param.Style = pfi.Style
+				param.Explode = &pfi.Explode
+			}
+			op.Parameters = append(op.Parameters, param)
+			pfi.param = param
 		}
 		return pfi
 	}, "Body")
 }
 
+// shareParams moves the documentation of params declared in embedded structs,
+// like common pagination params, to `components.parameters` so that every
+// operation embedding the struct references the same definition.
+func shareParams(oapi *OpenAPI, t reflect.Type, params *findResult[*paramFieldInfo]) {
+	for _, path := range params.Paths {
+		p := path.Value
+		if p.param == nil {
+			// Hidden params aren't documented.
+			continue
+		}
+
+		// Find the struct declaring the param and whether it was embedded.
+		parent := deref(t)
+		embedded := false
+		for _, i := range path.Path[:len(path.Path)-1] {
+			f := parent.Field(i)
+			parent = deref(f.Type)
+			embedded = f.Anonymous
+		}
+		if !embedded || parent.Name() == "" {
+			continue
+		}
+
+		key := DefaultSchemaNamer(parent, "") + parent.Field(path.Path[len(path.Path)-1]).Name
+		if oapi.Components.Parameters == nil {
+			oapi.Components.Parameters = map[string]*Param{}
+		}
+		if existing, ok := oapi.Components.Parameters[key]; ok {
+			if existing.Name != p.param.Name || existing.In != p.param.In {
+				panic(fmt.Sprintf("duplicate parameter %s does not match existing %s parameter %s", key, existing.In, existing.Name))
+			}
+		} else {
+			shared := *p.param
+			oapi.Components.Parameters[key] = &shared
+		}
+		p.param.Ref = "#/components/parameters/" + key
+	}
+}
+
 func findResolvers(resolverType, t reflect.Type) *findResult[bool] {
 	return findInType(t, func(t reflect.Type, path []int) bool {
 		if reflect.PtrTo(t).Implements(resolverType) {
@@ -125,8 +224,40 @@ func findResolvers(resolverType, t reflect.Type) *findResult[bool] {
 	}, nil)
 }
 
+// findValidators finds the types implementing `Validator` within the body
+// field at `bodyIndex`.
+func findValidators(t reflect.Type, bodyIndex int) *findResult[bool] {
+	return findInType(t, func(t reflect.Type, path []int) bool {
+		return len(path) > 0 && path[0] == bodyIndex && reflect.PtrTo(t).Implements(validatorType)
+	}, nil)
+}
+
+// validatorError converts an error returned by a `Validator` into an error
+// detail located relative to the validated value at `pb`.
+func validatorError(pb *PathBuffer, err error) *ErrorDetail {
+	var detail *ErrorDetail
+	if d, ok := err.(ErrorDetailer); ok {
+		detail = d.ErrorDetail()
+	} else {
+		return &ErrorDetail{Message: err.Error(), Location: pb.String()}
+	}
+	switch {
+	case detail.Location == "":
+		detail.Location = pb.String()
+	case detail.Location[0] == '[':
+		detail.Location = pb.String() + detail.Location
+	default:
+		detail.Location = pb.With(detail.Location)
+	}
+	return detail
+}
+
 func findDefaults(t reflect.Type) *findResult[any] {
 	return findInType(t, nil, func(sf reflect.StructField, i []int) any {
+		if sf.Tag.Get("path") != "" || sf.Tag.Get("query") != "" || sf.Tag.Get("header") != "" {
+			// Parameter defaults are handled when parsing the parameter.
+			return nil
+		}
 		if d := sf.Tag.Get("default"); d != "" {
 			return jsonTagValue(sf, sf.Type, d)
 		}
@@ -140,7 +271,7 @@ type headerInfo struct {
 	TimeFormat string
 }
 
-func findHeaders(t reflect.Type) *findResult[*headerInfo] {
+func findHeaders(t reflect.Type, ignore ...string) *findResult[*headerInfo] {
 	return findInType(t, nil, func(sf reflect.StructField, i []int) *headerInfo {
 		header := sf.Tag.Get("header")
 		if header == "" {
@@ -154,7 +285,7 @@ func findHeaders(t reflect.Type) *findResult[*headerInfo] {
 			}
 		}
 		return &headerInfo{sf, header, timeFormat}
-	}, "Status", "Body")
+	}, append([]string{"Status", "Body"}, ignore...)...)
 }
 
 type findResultPath[T comparable] struct {
@@ -197,6 +328,24 @@ func (r *findResult[T]) Every(v reflect.Value, f func(reflect.Value, T)) {
 	}
 }
 
+// EveryField is like `Every` but passes the found struct field itself, even
+// if it is a slice or map, rather than each of its items. Paths through
+// slices or maps are skipped.
+func (r *findResult[T]) EveryField(v reflect.Value, f func(reflect.Value, T)) {
+outer:
+	for i := range r.Paths {
+		current := v
+		for _, index := range r.Paths[i].Path {
+			current = reflect.Indirect(current)
+			if current.Kind() != reflect.Struct {
+				continue outer
+			}
+			current = current.Field(index)
+		}
+		f(current, r.Paths[i].Value)
+	}
+}
+
 func jsonName(field reflect.StructField) string {
 	name := strings.ToLower(field.Name)
 	if jsonName := field.Tag.Get("json"); jsonName != "" {
@@ -207,6 +356,9 @@ func jsonName(field reflect.StructField) string {
 
 func (r *findResult[T]) everyPB(current reflect.Value, path []int, pb *PathBuffer, v T, f func(reflect.Value, T)) {
 	switch current.Kind() {
+	case reflect.Invalid:
+		// Nil pointer, so there is nothing to visit.
+		return
 	case reflect.Struct:
 		if len(path) == 0 {
 			f(current, v)
@@ -306,6 +458,9 @@ func getHint(parent reflect.Type, name string, other string) string {
 type validateDeps struct {
 	pb  *PathBuffer
 	res *ValidateResult
+
+	// m is reused for parsing JSON object bodies before validation.
+	m map[string]any
 }
 
 var validatePool = sync.Pool{
@@ -313,16 +468,48 @@ var validatePool = sync.Pool{
 		return &validateDeps{
 			pb:  &PathBuffer{buf: make([]byte, 0, 128)},
 			res: &ValidateResult{},
+			m:   map[string]any{},
 		}
 	},
 }
 
+// reset clears the dependencies so they can be returned to the pool without
+// keeping request data alive.
+func (d *validateDeps) reset() {
+	d.pb.Reset()
+	d.res.Reset()
+	for k := range d.m {
+		delete(d.m, k)
+	}
+}
+
 var bufPool = sync.Pool{
 	New: func() any {
 		return bytes.NewBuffer(make([]byte, 0, 128))
 	},
 }
 
+// autoErrors returns the status codes of the errors Huma itself may return
+// for an operation, see `Operation.SkipAutoErrors`.
+func autoErrors(oapi *OpenAPI, op *Operation, hasParams, hasBody bool) []int {
+	codes := []int{}
+	if hasBody {
+		// Malformed or missing request body.
+		codes = append(codes, http.StatusBadRequest)
+	}
+	security := op.Security
+	if security == nil {
+		security = oapi.Security
+	}
+	if len(security) > 0 {
+		codes = append(codes, http.StatusUnauthorized, http.StatusForbidden)
+	}
+	if hasParams || hasBody {
+		codes = append(codes, http.StatusUnprocessableEntity)
+	}
+	return append(codes, http.StatusInternalServerError)
+}
+
 // Register an operation handler for an API. The handler must be a function that
 // takes a context and a pointer to the input struct and returns a pointer to the
 // output struct and an error. The input struct must be a struct with fields
@@ -330,31 +517,110 @@ var bufPool = sync.Pool{
 // must be a  struct with fields for the output headers and body of the
 // operation, if any.
 func Register[I, O any](api API, op Operation, handler func(context.Context, *I) (*O, error)) {
+	inputType := reflect.TypeOf((*I)(nil)).Elem()
+	outputType := reflect.TypeOf((*O)(nil)).Elem()
+	register(api, op, inputType, outputType, func(ctx context.Context, input reflect.Value) (reflect.Value, error) {
+		output, err := handler(ctx, input.Interface().(*I))
+		return reflect.ValueOf(output), err
+	})
+}
+
+// register is `Register` for input & output types only known at runtime, e.g.
+// from `AutoRegister`. The handler is given a pointer to a new input struct
+// and returns a pointer to the output struct.
+func register(api API, op Operation, inputType, outputType reflect.Type, handler func(context.Context, reflect.Value) (reflect.Value, error)) {
 	oapi := api.OpenAPI()
 	registry := oapi.Components.Schemas
 
+	// Formats parsed by `encoding/json` & the language for messages.
+	var jsonFormats map[string]bool
+	language := ""
+	validateResponses := false
+	if r := baseAPI(api); r != nil {
+		jsonFormats = r.jsonFormats
+		language = r.config.Language
+		validateResponses = r.config.DebugValidateResponses
+		op.trustForwarded = r.config.TrustForwardedHeaders
+	}
+
+	if m, ok := api.(operationModifier); ok {
+		m.modifyOperation(&op)
+	}
+
 	if op.Method == "" || op.Path == "" {
 		panic("method and path must be specified in operation")
 	}
+	op.inputType = inputType
+	op.outputType = outputType
+	op.registry = registry
 
-	inputType := reflect.TypeOf((*I)(nil)).Elem()
 	if inputType.Kind() != reflect.Struct {
 		panic("input must be a struct")
 	}
+	paramCount := len(op.Parameters)
 	inputParams := findParams(registry, &op, inputType)
+	if op.declared {
+		// The loaded spec documents the params & is used to validate them.
+		op.Parameters = op.Parameters[:paramCount:paramCount]
+		declareParams(oapi, &op, inputParams)
+	}
+	shareParams(oapi, inputType, inputParams)
 	inputBodyIndex := -1
 	var inSchema *Schema
 	if f, ok := inputType.FieldByName("Body"); ok {
 		inputBodyIndex = f.Index[0]
-		inSchema = registry.Schema(f.Type, true, getHint(inputType, f.Name, op.OperationID+"Request"))
-		op.RequestBody = &RequestBody{
-			Content: map[string]*MediaType{
-				"application/json": {
-					Schema: inSchema,
-				},
-			},
+		contentType := "application/json"
+		if ctf, ok := reflect.New(f.Type).Interface().(ContentTypeFilter); ok {
+			// Allow body types to document a more specific content type, e.g.
+			// `application/json-patch+json`.
+			contentType = ctf.ContentType(contentType)
+		}
+		if op.declared {
+			inSchema = declaredBodySchema(oapi, &op, contentType, jsonFormats)
+		} else {
+			inSchema = registry.Schema(f.Type, true, getHint(inputType, f.Name, op.OperationID+"Request"))
+			if f.Type == rawMessageType && op.RequestBody != nil && op.RequestBody.Content[contentType] != nil && op.RequestBody.Content[contentType].Schema != nil {
+				// Raw JSON passes through as-is, but may be documented & validated
+				// using a schema provided with the operation.
+				inSchema = op.RequestBody.Content[contentType].Schema
+				inSchema.PrecomputeMessages()
+			} else {
+				op.RequestBody = &RequestBody{
+					Content: map[string]*MediaType{
+						contentType: {
+							Schema: inSchema,
+						},
+					},
+				}
+			}
+		}
+	}
+	// The body decoder is compiled on first use since the schemas may be
+	// modified until the server starts.
+	var bodyDec *bodyDecoder
+	var bodyDecOnce sync.Once
+	rawBodyIndex := -1
+	rawBodyReader := false
+	if f, ok := inputType.FieldByName("RawBody"); ok {
+		rawBodyIndex = f.Index[0]
+		if f.Type == readerType {
+			rawBodyReader = true
+		} else if f.Type.Kind() != reflect.Slice || f.Type.Elem().Kind() != reflect.Uint8 {
+			panic("raw body field must be []byte or io.Reader")
 		}
 
+		if op.RequestBody == nil && !op.declared {
+			// No parsed body, so document the raw bytes instead.
+			op.RequestBody = &RequestBody{
+				Content: map[string]*MediaType{
+					"application/octet-stream": {
+						Schema: &Schema{Type: TypeString, Format: "binary"},
+					},
+				},
+			}
+		}
+	}
+	if inputBodyIndex != -1 || rawBodyIndex != -1 {
 		if op.BodyReadTimeout == 0 {
 			// 5 second default
 			op.BodyReadTimeout = 5 * time.Second
@@ -365,17 +631,17 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			op.MaxBodyBytes = 1024 * 1024
 		}
 	}
-	rawBodyIndex := -1
-	if f, ok := inputType.FieldByName("RawBody"); ok {
-		rawBodyIndex = f.Index[0]
-	}
 	resolvers := findResolvers(resolverType, inputType)
+	injected := findInjected(api, inputType)
 	defaults := findDefaults(inputType)
+	var validators *findResult[bool]
+	if inputBodyIndex != -1 {
+		validators = findValidators(inputType, inputBodyIndex)
+	}
 
 	if op.Responses == nil {
 		op.Responses = map[string]*Response{}
 	}
-	outputType := reflect.TypeOf((*O)(nil)).Elem()
 	if outputType.Kind() != reflect.Struct {
 		panic("output must be a struct")
 	}
@@ -390,9 +656,18 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		// TODO: register each of the possible responses with the right model
 		//       and headers down below.
 	}
-	outHeaders := findHeaders(outputType)
+	if op.Upgrade != "" && op.DefaultStatus == 0 {
+		op.DefaultStatus = http.StatusSwitchingProtocols
+	}
+	outAltBodies := findStatusBodies(outputType)
+	altNames := make([]string, len(outAltBodies))
+	for i, alt := range outAltBodies {
+		altNames[i] = outputType.Field(alt.index).Name
+	}
+	outHeaders := findHeaders(outputType, altNames...)
 	outBodyIndex := -1
 	outBodyFunc := false
+	outBodyCT := ""
 	if f, ok := outputType.FieldByName("Body"); ok {
 		outBodyIndex = f.Index[0]
 		if f.Type.Kind() == reflect.Func {
@@ -402,31 +677,64 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				panic("body field must be a function with signature func(huma.Context)")
 			}
 		}
-		status := op.DefaultStatus
-		if status == 0 {
-			status = http.StatusOK
-		}
-		statusStr := fmt.Sprintf("%d", status)
-		if op.Responses[statusStr] == nil {
-			op.Responses[statusStr] = &Response{}
-		}
-		if op.Responses[statusStr].Description == "" {
-			op.Responses[statusStr].Description = http.StatusText(status)
-		}
-		if op.Responses[statusStr].Headers == nil {
-			op.Responses[statusStr].Headers = map[string]*Param{}
+		if outBodyCT = f.Tag.Get("contentType"); outBodyCT != "" {
+			if f.Type.Kind() != reflect.String && (f.Type.Kind() != reflect.Slice || f.Type.Elem().Kind() != reflect.Uint8) {
+				panic("body field with a content type must be a string or []byte")
+			}
 		}
-		if !outBodyFunc {
-			outSchema := registry.Schema(f.Type, true, getHint(outputType, f.Name, op.OperationID+"Response"))
-			if op.Responses[statusStr].Content == nil {
-				op.Responses[statusStr].Content = map[string]*MediaType{}
+		if !op.declared {
+			status := op.DefaultStatus
+			if status == 0 {
+				status = http.StatusOK
+			}
+			statusStr := fmt.Sprintf("%d", status)
+			if op.Responses[statusStr] == nil {
+				op.Responses[statusStr] = &Response{}
 			}
-			if _, ok := op.Responses[statusStr].Content["application/json"]; !ok {
-				op.Responses[statusStr].Content["application/json"] = &MediaType{}
+			if op.Responses[statusStr].Description == "" {
+				op.Responses[statusStr].Description = http.StatusText(status)
+			}
+			if op.Responses[statusStr].Headers == nil {
+				op.Responses[statusStr].Headers = map[string]*Param{}
+			}
+			if outBodyCT != "" {
+				// Text & binary bodies are sent as-is rather than serialized.
+				outSchema := &Schema{Type: TypeString}
+				if f.Type.Kind() == reflect.Slice {
+					outSchema.Format = "binary"
+				}
+				if op.Responses[statusStr].Content == nil {
+					op.Responses[statusStr].Content = map[string]*MediaType{}
+				}
+				if op.Responses[statusStr].Content[outBodyCT] == nil {
+					op.Responses[statusStr].Content[outBodyCT] = &MediaType{Schema: outSchema}
+				}
+			} else if !outBodyFunc {
+				outSchema := registry.Schema(f.Type, true, getHint(outputType, f.Name, op.OperationID+"Response"))
+				if op.Responses[statusStr].Content == nil {
+					op.Responses[statusStr].Content = map[string]*MediaType{}
+				}
+				if _, ok := op.Responses[statusStr].Content["application/json"]; !ok {
+					op.Responses[statusStr].Content["application/json"] = &MediaType{}
+				}
+				if f.Type != rawMessageType || op.Responses[statusStr].Content["application/json"].Schema == nil {
+					// Raw JSON keeps any schema provided with the operation.
+					op.Responses[statusStr].Content["application/json"].Schema = outSchema
+				}
 			}
-			op.Responses[statusStr].Content["application/json"].Schema = outSchema
 		}
 	}
+	if op.Upgrade != "" {
+		if !outBodyFunc {
+			panic(fmt.Sprintf("operation %s: upgrading the connection requires a streaming response body", op.OperationID))
+		}
+		if !slices.Contains(op.Errors, http.StatusUpgradeRequired) {
+			op.Errors = append(op.Errors, http.StatusUpgradeRequired)
+		}
+	}
+	if op.declared && op.DefaultStatus == 0 {
+		op.DefaultStatus = declaredStatus(&op, outBodyIndex != -1)
+	}
 	if op.DefaultStatus == 0 {
 		if outBodyIndex != -1 {
 			op.DefaultStatus = http.StatusOK
@@ -434,84 +742,216 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			op.DefaultStatus = http.StatusNoContent
 		}
 	}
+	if outputType == fileResponseType && !op.declared {
+		documentFileResponse(&op)
+	}
+	if outBodyIndex != -1 && !outBodyFunc && (op.DefaultStatus == http.StatusNoContent || op.DefaultStatus == http.StatusNotModified) {
+		panic(fmt.Sprintf("operation %s: default status %d cannot have a response body", op.OperationID, op.DefaultStatus))
+	}
 	defaultStatusStr := fmt.Sprintf("%d", op.DefaultStatus)
 	if op.Responses[defaultStatusStr] == nil {
 		op.Responses[defaultStatusStr] = &Response{
 			Description: http.StatusText(op.DefaultStatus),
 		}
 	}
-	for _, entry := range outHeaders.Paths {
-		// Document the header's name and type.
-		if op.Responses[defaultStatusStr].Headers == nil {
-			op.Responses[defaultStatusStr].Headers = map[string]*Param{}
+	if !op.declared {
+		for _, entry := range outHeaders.Paths {
+			// Document the header's name and type.
+			if op.Responses[defaultStatusStr].Headers == nil {
+				op.Responses[defaultStatusStr].Headers = map[string]*Param{}
+			}
+			v := entry.Value
+			op.Responses[defaultStatusStr].Headers[v.Name] = &Header{
+				// We need to generate the schema from the field to get validation info
+				// like min/max and enums. Useful to let the client know possible values.
+				Schema: SchemaFromField(registry, outputType, v.Field),
+			}
 		}
-		v := entry.Value
-		op.Responses[defaultStatusStr].Headers[v.Name] = &Header{
-			// We need to generate the schema from the field to get validation info
-			// like min/max and enums. Useful to let the client know possible values.
-			Schema: SchemaFromField(registry, outputType, v.Field),
+	}
+
+	if op.Upgrade != "" && !op.declared {
+		documentUpgrade(&op)
+	}
+
+	cacheControl := ""
+	if op.Cache != nil {
+		if problem := op.Cache.validate(); problem != "" {
+			panic(fmt.Sprintf("operation %s: cache policy %s", op.OperationID, problem))
 		}
+		resp := op.Responses[defaultStatusStr]
+		if resp.Headers["Cache-Control"] != nil {
+			panic(fmt.Sprintf("operation %s: cache policy conflicts with the Cache-Control output header", op.OperationID))
+		}
+		cacheControl = op.Cache.String()
+		if resp.Headers == nil {
+			resp.Headers = map[string]*Param{}
+		}
+		resp.Headers["Cache-Control"] = &Header{
+			Description: "Caching policy for the response.",
+			Schema:      &Schema{Type: TypeString, Examples: []any{cacheControl}},
+		}
+		if op.Extensions == nil {
+			op.Extensions = map[string]any{}
+		}
+		op.Extensions["x-cache-control"] = cacheControl
 	}
 
-	if len(op.Errors) > 0 && (len(inputParams.Paths) > 0 || inputBodyIndex >= -1) {
-		op.Errors = append(op.Errors, http.StatusUnprocessableEntity)
+	if !op.declared {
+		// No errors are defined, so set a default response in addition to the
+		// errors Huma itself may return.
+		defaultErr := len(op.Responses) <= 1 && len(op.Errors) == 0 || op.DefaultErrorResponse
+
+		exampleErr := newAPIError(api, 0, "")
+		errContentType := "application/json"
+		if ctf, ok := exampleErr.(ContentTypeFilter); ok {
+			errContentType = ctf.ContentType(errContentType)
+		}
+		errType := reflect.TypeOf(exampleErr)
+		errSchema := registry.Schema(errType, true, getHint(errType, "", "Error"))
+		for _, code := range op.Errors {
+			if op.DefaultErrorResponse {
+				// Covered by the default response.
+				break
+			}
+			op.Responses[fmt.Sprintf("%d", code)] = &Response{
+				Description: http.StatusText(code),
+				Content: map[string]*MediaType{
+					errContentType: {
+						Schema: errSchema,
+					},
+				},
+			}
+		}
+		if !op.SkipAutoErrors {
+			for _, code := range autoErrors(oapi, &op, len(inputParams.Paths) > 0, inputBodyIndex != -1) {
+				status := strconv.Itoa(code)
+				if op.Responses[status] != nil {
+					continue
+				}
+				op.Errors = append(op.Errors, code)
+				if op.DefaultErrorResponse {
+					continue
+				}
+				op.Responses[status] = &Response{
+					Description: http.StatusText(code),
+					Content: map[string]*MediaType{
+						errContentType: {
+							Schema: errSchema,
+						},
+					},
+				}
+			}
+		}
+		if defaultErr && op.Responses["default"] == nil {
+			// Errors without their own response use the default response.
+			op.Responses["default"] = &Response{
+				Description: "Error",
+				Content: map[string]*MediaType{
+					errContentType: {
+						Schema: errSchema,
+					},
+				},
+			}
+		}
+
+		// Document additional bodies sent with other statuses, e.g. a `Conflict`
+		// field with a `status:"409"` tag.
+		for _, alt := range outAltBodies {
+			f := outputType.Field(alt.index)
+			statusStr := strconv.Itoa(alt.status)
+			if op.Responses[statusStr] == nil {
+				op.Responses[statusStr] = &Response{}
+			}
+			if op.Responses[statusStr].Description == "" {
+				op.Responses[statusStr].Description = http.StatusText(alt.status)
+			}
+			if op.Responses[statusStr].Content == nil {
+				op.Responses[statusStr].Content = map[string]*MediaType{}
+			}
+			op.Responses[statusStr].Content["application/json"] = &MediaType{
+				Schema: registry.Schema(f.Type, true, getHint(outputType, f.Name, op.OperationID+f.Name+"Response")),
+			}
+		}
 	}
-	if len(op.Errors) > 0 {
-		op.Errors = append(op.Errors, http.StatusInternalServerError)
+
+	deprecation := deprecationHeaders(&op)
+	if deprecation != nil {
+		documentDeprecation(&op, deprecation)
 	}
 
-	exampleErr := NewError(0, "")
-	errContentType := "application/json"
-	if ctf, ok := exampleErr.(ContentTypeFilter); ok {
-		errContentType = ctf.ContentType(errContentType)
+	if len(op.RequestExamples) > 0 {
+		if op.RequestBody == nil {
+			panic(fmt.Sprintf("operation %s: request examples need a request body", op.OperationID))
+		}
+		for _, mt := range op.RequestBody.Content {
+			addExamples(registry, op.OperationID, mt, ModeWriteToServer, op.RequestExamples)
+		}
 	}
-	errType := reflect.TypeOf(exampleErr)
-	errSchema := registry.Schema(errType, true, getHint(errType, "", "Error"))
-	for _, code := range op.Errors {
-		op.Responses[fmt.Sprintf("%d", code)] = &Response{
-			Description: http.StatusText(code),
-			Content: map[string]*MediaType{
-				errContentType: {
-					Schema: errSchema,
-				},
-			},
+	for status, examples := range op.ResponseExamples {
+		resp := op.Responses[strconv.Itoa(status)]
+		if resp == nil || len(resp.Content) == 0 {
+			panic(fmt.Sprintf("operation %s: response %d examples need a response body", op.OperationID, status))
+		}
+		for _, mt := range resp.Content {
+			addExamples(registry, op.OperationID, mt, ModeReadFromServer, examples)
 		}
 	}
-	if len(op.Responses) <= 1 && len(op.Errors) == 0 {
-		// No errors are defined, so set a default response.
-		op.Responses["default"] = &Response{
-			Description: "Error",
-			Content: map[string]*MediaType{
-				errContentType: {
-					Schema: errSchema,
-				},
-			},
+
+	for _, security := range [][]map[string][]string{oapi.Security, op.Security} {
+		if err := validateSecurity(oapi, security); err != nil {
+			panic(fmt.Sprintf("operation %s: %v", op.OperationID, err))
 		}
 	}
 
+	if validateResponses {
+		op.responses = snapshotResponses(op.Responses)
+	}
 	if !op.Hidden {
 		oapi.AddOperation(&op)
 	}
 
 	a := api.Adapter()
 
-	a.Handle(&op, func(ctx Context) {
-		var input I
+	a.Handle(&op, chainMiddlewares(op.Middlewares, func(ctx Context) {
+		if len(op.EarlyHints) > 0 {
+			WriteEarlyHints(ctx, op.EarlyHints...)
+		}
+		for name, values := range deprecation {
+			for _, value := range values {
+				ctx.AppendHeader(name, value)
+			}
+		}
+
+		input := reflect.New(inputType)
 
 		// Get the validation dependencies from the shared pool.
 		deps := validatePool.Get().(*validateDeps)
 		defer func() {
-			deps.pb.Reset()
-			deps.res.Reset()
+			deps.reset()
 			validatePool.Put(deps)
 		}()
 		pb := deps.pb
 		res := deps.res
+		res.Limit = op.MaxValidationErrors
 
 		errStatus := http.StatusUnprocessableEntity
 
-		v := reflect.ValueOf(&input).Elem()
-		inputParams.Every(v, func(f reflect.Value, p *paramFieldInfo) {
+		v := input.Elem()
+		var query url.Values
+		inputParams.EveryField(v, func(f reflect.Value, p *paramFieldInfo) {
+			if p.parseQuery != nil {
+				if query == nil {
+					// Only parsed when needed, as most params are single values.
+					u := ctx.URL()
+					query = u.Query()
+				}
+				pb.Reset()
+				pb.Push(p.Loc)
+				pb.Push(p.Name)
+				p.parseQuery(f, query, pb, res, !op.SkipValidateParams)
+				return
+			}
+
 			var value string
 			switch p.Loc {
 			case "path":
@@ -519,7 +959,11 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			case "query":
 				value = ctx.Query(p.Name)
 			case "header":
-				value = ctx.Header(p.Name)
+				if p.Style == "simple" {
+					value = headerValues(ctx, p.Name)
+				} else {
+					value = ctx.Header(p.Name)
+				}
 			}
 
 			pb.Reset()
@@ -532,89 +976,21 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 
 			if p.Loc == "path" && value == "" {
 				// Path params are always required.
-				res.Add(pb, "", "required path parameter is missing")
+				res.Add(pb, "", message("requiredPathParam"))
 				return
 			}
 
 			if value != "" {
-				var pv any
-
-				switch p.Type.Kind() {
-				case reflect.String:
-					f.SetString(value)
-					pv = value
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-					v, err := strconv.ParseInt(value, 10, 64)
-					if err != nil {
-						res.Add(pb, value, "invalid integer")
-						return
-					}
-					f.SetInt(v)
-					pv = v
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					v, err := strconv.ParseUint(value, 10, 64)
-					if err != nil {
-						res.Add(pb, value, "invalid integer")
-						return
-					}
-					f.SetUint(v)
-					pv = v
-				case reflect.Float32, reflect.Float64:
-					v, err := strconv.ParseFloat(value, 64)
-					if err != nil {
-						res.Add(pb, value, "invalid float")
-						return
-					}
-					f.SetFloat(v)
-					pv = v
-				case reflect.Bool:
-					v, err := strconv.ParseBool(value)
-					if err != nil {
-						res.Add(pb, value, "invalid boolean")
-						return
-					}
-					f.SetBool(v)
-					pv = v
-				default:
-					// Special case: list of strings
-					if f.Type().Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.String {
-						values := strings.Split(value, ",")
-						f.Set(reflect.ValueOf(values))
-						pv = values
-						break
-					}
-
-					// Special case: time.Time
-					if f.Type() == timeType {
-						timeFormat := time.RFC3339Nano
-						if p.Loc == "header" {
-							timeFormat = http.TimeFormat
-						}
-						if p.TimeFormat != "" {
-							timeFormat = p.TimeFormat
-						}
-
-						t, err := time.Parse(timeFormat, value)
-						if err != nil {
-							res.Add(pb, value, "invalid time")
-							return
-						}
-						f.Set(reflect.ValueOf(t))
-						pv = t
-						break
-					}
-
-					panic("unsupported param type " + p.Type.String())
-				}
-
-				if !op.SkipValidateParams {
-					Validate(oapi.Components.Schemas, p.Schema, pb, ModeWriteToServer, pv, res)
-				}
+				p.parse(f, value, pb, res, !op.SkipValidateParams)
 			}
 		})
 
 		// Read input body if defined.
-		if inputBodyIndex != -1 {
+		if rawBodyReader && inputBodyIndex == -1 {
+			// Nothing to parse, so hand the stream straight to the handler without
+			// buffering it. Limits are up to the handler in this case.
+			v.Field(rawBodyIndex).Set(reflect.ValueOf(ctx.BodyReader()))
+		} else if inputBodyIndex != -1 || rawBodyIndex != -1 {
 			if op.BodyReadTimeout > 0 {
 				ctx.SetReadDeadline(time.Now().Add(op.BodyReadTimeout))
 			} else if op.BodyReadTimeout < 0 {
@@ -622,27 +998,46 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				ctx.SetReadDeadline(time.Time{})
 			}
 
+			// The buffer is only returned to the pool once the handler is done, as
+			// the raw body may reference its underlying bytes.
 			buf := bufPool.Get().(*bytes.Buffer)
+			defer func() {
+				buf.Reset()
+				bufPool.Put(buf)
+			}()
 			reader := ctx.BodyReader()
 			if closer, ok := reader.(io.Closer); ok {
 				defer closer.Close()
 			}
 			if op.MaxBodyBytes > 0 {
-				reader = io.LimitReader(reader, op.MaxBodyBytes)
+				if cl := ctx.Header("Content-Length"); cl != "" {
+					// Fail fast without reading the body if the client tells us up
+					// front that it will be too large.
+					if length, err := strconv.ParseInt(cl, 10, 64); err == nil && length > op.MaxBodyBytes {
+						WriteErr(api, ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is too large limit=%d bytes", op.MaxBodyBytes), res.Errors...)
+						return
+					}
+				}
+
+				// Read one byte past the limit so a body of exactly the limit is
+				// allowed while anything larger is detected.
+				reader = io.LimitReader(reader, op.MaxBodyBytes+1)
 			}
 			count, err := io.Copy(buf, reader)
 			if op.MaxBodyBytes > 0 {
-				if count == op.MaxBodyBytes {
-					buf.Reset()
-					bufPool.Put(buf)
+				if count > op.MaxBodyBytes {
 					WriteErr(api, ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is too large limit=%d bytes", op.MaxBodyBytes), res.Errors...)
 					return
 				}
 			}
 			if err != nil {
-				buf.Reset()
-				bufPool.Put(buf)
-
+				var se StatusError
+				if errors.As(err, &se) {
+					// The body reader knows best, e.g. for an invalid compressed
+					// body.
+					writeStatusErr(api, ctx, se)
+					return
+				}
 				if e, ok := err.(net.Error); ok && e.Timeout() {
 					WriteErr(api, ctx, http.StatusRequestTimeout, "request body read timeout", res.Errors...)
 					return
@@ -653,74 +1048,151 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			}
 			body := buf.Bytes()
 
-			if rawBodyIndex != -1 {
-				f := v.Field(rawBodyIndex)
-				f.SetBytes(body)
-			}
-
-			if len(body) == 0 {
-				kind := v.Field(inputBodyIndex).Kind()
-				if kind != reflect.Ptr && kind != reflect.Interface {
-					buf.Reset()
-					bufPool.Put(buf)
-					WriteErr(api, ctx, http.StatusBadRequest, "request body is required", res.Errors...)
-					return
-				}
-			} else {
-				parseErrCount := 0
-				if !op.SkipValidateBody {
-					// Validate the input. First, parse the body into []any or map[string]any
-					// or equivalent, which can be easily validated. Then, convert to the
-					// expected struct type to call the handler.
-					var parsed any
-					if err := api.Unmarshal(ctx.Header("Content-Type"), body, &parsed); err != nil {
-						// TODO: handle not acceptable
-						errStatus = http.StatusBadRequest
-						res.Errors = append(res.Errors, &ErrorDetail{
-							Location: "body",
-							Message:  err.Error(),
-							Value:    body,
+			if inputBodyIndex != -1 {
+				if len(body) == 0 {
+					kind := v.Field(inputBodyIndex).Kind()
+					if kind != reflect.Ptr && kind != reflect.Interface {
+						WriteErr(api, ctx, http.StatusBadRequest, "request body is required", res.Errors...)
+						return
+					}
+				} else {
+					decoded := false
+					if !op.SkipValidateBody && jsonFormats[formatKey(ctx.Header("Content-Type"))] {
+						// Fast path: validate while decoding straight into the body's
+						// type. Invalid bodies and unsupported types fall through to
+						// the regular path below, which reports the errors.
+						bodyDecOnce.Do(func() {
+							bodyDec = compileBodyDecoder(registry, inSchema, inputType.Field(inputBodyIndex).Type, op.StripReadOnly)
 						})
-						parseErrCount++
-					} else {
-						pb.Reset()
-						pb.Push("body")
-						count := len(res.Errors)
-						Validate(oapi.Components.Schemas, inSchema, pb, ModeWriteToServer, parsed, res)
-						parseErrCount = len(res.Errors) - count
-						if parseErrCount > 0 {
-							errStatus = http.StatusUnprocessableEntity
+						decoded = bodyDec != nil && bodyDec.Decode(body, v.Field(inputBodyIndex))
+					}
+
+					parseErrCount := 0
+					if !decoded && !op.SkipValidateBody {
+						// Validate the input. First, parse the body into []any or map[string]any
+						// or equivalent, which can be easily validated. Then, convert to the
+						// expected struct type to call the handler.
+						var parsed any
+						var err error
+						if jsonFormats[formatKey(ctx.Header("Content-Type"))] && body[0] == '{' {
+							// Objects are parsed into a pooled map, which gives the same
+							// result as parsing into `any`.
+							err = json.Unmarshal(body, &deps.m)
+							parsed = deps.m
+						} else {
+							err = api.Unmarshal(ctx.Header("Content-Type"), body, &parsed)
+						}
+						if err != nil {
+							// TODO: handle not acceptable
+							errStatus = http.StatusBadRequest
+							res.Errors = append(res.Errors, &ErrorDetail{
+								Location: "body",
+								Message:  err.Error(),
+								Value:    body,
+							})
+							parseErrCount++
+						} else {
+							unknown := op.UnknownFields != UnknownFieldsReject
+							if (unknown || op.StripReadOnly) && stripProperties(oapi.Components.Schemas, inSchema, parsed, unknown, op.StripReadOnly) &&
+								(op.UnknownFields == UnknownFieldsStrip || op.StripReadOnly) && jsonFormats[formatKey(ctx.Header("Content-Type"))] {
+								// Re-encode so the removed properties are neither decoded into
+								// the input struct nor present in `RawBody`.
+								if stripped, err := json.Marshal(parsed); err == nil {
+									body = stripped
+								}
+							}
+
+							pb.Reset()
+							pb.Push("body")
+							count := len(res.Errors)
+							Validate(oapi.Components.Schemas, inSchema, pb, ModeWriteToServer, parsed, res)
+							parseErrCount = len(res.Errors) - count
+							if parseErrCount > 0 {
+								errStatus = http.StatusUnprocessableEntity
+							}
 						}
 					}
-				}
 
-				// We need to get the body into the correct type now that it has been
-				// validated. Benchmarks on Go 1.20 show that using `json.Unmarshal` a
-				// second time is faster than `mapstructure.Decode` or any of the other
-				// common reflection-based approaches when using real-world medium-sized
-				// JSON payloads with lots of strings.
-				f := v.Field(inputBodyIndex)
-				if err := api.Unmarshal(ctx.Header("Content-Type"), body, f.Addr().Interface()); err != nil {
-					if parseErrCount == 0 {
-						// Hmm, this should have worked... validator missed something?
-						res.Errors = append(res.Errors, &ErrorDetail{
-							Location: "body",
-							Message:  err.Error(),
-							Value:    string(body),
+					// We need to get the body into the correct type now that it has been
+					// validated. Benchmarks on Go 1.20 show that using `json.Unmarshal` a
+					// second time is faster than `mapstructure.Decode` or any of the other
+					// common reflection-based approaches when using real-world medium-sized
+					// JSON payloads with lots of strings.
+					if !decoded {
+						f := v.Field(inputBodyIndex)
+						if err := api.Unmarshal(ctx.Header("Content-Type"), body, f.Addr().Interface()); err != nil {
+							if parseErrCount == 0 {
+								// Hmm, this should have worked... validator missed something?
+								res.Errors = append(res.Errors, &ErrorDetail{
+									Location: "body",
+									Message:  err.Error(),
+									Value:    string(body),
+								})
+							}
+						} else {
+							decoded = true
+						}
+					}
+					if decoded {
+						// Set defaults for any fields that were not in the input.
+						defaults.Every(v, func(item reflect.Value, def any) {
+							if item.IsZero() {
+								item.Set(reflect.Indirect(reflect.ValueOf(def)))
+							}
 						})
+
+						if !op.SkipValidateBody && parseErrCount == 0 {
+							// Custom validators only run on schema-valid bodies.
+							validators.EveryPB(pb, v, func(item reflect.Value, _ bool) {
+								if !item.CanAddr() {
+									// Map values aren't addressable, so validate a copy.
+									tmp := reflect.New(item.Type()).Elem()
+									tmp.Set(item)
+									item = tmp
+								}
+								for _, err := range item.Addr().Interface().(Validator).ValidateHuma(ctx) {
+									if res.full() {
+										return
+									}
+									res.Errors = append(res.Errors, validatorError(pb, err))
+								}
+							})
+						}
 					}
+				}
+			}
+
+			if rawBodyIndex != -1 {
+				f := v.Field(rawBodyIndex)
+				if rawBodyReader {
+					f.Set(reflect.ValueOf(bytes.NewReader(body)))
 				} else {
-					// Set defaults for any fields that were not in the input.
-					defaults.Every(v, func(item reflect.Value, def any) {
-						if item.IsZero() {
-							item.Set(reflect.Indirect(reflect.ValueOf(def)))
-						}
-					})
+					f.SetBytes(body)
 				}
+			}
+		}
 
-				buf.Reset()
-				bufPool.Put(buf)
+		var injectErr error
+		injected.EveryField(v, func(f reflect.Value, info *injectInfo) {
+			if injectErr != nil {
+				return
+			}
+			value, err := info.provider(ctx)
+			if err != nil {
+				injectErr = err
+				return
+			}
+			if value != nil {
+				f.Set(reflect.ValueOf(value))
 			}
+		})
+		if injectErr != nil {
+			var se StatusError
+			if !errors.As(injectErr, &se) {
+				se = newAPIError(api, http.StatusInternalServerError, injectErr.Error())
+			}
+			writeStatusErr(api, ctx, se)
+			return
 		}
 
 		resolvers.EveryPB(pb, v, func(item reflect.Value, _ bool) {
@@ -738,33 +1210,24 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		})
 
 		if len(res.Errors) > 0 {
+			translateErrors(language, ctx.Header("Accept-Language"), res.Errors)
 			WriteErr(api, ctx, errStatus, "validation failed", res.Errors...)
 			return
 		}
 
-		output, err := handler(ctx.Context(), &input)
+		output, err := handler(ctx.Context(), input)
 		if err != nil {
-			status := http.StatusInternalServerError
-			if se, ok := err.(StatusError); ok {
-				status = se.GetStatus()
-			} else {
-				err = NewError(http.StatusInternalServerError, err.Error())
-			}
-
-			ct, _ := api.Negotiate(ctx.Header("Accept"))
-			if ctf, ok := err.(ContentTypeFilter); ok {
-				ct = ctf.ContentType(ct)
+			var se StatusError
+			if !errors.As(err, &se) {
+				se = newAPIError(api, http.StatusInternalServerError, err.Error())
 			}
-
-			ctx.SetStatus(status)
-			ctx.SetHeader("Content-Type", ct)
-			api.Marshal(ctx, strconv.Itoa(status), ct, err)
+			writeStatusErr(api, ctx, se)
 			return
 		}
 
 		// Serialize output headers
 		ct := ""
-		vo := reflect.ValueOf(output).Elem()
+		vo := output.Elem()
 		outHeaders.Every(vo, func(f reflect.Value, info *headerInfo) {
 			switch f.Kind() {
 			case reflect.String:
@@ -792,16 +1255,59 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 
 		status := op.DefaultStatus
 		if outStatusIndex != -1 {
-			status = int(vo.Field(outStatusIndex).Int())
+			// A zero status means the handler didn't set one, so use the default.
+			if s := int(vo.Field(outStatusIndex).Int()); s != 0 {
+				status = s
+			}
 		}
 
-		if outBodyIndex != -1 {
+		// Use the first additional body which was set, if any, along with its
+		// status code unless the handler set a dynamic status.
+		var alt *statusBody
+		if vo.IsValid() {
+			for i := range outAltBodies {
+				if !vo.Field(outAltBodies[i].index).IsNil() {
+					alt = &outAltBodies[i]
+					break
+				}
+			}
+		}
+		respKey := strconv.Itoa(op.DefaultStatus)
+		if alt != nil {
+			if status == op.DefaultStatus {
+				status = alt.status
+			}
+			respKey = strconv.Itoa(alt.status)
+		}
+		if cacheControl != "" && status < 400 {
+			ctx.SetHeader("Cache-Control", cacheControl)
+		}
+
+		if outBodyIndex != -1 || alt != nil {
 			// Serialize output body
-			body := vo.Field(outBodyIndex).Interface()
+			var body any
+			if alt != nil {
+				body = vo.Field(alt.index).Interface()
+			} else {
+				body = vo.Field(outBodyIndex).Interface()
 
-			if outBodyFunc {
-				body.(func(Context))(ctx)
-				return
+				if outBodyFunc {
+					body.(func(Context))(ctx)
+					return
+				}
+
+				if outBodyCT != "" {
+					if ct == "" {
+						ctx.SetHeader("Content-Type", outBodyCT)
+					}
+					ctx.SetStatus(status)
+					if f := vo.Field(outBodyIndex); f.Kind() == reflect.String {
+						io.WriteString(ctx.BodyWriter(), f.String())
+					} else {
+						ctx.BodyWriter().Write(f.Bytes())
+					}
+					return
+				}
 			}
 
 			if b, ok := body.([]byte); ok {
@@ -825,18 +1331,93 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				ctx.SetHeader("Content-Type", ct)
 			}
 
-			ctx.SetStatus(status)
-			api.Marshal(ctx, strconv.Itoa(op.DefaultStatus), ct, body)
+			marshalWithStatus(api, ctx, status, respKey, ct, body)
 		} else {
 			ctx.SetStatus(status)
 		}
-	})
+	}))
+}
+
+// marshalWithStatus marshals the response body with the status code, which
+// is only written along with the body so that transformers can still set
+// response headers.
+func marshalWithStatus(api API, ctx Context, status int, respKey, ct string, body any) {
+	sc := &statusContext{humaContext: ctx, status: status}
+	api.Marshal(sc, respKey, ct, body)
+	sc.writeStatus()
+}
+
+// statusContext delays writing the status code until the body is written.
+type statusContext struct {
+	humaContext
+	status  int
+	written bool
+}
+
+// Unwrap returns the wrapped context, see `UnwrapContext`.
+func (c *statusContext) Unwrap() Context {
+	return c.humaContext
+}
+
+func (c *statusContext) writeStatus() {
+	if !c.written {
+		c.written = true
+		c.humaContext.SetStatus(c.status)
+	}
+}
+
+func (c *statusContext) SetStatus(code int) {
+	if c.written || isInterim(code) {
+		c.humaContext.SetStatus(code)
+		return
+	}
+	c.status = code
+}
+
+func (c *statusContext) BodyWriter() io.Writer {
+	c.writeStatus()
+	return c.humaContext.BodyWriter()
+}
+
+func (c *statusContext) Flush() error {
+	c.writeStatus()
+	return Flush(c.humaContext)
+}
+
+// statusBody is an output struct field for a response body which is only
+// sent with a specific status code, e.g. a `Conflict` field with a
+// `status:"409"` tag.
+type statusBody struct {
+	index  int
+	status int
+}
+
+func findStatusBodies(t reflect.Type) []statusBody {
+	bodies := []statusBody{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("status")
+		if tag == "" || f.Name == "Body" || f.Name == "Status" {
+			continue
+		}
+		status, err := strconv.Atoi(tag)
+		if err != nil || status < 100 || status > 599 {
+			panic(fmt.Sprintf("invalid status %q for output field %s", tag, f.Name))
+		}
+		switch f.Type.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		default:
+			panic(fmt.Sprintf("output field %s with a status must be a pointer, slice, map, or interface so it can be unset", f.Name))
+		}
+		bodies = append(bodies, statusBody{index: i, status: status})
+	}
+	return bodies
 }
 
 // AutoRegister auto-detects operation registration methods and registers them
-// with the given API. Any method named `Register...` will be called and
-// passed the API as the only argument. Since registration happens at
-// service startup, no errors are returned and methods should panic on error.
+// with the given API. Any method named `Register...` which takes the API as
+// its only argument will be called. Since registration happens at service
+// startup, no errors are returned and methods should panic on error.
 //
 //	type ItemServer struct {}
 //
@@ -853,14 +1434,69 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 //		itemServer := &ItemServer{}
 //		huma.AutoRegister(api, itemServer)
 //	}
+//
+// Alternatively, a `Register...` method can take a `*huma.Operation` to
+// describe the handler method of the same name without the prefix, which must
+// have the usual `func(context.Context, *Input) (*Output, error)` signature.
+// The operation ID defaults to the kebab-cased method name, e.g. `list-items`.
+//
+//	func (s *ItemServer) RegisterListItems(op *huma.Operation) {
+//		op.Method = http.MethodGet
+//		op.Path = "/items"
+//	}
+//
+//	func (s *ItemServer) ListItems(ctx context.Context, input *ListInput) (*ListOutput, error) {
+//		// ...
+//	}
 func AutoRegister(api API, server any) {
-	args := []reflect.Value{reflect.ValueOf(server), reflect.ValueOf(api)}
-
-	t := reflect.TypeOf(server)
+	v := reflect.ValueOf(server)
+	t := v.Type()
 	for i := 0; i < t.NumMethod(); i++ {
 		m := t.Method(i)
-		if strings.HasPrefix(m.Name, "Register") && len(m.Name) > 8 {
-			m.Func.Call(args)
+		name, ok := strings.CutPrefix(m.Name, "Register")
+		if !ok || name == "" {
+			continue
+		}
+
+		mt := v.Method(i).Type()
+		switch {
+		case mt.NumIn() == 1 && mt.In(0) == operationPtrType:
+			autoRegisterHandler(api, v, name, v.Method(i))
+		case mt.NumIn() == 1 && reflect.TypeOf(api).AssignableTo(mt.In(0)):
+			v.Method(i).Call([]reflect.Value{reflect.ValueOf(api)})
+		default:
+			panic(fmt.Sprintf("method %s must take either huma.API or *huma.Operation", m.Name))
 		}
 	}
 }
+
+var (
+	operationPtrType = reflect.TypeOf(&Operation{})
+	contextType      = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType        = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// autoRegisterHandler registers the server's handler method with the given
+// name, using the operation described by its `Register...` companion.
+func autoRegisterHandler(api API, server reflect.Value, name string, companion reflect.Value) {
+	handler := server.MethodByName(name)
+	if !handler.IsValid() {
+		panic(fmt.Sprintf("method Register%s has no handler method %s", name, name))
+	}
+	ht := handler.Type()
+	if ht.NumIn() != 2 || ht.In(0) != contextType ||
+		ht.In(1).Kind() != reflect.Pointer || ht.In(1).Elem().Kind() != reflect.Struct ||
+		ht.NumOut() != 2 || ht.Out(1) != errorType ||
+		ht.Out(0).Kind() != reflect.Pointer || ht.Out(0).Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("handler method %s must be func(context.Context, *Input) (*Output, error)", name))
+	}
+
+	op := Operation{OperationID: casing.Kebab(name)}
+	companion.Call([]reflect.Value{reflect.ValueOf(&op)})
+
+	register(api, op, ht.In(1).Elem(), ht.Out(0).Elem(), func(ctx context.Context, input reflect.Value) (reflect.Value, error) {
+		out := handler.Call([]reflect.Value{reflect.ValueOf(ctx), input})
+		err, _ := out[1].Interface().(error)
+		return out[0], err
+	})
+}
@@ -1,20 +1,29 @@
 package huma
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/tls"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"net/url"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2/queryparam"
+	"github.com/fxamacker/cbor/v2"
 	"github.com/go-chi/chi"
+	"github.com/goccy/go-yaml"
+	"github.com/google/uuid"
 	"github.com/mitchellh/mapstructure"
 	"github.com/stretchr/testify/assert"
 )
@@ -45,185 +54,2337 @@ func (ctx *testContext) Host() string {
 	return ctx.r.Host
 }
 
+func (ctx *testContext) RemoteAddr() string {
+	return ctx.r.RemoteAddr
+}
+
+func (ctx *testContext) TLS() *tls.ConnectionState {
+	return ctx.r.TLS
+}
+
+func (ctx *testContext) Version() ProtoVersion {
+	return ProtoVersion{
+		Proto:      ctx.r.Proto,
+		ProtoMajor: ctx.r.ProtoMajor,
+		ProtoMinor: ctx.r.ProtoMinor,
+	}
+}
+
 func (ctx *testContext) URL() url.URL {
 	return *ctx.r.URL
 }
 
-func (ctx *testContext) Param(name string) string {
-	return chi.URLParam(ctx.r, name)
-}
+func (ctx *testContext) Param(name string) string {
+	return chi.URLParam(ctx.r, name)
+}
+
+func (ctx *testContext) Query(name string) string {
+	return queryparam.Get(ctx.r.URL.RawQuery, name)
+}
+
+func (ctx *testContext) Header(name string) string {
+	return ctx.r.Header.Get(name)
+}
+
+func (ctx *testContext) EachHeader(cb func(name, value string)) {
+	for name, values := range ctx.r.Header {
+		for _, value := range values {
+			cb(name, value)
+		}
+	}
+}
+
+func (ctx *testContext) Body() ([]byte, error) {
+	return io.ReadAll(ctx.r.Body)
+}
+
+func (ctx *testContext) BodyReader() io.Reader {
+	return ctx.r.Body
+}
+
+func (ctx *testContext) SetReadDeadline(deadline time.Time) error {
+	return http.NewResponseController(ctx.w).SetReadDeadline(deadline)
+}
+
+func (ctx *testContext) SetWriteDeadline(deadline time.Time) error {
+	return http.NewResponseController(ctx.w).SetWriteDeadline(deadline)
+}
+
+func (ctx *testContext) SetStatus(code int) {
+	ctx.w.WriteHeader(code)
+}
+
+func (ctx *testContext) AppendHeader(name string, value string) {
+	ctx.w.Header().Add(name, value)
+}
+
+func (ctx *testContext) SetHeader(name string, value string) {
+	ctx.w.Header().Set(name, value)
+}
+
+func (ctx *testContext) BodyWriter() io.Writer {
+	return ctx.w
+}
+
+type testAdapter struct {
+	router chi.Router
+}
+
+func (a *testAdapter) Handle(op *Operation, handler func(Context)) {
+	a.router.MethodFunc(op.Method, op.Path, func(w http.ResponseWriter, r *http.Request) {
+		handler(&testContext{op: op, r: r, w: w})
+	})
+}
+
+func (a *testAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.router.ServeHTTP(w, r)
+}
+
+func NewTestAdapter(r chi.Router, config Config) API {
+	return NewAPI(config, &testAdapter{router: r})
+}
+
+type ExhaustiveErrorsInputBody struct {
+	Name  string `json:"name" maxLength:"10"`
+	Count int    `json:"count" minimum:"1"`
+}
+
+func (b *ExhaustiveErrorsInputBody) Resolve(ctx Context) []error {
+	return []error{fmt.Errorf("body resolver error")}
+}
+
+type ExhaustiveErrorsInput struct {
+	ID   string                    `path:"id" maxLength:"5"`
+	Body ExhaustiveErrorsInputBody `json:"body"`
+}
+
+func (i *ExhaustiveErrorsInput) Resolve(ctx Context) []error {
+	return []error{&ErrorDetail{
+		Location: "path.id",
+		Message:  "input resolver error",
+		Value:    i.ID,
+	}}
+}
+
+type ExhaustiveErrorsOutput struct {
+}
+
+func TestExhaustiveErrors(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+	Register(app, Operation{
+		OperationID: "test",
+		Method:      http.MethodPut,
+		Path:        "/errors/{id}",
+	}, func(ctx context.Context, input *ExhaustiveErrorsInput) (*ExhaustiveErrorsOutput, error) {
+		return &ExhaustiveErrorsOutput{}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, "/errors/123456", strings.NewReader(`{"name": "12345678901", "count": 0}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{
+		"$schema": "http:///schemas/ErrorModel.json",
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "validation failed",
+		"errors": [
+			{
+				"message": "expected length <= 5",
+				"location": "path.id",
+				"value": "123456"
+			}, {
+				"message": "expected length <= 10",
+				"location": "body.name",
+				"value": "12345678901"
+			}, {
+				"message": "expected number >= 1",
+				"location": "body.count",
+				"value": 0
+			}, {
+				"message": "input resolver error",
+				"location": "path.id",
+				"value": "123456"
+			}, {
+				"message": "body resolver error"
+			}
+		]
+	}`, w.Body.String())
+}
+
+type NestedResolversStruct struct {
+	Field2 string `json:"field2"`
+}
+
+func (b *NestedResolversStruct) Resolve(ctx Context, prefix *PathBuffer) []error {
+	return []error{&ErrorDetail{
+		Location: prefix.With("field2"),
+		Message:  "resolver error",
+		Value:    b.Field2,
+	}}
+}
+
+var _ ResolverWithPath = (*NestedResolversStruct)(nil)
+
+type NestedResolversBody struct {
+	Field1 map[string][]NestedResolversStruct `json:"field1"`
+}
+
+type NestedResolverRequest struct {
+	Body NestedResolversBody
+}
+
+func TestNestedResolverWithPath(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+	Register(app, Operation{
+		OperationID: "test",
+		Method:      http.MethodPut,
+		Path:        "/test",
+	}, func(ctx context.Context, input *NestedResolverRequest) (*struct{}, error) {
+		return nil, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, "/test", strings.NewReader(`{"field1": {"foo": [{"field2": "bar"}]}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"location":"body.field1.foo[0].field2"`)
+}
+
+func TestRawBody(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+	Register(app, Operation{
+		OperationID: "raw-bytes",
+		Method:      http.MethodPut,
+		Path:        "/raw-bytes",
+	}, func(ctx context.Context, input *struct {
+		RawBody []byte
+	}) (*struct{}, error) {
+		assert.Equal(t, `{"foo": "bar"}`, string(input.RawBody))
+		return nil, nil
+	})
+
+	Register(app, Operation{
+		OperationID: "raw-reader",
+		Method:      http.MethodPut,
+		Path:        "/raw-reader",
+	}, func(ctx context.Context, input *struct {
+		RawBody io.Reader
+		Body    struct {
+			Foo string `json:"foo"`
+		}
+	}) (*struct{}, error) {
+		b, err := io.ReadAll(input.RawBody)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"foo": "bar"}`, string(b))
+		assert.Equal(t, "bar", input.Body.Foo)
+		return nil, nil
+	})
+
+	for _, path := range []string{"/raw-bytes", "/raw-reader"} {
+		req, _ := http.NewRequest(http.MethodPut, path, strings.NewReader(`{"foo": "bar"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	}
+
+	assert.Equal(t, "binary", app.OpenAPI().Paths["/raw-bytes"].Put.RequestBody.Content["application/octet-stream"].Schema.Format)
+
+	assert.Panics(t, func() {
+		Register(app, Operation{
+			OperationID: "raw-invalid",
+			Method:      http.MethodPut,
+			Path:        "/raw-invalid",
+		}, func(ctx context.Context, input *struct {
+			RawBody string
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestBodyLimits(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+	Register(app, Operation{
+		OperationID:  "limited",
+		Method:       http.MethodPut,
+		Path:         "/limited",
+		MaxBodyBytes: 5,
+	}, func(ctx context.Context, input *struct {
+		RawBody []byte
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	for _, item := range []struct {
+		body   string
+		status int
+	}{
+		{"1234", http.StatusNoContent},
+		{"12345", http.StatusNoContent},
+		{"123456", http.StatusRequestEntityTooLarge},
+	} {
+		req, _ := http.NewRequest(http.MethodPut, "/limited", strings.NewReader(item.body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, item.status, w.Code, item.body)
+	}
+
+	// Content-Length is checked before reading anything.
+	req, _ := http.NewRequest(http.MethodPut, "/limited", strings.NewReader("123"))
+	req.Header.Set("Content-Length", "100")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "limit=5 bytes")
+}
+
+func TestWriteDeadline(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+	Register(app, Operation{
+		OperationID: "slow",
+		Method:      http.MethodGet,
+		Path:        "/slow",
+	}, func(ctx context.Context, input *struct {
+		Extend bool `query:"extend"`
+	}) (*StreamResponse, error) {
+		return &StreamResponse{Body: func(ctx Context) {
+			if input.Extend {
+				assert.NoError(t, ctx.SetWriteDeadline(time.Now().Add(5*time.Second)))
+			}
+			time.Sleep(200 * time.Millisecond)
+			ctx.SetHeader("Content-Type", "text/plain")
+			ctx.BodyWriter().Write([]byte("done"))
+		}}, nil
+	})
+
+	server := httptest.NewUnstartedServer(r)
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	// The server-wide timeout is exceeded, so the response is never sent.
+	_, err := http.Get(server.URL + "/slow")
+	assert.Error(t, err)
+
+	// Extending the deadline gives the response time to complete.
+	resp, err := http.Get(server.URL + "/slow?extend=true")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "done", string(body))
+}
+
+func TestSpecEndpoints(t *testing.T) {
+	r := chi.NewRouter()
+	NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	for _, item := range []struct {
+		path     string
+		ct       string
+		contains string
+	}{
+		{"/openapi.json", "application/vnd.oai.openapi+json", `"title":"Test API"`},
+		{"/openapi.yaml", "application/vnd.oai.openapi+yaml", "title: Test API"},
+		{"/docs", "text/html", "<title>Test API Reference</title>"},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, item.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, item.path)
+		assert.Equal(t, item.ct, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), item.contains)
+	}
+
+	// Paths can be changed or disabled.
+	r = chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.OpenAPIPath = "/spec"
+	config.DocsPath = ""
+	NewTestAdapter(r, config)
+
+	for path, status := range map[string]int{
+		"/spec.json":    http.StatusOK,
+		"/openapi.json": http.StatusNotFound,
+		"/docs":         http.StatusNotFound,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, status, w.Code, path)
+	}
+}
+
+func TestDocsUI(t *testing.T) {
+	selfHosted := SwaggerUI()
+	selfHosted.AssetsURL = "/static/swagger"
+
+	for _, item := range []struct {
+		name     string
+		ui       DocsUI
+		contains []string
+	}{
+		{"elements", StoplightElements(), []string{`apiDescriptionUrl="/openapi.yaml"`, "unpkg.com/@stoplight/elements"}},
+		{"swagger", SwaggerUI(), []string{`url: "\/openapi.yaml"`, "swagger-ui-bundle.js"}},
+		{"swagger-self-hosted", selfHosted, []string{`src="/static/swagger/swagger-ui-bundle.js"`}},
+		{"redoc", Redoc(), []string{`<redoc spec-url="/openapi.yaml">`, "redoc.standalone.js"}},
+		{"scalar", Scalar(), []string{`data-url="/openapi.yaml"`, "@scalar/api-reference"}},
+		{"custom", NewDocsTemplate(`<h1>{{.Title}}</h1><a href="{{.SpecURL}}">spec</a>`, ""), []string{`<h1>Test API &lt;v1&gt; Reference</h1><a href="/openapi.yaml">`}},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			config := DefaultConfig("Test API <v1>", "1.0.0")
+			config.DocsUI = item.ui
+			NewTestAdapter(r, config)
+
+			req, _ := http.NewRequest(http.MethodGet, "/docs", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+			for _, s := range item.contains {
+				assert.Contains(t, w.Body.String(), s)
+			}
+		})
+	}
+}
+
+func TestSecurity(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Components.SecuritySchemes = map[string]*SecurityScheme{
+		"bearer": NewBearerAuthScheme("JWT"),
+		"basic":  NewBasicAuthScheme(),
+		"apiKey": NewAPIKeyScheme("header", "X-API-Key"),
+		"oauth2": NewOAuth2Scheme(&OAuthFlows{
+			ClientCredentials: &OAuthFlow{
+				TokenURL: "https://example.com/token",
+				Scopes:   map[string]string{"read": "Read access"},
+			},
+		}),
+		"oidc": NewOpenIDConnectScheme("https://example.com/.well-known/openid-configuration"),
+	}
+	app := NewTestAdapter(r, config)
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}
+
+	Register(app, Operation{
+		OperationID: "secure",
+		Method:      http.MethodGet,
+		Path:        "/secure",
+		Security: []map[string][]string{
+			{"bearer": {}},
+			{"oauth2": {"read"}},
+			{"apiKey": {}, "basic": {}, "oidc": {"anything"}},
+		},
+	}, handler)
+
+	spec, _ := json.Marshal(app.OpenAPI())
+	assert.Contains(t, string(spec), `"bearerFormat":"JWT"`)
+	assert.Contains(t, string(spec), `"tokenUrl":"https://example.com/token"`)
+	assert.NotContains(t, string(spec), `"authorizationUrl"`)
+
+	assert.PanicsWithValue(t, "operation unknown-scheme: unknown security scheme missing", func() {
+		Register(app, Operation{
+			OperationID: "unknown-scheme",
+			Method:      http.MethodGet,
+			Path:        "/unknown-scheme",
+			Security:    []map[string][]string{{"missing": {}}},
+		}, handler)
+	})
+
+	assert.PanicsWithValue(t, "operation unknown-scope: unknown scope write for security scheme oauth2", func() {
+		Register(app, Operation{
+			OperationID: "unknown-scope",
+			Method:      http.MethodGet,
+			Path:        "/unknown-scope",
+			Security:    []map[string][]string{{"oauth2": {"write"}}},
+		}, handler)
+	})
+
+	assert.Panics(t, func() {
+		NewAPIKeyScheme("body", "key")
+	})
+}
+
+func TestAuthenticator(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Components.SecuritySchemes = map[string]*SecurityScheme{
+		"bearer": NewBearerAuthScheme("JWT"),
+		"apiKey": NewAPIKeyScheme("header", "X-API-Key"),
+	}
+	config.Security = []map[string][]string{{"bearer": {}}}
+	config.Authenticator = func(ctx Context, scheme string, scopes []string) error {
+		switch scheme {
+		case "bearer":
+			token := strings.TrimPrefix(ctx.Header("Authorization"), "Bearer ")
+			if token == "" {
+				return fmt.Errorf("missing token")
+			}
+			for _, scope := range scopes {
+				if scope != token {
+					return Error403Forbidden("missing scope " + scope)
+				}
+			}
+			return nil
+		case "apiKey":
+			if ctx.Header("X-API-Key") != "secret" {
+				return fmt.Errorf("invalid api key")
+			}
+			return nil
+		}
+		return fmt.Errorf("unknown scheme %s", scheme)
+	}
+	config.CORS = &CORSConfig{AllowOrigins: []string{"https://example.com"}}
+	app := NewTestAdapter(r, config)
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}
+	Register(app, Operation{
+		OperationID: "default-security",
+		Method:      http.MethodGet,
+		Path:        "/default",
+	}, handler)
+	Register(app, Operation{
+		OperationID: "scoped",
+		Method:      http.MethodGet,
+		Path:        "/scoped",
+		Security: []map[string][]string{
+			{"bearer": {"admin"}},
+			{"apiKey": {}},
+		},
+	}, handler)
+	Register(app, Operation{
+		OperationID: "public",
+		Method:      http.MethodGet,
+		Path:        "/public",
+		Security:    []map[string][]string{},
+	}, handler)
+
+	for _, item := range []struct {
+		path    string
+		headers map[string]string
+		status  int
+	}{
+		{"/default", nil, http.StatusUnauthorized},
+		{"/default", map[string]string{"Authorization": "Bearer user"}, http.StatusNoContent},
+		{"/scoped", map[string]string{"Authorization": "Bearer user"}, http.StatusForbidden},
+		{"/scoped", map[string]string{"Authorization": "Bearer admin"}, http.StatusNoContent},
+		{"/scoped", map[string]string{"X-API-Key": "secret"}, http.StatusNoContent},
+		{"/public", nil, http.StatusNoContent},
+		{"/openapi.json", nil, http.StatusOK},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, item.path, nil)
+		for k, v := range item.headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, item.status, w.Code, item.path, item.headers, w.Body.String())
+		if item.status == http.StatusUnauthorized {
+			assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+			assert.Contains(t, w.Body.String(), "missing token")
+		}
+	}
+
+	// Browsers can read auth errors.
+	req, _ := http.NewRequest(http.MethodGet, "/default", nil)
+	req.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "https://example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Auth errors are documented when security applies.
+	assert.NotNil(t, app.OpenAPI().Paths["/default"].Get.Responses["401"])
+	assert.NotNil(t, app.OpenAPI().Paths["/scoped"].Get.Responses["403"])
+	assert.Nil(t, app.OpenAPI().Paths["/public"].Get.Responses["401"])
+}
+
+func TestOperationMiddlewares(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	calls := []string{}
+	record := func(name string) Middleware {
+		return func(ctx Context, next func(Context)) {
+			calls = append(calls, name)
+			next(ctx)
+		}
+	}
+
+	Register(app, Operation{
+		OperationID: "test",
+		Method:      http.MethodGet,
+		Path:        "/test",
+		Middlewares: []Middleware{
+			record("first"),
+			record("second"),
+			func(ctx Context, next func(Context)) {
+				if ctx.Header("X-Block") != "" {
+					ctx.SetStatus(http.StatusTooManyRequests)
+					return
+				}
+				next(ctx)
+			},
+		},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"first", "second", "handler"}, calls)
+
+	calls = nil
+	req, _ = http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Block", "true")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, []string{"first", "second"}, calls)
+}
+
+func TestMiddlewareContextValues(t *testing.T) {
+	type contextKey string
+
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Register(app, Operation{
+		OperationID: "test",
+		Method:      http.MethodGet,
+		Path:        "/test",
+		Middlewares: []Middleware{
+			func(ctx Context, next func(Context)) {
+				next(WithValue(ctx, contextKey("user"), ctx.Header("X-User")))
+			},
+			func(ctx Context, next func(Context)) {
+				// Values set by earlier middleware are visible to later ones.
+				user := ctx.Context().Value(contextKey("user")).(string)
+				next(WithValue(ctx, contextKey("greeting"), "hello "+user))
+			},
+		},
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: ctx.Value(contextKey("greeting")).(string)}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-User", "alice")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"hello alice"`, strings.TrimSpace(w.Body.String()))
+}
+
+func TestCORS(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Servers = []*Server{{URL: "https://example.com/api"}}
+	config.Components.SecuritySchemes = map[string]*SecurityScheme{
+		"apiKey": NewAPIKeyScheme("header", "X-API-Key"),
+	}
+	config.CORS = &CORSConfig{MaxAge: time.Hour}
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "get-item",
+		Method:      http.MethodGet,
+		Path:        "/items/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID      string `path:"id"`
+		IfMatch string `header:"If-Match"`
+	}) (*struct {
+		ETag string `header:"ETag"`
+	}, error) {
+		return &struct {
+			ETag string `header:"ETag"`
+		}{ETag: "abc"}, nil
+	})
+	Register(app, Operation{
+		OperationID: "put-item",
+		Method:      http.MethodPut,
+		Path:        "/items/{id}",
+		Security:    []map[string][]string{{"apiKey": {}}},
+	}, func(ctx context.Context, input *struct {
+		ID   string `path:"id"`
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	// Preflight from an allowed origin.
+	req, _ := http.NewRequest(http.MethodOptions, "/items/123", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, PUT", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type, If-Match, X-API-Key", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "3600", w.Header().Get("Access-Control-Max-Age"))
+
+	// Preflight from another origin.
+	req, _ = http.NewRequest(http.MethodOptions, "/items/123", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Actual request.
+	req, _ = http.NewRequest(http.MethodGet, "/items/123", nil)
+	req.Header.Set("Origin", "https://example.com")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "ETag", w.Header().Get("Access-Control-Expose-Headers"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+}
+
+func TestCORSAnyOriginCredentials(t *testing.T) {
+	config := DefaultConfig("Test API", "1.0.0")
+	config.CORS = &CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true}
+	assert.Panics(t, func() {
+		NewTestAdapter(chi.NewRouter(), config)
+	})
+}
+
+func TestDebugValidateResponses(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.DebugValidateResponses = true
+	config.Transformers = append(config.Transformers, FieldSelectTransform)
+	app := NewTestAdapter(r, config)
+
+	type Body struct {
+		Name  string `json:"name" minLength:"3"`
+		Count int    `json:"count" minimum:"0"`
+	}
+
+	Register(app, Operation{
+		OperationID: "test",
+		Method:      http.MethodGet,
+		Path:        "/test",
+	}, func(ctx context.Context, input *struct {
+		Name  string `query:"name"`
+		Count int    `query:"count"`
+	}) (*struct {
+		ETag string `header:"ETag"`
+		Body Body
+	}, error) {
+		resp := &struct {
+			ETag string `header:"ETag"`
+			Body Body
+		}{ETag: "abc"}
+		resp.Body.Name = input.Name
+		resp.Body.Count = input.Count
+		return resp, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/test?name=valid&count=1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"$schema": "http:///schemas/Body.json", "name": "valid", "count": 1}`, w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodGet, "/test?name=no&count=-1", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), "response validation failed")
+	assert.Contains(t, w.Body.String(), "body.name")
+	assert.Contains(t, w.Body.String(), "body.count")
+
+	// Bodies are validated before transformers, so selecting fields doesn't
+	// cause missing required fields.
+	req, _ = http.NewRequest(http.MethodGet, "/test?name=valid&count=1", nil)
+	req.Header.Set("Fields", "name")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "abc", w.Header().Get("ETag"))
+	assert.JSONEq(t, `{"name": "valid"}`, w.Body.String())
+}
+
+func TestDebugValidateResponsesCompressed(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.DebugValidateResponses = true
+	config.Compression = &CompressionConfig{MinSize: 10}
+	app := NewTestAdapter(r, config)
+
+	type Body struct {
+		Name string `json:"name" maxLength:"100"`
+	}
+
+	Register(app, Operation{
+		OperationID: "test",
+		Method:      http.MethodGet,
+		Path:        "/test",
+	}, func(ctx context.Context, input *struct {
+		Size int `query:"size"`
+	}) (*struct{ Body Body }, error) {
+		return &struct{ Body Body }{Body: Body{Name: strings.Repeat("a", input.Size)}}, nil
+	})
+
+	// The validator sees the uncompressed body.
+	req, _ := http.NewRequest(http.MethodGet, "/test?size=50", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	reader, err := gzip.NewReader(w.Body)
+	if assert.NoError(t, err) {
+		var body Body
+		assert.NoError(t, json.NewDecoder(reader).Decode(&body))
+		assert.Len(t, body.Name, 50)
+	}
+
+	// Invalid responses are still caught, and the error is compressed.
+	req, _ = http.NewRequest(http.MethodGet, "/test?size=150", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	reader, err = gzip.NewReader(w.Body)
+	if assert.NoError(t, err) {
+		b, _ := io.ReadAll(reader)
+		assert.Contains(t, string(b), "response validation failed")
+	}
+}
+
+func TestGroup(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Components.SecuritySchemes = map[string]*SecurityScheme{
+		"bearer": NewBearerAuthScheme(""),
+	}
+	app := NewTestAdapter(r, config)
+
+	calls := []string{}
+	record := func(name string) Middleware {
+		return func(ctx Context, next func(Context)) {
+			calls = append(calls, name)
+			next(ctx)
+		}
+	}
+
+	v1 := NewGroup(app, "/v1")
+	v1.Tags = []string{"v1"}
+	v1.Security = []map[string][]string{{"bearer": {}}}
+	v1.Middlewares = []Middleware{record("v1")}
+
+	admin := NewGroup(v1, "/admin")
+	admin.Tags = []string{"admin"}
+	admin.Middlewares = []Middleware{record("admin")}
+
+	Register(admin, Operation{
+		OperationID: "list-users",
+		Method:      http.MethodGet,
+		Path:        "/users",
+		Tags:        []string{"users"},
+		Middlewares: []Middleware{record("op")},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	})
+
+	op := app.OpenAPI().Paths["/v1/admin/users"].Get
+	assert.NotNil(t, op)
+	assert.Equal(t, []string{"v1", "admin", "users"}, op.Tags)
+	assert.Equal(t, v1.Security, op.Security)
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/admin/users", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"v1", "admin", "op", "handler"}, calls)
+}
+
+func TestDefaultStatus(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	type CreateOutput struct {
+		Status   int
+		Location string `header:"Location"`
+		Body     struct {
+			ID string `json:"id"`
+		}
+	}
+
+	Register(app, Operation{
+		OperationID:   "create",
+		Method:        http.MethodPost,
+		Path:          "/things",
+		DefaultStatus: http.StatusCreated,
+	}, func(ctx context.Context, input *struct {
+		Exists bool `query:"exists"`
+	}) (*CreateOutput, error) {
+		resp := &CreateOutput{Location: "/things/abc"}
+		if input.Exists {
+			// Dynamic status overrides the default.
+			resp.Status = http.StatusOK
+		}
+		resp.Body.ID = "abc"
+		return resp, nil
+	})
+
+	Register(app, Operation{
+		OperationID: "delete",
+		Method:      http.MethodDelete,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	create := app.OpenAPI().Paths["/things"].Post
+	assert.NotNil(t, create.Responses["201"].Content["application/json"])
+	assert.NotNil(t, create.Responses["201"].Headers["Location"])
+	assert.Nil(t, create.Responses["200"])
+
+	del := app.OpenAPI().Paths["/things/{id}"].Delete
+	assert.Equal(t, http.StatusNoContent, del.DefaultStatus)
+	assert.Nil(t, del.Responses["204"].Content)
+
+	for _, item := range []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodPost, "/things", http.StatusCreated},
+		{http.MethodPost, "/things?exists=true", http.StatusOK},
+		{http.MethodDelete, "/things/abc", http.StatusNoContent},
+	} {
+		req, _ := http.NewRequest(item.method, item.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, item.status, w.Code, item.path)
+		if item.status == http.StatusNoContent {
+			assert.Empty(t, w.Body.String())
+			assert.Empty(t, w.Header().Get("Content-Type"))
+		}
+	}
+
+	// A body can't be sent with a 204 No Content.
+	assert.PanicsWithValue(t, "operation bad: default status 204 cannot have a response body", func() {
+		Register(app, Operation{
+			OperationID:   "bad",
+			Method:        http.MethodGet,
+			Path:          "/bad",
+			DefaultStatus: http.StatusNoContent,
+		}, func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestMultipleResponseBodies(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	type Thing struct {
+		ID string `json:"id"`
+	}
+	type ConflictInfo struct {
+		ExistingID string `json:"existingId"`
+	}
+	type PutOutput struct {
+		ETag     string        `header:"ETag"`
+		Body     *Thing        // 200 OK
+		Created  *Thing        `status:"201"`
+		Conflict *ConflictInfo `status:"409"`
+	}
+
+	Register(app, Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*PutOutput, error) {
+		resp := &PutOutput{ETag: "abc"}
+		switch input.ID {
+		case "new":
+			resp.Created = &Thing{ID: input.ID}
+		case "conflict":
+			resp.Conflict = &ConflictInfo{ExistingID: "other"}
+		default:
+			resp.Body = &Thing{ID: input.ID}
+		}
+		return resp, nil
+	})
+
+	responses := app.OpenAPI().Paths["/things/{id}"].Put.Responses
+	registry := app.OpenAPI().Components.Schemas
+	assert.Equal(t, "#/components/schemas/Thing", responses["200"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/Thing", responses["201"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/ConflictInfo", responses["409"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "Conflict", responses["409"].Description)
+	assert.NotNil(t, registry.Map()["ConflictInfo"])
+	assert.Nil(t, responses["200"].Headers["Conflict"])
+
+	for _, item := range []struct {
+		id     string
+		status int
+		body   string
+	}{
+		{"abc", http.StatusOK, `"id":"abc"`},
+		{"new", http.StatusCreated, `"id":"new"`},
+		{"conflict", http.StatusConflict, `"existingId":"other"`},
+	} {
+		req, _ := http.NewRequest(http.MethodPut, "/things/"+item.id, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, item.status, w.Code)
+		assert.Contains(t, w.Body.String(), item.body)
+		assert.Equal(t, "abc", w.Header().Get("ETag"))
+		assert.Empty(t, w.Header().Get("Conflict"))
+	}
+
+	assert.Panics(t, func() {
+		Register(app, Operation{
+			OperationID: "bad",
+			Method:      http.MethodGet,
+			Path:        "/bad",
+		}, func(ctx context.Context, input *struct{}) (*struct {
+			Created Thing `status:"201"`
+		}, error) {
+			return nil, nil
+		})
+	})
+}
+
+type CustomError struct {
+	Code    int      `json:"code"`
+	Message string   `json:"message"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+func (e *CustomError) Error() string {
+	return e.Message
+}
+
+func (e *CustomError) GetStatus() int {
+	return e.Code
+}
+
+func TestErrors(t *testing.T) {
+	// The custom error type doesn't leak into APIs created afterwards.
+	for _, custom := range []bool{true, false} {
+		r := chi.NewRouter()
+		config := DefaultConfig("Test API", "1.0.0")
+		if custom {
+			config.NewError = func(status int, msg string, errs ...error) StatusError {
+				reasons := make([]string, len(errs))
+				for i, err := range errs {
+					reasons[i] = err.Error()
+				}
+				return &CustomError{Code: status, Message: msg, Reasons: reasons}
+			}
+		}
+		app := NewTestAdapter(r, config)
+
+		Register(app, Operation{
+			OperationID: "test",
+			Method:      http.MethodGet,
+			Path:        "/test",
+			Errors:      []int{http.StatusNotFound},
+		}, func(ctx context.Context, input *struct {
+			Count int `query:"count" minimum:"1"`
+		}) (*struct{}, error) {
+			// Status errors can be wrapped.
+			return nil, fmt.Errorf("lookup failed: %w", Error404NotFound("not found"))
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "/test?count=5", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+		if custom {
+			// Errors from the `ErrorXXX` constructors use the API's error type.
+			assert.Contains(t, w.Body.String(), `"code":404`)
+		} else {
+			assert.Contains(t, w.Body.String(), `"status":404`)
+		}
+
+		req, _ = http.NewRequest(http.MethodGet, "/test?count=0", nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+		errSchema := app.OpenAPI().Paths["/test"].Get.Responses["404"]
+		if custom {
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), `"code":422`)
+			assert.Contains(t, w.Body.String(), `"reasons":["expected number \u003e= 1`)
+			assert.Equal(t, "#/components/schemas/CustomError", errSchema.Content["application/json"].Schema.Ref)
+		} else {
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), `"status":422`)
+			assert.Equal(t, "#/components/schemas/ErrorModel", errSchema.Content["application/problem+json"].Schema.Ref)
+		}
+	}
+}
+
+func TestErrorExtensions(t *testing.T) {
+	origModel, origDetail := ErrorModelExtensions, ErrorDetailExtensions
+	t.Cleanup(func() {
+		ErrorModelExtensions, ErrorDetailExtensions = origModel, origDetail
+	})
+	ErrorModelExtensions = map[string]*Schema{
+		"traceId": {Type: TypeString, Description: "Request trace ID"},
+	}
+	ErrorDetailExtensions = map[string]*Schema{
+		"code": {Type: TypeString},
+	}
+
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Register(app, Operation{
+		OperationID: "test",
+		Method:      http.MethodGet,
+		Path:        "/test",
+		Errors:      []int{http.StatusConflict},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		err := Error409Conflict("already exists", &ErrorDetail{
+			Message:    "duplicate name",
+			Location:   "body.name",
+			Extensions: map[string]any{"code": "E_DUP"},
+		}).(*ErrorModel)
+		err.Extensions = map[string]any{"traceId": "abc123", "status": 1}
+		return nil, err
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{
+		"$schema": "http:///schemas/ErrorModel.json",
+		"title": "Conflict",
+		"status": 409,
+		"detail": "already exists",
+		"traceId": "abc123",
+		"errors": [
+			{
+				"message": "duplicate name",
+				"location": "body.name",
+				"code": "E_DUP"
+			}
+		]
+	}`, w.Body.String())
+
+	// Extension members round-trip.
+	var decoded ErrorModel
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &decoded))
+	assert.Equal(t, "abc123", decoded.Extensions["traceId"])
+	assert.Equal(t, "E_DUP", decoded.Errors[0].Extensions["code"])
+
+	// They are also sent via CBOR.
+	req, _ = http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept", "application/cbor")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var m map[string]any
+	assert.NoError(t, cbor.Unmarshal(w.Body.Bytes(), &m))
+	assert.Equal(t, "abc123", m["traceId"])
+	assert.EqualValues(t, 409, m["status"])
+
+	// The documented members show up in the schemas.
+	schemas := app.OpenAPI().Components.Schemas.Map()
+	assert.Equal(t, true, schemas["ErrorModel"].AdditionalProperties)
+	assert.Equal(t, TypeString, schemas["ErrorModel"].Properties["traceId"].Type)
+	assert.Equal(t, TypeString, schemas["ErrorDetail"].Properties["code"].Type)
+}
+
+func TestAutoErrors(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Components.SecuritySchemes = map[string]*SecurityScheme{
+		"bearer": {Type: "http", Scheme: "bearer"},
+	}
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "list",
+		Method:      http.MethodGet,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	Register(app, Operation{
+		OperationID: "create",
+		Method:      http.MethodPost,
+		Path:        "/things",
+		Security:    []map[string][]string{{"bearer": {}}},
+		Errors:      []int{http.StatusConflict},
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	Register(app, Operation{
+		OperationID:    "get",
+		Method:         http.MethodGet,
+		Path:           "/things/{id}",
+		SkipAutoErrors: true,
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	codes := func(op *Operation) []string {
+		keys := []string{}
+		for k := range op.Responses {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	paths := app.OpenAPI().Paths
+	assert.Equal(t, []string{"204", "500", "default"}, codes(paths["/things"].Get))
+	assert.Equal(t, []string{"204", "400", "401", "403", "409", "422", "500"}, codes(paths["/things"].Post))
+	assert.Equal(t, []string{"204", "default"}, codes(paths["/things/{id}"].Get))
+	assert.Equal(t, "#/components/schemas/ErrorModel", paths["/things"].Post.Responses["422"].Content["application/problem+json"].Schema.Ref)
+}
+
+func TestDefaultErrorResponse(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Components.SecuritySchemes = map[string]*SecurityScheme{
+		"bearer": {Type: "http", Scheme: "bearer"},
+	}
+	config.DefaultErrorResponse = true
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "create",
+		Method:      http.MethodPost,
+		Path:        "/things",
+		Security:    []map[string][]string{{"bearer": {}}},
+		Errors:      []int{http.StatusConflict},
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{}, error) {
+		return nil, Error409Conflict("exists")
+	})
+
+	// A declared default response is kept.
+	custom := &Response{
+		Description: "Custom error",
+		Content: map[string]*MediaType{
+			"text/plain": {Schema: &Schema{Type: TypeString}},
+		},
+	}
+	Register(app, Operation{
+		OperationID: "get",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+		Responses:   map[string]*Response{"default": custom},
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	paths := app.OpenAPI().Paths
+	post := paths["/things"].Post
+	assert.Len(t, post.Responses, 2)
+	assert.Contains(t, post.Responses, "204")
+	assert.Equal(t, "#/components/schemas/ErrorModel", post.Responses["default"].Content["application/problem+json"].Schema.Ref)
+	// The errors are still known, e.g. for generated operations.
+	assert.Subset(t, post.Errors, []int{http.StatusConflict, http.StatusUnprocessableEntity})
+	assert.Same(t, custom, paths["/things/{id}"].Get.Responses["default"])
+
+	// Errors are sent as usual.
+	req, _ := http.NewRequest(http.MethodPost, "/things", strings.NewReader(`{"name": "foo"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestRecover(t *testing.T) {
+	var recovered any
+	var stack []byte
+
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Recover = func(ctx Context, v any, s []byte) {
+		recovered = v
+		stack = s
+	}
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "panic",
+		Method:      http.MethodGet,
+		Path:        "/panic",
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		panic("oops")
+	})
+
+	Register(app, Operation{
+		OperationID: "partial",
+		Method:      http.MethodGet,
+		Path:        "/partial",
+	}, func(ctx context.Context, input *struct{}) (*struct {
+		Body func(ctx Context)
+	}, error) {
+		return &struct{ Body func(ctx Context) }{Body: func(ctx Context) {
+			ctx.SetStatus(http.StatusOK)
+			ctx.BodyWriter().Write([]byte("partial"))
+			panic("oops again")
+		}}, nil
+	})
+
+	Register(app, Operation{
+		OperationID: "abort",
+		Method:      http.MethodGet,
+		Path:        "/abort",
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		panic(http.ErrAbortHandler)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/panic", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"detail":"internal server error"`)
+	assert.Equal(t, "oops", recovered)
+	assert.Contains(t, string(stack), "TestRecover")
+
+	// Once the response has started, an error can't be sent so the response
+	// is aborted instead.
+	req, _ = http.NewRequest(http.MethodGet, "/partial", nil)
+	w = httptest.NewRecorder()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		r.ServeHTTP(w, req)
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "partial", w.Body.String())
+	assert.Equal(t, "oops again", recovered)
+
+	req, _ = http.NewRequest(http.MethodGet, "/abort", nil)
+	w = httptest.NewRecorder()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		r.ServeHTTP(w, req)
+	})
+}
+
+func TestJSONHooks(t *testing.T) {
+	marshaled, unmarshaled := 0, 0
+
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.JSONMarshal = func(w io.Writer, v any) error {
+		marshaled++
+		return json.NewEncoder(w).Encode(v)
+	}
+	config.JSONUnmarshal = func(data []byte, v any) error {
+		unmarshaled++
+		return json.Unmarshal(data, v)
+	}
+	// Custom formats marked as JSON use the hooks too.
+	config.Formats["application/vnd.custom+json"] = Format{
+		Marshal:   func(w io.Writer, v any) error { return nil },
+		Unmarshal: func(data []byte, v any) error { return nil },
+		JSON:      true,
+	}
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "echo",
+		Method:      http.MethodPost,
+		Path:        "/echo",
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct {
+		Body string
+	}, error) {
+		return &struct{ Body string }{Body: input.Body.Name}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"name": "foo"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `"foo"`+"\n", w.Body.String())
+	assert.Equal(t, 1, marshaled)
+	assert.Equal(t, 2, unmarshaled, "validate & decode")
+
+	req, _ = http.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"name": "foo"}`))
+	req.Header.Set("Content-Type", "application/vnd.custom+json")
+	req.Header.Set("Accept", "application/vnd.custom+json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `"foo"`+"\n", w.Body.String())
+	assert.Equal(t, 2, marshaled)
+	assert.Equal(t, 4, unmarshaled)
+
+	// Other formats are unaffected.
+	req, _ = http.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"name": "foo"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/cbor")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, marshaled)
+}
+
+func TestParamParsers(t *testing.T) {
+	type Input struct {
+		Name   string        `path:"name" maxLength:"5"`
+		Count  int8          `query:"count" minimum:"1"`
+		Size   uint          `query:"size" enum:"1,2,4"`
+		Ratio  float32       `query:"ratio" exclusiveMaximum:"1"`
+		Active bool          `query:"active"`
+		Tags   []string      `query:"tags" maxItems:"2"`
+		Since  time.Time     `header:"Since"`
+		Wait   time.Duration `query:"wait" format:"duration" default:"PT5S" example:"PT1M"`
+	}
+
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	op := &Operation{}
+	params := findParams(registry, op, reflect.TypeOf(Input{}))
+
+	// Durations are documented as ISO 8601 strings.
+	wait := op.Parameters[len(op.Parameters)-1]
+	assert.Equal(t, TypeString, wait.Schema.Type)
+	assert.Equal(t, "PT5S", wait.Schema.Default)
+	assert.Equal(t, "PT1M", wait.Example)
+
+	for _, item := range []struct {
+		name  string
+		value string
+		errs  []string
+	}{
+		{"name", "foo", nil},
+		{"name", "foobar", []string{"expected length <= 5"}},
+		{"count", "5", nil},
+		{"count", "0", []string{"expected number >= 1"}},
+		{"count", "300", []string{"invalid integer"}},
+		{"size", "4", nil},
+		{"size", "3", []string{"expected value to be one of \"1, 2, 4\""}},
+		{"size", "-1", []string{"invalid integer"}},
+		{"ratio", "0.5", nil},
+		{"ratio", "1", []string{"expected number < 1"}},
+		{"active", "true", nil},
+		{"active", "yes", []string{"invalid boolean"}},
+		{"tags", "a, b", nil},
+		{"tags", "a,b,c", []string{"expected array length <= 2"}},
+		{"Since", "Mon, 02 Jan 2006 15:04:05 GMT", nil},
+		{"Since", "yesterday", []string{"invalid time"}},
+		{"wait", "PT1M", nil},
+		{"wait", "1m30s", nil},
+		{"wait", "soon", []string{"invalid duration"}},
+	} {
+		t.Run(item.name+"="+item.value, func(t *testing.T) {
+			var input Input
+			res := &ValidateResult{}
+			params.EveryField(reflect.ValueOf(&input).Elem(), func(f reflect.Value, p *paramFieldInfo) {
+				if p.Name == item.name {
+					p.parse(f, item.value, NewPathBuffer([]byte{}, 0), res, true)
+				}
+			})
+			msgs := []string{}
+			for _, err := range res.Errors {
+				msgs = append(msgs, err.(*ErrorDetail).Message)
+			}
+			assert.ElementsMatch(t, item.errs, msgs)
+		})
+	}
+
+	// Valid scalar parameters are parsed & validated without allocating.
+	var input Input
+	v := reflect.ValueOf(&input).Elem()
+	pb := NewPathBuffer([]byte{}, 0)
+	res := &ValidateResult{}
+	allocs := testing.AllocsPerRun(100, func() {
+		params.EveryField(v, func(f reflect.Value, p *paramFieldInfo) {
+			switch p.Name {
+			case "name":
+				p.parse(f, "foo", pb, res, true)
+			case "count", "size":
+				p.parse(f, "2", pb, res, true)
+			}
+		})
+	})
+	assert.Empty(t, res.Errors)
+	assert.Zero(t, allocs)
+
+	assert.PanicsWithValue(t, "unsupported param type []int", func() {
+		findParams(registry, &Operation{}, reflect.TypeOf(struct {
+			IDs []int `query:"ids"`
+		}{}))
+	})
+}
+
+func TestQueryStyles(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	type Filter struct {
+		Name    string    `json:"name,omitempty"`
+		MinAge  int       `json:"min_age,omitempty" minimum:"0"`
+		Active  bool      `json:"active"`
+		Created time.Time `json:"created,omitempty"`
+	}
+
+	var got struct {
+		IDs    []string
+		Tags   []string
+		Words  []string
+		Pipes  []string
+		Filter Filter
+		Labels map[string]int
+	}
+
+	Register(app, Operation{
+		OperationID: "list",
+		Method:      http.MethodGet,
+		Path:        "/items",
+	}, func(ctx context.Context, input *struct {
+		IDs    []string       `query:"id,explode" maxItems:"3"`
+		Tags   []string       `query:"tags"`
+		Words  []string       `query:"words,spaceDelimited"`
+		Pipes  []string       `query:"pipes,pipeDelimited" default:"a,b"`
+		Filter Filter         `query:"filter,deepObject"`
+		Labels map[string]int `query:"labels,deepObject"`
+	}) (*struct{}, error) {
+		got.IDs = input.IDs
+		got.Tags = input.Tags
+		got.Words = input.Words
+		got.Pipes = input.Pipes
+		got.Filter = input.Filter
+		got.Labels = input.Labels
+		return nil, nil
+	})
+
+	params := map[string]*Param{}
+	for _, p := range app.OpenAPI().Paths["/items"].Get.Parameters {
+		params[p.Name] = p
+	}
+	for name, expected := range map[string]struct {
+		style   string
+		explode bool
+	}{
+		"id":     {"form", true},
+		"tags":   {"form", false},
+		"words":  {"spaceDelimited", false},
+		"pipes":  {"pipeDelimited", false},
+		"filter": {"deepObject", true},
+		"labels": {"deepObject", true},
+	} {
+		assert.Equal(t, expected.style, params[name].Style, name)
+		if assert.NotNil(t, params[name].Explode, name) {
+			assert.Equal(t, expected.explode, *params[name].Explode, name)
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/items?id=1&id=2&tags=a,b&words=x%20y&pipes=c|d&filter[name]=bob&filter%5Bmin_age%5D=30&filter[active]=true&labels[a]=1&labels[b]=2", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, []string{"1", "2"}, got.IDs)
+	assert.Equal(t, []string{"a", "b"}, got.Tags)
+	assert.Equal(t, []string{"x", "y"}, got.Words)
+	assert.Equal(t, []string{"c", "d"}, got.Pipes)
+	assert.Equal(t, Filter{Name: "bob", MinAge: 30, Active: true}, got.Filter)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, got.Labels)
+
+	// Unlike headers, query values are used as given.
+	req, _ = http.NewRequest(http.MethodGet, "/items?tags=a,%20b", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, []string{"a", " b"}, got.Tags)
+
+	req, _ = http.NewRequest(http.MethodGet, "/items", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Nil(t, got.IDs)
+	assert.Equal(t, []string{"a", "b"}, got.Pipes)
+	assert.Equal(t, Filter{}, got.Filter)
+
+	req, _ = http.NewRequest(http.MethodGet, "/items?id=1&id=2&id=3&id=4&filter[min_age]=-1&filter[nope]=1&labels[a]=x", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var model ErrorModel
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &model))
+	locations := map[string]string{}
+	for _, e := range model.Errors {
+		locations[e.Location] = e.Message
+	}
+	assert.Equal(t, map[string]string{
+		"query.id":             "expected array length <= 3",
+		"query.filter.min_age": "expected number >= 0",
+		"query.filter":         "expected required property active to be present",
+		"query.filter.nope":    "unexpected property",
+		"query.labels.a":       "invalid integer",
+	}, locations)
+
+	for _, input := range []any{
+		struct {
+			ID string `query:"id,explode"`
+		}{},
+		struct {
+			IDs []string `query:"ids,pipeDelimited,explode"`
+		}{},
+		struct {
+			IDs []string `query:"ids,csv"`
+		}{},
+		struct {
+			Filter []string `query:"filter,deepObject"`
+		}{},
+	} {
+		assert.Panics(t, func() {
+			findParams(NewMapRegistry("#/components/schemas/", DefaultSchemaNamer), &Operation{}, reflect.TypeOf(input))
+		})
+	}
+}
+
+func TestQueryDeepObjectMap(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	var got map[string]string
+	Register(app, Operation{
+		OperationID: "list",
+		Method:      http.MethodGet,
+		Path:        "/issues",
+	}, func(ctx context.Context, input *struct {
+		Filter map[string]string `query:"filter,deepObject" doc:"Filter by field"`
+	}) (*struct{}, error) {
+		got = input.Filter
+		return nil, nil
+	})
+
+	param := app.OpenAPI().Paths["/issues"].Get.Parameters[0]
+	assert.Equal(t, "deepObject", param.Style)
+	assert.Equal(t, TypeObject, param.Schema.Type)
+	assert.Equal(t, TypeString, param.Schema.AdditionalProperties.(*Schema).Type)
+
+	req, _ := http.NewRequest(http.MethodGet, "/issues?filter[status]=open&filter[owner]=me&other=1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, map[string]string{"status": "open", "owner": "me"}, got)
+}
+
+func TestHeaderArrays(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	var got []string
+	Register(app, Operation{
+		OperationID: "list",
+		Method:      http.MethodGet,
+		Path:        "/items",
+	}, func(ctx context.Context, input *struct {
+		Tags  []string `header:"X-Tags" pattern:"^[a-z]+$" maxLength:"5"`
+		Kinds []string `header:"X-Kinds" enum:"a,b"`
+	}) (*struct{}, error) {
+		got = input.Tags
+		return nil, nil
+	})
+
+	param := app.OpenAPI().Paths["/items"].Get.Parameters[0]
+	assert.Equal(t, "simple", param.Style)
+	assert.False(t, *param.Explode)
+	assert.Equal(t, TypeArray, param.Schema.Type)
+	assert.Equal(t, "^[a-z]+$", param.Schema.Items.Pattern)
+	assert.Empty(t, param.Schema.Pattern)
+
+	req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Add("X-Tags", "one, two")
+	req.Header.Add("X-Tags", "three")
+	req.Header.Add("X-Kinds", "a")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, []string{"one", "two", "three"}, got)
+
+	req, _ = http.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Add("X-Tags", "one")
+	req.Header.Add("X-Tags", "Two,toolong")
+	req.Header.Add("X-Kinds", "a,c")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var model ErrorModel
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &model))
+	locations := map[string]string{}
+	for _, e := range model.Errors {
+		locations[e.Location] = e.Message
+	}
+	assert.Equal(t, map[string]string{
+		"header.X-Tags[1]":  "expected string to match pattern ^[a-z]+$",
+		"header.X-Tags[2]":  "expected length <= 5",
+		"header.X-Kinds[1]": "expected value to be one of \"a, b\"",
+	}, locations)
+}
+
+type SharedPaginationParams struct {
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" minimum:"1"`
+}
+
+func TestSharedParams(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	for _, path := range []string{"/things", "/others"} {
+		Register(app, Operation{
+			OperationID: "list" + path[1:],
+			Method:      http.MethodGet,
+			Path:        path,
+		}, func(ctx context.Context, input *struct {
+			SharedPaginationParams
+			Query string `query:"q"`
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	}
+
+	params := app.OpenAPI().Components.Parameters
+	assert.Len(t, params, 2)
+	assert.Equal(t, "cursor", params["SharedPaginationParamsCursor"].Name)
+	assert.Equal(t, "limit", params["SharedPaginationParamsLimit"].Name)
+	assert.Empty(t, params["SharedPaginationParamsLimit"].Ref)
+
+	b, err := json.Marshal(app.OpenAPI())
+	assert.NoError(t, err)
+	var spec struct {
+		Paths map[string]map[string]struct {
+			Parameters []map[string]any `json:"parameters"`
+		} `json:"paths"`
+		Components struct {
+			Parameters map[string]map[string]any `json:"parameters"`
+		} `json:"components"`
+	}
+	assert.NoError(t, json.Unmarshal(b, &spec))
+	for _, path := range []string{"/things", "/others"} {
+		assert.Equal(t, []map[string]any{
+			{"$ref": "#/components/parameters/SharedPaginationParamsCursor"},
+			{"$ref": "#/components/parameters/SharedPaginationParamsLimit"},
+			{"name": "q", "in": "query", "schema": map[string]any{"type": "string"}},
+		}, spec.Paths[path]["get"].Parameters)
+	}
+	assert.Equal(t, "query", spec.Components.Parameters["SharedPaginationParamsLimit"]["in"])
+
+	// Params are still parsed & validated as usual.
+	req, _ := http.NewRequest(http.MethodGet, "/things?limit=0", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+	assert.Panics(t, func() {
+		type SharedPaginationParams struct {
+			Limit int `query:"max"`
+		}
+		Register(app, Operation{
+			OperationID: "list-dupe",
+			Method:      http.MethodGet,
+			Path:        "/dupe",
+		}, func(ctx context.Context, input *struct {
+			SharedPaginationParams
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestTextTypes(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Register(app, Operation{
+		OperationID: "put-host",
+		Method:      http.MethodPut,
+		Path:        "/hosts/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID   uuid.UUID `path:"id"`
+		Body struct {
+			Addr netip.Addr `json:"addr"`
+		}
+	}) (*struct {
+		Body string
+	}, error) {
+		return &struct{ Body string }{Body: input.ID.String() + " " + input.Body.Addr.String()}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, "/hosts/a1b2c3d4-0000-4000-8000-000000000000", strings.NewReader(`{"addr": "::1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `"a1b2c3d4-0000-4000-8000-000000000000 ::1"`+"\n", w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodPut, "/hosts/abc", strings.NewReader(`{"addr": "nope"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "expected string to be RFC 4122 uuid")
+	assert.Contains(t, w.Body.String(), "ParseAddr")
+}
+
+func TestContentTypeBodies(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.DebugValidateResponses = true
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "robots",
+		Method:      http.MethodGet,
+		Path:        "/robots.txt",
+	}, func(ctx context.Context, input *struct{}) (*struct {
+		Body string `contentType:"text/plain"`
+	}, error) {
+		return &struct {
+			Body string `contentType:"text/plain"`
+		}{Body: "User-agent: *\nDisallow: /"}, nil
+	})
+
+	Register(app, Operation{
+		OperationID: "blob",
+		Method:      http.MethodGet,
+		Path:        "/blob",
+	}, func(ctx context.Context, input *struct{}) (*struct {
+		ContentType string `header:"Content-Type"`
+		Body        []byte `contentType:"application/octet-stream"`
+	}, error) {
+		return &struct {
+			ContentType string `header:"Content-Type"`
+			Body        []byte `contentType:"application/octet-stream"`
+		}{ContentType: "image/png", Body: []byte{0x89, 'P', 'N', 'G'}}, nil
+	})
+
+	assert.Equal(t, &Schema{Type: TypeString}, app.OpenAPI().Paths["/robots.txt"].Get.Responses["200"].Content["text/plain"].Schema)
+	assert.Equal(t, &Schema{Type: TypeString, Format: "binary"}, app.OpenAPI().Paths["/blob"].Get.Responses["200"].Content["application/octet-stream"].Schema)
+	assert.NotContains(t, app.OpenAPI().Paths["/robots.txt"].Get.Responses["200"].Content, "application/json")
+
+	req, _ := http.NewRequest(http.MethodGet, "/robots.txt", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	assert.Equal(t, "User-agent: *\nDisallow: /", w.Body.String())
+
+	// A content type header takes precedence over the tag.
+	req, _ = http.NewRequest(http.MethodGet, "/blob", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, []byte{0x89, 'P', 'N', 'G'}, w.Body.Bytes())
+
+	assert.PanicsWithValue(t, "body field with a content type must be a string or []byte", func() {
+		Register(app, Operation{
+			OperationID: "bad",
+			Method:      http.MethodGet,
+			Path:        "/bad",
+		}, func(ctx context.Context, input *struct{}) (*struct {
+			Body int `contentType:"text/plain"`
+		}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestRawMessage(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Register(app, Operation{
+		OperationID: "proxy",
+		Method:      http.MethodPost,
+		Path:        "/proxy",
+		RequestBody: &RequestBody{
+			Content: map[string]*MediaType{
+				"application/json": {
+					Schema: &Schema{Type: TypeObject, Required: []string{"name"}},
+				},
+			},
+		},
+	}, func(ctx context.Context, input *struct {
+		Body json.RawMessage
+	}) (*struct {
+		Body json.RawMessage
+	}, error) {
+		return &struct{ Body json.RawMessage }{Body: input.Body}, nil
+	})
+
+	op := app.OpenAPI().Paths["/proxy"].Post
+	assert.Equal(t, TypeObject, op.RequestBody.Content["application/json"].Schema.Type)
+	assert.Equal(t, &Schema{}, op.Responses["200"].Content["application/json"].Schema)
+
+	req, _ := http.NewRequest(http.MethodPost, "/proxy", strings.NewReader(`{"name": "foo", "extra": [1, {"a": null}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"name": "foo", "extra": [1, {"a": null}]}`, w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodPost, "/proxy", strings.NewReader(`{"extra": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+}
+
+// testNullString is a `sql.NullString` which marshals as its value or `null`.
+type testNullString struct{ sql.NullString }
 
-func (ctx *testContext) Query(name string) string {
-	return queryparam.Get(ctx.r.URL.RawQuery, name)
+func (n testNullString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.String)
 }
 
-func (ctx *testContext) Header(name string) string {
-	return ctx.r.Header.Get(name)
+func (n *testNullString) UnmarshalJSON(b []byte) error {
+	n.String, n.Valid = "", string(b) != "null"
+	if !n.Valid {
+		return nil
+	}
+	return json.Unmarshal(b, &n.String)
 }
 
-func (ctx *testContext) EachHeader(cb func(name, value string)) {
-	for name, values := range ctx.r.Header {
-		for _, value := range values {
-			cb(name, value)
-		}
+// testNullInt64 is a `sql.NullInt64` which marshals as its value or `null`.
+type testNullInt64 struct{ sql.NullInt64 }
+
+func (n testNullInt64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
 	}
+	return json.Marshal(n.Int64)
 }
 
-func (ctx *testContext) Body() ([]byte, error) {
-	return io.ReadAll(ctx.r.Body)
+func (n *testNullInt64) UnmarshalJSON(b []byte) error {
+	n.Int64, n.Valid = 0, string(b) != "null"
+	if !n.Valid {
+		return nil
+	}
+	return json.Unmarshal(b, &n.Int64)
 }
 
-func (ctx *testContext) BodyReader() io.Reader {
-	return ctx.r.Body
-}
+func TestNullTypes(t *testing.T) {
+	NullTypes[reflect.TypeOf(testNullString{})] = reflect.TypeOf("")
+	NullTypes[reflect.TypeOf(testNullInt64{})] = reflect.TypeOf(int64(0))
+	defer delete(NullTypes, reflect.TypeOf(testNullString{}))
+	defer delete(NullTypes, reflect.TypeOf(testNullInt64{}))
+
+	type Pet struct {
+		Name     string         `json:"name"`
+		Nickname testNullString `json:"nickname"`
+		Age      testNullInt64  `json:"age,omitempty"`
+		Raw      sql.NullInt64  `json:"raw,omitempty"`
+	}
 
-func (ctx *testContext) SetReadDeadline(deadline time.Time) error {
-	return http.NewResponseController(ctx.w).SetReadDeadline(deadline)
-}
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
 
-func (ctx *testContext) SetStatus(code int) {
-	ctx.w.WriteHeader(code)
-}
+	Register(app, Operation{
+		OperationID: "echo",
+		Method:      http.MethodPost,
+		Path:        "/pets",
+	}, func(ctx context.Context, input *struct {
+		Body Pet
+	}) (*struct {
+		Body []Pet
+	}, error) {
+		return &struct{ Body []Pet }{Body: []Pet{input.Body}}, nil
+	})
 
-func (ctx *testContext) AppendHeader(name string, value string) {
-	ctx.w.Header().Add(name, value)
-}
+	pet := app.OpenAPI().Components.Schemas.Map()["Pet"]
+	assert.Equal(t, TypeString, pet.Properties["nickname"].Type)
+	assert.True(t, pet.Properties["nickname"].Nullable)
+	assert.Contains(t, pet.Required, "nickname")
 
-func (ctx *testContext) SetHeader(name string, value string) {
-	ctx.w.Header().Set(name, value)
-}
+	b, _ := json.Marshal(pet.Properties["age"])
+	assert.JSONEq(t, `{"type": ["integer", "null"], "format": "int64"}`, string(b))
+	b, _ = yaml.Marshal(pet.Properties["age"])
+	assert.Contains(t, string(b), `- "null"`)
 
-func (ctx *testContext) BodyWriter() io.Writer {
-	return ctx.w
-}
+	// Unregistered types are documented as they marshal.
+	assert.Equal(t, "#/components/schemas/NullInt64", pet.Properties["raw"].Ref)
 
-type testAdapter struct {
-	router chi.Router
-}
+	req, _ := http.NewRequest(http.MethodPost, "/pets", strings.NewReader(`{"name": "Fluffy", "nickname": null, "age": 3}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `[{"name": "Fluffy", "nickname": null, "age": 3, "raw": {"Int64": 0, "Valid": false}}]`, w.Body.String())
 
-func (a *testAdapter) Handle(op *Operation, handler func(Context)) {
-	a.router.MethodFunc(op.Method, op.Path, func(w http.ResponseWriter, r *http.Request) {
-		handler(&testContext{op: op, r: r, w: w})
-	})
-}
+	req, _ = http.NewRequest(http.MethodPost, "/pets", strings.NewReader(`{"name": "Fluffy", "nickname": "Fluff"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `[{"name": "Fluffy", "nickname": "Fluff", "age": null, "raw": {"Int64": 0, "Valid": false}}]`, w.Body.String())
 
-func (a *testAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	a.router.ServeHTTP(w, r)
+	// Nullable properties which are required must still be present.
+	req, _ = http.NewRequest(http.MethodPost, "/pets", strings.NewReader(`{"name": "Fluffy", "age": "old"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "expected required property nickname to be present")
+	assert.Contains(t, w.Body.String(), "expected number")
 }
 
-func NewTestAdapter(r chi.Router, config Config) API {
-	return NewAPI(config, &testAdapter{router: r})
-}
+func TestNullable(t *testing.T) {
+	type Owner struct {
+		Name string `json:"name"`
+	}
+	type UserPatch struct {
+		Nickname Nullable[string] `json:"nickname" maxLength:"5"`
+		Owner    Nullable[Owner]  `json:"owner"`
+	}
 
-type ExhaustiveErrorsInputBody struct {
-	Name  string `json:"name" maxLength:"10"`
-	Count int    `json:"count" minimum:"1"`
-}
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
 
-func (b *ExhaustiveErrorsInputBody) Resolve(ctx Context) []error {
-	return []error{fmt.Errorf("body resolver error")}
-}
+	var got UserPatch
+	Register(app, Operation{
+		OperationID: "patch",
+		Method:      http.MethodPatch,
+		Path:        "/users",
+	}, func(ctx context.Context, input *struct {
+		Body UserPatch
+	}) (*struct {
+		Body []UserPatch
+	}, error) {
+		got = input.Body
+		return &struct{ Body []UserPatch }{Body: []UserPatch{input.Body}}, nil
+	})
 
-type ExhaustiveErrorsInput struct {
-	ID   string                    `path:"id" maxLength:"5"`
-	Body ExhaustiveErrorsInputBody `json:"body"`
-}
+	s := app.OpenAPI().Components.Schemas.Map()["UserPatch"]
+	assert.Empty(t, s.Required)
+	b, _ := json.Marshal(s.Properties["nickname"])
+	assert.JSONEq(t, `{"type": ["string", "null"], "maxLength": 5}`, string(b))
+	assert.Equal(t, TypeObject, s.Properties["owner"].Type)
+	assert.True(t, s.Properties["owner"].Nullable)
+	assert.False(t, app.OpenAPI().Components.Schemas.Map()["Owner"].Nullable)
+
+	for _, item := range []struct {
+		body     string
+		expected UserPatch
+		response string
+	}{
+		{`{}`, UserPatch{}, `[{"nickname": null, "owner": null}]`},
+		{`{"nickname": null}`, UserPatch{Nickname: Null[string]()}, `[{"nickname": null, "owner": null}]`},
+		{`{"nickname": "Bob", "owner": {"name": "Alice"}}`, UserPatch{Nickname: NewNullable("Bob"), Owner: NewNullable(Owner{Name: "Alice"})}, `[{"nickname": "Bob", "owner": {"name": "Alice"}}]`},
+	} {
+		req, _ := http.NewRequest(http.MethodPatch, "/users", strings.NewReader(item.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, item.expected, got, item.body)
+		assert.JSONEq(t, item.response, w.Body.String())
+	}
 
-func (i *ExhaustiveErrorsInput) Resolve(ctx Context) []error {
-	return []error{&ErrorDetail{
-		Location: "path.id",
-		Message:  "input resolver error",
-		Value:    i.ID,
-	}}
+	req, _ := http.NewRequest(http.MethodPatch, "/users", strings.NewReader(`{"nickname": "toolong", "owner": {}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "body.nickname")
+	assert.Contains(t, w.Body.String(), "expected required property name to be present")
 }
 
-type ExhaustiveErrorsOutput struct {
-}
+func TestUnknownFields(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+	type Order struct {
+		ID    string `json:"id"`
+		Items []Item `json:"items"`
+	}
+	type Event struct {
+		_    struct{} `additionalProperties:"true"`
+		Kind string   `json:"kind"`
+	}
 
-func TestExhaustiveErrors(t *testing.T) {
 	r := chi.NewRouter()
 	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	for _, mode := range []struct {
+		path string
+		mode UnknownFieldsMode
+	}{
+		{"/reject", UnknownFieldsReject},
+		{"/ignore", UnknownFieldsIgnore},
+		{"/strip", UnknownFieldsStrip},
+	} {
+		Register(app, Operation{
+			OperationID:   "order" + mode.path[1:],
+			Method:        http.MethodPost,
+			Path:          mode.path,
+			UnknownFields: mode.mode,
+		}, func(ctx context.Context, input *struct {
+			RawBody []byte
+			Body    Order
+		}) (*struct {
+			Body string
+		}, error) {
+			assert.Equal(t, "abc", input.Body.ID)
+			assert.Equal(t, "foo", input.Body.Items[0].Name)
+			return &struct{ Body string }{Body: string(input.RawBody)}, nil
+		})
+	}
+
 	Register(app, Operation{
-		OperationID: "test",
-		Method:      http.MethodPut,
-		Path:        "/errors/{id}",
-	}, func(ctx context.Context, input *ExhaustiveErrorsInput) (*ExhaustiveErrorsOutput, error) {
-		return &ExhaustiveErrorsOutput{}, nil
+		OperationID: "event",
+		Method:      http.MethodPost,
+		Path:        "/events",
+	}, func(ctx context.Context, input *struct {
+		Body Event
+	}) (*struct{}, error) {
+		assert.Equal(t, "created", input.Body.Kind)
+		return nil, nil
 	})
 
-	req, _ := http.NewRequest(http.MethodPut, "/errors/123456", strings.NewReader(`{"name": "12345678901", "count": 0}`))
+	assert.Equal(t, true, app.OpenAPI().Components.Schemas.Map()["Event"].AdditionalProperties)
+
+	body := `{"id": "abc", "meta": {"trace": 1}, "items": [{"name": "foo", "extra": true}]}`
+
+	req, _ := http.NewRequest(http.MethodPost, "/reject", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
-	assert.JSONEq(t, `{
-		"$schema": "https:///schemas/ErrorModel.json",
-		"title": "Unprocessable Entity",
-		"status": 422,
-		"detail": "validation failed",
-		"errors": [
-			{
-				"message": "expected length <= 5",
-				"location": "path.id",
-				"value": "123456"
-			}, {
-				"message": "expected length <= 10",
-				"location": "body.name",
-				"value": "12345678901"
-			}, {
-				"message": "expected number >= 1",
-				"location": "body.count",
-				"value": 0
-			}, {
-				"message": "input resolver error",
-				"location": "path.id",
-				"value": "123456"
-			}, {
-				"message": "body resolver error"
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "body.meta")
+	assert.Contains(t, w.Body.String(), "body.items[0].extra")
+
+	req, _ = http.NewRequest(http.MethodPost, "/ignore", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var raw string
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &raw))
+	assert.JSONEq(t, body, raw)
+
+	req, _ = http.NewRequest(http.MethodPost, "/strip", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &raw))
+	assert.JSONEq(t, `{"id": "abc", "items": [{"name": "foo"}]}`, raw)
+
+	// Unknown properties are still validated against the rest of the schema.
+	req, _ = http.NewRequest(http.MethodPost, "/strip", strings.NewReader(`{"id": 1, "extra": true, "items": []}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "unexpected property")
+
+	req, _ = http.NewRequest(http.MethodPost, "/events", strings.NewReader(`{"kind": "created", "source": "test"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+}
+
+func TestStripReadOnly(t *testing.T) {
+	type Note struct {
+		ID      string    `json:"id" readOnly:"true" format:"uuid"`
+		Created time.Time `json:"created" readOnly:"true"`
+		Text    string    `json:"text"`
+	}
+
+	for _, strip := range []bool{false, true} {
+		t.Run(fmt.Sprint(strip), func(t *testing.T) {
+			r := chi.NewRouter()
+			config := DefaultConfig("Test API", "1.0.0")
+			config.StripReadOnly = strip
+			app := NewTestAdapter(r, config)
+
+			Register(app, Operation{
+				OperationID: "put-note",
+				Method:      http.MethodPut,
+				Path:        "/notes",
+			}, func(ctx context.Context, input *struct {
+				RawBody []byte
+				Body    Note
+			}) (*struct{}, error) {
+				assert.Empty(t, input.Body.ID)
+				assert.True(t, input.Body.Created.IsZero())
+				assert.Equal(t, "hello", input.Body.Text)
+				assert.NotContains(t, string(input.RawBody), "created")
+				return nil, nil
+			})
+
+			for _, body := range []string{
+				`{"text": "hello"}`,
+				`{"id": "not-a-uuid", "created": "2024-01-01T00:00:00Z", "text": "hello"}`,
+			} {
+				req, _ := http.NewRequest(http.MethodPut, "/notes", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				if strip || body == `{"text": "hello"}` {
+					assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+				} else {
+					assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+				}
 			}
-		]
-	}`, w.Body.String())
+		})
+	}
 }
 
-type NestedResolversStruct struct {
-	Field2 string `json:"field2"`
+func TestMaxValidationErrors(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.MaxValidationErrors = 2
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "tags",
+		Method:      http.MethodPost,
+		Path:        "/tags",
+	}, func(ctx context.Context, input *struct {
+		Body []struct {
+			Name string `json:"name" minLength:"1"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/tags", strings.NewReader(`[{"name": ""}, {"name": ""}, {"name": ""}, {"name": ""}]`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var model ErrorModel
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &model))
+	assert.Len(t, model.Errors, 2)
 }
 
-func (b *NestedResolversStruct) Resolve(ctx Context, prefix *PathBuffer) []error {
-	return []error{&ErrorDetail{
-		Location: prefix.With("field2"),
-		Message:  "resolver error",
-		Value:    b.Field2,
-	}}
+type ValidatorRange struct {
+	Start int `json:"start"`
+	End   int `json:"end" minimum:"0"`
 }
 
-var _ ResolverWithPath = (*NestedResolversStruct)(nil)
+func (r *ValidatorRange) ValidateHuma(ctx Context) []error {
+	if r.End < r.Start {
+		return []error{&ErrorDetail{Message: "end must not be before start", Location: "end", Value: r.End}}
+	}
+	return nil
+}
 
-type NestedResolversBody struct {
-	Field1 map[string][]NestedResolversStruct `json:"field1"`
+type ValidatorBody struct {
+	Name   string                    `json:"name"`
+	Ranges []ValidatorRange          `json:"ranges"`
+	Extra  *ValidatorRange           `json:"extra,omitempty"`
+	Named  map[string]ValidatorRange `json:"named,omitempty"`
 }
 
-type NestedResolverRequest struct {
-	Body NestedResolversBody
+func (b *ValidatorBody) ValidateHuma(ctx Context) []error {
+	if b.Name == "reserved" {
+		return []error{errors.New("name is reserved")}
+	}
+	return nil
 }
 
-func TestNestedResolverWithPath(t *testing.T) {
+func TestValidator(t *testing.T) {
 	r := chi.NewRouter()
 	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
 	Register(app, Operation{
-		OperationID: "test",
-		Method:      http.MethodPut,
-		Path:        "/test",
-	}, func(ctx context.Context, input *NestedResolverRequest) (*struct{}, error) {
+		OperationID: "create",
+		Method:      http.MethodPost,
+		Path:        "/ranges",
+	}, func(ctx context.Context, input *struct {
+		Body ValidatorBody
+	}) (*struct{}, error) {
 		return nil, nil
 	})
 
-	req, _ := http.NewRequest(http.MethodPut, "/test", strings.NewReader(`{"field1": {"foo": [{"field2": "bar"}]}}`))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-	r.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
-	assert.Contains(t, w.Body.String(), `"location":"body.field1.foo[0].field2"`)
+	call := func(body string) (int, []*ErrorDetail) {
+		req, _ := http.NewRequest(http.MethodPost, "/ranges", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var model ErrorModel
+		if w.Code != http.StatusNoContent {
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &model))
+		}
+		return w.Code, model.Errors
+	}
+
+	code, errs := call(`{"name": "ok", "ranges": [{"start": 1, "end": 2}]}`)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Empty(t, errs)
+
+	code, errs = call(`{"name": "reserved", "ranges": [{"start": 1, "end": 2}, {"start": 3, "end": 2}], "extra": {"start": 5, "end": 1}, "named": {"a": {"start": 2, "end": 1}}}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	locations := map[string]string{}
+	for _, e := range errs {
+		locations[e.Location] = e.Message
+	}
+	assert.Equal(t, map[string]string{
+		"body":               "name is reserved",
+		"body.ranges[1].end": "end must not be before start",
+		"body.extra.end":     "end must not be before start",
+		"body.named.a.end":   "end must not be before start",
+	}, locations)
+
+	// Validators don't run when the schema validation fails.
+	code, errs = call(`{"name": "reserved", "ranges": [{"start": 3, "end": -1}]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "body.ranges[0].end", errs[0].Location)
+	assert.Equal(t, "expected number >= 0", errs[0].Message)
 }
 
 func BenchmarkSecondDecode(b *testing.B) {
@@ -480,3 +2641,194 @@ func BenchmarkSecondDecode(b *testing.B) {
 // 		_ = summaries
 // 	}
 // }
+
+type AutoItemServer struct {
+	registered bool
+}
+
+type AutoItemOutput struct {
+	Body []string
+}
+
+func (s *AutoItemServer) RegisterHealth(api API) {
+	s.registered = true
+}
+
+func (s *AutoItemServer) RegisterListItems(op *Operation) {
+	op.Method = http.MethodGet
+	op.Path = "/items"
+	op.Summary = "List items"
+}
+
+func (s *AutoItemServer) ListItems(ctx context.Context, input *struct {
+	Limit int `query:"limit" minimum:"1"`
+}) (*AutoItemOutput, error) {
+	if input.Limit > 2 {
+		return nil, Error400BadRequest("too many")
+	}
+	return &AutoItemOutput{Body: []string{"a", "b"}[:input.Limit]}, nil
+}
+
+type AutoMissingServer struct{}
+
+func (s AutoMissingServer) RegisterGetItem(op *Operation) {}
+
+type AutoBadServer struct{}
+
+func (s AutoBadServer) RegisterGetItem(op *Operation) {}
+
+func (s AutoBadServer) GetItem(ctx context.Context, id string) (*struct{}, error) {
+	return nil, nil
+}
+
+func TestAutoRegister(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	server := &AutoItemServer{}
+	AutoRegister(app, server)
+	assert.True(t, server.registered)
+
+	op := app.OpenAPI().Paths["/items"].Get
+	assert.Equal(t, "list-items", op.OperationID)
+	assert.Equal(t, "List items", op.Summary)
+
+	req, _ := http.NewRequest(http.MethodGet, "/items?limit=1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `["a"]`, w.Body.String())
+
+	for url, status := range map[string]int{
+		"/items?limit=0": http.StatusUnprocessableEntity,
+		"/items?limit=3": http.StatusBadRequest,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, status, w.Code, url)
+	}
+
+	assert.PanicsWithValue(t, "method RegisterGetItem has no handler method GetItem", func() {
+		AutoRegister(app, AutoMissingServer{})
+	})
+	assert.PanicsWithValue(t, "handler method GetItem must be func(context.Context, *Input) (*Output, error)", func() {
+		AutoRegister(app, AutoBadServer{})
+	})
+}
+
+type SchemaLinkThing struct {
+	Name string `json:"name"`
+}
+
+func TestSchemaLinks(t *testing.T) {
+	handler := func(ctx context.Context, input *struct{}) (*struct{ Body SchemaLinkThing }, error) {
+		return &struct{ Body SchemaLinkThing }{SchemaLinkThing{Name: "foo"}}, nil
+	}
+
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+	Register(app, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/thing",
+	}, handler)
+
+	trusted := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.TrustForwardedHeaders = true
+	app = NewTestAdapter(trusted, config)
+	Register(app, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/thing",
+	}, handler)
+
+	for _, item := range []struct {
+		router  *chi.Mux
+		host    string
+		tls     bool
+		headers map[string]string
+		link    string
+	}{
+		{r, "localhost:8888", false, nil, "http://localhost:8888/schemas/SchemaLinkThing.json"},
+		{r, "api.example.com", false, nil, "http://api.example.com/schemas/SchemaLinkThing.json"},
+		{r, "api.example.com", true, nil, "https://api.example.com/schemas/SchemaLinkThing.json"},
+		{r, "10.0.0.1", true, map[string]string{"X-Forwarded-Proto": "http", "X-Forwarded-Host": "example.com"}, "https://10.0.0.1/schemas/SchemaLinkThing.json"},
+		{trusted, "10.0.0.1", true, map[string]string{"X-Forwarded-Proto": "http", "X-Forwarded-Host": "example.com"}, "http://example.com/schemas/SchemaLinkThing.json"},
+		{trusted, "10.0.0.1", false, map[string]string{"Forwarded": `for=1.2.3.4;proto=https;host="example.com", for=5.6.7.8`}, "https://example.com/schemas/SchemaLinkThing.json"},
+		{trusted, "api.example.com", true, nil, "https://api.example.com/schemas/SchemaLinkThing.json"},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/thing", nil)
+		req.Host = item.host
+		if item.tls {
+			req.TLS = &tls.ConnectionState{}
+		}
+		for k, v := range item.headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		item.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "</schemas/SchemaLinkThing.json>; rel=\"describedBy\"", w.Header().Get("Link"))
+		assert.JSONEq(t, `{"$schema": "`+item.link+`", "name": "foo"}`, w.Body.String())
+	}
+
+	r = chi.NewRouter()
+	config = DefaultConfig("Test API", "1.0.0")
+	link := NewSchemaLinkTransformer("#/components/schemas/", config.SchemasPath)
+	link.BaseURL = "https://api.example.com/"
+	config.OnAddOperation = []AddOpFunc{link.OnAddOperation}
+	config.Transformers = []Transformer{link.Transform}
+	app = NewTestAdapter(r, config)
+	Register(app, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/thing",
+	}, handler)
+
+	req, _ := http.NewRequest(http.MethodGet, "/thing", nil)
+	req.Host = "internal:8888"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.JSONEq(t, `{"$schema": "https://api.example.com/schemas/SchemaLinkThing.json", "name": "foo"}`, w.Body.String())
+}
+
+func TestContextConnection(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	var remote string
+	var state *tls.ConnectionState
+	var version ProtoVersion
+	Register(app, Operation{
+		OperationID: "connection",
+		Method:      http.MethodGet,
+		Path:        "/connection",
+		Middlewares: []Middleware{func(ctx Context, next func(Context)) {
+			remote = ctx.RemoteAddr()
+			state = ctx.TLS()
+			version = ctx.Version()
+			next(ctx)
+		}},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	server := httptest.NewTLSServer(r)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/connection")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(remote, "127.0.0.1:"))
+	assert.NotNil(t, state)
+	assert.True(t, state.HandshakeComplete)
+	assert.Equal(t, ProtoVersion{Proto: "HTTP/1.1", ProtoMajor: 1, ProtoMinor: 1}, version)
+
+	// Plain HTTP requests have no TLS state.
+	req, _ := http.NewRequest(http.MethodGet, "/connection", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Nil(t, state)
+}
@@ -0,0 +1,576 @@
+package humatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// FuzzOptions customizes how `Fuzz` tests an API.
+type FuzzOptions struct {
+	// Iterations is the number of random valid requests sent to each
+	// operation. Defaults to 20.
+	Iterations int
+
+	// Seed for the random values, so failures can be reproduced. Defaults to
+	// 1, so runs are deterministic unless changed.
+	Seed int64
+
+	// Skip returns whether to leave out an operation, e.g. one with side
+	// effects outside of the test.
+	Skip func(op *huma.Operation) bool
+}
+
+// Fuzz sends generated requests to every operation in the API's OpenAPI
+// document and reports an error for each response which shows a bug in the
+// handlers. For each operation, random requests which are valid for the
+// documented params & body schemas are sent, followed by requests with a
+// single value just outside of its schema, e.g. a number above the maximum,
+// a string that is too long, an unknown enum value or a missing required
+// property.
+//
+// A response fails the test if the handler panicked, the status isn't one of
+// the operation's documented responses, or the status is 500, which usually
+// means a panic was recovered or an error wasn't handled.
+//
+//	func TestAPIFuzz(t *testing.T) {
+//		_, api := humatest.New(t)
+//		registerRoutes(api)
+//		humatest.Fuzz(t, api)
+//	}
+func Fuzz(tb TB, api huma.API, opts ...FuzzOptions) {
+	tb.Helper()
+	o := FuzzOptions{}
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Iterations == 0 {
+		o.Iterations = 20
+	}
+	if o.Seed == 0 {
+		o.Seed = 1
+	}
+
+	oapi := api.OpenAPI()
+	g := &generator{rnd: rand.New(rand.NewSource(o.Seed)), oapi: oapi}
+
+	paths := make([]string, 0, len(oapi.Paths))
+	for path := range oapi.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := oapi.Paths[path]
+		for _, op := range []*huma.Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head, item.Patch, item.Trace} {
+			if op == nil || (o.Skip != nil && o.Skip(op)) {
+				continue
+			}
+			f := newFuzzOperation(g, item, op)
+			for i := 0; i < o.Iterations; i++ {
+				f.check(tb, api, f.valid(g.value))
+			}
+			for _, req := range f.invalid() {
+				f.check(tb, api, req)
+			}
+		}
+	}
+}
+
+// fuzzRequest holds the values sent in a generated request.
+type fuzzRequest struct {
+	params  map[*huma.Param]any
+	body    any
+	hasBody bool
+}
+
+// fuzzOperation generates requests for an operation.
+type fuzzOperation struct {
+	g           *generator
+	op          *huma.Operation
+	params      []*huma.Param
+	contentType string
+	body        *huma.Schema
+}
+
+func newFuzzOperation(g *generator, item *huma.PathItem, op *huma.Operation) *fuzzOperation {
+	f := &fuzzOperation{g: g, op: op}
+	seen := map[string]bool{}
+	for _, p := range append(append([]*huma.Param{}, op.Parameters...), item.Parameters...) {
+		if p != nil && p.Name == "" && p.Ref != "" && g.oapi.Components != nil {
+			p = g.oapi.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+		}
+		if p == nil || seen[p.In+"."+p.Name] || p.In == "cookie" {
+			continue
+		}
+		// Operation params override those of the path item.
+		seen[p.In+"."+p.Name] = true
+		f.params = append(f.params, p)
+	}
+
+	body := op.RequestBody
+	if body != nil && body.Ref != "" && g.oapi.Components != nil {
+		body = g.oapi.Components.RequestBodies[strings.TrimPrefix(body.Ref, "#/components/requestBodies/")]
+	}
+	if body != nil {
+		types := make([]string, 0, len(body.Content))
+		for ct := range body.Content {
+			types = append(types, ct)
+		}
+		sort.Strings(types)
+		for _, ct := range types {
+			if mt := body.Content[ct]; mt != nil && mt.Schema != nil && strings.HasSuffix(ct, "json") {
+				f.contentType = ct
+				f.body = mt.Schema
+				break
+			}
+		}
+	}
+	return f
+}
+
+// valid returns a request with values for every required param & the body,
+// and for some optional params.
+func (f *fuzzOperation) valid(value func(s *huma.Schema, depth int) any) *fuzzRequest {
+	req := &fuzzRequest{params: map[*huma.Param]any{}}
+	for _, p := range f.params {
+		if p.Schema != nil && (p.Required || p.In == "path" || f.g.rnd.Intn(2) == 0) {
+			v := value(p.Schema, 0)
+			if p.In == "path" && formatValue(v) == "" {
+				// Empty path segments won't match the route.
+				v = huma.MockValue(f.g.oapi.Components.Schemas, p.Schema, huma.ModeWriteToServer)
+			}
+			req.params[p] = v
+		}
+	}
+	if f.body != nil {
+		req.body = value(f.body, 0)
+		req.hasBody = true
+	}
+	return req
+}
+
+// invalid returns requests which each have a single value just outside of
+// its schema.
+func (f *fuzzOperation) invalid() []*fuzzRequest {
+	base := f.valid(func(s *huma.Schema, depth int) any {
+		return huma.MockValue(f.g.oapi.Components.Schemas, s, huma.ModeWriteToServer)
+	})
+	// Send every param, so each can be made invalid.
+	for _, p := range f.params {
+		if _, ok := base.params[p]; !ok && p.Schema != nil {
+			base.params[p] = huma.MockValue(f.g.oapi.Components.Schemas, p.Schema, huma.ModeWriteToServer)
+		}
+	}
+
+	reqs := []*fuzzRequest{}
+	for _, p := range f.params {
+		if p.Schema == nil {
+			continue
+		}
+		for _, v := range f.g.boundaries(p.Schema, base.params[p], 0) {
+			req := &fuzzRequest{params: map[*huma.Param]any{}, body: base.body, hasBody: base.hasBody}
+			for k, v := range base.params {
+				req.params[k] = v
+			}
+			req.params[p] = v
+			reqs = append(reqs, req)
+		}
+		if p.Required && p.In != "path" {
+			req := &fuzzRequest{params: map[*huma.Param]any{}, body: base.body, hasBody: base.hasBody}
+			for k, v := range base.params {
+				if k != p {
+					req.params[k] = v
+				}
+			}
+			reqs = append(reqs, req)
+		}
+	}
+	if f.body != nil {
+		for _, v := range f.g.boundaries(f.body, base.body, 0) {
+			reqs = append(reqs, &fuzzRequest{params: base.params, body: v, hasBody: true})
+		}
+		// Missing body.
+		reqs = append(reqs, &fuzzRequest{params: base.params})
+	}
+	return reqs
+}
+
+// request builds the HTTP request.
+func (f *fuzzOperation) request(r *fuzzRequest) *http.Request {
+	path := f.op.Path
+	query := []string{}
+	header := http.Header{}
+	for _, p := range f.params {
+		v, ok := r.params[p]
+		if !ok {
+			continue
+		}
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(formatValue(v)))
+		case "query":
+			query = append(query, queryValues(p, v)...)
+		case "header":
+			header.Set(p.Name, formatValue(v))
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + strings.Join(query, "&")
+	}
+
+	var req *http.Request
+	if r.hasBody {
+		b, _ := json.Marshal(r.body)
+		req = httptest.NewRequest(f.op.Method, path, bytes.NewReader(b))
+		req.Header.Set("Content-Type", f.contentType)
+	} else {
+		req = httptest.NewRequest(f.op.Method, path, nil)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	return req
+}
+
+// check sends the request and reports any problems with the response.
+func (f *fuzzOperation) check(tb TB, api huma.API, r *fuzzRequest) {
+	tb.Helper()
+	req := f.request(r)
+	dump, _ := httputil.DumpRequest(req, true)
+	// Restore the consumed body.
+	req = f.request(r)
+
+	w := httptest.NewRecorder()
+	problem := func() (problem string) {
+		defer func() {
+			if v := recover(); v != nil {
+				problem = fmt.Sprintf("handler panicked: %v", v)
+			}
+		}()
+		api.Adapter().ServeHTTP(w, req)
+		return ""
+	}()
+	if problem == "" {
+		if w.Code == http.StatusInternalServerError {
+			problem = "internal server error: " + strings.TrimSpace(w.Body.String())
+		} else if !documented(f.op, w.Code) {
+			problem = fmt.Sprintf("undocumented response status %d: %s", w.Code, strings.TrimSpace(w.Body.String()))
+		}
+	}
+	if problem != "" {
+		tb.Errorf("%s %s: %s\nRequest:\n%s", f.op.Method, f.op.Path, problem, strings.TrimSpace(string(dump)))
+	}
+}
+
+// documented returns whether the operation documents the response status,
+// directly, via a range like `4XX` or via a `default` response.
+func documented(op *huma.Operation, status int) bool {
+	key := strconv.Itoa(status)
+	return op.Responses[key] != nil || op.Responses[key[:1]+"XX"] != nil || op.Responses["default"] != nil
+}
+
+// formatValue formats a single param value, joining lists with commas.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatValue(item)
+		}
+		return strings.Join(parts, ",")
+	case map[string]any:
+		keys := sortedKeys(v)
+		parts := make([]string, 0, len(v)*2)
+		for _, k := range keys {
+			parts = append(parts, k, formatValue(v[k]))
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// queryValues formats a query param using its style.
+func queryValues(p *huma.Param, v any) []string {
+	name := url.QueryEscape(p.Name)
+	switch v := v.(type) {
+	case []any:
+		if p.Explode != nil && *p.Explode {
+			values := make([]string, len(v))
+			for i, item := range v {
+				values[i] = name + "=" + url.QueryEscape(formatValue(item))
+			}
+			return values
+		}
+	case map[string]any:
+		if p.Style == "deepObject" {
+			values := []string{}
+			for _, k := range sortedKeys(v) {
+				values = append(values, url.QueryEscape(p.Name+"["+k+"]")+"="+url.QueryEscape(formatValue(v[k])))
+			}
+			return values
+		}
+	}
+	return []string{name + "=" + url.QueryEscape(formatValue(v))}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// generator creates random values for schemas.
+type generator struct {
+	rnd  *rand.Rand
+	oapi *huma.OpenAPI
+}
+
+func (g *generator) resolve(s *huma.Schema) *huma.Schema {
+	for s != nil && s.Ref != "" {
+		s = g.oapi.Components.Schemas.SchemaFromRef(s.Ref)
+	}
+	return s
+}
+
+// value returns a random value which is valid for the schema.
+func (g *generator) value(s *huma.Schema, depth int) any {
+	s = g.resolve(s)
+	if s == nil {
+		return nil
+	}
+	if depth > 4 || (s.Type == huma.TypeString && (s.Pattern != "" || s.Format != "")) {
+		// Deeply nested values and string patterns & formats use a known
+		// valid value.
+		return huma.MockValue(g.oapi.Components.Schemas, s, huma.ModeWriteToServer)
+	}
+	switch {
+	case s.Const != nil:
+		return s.Const
+	case len(s.Enum) > 0:
+		return s.Enum[g.rnd.Intn(len(s.Enum))]
+	case len(s.Examples) > 0 && g.rnd.Intn(2) == 0:
+		return s.Examples[g.rnd.Intn(len(s.Examples))]
+	case s.Nullable && g.rnd.Intn(10) == 0:
+		return nil
+	}
+
+	switch s.Type {
+	case huma.TypeBoolean:
+		return g.rnd.Intn(2) == 0
+	case huma.TypeInteger, huma.TypeNumber:
+		return g.number(s)
+	case huma.TypeString:
+		min, max := 0, 20
+		if s.MinLength != nil {
+			min = *s.MinLength
+			max = min + 20
+		}
+		if s.MaxLength != nil && *s.MaxLength < max {
+			max = *s.MaxLength
+		}
+		const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+		b := make([]byte, min+g.rnd.Intn(max-min+1))
+		for i := range b {
+			b[i] = letters[g.rnd.Intn(len(letters))]
+		}
+		return string(b)
+	case huma.TypeArray:
+		min, max := 0, 3
+		if s.MinItems != nil {
+			min = *s.MinItems
+			max = min + 3
+		}
+		if s.MaxItems != nil && *s.MaxItems < max {
+			max = *s.MaxItems
+		}
+		if s.UniqueItems && min <= 1 {
+			// Generated items may repeat.
+			max = min
+		}
+		items := make([]any, min+g.rnd.Intn(max-min+1))
+		for i := range items {
+			items[i] = g.value(s.Items, depth+1)
+		}
+		return items
+	case huma.TypeObject:
+		obj := map[string]any{}
+		for _, name := range sortedSchemaKeys(s.Properties) {
+			prop := g.resolve(s.Properties[name])
+			if prop == nil || prop.ReadOnly {
+				continue
+			}
+			if contains(s.Required, name) || g.rnd.Intn(2) == 0 {
+				obj[name] = g.value(prop, depth+1)
+			}
+		}
+		return obj
+	}
+	return huma.MockValue(g.oapi.Components.Schemas, s, huma.ModeWriteToServer)
+}
+
+// number returns a random number within the schema's range.
+func (g *generator) number(s *huma.Schema) any {
+	min, max := -1000.0, 1000.0
+	if s.Minimum != nil {
+		min = *s.Minimum
+		max = math.Max(max, min+1000)
+	}
+	if s.ExclusiveMinimum != nil {
+		min = *s.ExclusiveMinimum + 1
+		max = math.Max(max, min+1000)
+	}
+	if s.Maximum != nil {
+		max = *s.Maximum
+		min = math.Min(min, max-1000)
+		if s.Minimum != nil {
+			min = *s.Minimum
+		}
+	}
+	if s.ExclusiveMaximum != nil {
+		max = *s.ExclusiveMaximum - 1
+	}
+	if s.Type == huma.TypeInteger {
+		min, max = math.Ceil(min), math.Floor(max)
+	}
+	if max < min || (s.MultipleOf != nil && *s.MultipleOf > 0) {
+		return huma.MockValue(g.oapi.Components.Schemas, s, huma.ModeWriteToServer)
+	}
+	v := min + g.rnd.Float64()*(max-min)
+	if s.Type == huma.TypeInteger {
+		return math.Round(v)
+	}
+	return v
+}
+
+// boundaries returns values which are just outside of the schema, based on
+// the valid value `v`.
+func (g *generator) boundaries(s *huma.Schema, v any, depth int) []any {
+	s = g.resolve(s)
+	if s == nil || depth > 4 {
+		return nil
+	}
+	values := []any{}
+	switch s.Type {
+	case huma.TypeInteger, huma.TypeNumber:
+		values = append(values, "not-a-number")
+		if s.Minimum != nil {
+			values = append(values, *s.Minimum-1)
+		}
+		if s.ExclusiveMinimum != nil {
+			values = append(values, *s.ExclusiveMinimum)
+		}
+		if s.Maximum != nil {
+			values = append(values, *s.Maximum+1)
+		}
+		if s.ExclusiveMaximum != nil {
+			values = append(values, *s.ExclusiveMaximum)
+		}
+		if s.Type == huma.TypeInteger {
+			values = append(values, 1.5)
+		}
+	case huma.TypeBoolean:
+		values = append(values, "not-a-boolean")
+	case huma.TypeString:
+		if s.MinLength != nil && *s.MinLength > 0 {
+			values = append(values, strings.Repeat("a", *s.MinLength-1))
+		}
+		if s.MaxLength != nil {
+			values = append(values, strings.Repeat("a", *s.MaxLength+1))
+		}
+		if len(s.Enum) > 0 || s.Format != "" || s.Pattern != "" {
+			values = append(values, "!invalid-value!")
+		}
+	case huma.TypeArray:
+		items, _ := v.([]any)
+		if s.MinItems != nil && *s.MinItems > 0 && len(items) > 0 {
+			values = append(values, items[:*s.MinItems-1])
+		}
+		if s.MaxItems != nil {
+			item := huma.MockValue(g.oapi.Components.Schemas, s.Items, huma.ModeWriteToServer)
+			more := make([]any, *s.MaxItems+1)
+			for i := range more {
+				more[i] = item
+			}
+			values = append(values, more)
+		}
+		if len(items) > 0 {
+			for _, b := range g.boundaries(s.Items, items[0], depth+1) {
+				changed := append([]any{b}, items[1:]...)
+				values = append(values, changed)
+			}
+		}
+	case huma.TypeObject:
+		obj, _ := v.(map[string]any)
+		if obj == nil {
+			break
+		}
+		values = append(values, "not-an-object")
+		for _, name := range s.Required {
+			if _, ok := obj[name]; ok {
+				values = append(values, without(obj, name))
+			}
+		}
+		if s.AdditionalProperties == false {
+			changed := without(obj, "")
+			changed["unexpected-property"] = true
+			values = append(values, changed)
+		}
+		for _, name := range sortedKeys(obj) {
+			for _, b := range g.boundaries(s.Properties[name], obj[name], depth+1) {
+				changed := without(obj, "")
+				changed[name] = b
+				values = append(values, changed)
+			}
+		}
+	}
+	return values
+}
+
+// without returns a copy of the object without the property.
+func without(obj map[string]any, name string) map[string]any {
+	result := make(map[string]any, len(obj))
+	for k, v := range obj {
+		if k != name {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func sortedSchemaKeys(m map[string]*huma.Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
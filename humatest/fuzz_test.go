@@ -0,0 +1,104 @@
+package humatest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingTB records errors instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *recordingTB) Helper() {}
+
+func (tb *recordingTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+type FuzzThing struct {
+	Name  string   `json:"name" minLength:"2" maxLength:"8"`
+	Count int      `json:"count" minimum:"1" maximum:"10"`
+	Kind  string   `json:"kind,omitempty" enum:"small,large"`
+	Tags  []string `json:"tags,omitempty" maxItems:"3"`
+}
+
+func TestFuzz(t *testing.T) {
+	_, api := New(t)
+
+	calls := 0
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID     string   `path:"id" maxLength:"5"`
+		Limit  int      `query:"limit" minimum:"1" maximum:"100"`
+		Fields []string `query:"fields" enum:"name,count"`
+		Body   FuzzThing
+	}) (*struct{ Body FuzzThing }, error) {
+		calls++
+		assert.LessOrEqual(t, len(input.ID), 5)
+		assert.GreaterOrEqual(t, input.Body.Count, 1)
+		return &struct{ Body FuzzThing }{Body: input.Body}, nil
+	})
+
+	Fuzz(t, api, FuzzOptions{Iterations: 10})
+	assert.Equal(t, 10, calls)
+}
+
+func TestFuzzFailures(t *testing.T) {
+	_, api := New(t)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-item",
+		Method:      http.MethodGet,
+		Path:        "/items/{id}",
+		Errors:      []int{http.StatusNotFound},
+	}, func(ctx context.Context, input *struct {
+		ID int `path:"id" minimum:"1"`
+	}) (*struct{}, error) {
+		if input.ID > 500 {
+			panic("boom")
+		}
+		return nil, huma.NewError(http.StatusTeapot, "undocumented")
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-skipped",
+		Method:      http.MethodGet,
+		Path:        "/skipped",
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		panic("skipped")
+	})
+
+	tb := &recordingTB{TB: t}
+	Fuzz(tb, api, FuzzOptions{
+		Iterations: 5,
+		Seed:       42,
+		Skip: func(op *huma.Operation) bool {
+			return op.OperationID == "get-skipped"
+		},
+	})
+
+	panicked, undocumented := false, false
+	for _, e := range tb.errors {
+		assert.Contains(t, e, "GET /items/{id}")
+		assert.Contains(t, e, "Request:\nGET /items/")
+		if strings.Contains(e, "handler panicked: boom") {
+			panicked = true
+		}
+		if strings.Contains(e, "undocumented response status 418") {
+			undocumented = true
+		}
+	}
+	assert.True(t, panicked, tb.errors)
+	assert.True(t, undocumented, tb.errors)
+}
@@ -4,13 +4,19 @@
 package humatest
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -18,12 +24,16 @@ import (
 	"github.com/go-chi/chi"
 )
 
-// TB is a subset of the `testing.TB` interface used by the test API and
-// implemented by the `*testing.T` and `*testing.B` structs.
+// TB is a subset of the `testing.TB` interface used by the test API,
+// `Output` and `Fuzz`, and implemented by the `*testing.T` and `*testing.B`
+// structs. It includes `Errorf` & `Fatalf` so those helpers can report
+// failures, which custom implementations must now provide too.
 type TB interface {
 	Helper()
 	Log(args ...any)
 	Logf(format string, args ...any)
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
 }
 
 type testContext struct {
@@ -53,6 +63,22 @@ func (ctx *testContext) Host() string {
 	return ctx.r.Host
 }
 
+func (ctx *testContext) RemoteAddr() string {
+	return ctx.r.RemoteAddr
+}
+
+func (ctx *testContext) TLS() *tls.ConnectionState {
+	return ctx.r.TLS
+}
+
+func (ctx *testContext) Version() huma.ProtoVersion {
+	return huma.ProtoVersion{
+		Proto:      ctx.r.Proto,
+		ProtoMajor: ctx.r.ProtoMajor,
+		ProtoMinor: ctx.r.ProtoMinor,
+	}
+}
+
 func (ctx *testContext) URL() url.URL {
 	return *ctx.r.URL
 }
@@ -85,10 +111,48 @@ func (ctx *testContext) SetReadDeadline(deadline time.Time) error {
 	return http.NewResponseController(ctx.w).SetReadDeadline(deadline)
 }
 
+func (ctx *testContext) SetWriteDeadline(deadline time.Time) error {
+	return http.NewResponseController(ctx.w).SetWriteDeadline(deadline)
+}
+
 func (ctx *testContext) SetStatus(code int) {
+	if rec, ok := ctx.w.(*httptest.ResponseRecorder); ok && code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		// The recorder would treat interim responses like early hints as the
+		// final status, so they are recorded separately.
+		v, _ := interimResponses.Load(rec)
+		interim, _ := v.([]InterimResponse)
+		interimResponses.Store(rec, append(interim, InterimResponse{
+			Status: code,
+			Header: rec.Header().Clone(),
+		}))
+		return
+	}
 	ctx.w.WriteHeader(code)
 }
 
+// InterimResponse is an informational response, like `103 Early Hints`, which
+// was sent before the final response.
+type InterimResponse struct {
+	Status int
+	Header http.Header
+}
+
+// interimResponses holds the interim responses sent to each recorder until
+// they are read, or the test making the request ends.
+var interimResponses sync.Map
+
+// InterimResponses returns the interim responses, like `103 Early Hints`, in
+// the order they were sent before the final response to the recorder. They
+// are forgotten once read, so call it once per response.
+//
+//	resp := api.Get("/page")
+//	hints := humatest.InterimResponses(resp)
+func InterimResponses(resp *httptest.ResponseRecorder) []InterimResponse {
+	v, _ := interimResponses.LoadAndDelete(resp)
+	interim, _ := v.([]InterimResponse)
+	return interim
+}
+
 func (ctx *testContext) AppendHeader(name string, value string) {
 	ctx.w.Header().Add(name, value)
 }
@@ -101,6 +165,16 @@ func (ctx *testContext) BodyWriter() io.Writer {
 	return ctx.w
 }
 
+// Unwrap returns the underlying HTTP request & response writer of a context
+// created by the test adapter, like `humachi.Unwrap` does for chi. Panics if
+// the context is from another adapter.
+func Unwrap(ctx huma.Context) (*http.Request, http.ResponseWriter) {
+	if c, ok := huma.UnwrapContext(ctx).(*testContext); ok {
+		return c.r, c.w
+	}
+	panic("not a humatest context")
+}
+
 type testAdapter struct {
 	router chi.Router
 }
@@ -125,33 +199,29 @@ type TestAPI interface {
 	huma.API
 
 	// Do a request against the API. Args, if provided, should be string headers
-	// like `Content-Type: application/json` or an `io.Reader` for the request
-	// body. Anything else will panic.
+	// like `Content-Type: application/json`, an `io.Reader` for the request
+	// body, or a struct, map, or slice to send as a JSON body. Anything else
+	// will panic.
 	Do(method, path string, args ...any) *httptest.ResponseRecorder
 
-	// Get performs a GET request against the API. Args, if provided, should be
-	// string headers like `Content-Type: application/json` or an `io.Reader`
-	// for the request body. Anything else will panic.
+	// Get performs a GET request against the API. See `Do` for the supported
+	// args.
 	Get(path string, args ...any) *httptest.ResponseRecorder
 
-	// Post performs a POST request against the API. Args, if provided, should be
-	// string headers like `Content-Type: application/json` or an `io.Reader`
-	// for the request body. Anything else will panic.
+	// Post performs a POST request against the API. See `Do` for the supported
+	// args.
 	Post(path string, args ...any) *httptest.ResponseRecorder
 
-	// Put performs a PUT request against the API. Args, if provided, should be
-	// string headers like `Content-Type: application/json` or an `io.Reader`
-	// for the request body. Anything else will panic.
+	// Put performs a PUT request against the API. See `Do` for the supported
+	// args.
 	Put(path string, args ...any) *httptest.ResponseRecorder
 
-	// Patch performs a PATCH request against the API. Args, if provided, should
-	// be string headers like `Content-Type: application/json` or an `io.Reader`
-	// for the request body. Anything else will panic.
+	// Patch performs a PATCH request against the API. See `Do` for the
+	// supported args.
 	Patch(path string, args ...any) *httptest.ResponseRecorder
 
-	// Delete performs a DELETE request against the API. Args, if provided, should
-	// be string headers like `Content-Type: application/json` or an `io.Reader`
-	// for the request body. Anything else will panic.
+	// Delete performs a DELETE request against the API. See `Do` for the
+	// supported args.
 	Delete(path string, args ...any) *httptest.ResponseRecorder
 }
 
@@ -160,21 +230,38 @@ type testAPI struct {
 	tb TB
 }
 
+// Unwrap returns the wrapped API, e.g. so Huma can use its error type.
+func (a *testAPI) Unwrap() huma.API {
+	return a.API
+}
+
 func (a *testAPI) Do(method, path string, args ...any) *httptest.ResponseRecorder {
 	a.tb.Helper()
 	var b io.Reader
+	isJSON := false
 	for _, arg := range args {
 		if reader, ok := arg.(io.Reader); ok {
 			b = reader
 			break
 		} else if _, ok := arg.(string); ok {
 			// do nothing
+		} else if isJSONBody(arg) {
+			encoded, err := json.Marshal(arg)
+			if err != nil {
+				panic(err)
+			}
+			b = bytes.NewReader(encoded)
+			isJSON = true
+			break
 		} else {
-			panic("unsupported argument type, expected string header or io.Reader body")
+			panic("unsupported argument type, expected string header, io.Reader body, or JSON body value")
 		}
 	}
 
 	req, _ := http.NewRequest(method, path, b)
+	if isJSON {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	for _, arg := range args {
 		if s, ok := arg.(string); ok {
 			parts := strings.Split(s, ":")
@@ -182,6 +269,10 @@ func (a *testAPI) Do(method, path string, args ...any) *httptest.ResponseRecorde
 		}
 	}
 	resp := httptest.NewRecorder()
+	if c, ok := a.tb.(interface{ Cleanup(func()) }); ok {
+		// Don't keep unread interim responses after the test.
+		c.Cleanup(func() { interimResponses.Delete(resp) })
+	}
 
 	bytes, _ := httputil.DumpRequest(req, b != nil)
 	a.tb.Log("Making request:\n" + strings.TrimSpace(string(bytes)))
@@ -219,6 +310,131 @@ func (a *testAPI) Delete(path string, args ...any) *httptest.ResponseRecorder {
 	return a.Do(http.MethodDelete, path, args...)
 }
 
+// isJSONBody returns whether the value can be sent as a JSON request body.
+func isJSONBody(v any) bool {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice:
+		return true
+	}
+	return false
+}
+
+// Output decodes a response into a new instance of an operation's output
+// struct type `O`, so tests can check the response using the same types as
+// the handler. Fields with a `header` tag are set from the response headers,
+// a `Status` int field is set to the status code, and a `Body` field is set
+// by unmarshaling the response body using the API's formats.
+//
+//	resp := api.Get("/things/123")
+//	out := humatest.Output[GetThingOutput](t, api, resp)
+//	assert.Equal(t, "abc", out.Body.Name)
+func Output[O any](tb TB, api huma.API, resp *httptest.ResponseRecorder) *O {
+	tb.Helper()
+	var out O
+	v := reflect.ValueOf(&out).Elem()
+	if v.Kind() != reflect.Struct {
+		tb.Fatalf("output type must be a struct, got %s", v.Type())
+		return nil
+	}
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		f := v.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Name == "Status" && f.Kind() == reflect.Int {
+			f.SetInt(int64(resp.Code))
+			continue
+		}
+
+		if field.Name == "Body" {
+			if resp.Body.Len() == 0 {
+				continue
+			}
+			if b, ok := f.Addr().Interface().(*[]byte); ok {
+				*b = resp.Body.Bytes()
+				continue
+			}
+			ct := resp.Header().Get("Content-Type")
+			if ct == "" {
+				ct = "application/json"
+			}
+			if err := api.Unmarshal(ct, resp.Body.Bytes(), f.Addr().Interface()); err != nil {
+				tb.Fatalf("unable to decode response body: %v", err)
+			}
+			continue
+		}
+
+		name := field.Tag.Get("header")
+		if name == "" {
+			continue
+		}
+		value := resp.Header().Get(name)
+		if value == "" {
+			continue
+		}
+		if err := setHeaderField(f, value); err != nil {
+			tb.Fatalf("unable to decode response header %s: %v", name, err)
+		}
+	}
+
+	return &out
+}
+
+// setHeaderField parses a response header value into the output field.
+func setHeaderField(f reflect.Value, value string) error {
+	if f.Type() == timeType {
+		t, err := http.ParseTime(value)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		f.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		f.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(v)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(v)
+	}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
 // NewTestAPI creates a new test API from a chi router and API config.
 func NewTestAPI(tb TB, r chi.Router, config huma.Config) TestAPI {
 	api := huma.NewAPI(config, &testAdapter{router: r})
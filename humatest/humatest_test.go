@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/go-chi/chi"
@@ -76,6 +77,53 @@ func TestContext(t *testing.T) {
 
 	ctx.AppendHeader("Baz", "baz")
 	assert.Equal(t, "baz", w.Header().Get("Baz"))
+
+	ur, uw := Unwrap(ctx)
+	assert.Same(t, r, ur)
+	assert.Equal(t, w, uw)
+}
+
+func TestInterimResponses(t *testing.T) {
+	_, api := New(t)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "page",
+		Method:      http.MethodGet,
+		Path:        "/page",
+		EarlyHints:  []string{"</style.css>; rel=preload; as=style"},
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: "hello"}, nil
+	})
+
+	resp := api.Get("/page")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	interim := InterimResponses(resp)
+	if assert.Len(t, interim, 1) {
+		assert.Equal(t, http.StatusEarlyHints, interim[0].Status)
+		assert.Equal(t, "</style.css>; rel=preload; as=style", interim[0].Header.Get("Link"))
+	}
+	assert.Empty(t, InterimResponses(resp), "forgotten once read")
+
+	assert.Empty(t, InterimResponses(api.Get("/missing")))
+
+	// Unread interim responses are forgotten when the test ends.
+	var unread *httptest.ResponseRecorder
+	t.Run("unread", func(t *testing.T) {
+		_, api := New(t)
+		huma.Register(api, huma.Operation{
+			OperationID: "page",
+			Method:      http.MethodGet,
+			Path:        "/page",
+			EarlyHints:  []string{"</style.css>; rel=preload; as=style"},
+		}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+		unread = api.Get("/page")
+		_, ok := interimResponses.Load(unread)
+		assert.True(t, ok)
+	})
+	_, ok := interimResponses.Load(unread)
+	assert.False(t, ok)
 }
 
 func TestAdapter(t *testing.T) {
@@ -99,3 +147,64 @@ func TestNewAPI(t *testing.T) {
 		wrapped.Post("/", 1234)
 	})
 }
+
+func TestOutput(t *testing.T) {
+	type ThingOutput struct {
+		Status       int
+		ETag         string    `header:"ETag"`
+		Count        int       `header:"X-Count"`
+		LastModified time.Time `header:"Last-Modified"`
+		Body         struct {
+			Echo string `json:"echo"`
+		}
+	}
+
+	_, api := New(t)
+
+	modified := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	huma.Register(api, huma.Operation{
+		OperationID: "test",
+		Method:      http.MethodPost,
+		Path:        "/test",
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Value string `json:"value"`
+		}
+	}) (*ThingOutput, error) {
+		resp := &ThingOutput{Status: http.StatusCreated, ETag: "abc", Count: 5, LastModified: modified}
+		resp.Body.Echo = input.Body.Value
+		return resp, nil
+	})
+
+	// Structs, maps, and slices are sent as JSON bodies.
+	resp := api.Post("/test", map[string]any{"value": "hello"})
+
+	out := Output[ThingOutput](t, api, resp)
+	assert.Equal(t, http.StatusCreated, out.Status)
+	assert.Equal(t, "abc", out.ETag)
+	assert.Equal(t, 5, out.Count)
+	assert.True(t, modified.Equal(out.LastModified))
+	assert.Equal(t, "hello", out.Body.Echo)
+}
+
+func TestAddTransformer(t *testing.T) {
+	_, api := New(t)
+
+	huma.AddTransformer(api, huma.TransformerEntry{
+		Name: "suffix",
+		Transform: func(ctx huma.Context, status string, v any) (any, error) {
+			if s, ok := v.(string); ok {
+				return s + "-suffix", nil
+			}
+			return v, nil
+		},
+	})
+
+	huma.Get(api, "/transformed", func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{"body"}, nil
+	})
+
+	resp := api.Get("/transformed")
+	assert.JSONEq(t, `"body-suffix"`, resp.Body.String())
+	assert.Contains(t, huma.TransformerChain(api, api.OpenAPI().Paths["/transformed"].Get), "suffix")
+}
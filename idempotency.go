@@ -0,0 +1,397 @@
+package huma
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IdempotentResponse is a response saved for an `Idempotency-Key`, which is
+// replayed when a request with the same key is retried.
+type IdempotentResponse struct {
+	// Fingerprint identifies the request which the response is for, so that
+	// keys reused for different requests can be rejected.
+	Fingerprint string
+	Status      int
+	Headers     http.Header
+	Body        []byte
+}
+
+// IdempotencyStore saves responses by `Idempotency-Key` for
+// `NewIdempotencyMiddleware`. Implementations must be safe for concurrent use,
+// and should use a shared store like Redis when running multiple servers.
+type IdempotencyStore interface {
+	// Get returns the saved response for the key, or nil if there is none.
+	Get(ctx context.Context, key string) (*IdempotentResponse, error)
+
+	// Lock marks a request with the key as in progress, returning false if
+	// one already is. Shared stores should expire locks after a while, so a
+	// server which dies mid-request doesn't leave the key locked forever.
+	Lock(ctx context.Context, key string) (bool, error)
+
+	// Save saves the response for the key and unlocks it.
+	Save(ctx context.Context, key string, resp *IdempotentResponse) error
+
+	// Unlock releases the key without saving a response, so the request can
+	// be retried, e.g. after a server error.
+	Unlock(ctx context.Context, key string) error
+}
+
+// IdempotencyConfig controls `NewIdempotencyMiddleware`.
+type IdempotencyConfig struct {
+	// Store saves the responses for each key. Required.
+	Store IdempotencyStore
+
+	// Scope returns who made the request, so that keys are only shared by
+	// requests from the same caller and one caller can never get another's
+	// response. Defaults to the `Authorization` & `Cookie` headers, so all
+	// requests without either share a single key space. Return e.g. the
+	// authenticated user's ID when using other credentials.
+	Scope func(ctx Context) string
+
+	// StoreTimeout limits how long saving or unlocking a response may take.
+	// These calls don't use the request's context, since it is canceled when
+	// the client gives up, which is exactly when it will retry. Defaults to
+	// 5 seconds.
+	StoreTimeout time.Duration
+
+	// MaxBytes is the maximum size of saved responses. Larger or streamed
+	// responses are sent as-is without being saved. It also limits request
+	// bodies for operations without their own `MaxBodyBytes`, with larger
+	// requests getting a 413 error. Defaults to 1MB.
+	MaxBytes int64
+}
+
+// NewIdempotencyMiddleware creates a middleware which makes `POST` & `PATCH`
+// requests with an `Idempotency-Key` header safe to retry. The first response
+// for each key is saved to the store, except for server errors, and replayed
+// with an `Idempotent-Replayed: true` header for retries by the same caller.
+// Retries while the first request is in progress get a 409 error, and reusing
+// a key for a different request gets a 422 error. Use `DocumentIdempotency` to
+// document the header.
+//
+//	idempotent := huma.NewIdempotencyMiddleware(api, huma.IdempotencyConfig{
+//		Store: huma.NewMemoryIdempotencyStore(24 * time.Hour),
+//	})
+//
+//	huma.Register(api, huma.Operation{
+//		OperationID: "create-payment",
+//		Method:      http.MethodPost,
+//		Path:        "/payments",
+//		Middlewares: []huma.Middleware{idempotent},
+//	}, handler)
+func NewIdempotencyMiddleware(api API, config IdempotencyConfig) Middleware {
+	if config.Store == nil {
+		panic("idempotency store is required")
+	}
+	if config.Scope == nil {
+		config.Scope = func(ctx Context) string {
+			auth, cookie := ctx.Header("Authorization"), ctx.Header("Cookie")
+			if cookie == "" {
+				return auth
+			}
+			return auth + "\n" + cookie
+		}
+	}
+	if config.StoreTimeout == 0 {
+		config.StoreTimeout = 5 * time.Second
+	}
+	if config.MaxBytes == 0 {
+		config.MaxBytes = 1024 * 1024
+	}
+	store := config.Store
+
+	return func(ctx Context, next func(Context)) {
+		key := ctx.Header("Idempotency-Key")
+		if key == "" || (ctx.Method() != http.MethodPost && ctx.Method() != http.MethodPatch) {
+			next(ctx)
+			return
+		}
+		if scope := config.Scope(ctx); scope != "" {
+			// Hashed, as the scope may be a credential.
+			sum := sha256.Sum256([]byte(scope))
+			key = hex.EncodeToString(sum[:]) + ":" + key
+		}
+
+		limit := config.MaxBytes
+		if op := ctx.Operation(); op != nil && op.MaxBodyBytes > 0 {
+			limit = op.MaxBodyBytes
+		}
+		body, err := io.ReadAll(io.LimitReader(ctx.BodyReader(), limit+1))
+		if err != nil {
+			WriteErr(api, ctx, http.StatusBadRequest, "unable to read request body", err)
+			return
+		}
+		if int64(len(body)) > limit {
+			WriteErr(api, ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is too large limit=%d bytes", limit))
+			return
+		}
+		u := ctx.URL()
+		h := sha256.New()
+		h.Write([]byte(ctx.Method() + " " + u.Path + "?" + u.RawQuery + "\n"))
+		h.Write(body)
+		fingerprint := hex.EncodeToString(h.Sum(nil))
+
+		c := ctx.Context()
+		saved, err := store.Get(c, key)
+		if err == nil && saved == nil {
+			var locked bool
+			if locked, err = store.Lock(c, key); err == nil && !locked {
+				WriteErr(api, ctx, http.StatusConflict, "a request with this Idempotency-Key is already in progress")
+				return
+			}
+			if err == nil {
+				// The first request may have finished before the lock.
+				if saved, err = store.Get(c, key); saved != nil {
+					store.Unlock(c, key)
+				}
+			}
+		}
+		if err != nil {
+			WriteErr(api, ctx, http.StatusInternalServerError, "unable to check Idempotency-Key", err)
+			return
+		}
+
+		if saved != nil {
+			if saved.Fingerprint != fingerprint {
+				WriteErr(api, ctx, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+				return
+			}
+			for name, values := range saved.Headers {
+				for i, value := range values {
+					if i == 0 {
+						ctx.SetHeader(name, value)
+					} else {
+						ctx.AppendHeader(name, value)
+					}
+				}
+			}
+			ctx.SetHeader("Idempotent-Replayed", "true")
+			ctx.SetStatus(saved.Status)
+			ctx.BodyWriter().Write(saved.Body)
+			return
+		}
+
+		rc := &idempotencyContext{humaContext: ctx, body: bytes.NewReader(body), headers: http.Header{}, limit: config.MaxBytes}
+		completed := false
+		defer func() {
+			if !completed {
+				// The handler panicked, so allow retries.
+				c, cancel := context.WithTimeout(detachedContext{c}, config.StoreTimeout)
+				defer cancel()
+				store.Unlock(c, key)
+			}
+		}()
+		next(rc)
+		completed = true
+
+		// The client may have given up, but the response must still be saved
+		// or the key unlocked for its retry.
+		c, cancel := context.WithTimeout(detachedContext{c}, config.StoreTimeout)
+		defer cancel()
+
+		if rc.status == 0 {
+			rc.status = http.StatusOK
+		}
+		if rc.status >= 500 || rc.skip {
+			store.Unlock(c, key)
+			return
+		}
+		if err := store.Save(c, key, &IdempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      rc.status,
+			Headers:     rc.headers,
+			Body:        rc.buf.Bytes(),
+		}); err != nil {
+			// Don't leave the key locked, so the request can be retried.
+			store.Unlock(c, key)
+		}
+	}
+}
+
+// detachedContext keeps the values of a context without its cancellation or
+// deadline.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// idempotencyContext replays the request body which was already read and
+// records the response so it can be saved. Responses which are too large or
+// streamed are not recorded.
+type idempotencyContext struct {
+	humaContext
+	body    io.Reader
+	status  int
+	headers http.Header
+	buf     bytes.Buffer
+	limit   int64
+	skip    bool
+}
+
+// Unwrap returns the wrapped context, see `UnwrapContext`.
+func (c *idempotencyContext) Unwrap() Context {
+	return c.humaContext
+}
+
+func (c *idempotencyContext) BodyReader() io.Reader {
+	return c.body
+}
+
+func (c *idempotencyContext) SetStatus(code int) {
+	if !isInterim(code) {
+		c.status = code
+	}
+	c.humaContext.SetStatus(code)
+}
+
+func (c *idempotencyContext) SetHeader(name, value string) {
+	c.headers.Set(name, value)
+	c.humaContext.SetHeader(name, value)
+}
+
+func (c *idempotencyContext) AppendHeader(name, value string) {
+	c.headers.Add(name, value)
+	c.humaContext.AppendHeader(name, value)
+}
+
+func (c *idempotencyContext) BodyWriter() io.Writer {
+	return idempotencyWriter{c}
+}
+
+// Flush the wrapped context. Flushed responses are streamed, so they are not
+// saved.
+func (c *idempotencyContext) Flush() error {
+	c.skip = true
+	c.buf = bytes.Buffer{}
+	return Flush(c.humaContext)
+}
+
+// record saves a copy of the written body unless it is too large.
+func (c *idempotencyContext) record(p []byte) {
+	if c.skip {
+		return
+	}
+	if int64(c.buf.Len()+len(p)) > c.limit {
+		c.skip = true
+		c.buf = bytes.Buffer{}
+		return
+	}
+	c.buf.Write(p)
+}
+
+// idempotencyWriter writes the body to the client while recording it.
+type idempotencyWriter struct {
+	c *idempotencyContext
+}
+
+func (w idempotencyWriter) Write(p []byte) (int, error) {
+	w.c.record(p)
+	return w.c.humaContext.BodyWriter().Write(p)
+}
+
+// DocumentIdempotency documents the `Idempotency-Key` header and the 409 &
+// 422 errors sent by `NewIdempotencyMiddleware` for `POST` & `PATCH`
+// operations. Add it to the OpenAPI's `OnAddOperation` hooks before
+// registering operations.
+func DocumentIdempotency(oapi *OpenAPI, op *Operation) {
+	if op.Method != http.MethodPost && op.Method != http.MethodPatch {
+		return
+	}
+	for _, p := range op.Parameters {
+		if p.In == "header" && strings.EqualFold(p.Name, "Idempotency-Key") {
+			return
+		}
+	}
+	op.Parameters = append(op.Parameters, &Param{
+		Name:        "Idempotency-Key",
+		In:          "header",
+		Description: "Unique key, like a UUID, which makes the request safe to retry. Retries with the same key get the original response.",
+		Schema:      &Schema{Type: TypeString},
+	})
+	documentErrors(oapi, op, NewError(0, ""), http.StatusConflict, http.StatusUnprocessableEntity)
+}
+
+// memoryIdempotencyStore is an in-memory `IdempotencyStore`.
+type memoryIdempotencyStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	responses map[string]memoryIdempotentResponse
+	locks     map[string]bool
+	sweepAt   int
+}
+
+type memoryIdempotentResponse struct {
+	resp    *IdempotentResponse
+	expires time.Time
+}
+
+// NewMemoryIdempotencyStore creates an in-memory `IdempotencyStore` which
+// keeps responses for the given duration. It is only suitable for a single
+// server.
+func NewMemoryIdempotencyStore(ttl time.Duration) IdempotencyStore {
+	return &memoryIdempotencyStore{
+		ttl:       ttl,
+		responses: map[string]memoryIdempotentResponse{},
+		locks:     map[string]bool{},
+	}
+}
+
+func (s *memoryIdempotencyStore) Get(ctx context.Context, key string) (*IdempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved, ok := s.responses[key]
+	if !ok {
+		return nil, nil
+	}
+	if time.Now().After(saved.expires) {
+		delete(s.responses, key)
+		return nil, nil
+	}
+	return saved.resp, nil
+}
+
+func (s *memoryIdempotencyStore) Lock(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locks[key] {
+		return false, nil
+	}
+	s.locks[key] = true
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) Save(ctx context.Context, key string, resp *IdempotentResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if len(s.responses) >= s.sweepAt {
+		// Clean up expired responses whenever the number saved doubles.
+		for k, saved := range s.responses {
+			if now.After(saved.expires) {
+				delete(s.responses, k)
+			}
+		}
+		s.sweepAt = 2*len(s.responses) + 64
+	}
+	s.responses[key] = memoryIdempotentResponse{resp: resp, expires: now.Add(s.ttl)}
+	delete(s.locks, key)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Unlock(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.locks, key)
+	return nil
+}
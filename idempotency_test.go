@@ -0,0 +1,285 @@
+package huma
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.OnAddOperation = append(config.OnAddOperation, DocumentIdempotency)
+	app := NewTestAdapter(r, config)
+
+	store := NewMemoryIdempotencyStore(time.Hour)
+	idempotent := NewIdempotencyMiddleware(app, IdempotencyConfig{Store: store})
+
+	var calls atomic.Int32
+	Register(app, Operation{
+		OperationID:   "create-payment",
+		Method:        http.MethodPost,
+		Path:          "/payments",
+		DefaultStatus: http.StatusCreated,
+		MaxBodyBytes:  100,
+		Middlewares:   []Middleware{idempotent},
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Amount int `json:"amount"`
+		}
+	}) (*struct {
+		Location string `header:"Location"`
+		Body     struct {
+			ID     int32 `json:"id"`
+			Amount int   `json:"amount"`
+		}
+	}, error) {
+		if input.Body.Amount < 0 {
+			return nil, Error500InternalServerError("failed")
+		}
+		resp := &struct {
+			Location string `header:"Location"`
+			Body     struct {
+				ID     int32 `json:"id"`
+				Amount int   `json:"amount"`
+			}
+		}{}
+		resp.Body.ID = calls.Add(1)
+		resp.Body.Amount = input.Body.Amount
+		resp.Location = "/payments/1"
+		return resp, nil
+	})
+
+	do := func(key, body string, auth ...string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/payments", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		if len(auth) > 0 {
+			req.Header.Set("Authorization", auth[0])
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("k1", `{"amount": 5}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"$schema": "http:///schemas/create-paymentResponse.json", "id": 1, "amount": 5}`, w.Body.String())
+
+	// Retries replay the original response.
+	w = do("k1", `{"amount": 5}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, "/payments/1", w.Header().Get("Location"))
+	assert.JSONEq(t, `{"$schema": "http:///schemas/create-paymentResponse.json", "id": 1, "amount": 5}`, w.Body.String())
+	assert.EqualValues(t, 1, calls.Load())
+
+	// Reusing a key for a different request fails.
+	w = do("k1", `{"amount": 6}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	req, _ := http.NewRequest(http.MethodPost, "/payments?amount=6", strings.NewReader(`{"amount": 5}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "k1")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	// Requests without a key always run.
+	do("", `{"amount": 5}`)
+	do("", `{"amount": 5}`)
+	assert.EqualValues(t, 3, calls.Load())
+
+	// Keys are scoped to the caller, so others never get the response.
+	w = do("k1", `{"amount": 5}`, "Bearer alice")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	w = do("k1", `{"amount": 5}`, "Bearer bob")
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	w = do("k1", `{"amount": 5}`, "Bearer alice")
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.EqualValues(t, 5, calls.Load())
+
+	// Cookie sessions are scoped too.
+	cookie := func(key, session string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{"amount": 5}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		req.Header.Set("Cookie", "session="+session)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	w = cookie("k5", "alice")
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	w = cookie("k5", "bob")
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	w = cookie("k5", "alice")
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.EqualValues(t, 7, calls.Load())
+
+	// Concurrent retries are rejected.
+	locked, _ := store.Lock(context.Background(), "k2")
+	assert.True(t, locked)
+	w = do("k2", `{"amount": 5}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	store.Unlock(context.Background(), "k2")
+
+	// Server errors are not saved, so they can be retried.
+	w = do("k3", `{"amount": -1}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	w = do("k3", `{"amount": -1}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+
+	// Request bodies are limited by the operation.
+	w = do("k4", `{"amount": 5, "pad": "`+strings.Repeat("a", 100)+`"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	op := app.OpenAPI().Paths["/payments"].Post
+	assert.Equal(t, "Idempotency-Key", op.Parameters[0].Name)
+	assert.Contains(t, op.Responses, "409")
+	assert.Contains(t, op.Responses, "422")
+}
+
+// failingSaveStore is an `IdempotencyStore` which fails to save responses.
+type failingSaveStore struct {
+	IdempotencyStore
+}
+
+func (s failingSaveStore) Save(ctx context.Context, key string, resp *IdempotentResponse) error {
+	return errors.New("unavailable")
+}
+
+// canceledStore is an `IdempotencyStore` which fails when given a canceled
+// context, like most stores backed by a network service.
+type canceledStore struct {
+	IdempotencyStore
+}
+
+func (s canceledStore) Save(ctx context.Context, key string, resp *IdempotentResponse) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.IdempotencyStore.Save(ctx, key, resp)
+}
+
+func TestIdempotencyClientGone(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	var calls atomic.Int32
+	Register(app, Operation{
+		OperationID: "gone",
+		Method:      http.MethodPost,
+		Path:        "/gone",
+		Middlewares: []Middleware{NewIdempotencyMiddleware(app, IdempotencyConfig{
+			Store: canceledStore{NewMemoryIdempotencyStore(time.Hour)},
+		})},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		calls.Add(1)
+		return nil, nil
+	})
+
+	// The client times out while the handler runs, then retries.
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(c, http.MethodPost, "/gone", http.NoBody)
+	req.Header.Set("Idempotency-Key", "k1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest(http.MethodPost, "/gone", http.NoBody)
+	req.Header.Set("Idempotency-Key", "k1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestIdempotencyNotSaved(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	var calls atomic.Int32
+	handler := func(ctx context.Context, input *struct {
+		Size int `query:"size"`
+	}) (*struct{ Body string }, error) {
+		calls.Add(1)
+		return &struct{ Body string }{Body: strings.Repeat("a", input.Size)}, nil
+	}
+	Register(app, Operation{
+		OperationID: "failing",
+		Method:      http.MethodPost,
+		Path:        "/failing",
+		Middlewares: []Middleware{NewIdempotencyMiddleware(app, IdempotencyConfig{
+			Store: failingSaveStore{NewMemoryIdempotencyStore(time.Hour)},
+		})},
+	}, handler)
+	Register(app, Operation{
+		OperationID: "large",
+		Method:      http.MethodPost,
+		Path:        "/large",
+		Middlewares: []Middleware{NewIdempotencyMiddleware(app, IdempotencyConfig{
+			Store:    NewMemoryIdempotencyStore(time.Hour),
+			MaxBytes: 20,
+		})},
+	}, handler)
+	Register(app, Operation{
+		OperationID: "stream",
+		Method:      http.MethodPost,
+		Path:        "/stream",
+		Middlewares: []Middleware{NewIdempotencyMiddleware(app, IdempotencyConfig{
+			Store: NewMemoryIdempotencyStore(time.Hour),
+		})},
+	}, func(ctx context.Context, input *struct{}) (*StreamResponse, error) {
+		calls.Add(1)
+		return &StreamResponse{
+			Body: func(ctx Context) {
+				ctx.BodyWriter().Write([]byte("hello"))
+				Flush(ctx)
+			},
+		}, nil
+	})
+
+	for _, item := range []struct {
+		name string
+		path string
+	}{
+		// The key is unlocked, rather than getting a 409 error forever.
+		{"save error", "/failing"},
+		{"too large", "/large?size=50"},
+		{"streamed", "/stream"},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			calls.Store(0)
+			for i := 0; i < 2; i++ {
+				req, _ := http.NewRequest(http.MethodPost, item.path, http.NoBody)
+				req.Header.Set("Idempotency-Key", "k1")
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+				assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+			}
+			assert.EqualValues(t, 2, calls.Load())
+		})
+	}
+
+	// Small responses are still saved.
+	calls.Store(0)
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodPost, "/large?size=5", http.NoBody)
+		req.Header.Set("Idempotency-Key", "k2")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	assert.EqualValues(t, 1, calls.Load())
+}
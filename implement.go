@@ -0,0 +1,226 @@
+package huma
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// LoadOpenAPI loads an OpenAPI 3.1 document from JSON or YAML. Component
+// schemas are loaded into a map registry, so the document can be used as
+// `config.OpenAPI` for a design-first API whose declared operations are
+// implemented via `Implement`:
+//
+//	spec, err := huma.LoadOpenAPI(b)
+//	if err != nil {
+//		panic(err)
+//	}
+//	config := huma.DefaultConfig(spec.Info.Title, spec.Info.Version)
+//	config.OpenAPI = spec
+func LoadOpenAPI(data []byte) (*OpenAPI, error) {
+	oapi := &OpenAPI{}
+	if err := yaml.Unmarshal(data, oapi); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(oapi.OpenAPI, "3.1") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, only 3.1 is supported", oapi.OpenAPI)
+	}
+	stripKnownExtensions(reflect.ValueOf(oapi))
+
+	for path, item := range oapi.Paths {
+		if item == nil {
+			continue
+		}
+		for method, op := range map[string]*Operation{
+			http.MethodGet:     item.Get,
+			http.MethodPut:     item.Put,
+			http.MethodPost:    item.Post,
+			http.MethodDelete:  item.Delete,
+			http.MethodOptions: item.Options,
+			http.MethodHead:    item.Head,
+			http.MethodPatch:   item.Patch,
+			http.MethodTrace:   item.Trace,
+		} {
+			if op != nil {
+				op.Method = method
+				op.Path = path
+			}
+		}
+	}
+	return oapi, nil
+}
+
+// stripKnownExtensions removes the struct fields from the inline `Extensions`
+// of each struct, as every field is collected there when unmarshaling.
+// Schemas handle this themselves.
+func stripKnownExtensions(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() && v.Type().Elem() != reflect.TypeOf(Schema{}) {
+			stripKnownExtensions(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			stripKnownExtensions(v.Index(i))
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() == reflect.Interface {
+			// Arbitrary values, e.g. extensions or examples.
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			stripKnownExtensions(iter.Value())
+		}
+	case reflect.Struct:
+		t := v.Type()
+		ext := v.FieldByName("Extensions")
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Name == "Extensions" {
+				continue
+			}
+			if ext.IsValid() && !ext.IsNil() {
+				if name, _, _ := strings.Cut(f.Tag.Get("yaml"), ","); name != "" && name != "-" {
+					ext.SetMapIndex(reflect.ValueOf(name), reflect.Value{})
+				}
+			}
+			stripKnownExtensions(v.Field(i))
+		}
+		if ext.IsValid() && !ext.IsNil() && ext.Len() == 0 {
+			ext.Set(reflect.Zero(ext.Type()))
+		}
+	}
+}
+
+// Implement registers a handler for an operation declared in the API's
+// OpenAPI document, e.g. one loaded via `LoadOpenAPI`. This allows a
+// design-first workflow, where the document is written first and is the
+// source of truth for the API:
+//
+//	huma.Implement(api, "get-greeting", func(ctx context.Context, input *GreetingInput) (*GreetingOutput, error) {
+//		// ...
+//	})
+//
+// Like `Register`, the input & output structs describe the params, headers
+// and bodies using field tags, but these are only used to decode requests &
+// write responses. Each input param must be declared by the operation, and
+// params & bodies are validated using the declared schemas rather than ones
+// generated from the Go types. The declared documentation is left as-is.
+//
+// Panics if the operation is not declared or uses undeclared params or
+// request bodies.
+func Implement[I, O any](api API, operationID string, handler func(context.Context, *I) (*O, error)) {
+	declared := api.OpenAPI().FindOperation(operationID)
+	if declared == nil {
+		panic(fmt.Sprintf("operation %s is not declared in the OpenAPI document", operationID))
+	}
+	op := *declared
+	op.declared = true
+	Register(api, op, handler)
+}
+
+// declareParams uses the schemas of the operation's declared params to parse
+// & validate the input params.
+func declareParams(oapi *OpenAPI, op *Operation, params *findResult[*paramFieldInfo]) {
+	declared := map[string]*Param{}
+	var all []*Param
+	if item := oapi.Paths[op.Path]; item != nil {
+		all = append(all, item.Parameters...)
+	}
+	for _, p := range append(all, op.Parameters...) {
+		if p != nil && p.Name == "" && p.Ref != "" && oapi.Components != nil {
+			p = oapi.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+		}
+		if p != nil {
+			declared[paramKey(p.In, p.Name)] = p
+		}
+	}
+
+	registry := oapi.Components.Schemas
+	for _, entry := range params.Paths {
+		p := entry.Value
+		if p.param == nil {
+			// Hidden params aren't documented, so use the generated schema.
+			continue
+		}
+		d := declared[paramKey(p.Loc, p.Name)]
+		if d == nil {
+			panic(fmt.Sprintf("operation %s: %s param %s is not declared", op.OperationID, p.Loc, p.Name))
+		}
+		p.param = nil
+		if d.Schema == nil {
+			continue
+		}
+		p.Schema = d.Schema
+		if p.parseQuery = newQueryParser(registry, p); p.parseQuery == nil {
+			p.parse = newParamParser(registry, p)
+		}
+	}
+}
+
+// paramKey identifies a param by location & name. Header names are case
+// insensitive.
+func paramKey(in, name string) string {
+	if in == "header" {
+		name = http.CanonicalHeaderKey(name)
+	}
+	return in + "." + name
+}
+
+// declaredBodySchema returns the declared request body schema for the content
+// type, falling back to any declared JSON content type.
+func declaredBodySchema(oapi *OpenAPI, op *Operation, contentType string, jsonFormats map[string]bool) *Schema {
+	body := op.RequestBody
+	if body != nil && body.Ref != "" && oapi.Components != nil {
+		body = oapi.Components.RequestBodies[strings.TrimPrefix(body.Ref, "#/components/requestBodies/")]
+	}
+	if body == nil {
+		panic(fmt.Sprintf("operation %s: request body is not declared", op.OperationID))
+	}
+	mt := body.Content[contentType]
+	if mt == nil {
+		types := make([]string, 0, len(body.Content))
+		for ct := range body.Content {
+			types = append(types, ct)
+		}
+		sort.Strings(types)
+		for _, ct := range types {
+			if jsonFormats[formatKey(ct)] || strings.HasSuffix(ct, "json") {
+				mt = body.Content[ct]
+				break
+			}
+		}
+	}
+	if mt == nil || mt.Schema == nil {
+		panic(fmt.Sprintf("operation %s: request body schema for %s is not declared", op.OperationID, contentType))
+	}
+	return mt.Schema
+}
+
+// declaredStatus returns the first declared success status, preferring ones
+// with a body if the output has one, or zero if there are none.
+func declaredStatus(op *Operation, hasBody bool) int {
+	statuses := []int{}
+	for key := range op.Responses {
+		if status, err := strconv.Atoi(key); err == nil && status >= 200 && status < 300 {
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		if resp := op.Responses[strconv.Itoa(status)]; !hasBody || (resp != nil && len(resp.Content) > 0) {
+			return status
+		}
+	}
+	if len(statuses) > 0 {
+		return statuses[0]
+	}
+	return 0
+}
@@ -0,0 +1,207 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+var implementSpec = `
+openapi: 3.1.0
+info:
+  title: Greetings
+  version: 1.0.0
+  x-team: greeters
+paths:
+  /greetings/{name}:
+    parameters:
+      - name: name
+        in: path
+        required: true
+        schema:
+          type: string
+          maxLength: 10
+    get:
+      operationId: get-greeting
+      x-internal: false
+      parameters:
+        - $ref: "#/components/parameters/Polite"
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Greeting"
+    put:
+      operationId: put-greeting
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Greeting"
+      responses:
+        "201":
+          description: Created
+components:
+  parameters:
+    Polite:
+      name: polite
+      in: query
+      schema:
+        type: boolean
+  schemas:
+    Greeting:
+      type: object
+      additionalProperties: false
+      required: [message]
+      properties:
+        message:
+          type: string
+          minLength: 3
+`
+
+type ImplementGreeting struct {
+	Message string `json:"message"`
+}
+
+func TestLoadOpenAPI(t *testing.T) {
+	oapi, err := LoadOpenAPI([]byte(implementSpec))
+	assert.NoError(t, err)
+	assert.Equal(t, "Greetings", oapi.Info.Title)
+	assert.Equal(t, map[string]any{"x-team": "greeters"}, oapi.Info.Extensions)
+
+	op := oapi.FindOperation("get-greeting")
+	assert.Equal(t, http.MethodGet, op.Method)
+	assert.Equal(t, "/greetings/{name}", op.Path)
+	assert.Equal(t, map[string]any{"x-internal": false}, op.Extensions)
+	assert.Nil(t, oapi.Paths["/greetings/{name}"].Extensions)
+	assert.Equal(t, "#/components/parameters/Polite", op.Parameters[0].Ref)
+	assert.Equal(t, "polite", oapi.Components.Parameters["Polite"].Name)
+	assert.Equal(t, TypeString, oapi.Components.Schemas.SchemaFromRef("#/components/schemas/Greeting").Properties["message"].Type)
+
+	// The loaded document round-trips.
+	b, err := json.Marshal(oapi)
+	assert.NoError(t, err)
+	again, err := LoadOpenAPI(b)
+	assert.NoError(t, err)
+	b2, _ := json.Marshal(again)
+	assert.JSONEq(t, string(b), string(b2))
+
+	_, err = LoadOpenAPI([]byte(`openapi: 3.0.3`))
+	assert.ErrorContains(t, err, "only 3.1 is supported")
+
+	_, err = LoadOpenAPI([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestImplement(t *testing.T) {
+	oapi, err := LoadOpenAPI([]byte(implementSpec))
+	assert.NoError(t, err)
+	config := DefaultConfig(oapi.Info.Title, oapi.Info.Version)
+	config.OpenAPI = oapi
+	r := chi.NewRouter()
+	api := NewTestAdapter(r, config)
+
+	Implement(api, "get-greeting", func(ctx context.Context, input *struct {
+		Name   string `path:"name"`
+		Polite bool   `query:"polite"`
+	}) (*struct{ Body ImplementGreeting }, error) {
+		resp := &struct{ Body ImplementGreeting }{}
+		resp.Body.Message = "Hi " + input.Name
+		if input.Polite {
+			resp.Body.Message = "Hello, " + input.Name
+		}
+		return resp, nil
+	})
+
+	var stored ImplementGreeting
+	Implement(api, "put-greeting", func(ctx context.Context, input *struct {
+		Name string `path:"name"`
+		Body ImplementGreeting
+	}) (*struct{}, error) {
+		stored = input.Body
+		return nil, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/greetings/world?polite=true", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"message": "Hello, world"}`, w.Body.String())
+
+	// Params are validated using the declared schemas.
+	req, _ = http.NewRequest(http.MethodGet, "/greetings/everyone-here?polite=maybe", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"location":"path.name"`)
+	assert.Contains(t, w.Body.String(), `"location":"query.polite"`)
+
+	// The body is validated using the declared schema, and the declared
+	// success status is used.
+	req, _ = http.NewRequest(http.MethodPut, "/greetings/world", strings.NewReader(`{"message": "Hi", "extra": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"location":"body.message"`)
+	assert.Contains(t, w.Body.String(), `"location":"body.extra"`)
+
+	req, _ = http.NewRequest(http.MethodPut, "/greetings/world", strings.NewReader(`{"message": "Howdy"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "Howdy", stored.Message)
+
+	// The declared documentation is left as-is.
+	op := api.OpenAPI().FindOperation("put-greeting")
+	assert.Equal(t, []string{"201"}, keys(op.Responses))
+	assert.Equal(t, []string{"Greeting"}, keys(api.OpenAPI().Components.Schemas.Map()))
+	assert.Empty(t, api.OpenAPI().FindOperation("get-greeting").Parameters[0].Name)
+}
+
+func keys[T any](m map[string]T) []string {
+	result := []string{}
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+func TestImplementErrors(t *testing.T) {
+	oapi, _ := LoadOpenAPI([]byte(implementSpec))
+	config := DefaultConfig(oapi.Info.Title, oapi.Info.Version)
+	config.OpenAPI = oapi
+	api := NewTestAdapter(chi.NewRouter(), config)
+
+	assert.PanicsWithValue(t, "operation missing is not declared in the OpenAPI document", func() {
+		Implement(api, "missing", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+
+	assert.PanicsWithValue(t, "operation get-greeting: query param limit is not declared", func() {
+		Implement(api, "get-greeting", func(ctx context.Context, input *struct {
+			Limit int `query:"limit"`
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+
+	assert.PanicsWithValue(t, "operation get-greeting: request body is not declared", func() {
+		Implement(api, "get-greeting", func(ctx context.Context, input *struct {
+			Body ImplementGreeting
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
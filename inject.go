@@ -0,0 +1,100 @@
+package huma
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// providerFunc returns the value to inject into an input field for a request.
+type providerFunc func(ctx Context) (any, error)
+
+// providerRegistry is implemented by APIs which support `Provide`.
+type providerRegistry interface {
+	addProvider(t reflect.Type, provider providerFunc)
+	provider(t reflect.Type) providerFunc
+}
+
+// Provide registers a function which provides a value of type `T` for each
+// request, like a database handle, logger, or the authenticated user. Input
+// struct fields of type `T` with the `inject:"true"` tag are set to the
+// provided value after the request has been parsed, before any resolvers and
+// the handler run. Providers must be registered before the operations which
+// use them, and the same provider is used by groups of the API.
+//
+//	huma.Provide(api, func(ctx huma.Context) (*sql.DB, error) {
+//		return db, nil
+//	})
+//
+//	huma.Provide(api, func(ctx huma.Context) (*User, error) {
+//		user, ok := ctx.Context().Value(userKey).(*User)
+//		if !ok {
+//			return nil, huma.Error401Unauthorized("not logged in")
+//		}
+//		return user, nil
+//	})
+//
+//	type ListThingsInput struct {
+//		DB   *sql.DB `inject:"true"`
+//		User *User   `inject:"true"`
+//	}
+//
+// If a provider returns an error, the handler isn't called and the error is
+// sent using its status if it's a `huma.StatusError`, otherwise as a 500.
+func Provide[T any](api API, provider func(ctx Context) (T, error)) {
+	r, ok := api.(providerRegistry)
+	if !ok {
+		panic("API does not support providers")
+	}
+	r.addProvider(reflect.TypeOf((*T)(nil)).Elem(), func(ctx Context) (any, error) {
+		return provider(ctx)
+	})
+}
+
+// injectInfo is an input field which is set by a provider.
+type injectInfo struct {
+	provider providerFunc
+}
+
+// findInjected finds the input fields to inject values into, panicking if
+// there is no provider for one of them.
+func findInjected(api API, t reflect.Type) *findResult[*injectInfo] {
+	return findInType(t, nil, func(sf reflect.StructField, i []int) *injectInfo {
+		if !boolTag(sf, "inject") {
+			return nil
+		}
+		var provider providerFunc
+		if r, ok := api.(providerRegistry); ok {
+			provider = r.provider(sf.Type)
+		}
+		if provider == nil {
+			panic(fmt.Sprintf("no provider for injected field %s of type %s", sf.Name, sf.Type))
+		}
+		return &injectInfo{provider: provider}
+	}, "Body")
+}
+
+func (r *api) addProvider(t reflect.Type, provider providerFunc) {
+	if r.providers == nil {
+		r.providers = map[reflect.Type]providerFunc{}
+	}
+	r.providers[t] = provider
+}
+
+func (r *api) provider(t reflect.Type) providerFunc {
+	return r.providers[t]
+}
+
+func (g *Group) addProvider(t reflect.Type, provider providerFunc) {
+	r, ok := g.API.(providerRegistry)
+	if !ok {
+		panic("API does not support providers")
+	}
+	r.addProvider(t, provider)
+}
+
+func (g *Group) provider(t reflect.Type) providerFunc {
+	if r, ok := g.API.(providerRegistry); ok {
+		return r.provider(t)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package huma
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type InjectLogger interface {
+	Log(msg string)
+}
+
+type injectLogger struct {
+	msgs []string
+}
+
+func (l *injectLogger) Log(msg string) {
+	l.msgs = append(l.msgs, msg)
+}
+
+type InjectUser struct {
+	Name string
+}
+
+func TestInject(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+	group := NewGroup(app, "/v1")
+
+	logger := &injectLogger{}
+	Provide(app, func(ctx Context) (InjectLogger, error) {
+		return logger, nil
+	})
+	Provide(group, func(ctx Context) (*InjectUser, error) {
+		switch ctx.Header("Authorization") {
+		case "":
+			return nil, Error401Unauthorized("not logged in")
+		case "broken":
+			return nil, errors.New("user store unavailable")
+		}
+		return &InjectUser{Name: ctx.Header("Authorization")}, nil
+	})
+
+	Register(group, Operation{
+		OperationID: "greet",
+		Method:      http.MethodGet,
+		Path:        "/greet",
+	}, func(ctx context.Context, input *struct {
+		Logger InjectLogger `inject:"true"`
+		User   *InjectUser  `inject:"true"`
+	}) (*struct{}, error) {
+		input.Logger.Log("hello " + input.User.Name)
+		return nil, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/greet", nil)
+	req.Header.Set("Authorization", "alice")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, []string{"hello alice"}, logger.msgs)
+
+	for auth, status := range map[string]int{
+		"":       http.StatusUnauthorized,
+		"broken": http.StatusInternalServerError,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/v1/greet", nil)
+		req.Header.Set("Authorization", auth)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, status, w.Code, w.Body.String())
+	}
+	assert.Len(t, logger.msgs, 1)
+
+	assert.PanicsWithValue(t, "no provider for injected field Count of type int", func() {
+		Register(app, Operation{
+			OperationID: "missing",
+			Method:      http.MethodGet,
+			Path:        "/missing",
+		}, func(ctx context.Context, input *struct {
+			Count int `inject:"true"`
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
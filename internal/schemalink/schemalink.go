@@ -0,0 +1,21 @@
+// Package schemalink helps packages which call Huma operations and re-encode
+// their responses, like `jsonrpc` and `connect`.
+package schemalink
+
+import "encoding/json"
+
+// Strip removes the `$schema` property added to response bodies by the
+// default config, as it isn't part of the data being returned. Bodies which
+// aren't JSON objects are returned unchanged.
+func Strip(body []byte) []byte {
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(body, &obj) != nil || obj["$schema"] == nil {
+		return body
+	}
+	delete(obj, "$schema")
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return body
+	}
+	return b
+}
@@ -0,0 +1,13 @@
+package schemalink
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrip(t *testing.T) {
+	assert.JSONEq(t, `{"name": "foo"}`, string(Strip([]byte(`{"$schema": "https://example.com/schemas/Thing.json", "name": "foo"}`))))
+	assert.Equal(t, `{"name":"foo"}`, string(Strip([]byte(`{"name":"foo"}`))))
+	assert.Equal(t, `[1, 2]`, string(Strip([]byte(`[1, 2]`))))
+}
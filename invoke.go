@@ -0,0 +1,287 @@
+package huma
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Invoke calls an operation of the API in-process, with its params & body
+// given as a single object of arguments. This is used by RPC facades like the
+// `connect` and `jsonrpc` packages, so one registration serves both REST &
+// RPC clients. The request goes through the API's adapter like any other, so
+// middleware, validation & error handling all apply.
+//
+// Each argument with the name of one of the operation's path, query or header
+// params is sent as that param. Arrays are joined using the param's style,
+// and objects in `deepObject` query params are sent one key per property. If
+// the operation's JSON body is an object then the remaining arguments are its
+// properties, otherwise the body is the `body` argument. The header is sent
+// with the request, e.g. to pass on authentication. The request is canceled
+// along with the context, but doesn't get its values, as they may hold the
+// router's state for an incoming request.
+//
+// A 400 `StatusError` is returned if there are unknown arguments or an
+// argument can't be sent as a param. Otherwise, the operation's response is
+// returned whatever its status, with the body fully read.
+func Invoke(ctx context.Context, api API, op *Operation, header http.Header, args map[string]any) (*http.Response, error) {
+	oapi := api.OpenAPI()
+	path := op.Path
+	query := url.Values{}
+	reqHeader := http.Header{}
+	for name, values := range header {
+		reqHeader[name] = append([]string{}, values...)
+	}
+
+	remaining := make(map[string]any, len(args))
+	for k, v := range args {
+		remaining[k] = v
+	}
+
+	errs := []error{}
+	for _, p := range op.Parameters {
+		if p != nil && p.Name == "" && p.Ref != "" && oapi.Components != nil {
+			p = oapi.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+		}
+		if p == nil {
+			continue
+		}
+		v, ok := remaining[p.Name]
+		if !ok || v == nil {
+			if p.In == "path" {
+				errs = append(errs, &ErrorDetail{Message: "missing argument", Location: p.Name})
+			}
+			delete(remaining, p.Name)
+			continue
+		}
+		delete(remaining, p.Name)
+
+		switch p.In {
+		case "path":
+			s, err := formatArg(v)
+			if err != nil {
+				errs = append(errs, &ErrorDetail{Message: err.Error(), Location: p.Name, Value: v})
+				continue
+			}
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(s))
+		case "query":
+			if obj, ok := v.(map[string]any); ok && p.Style == "deepObject" {
+				for key, value := range obj {
+					s, err := formatArg(value)
+					if err != nil {
+						errs = append(errs, &ErrorDetail{Message: err.Error(), Location: p.Name + "." + key, Value: value})
+						continue
+					}
+					query.Add(p.Name+"["+key+"]", s)
+				}
+				continue
+			}
+			if items, ok := v.([]any); ok && p.Explode != nil && *p.Explode {
+				for _, item := range items {
+					s, err := formatArg(item)
+					if err != nil {
+						errs = append(errs, &ErrorDetail{Message: err.Error(), Location: p.Name, Value: item})
+						continue
+					}
+					query.Add(p.Name, s)
+				}
+				continue
+			}
+			s, err := formatList(v, listDelimiters[p.Style])
+			if err != nil {
+				errs = append(errs, &ErrorDetail{Message: err.Error(), Location: p.Name, Value: v})
+				continue
+			}
+			query.Set(p.Name, s)
+		case "header":
+			s, err := formatList(v, ",")
+			if err != nil {
+				errs = append(errs, &ErrorDetail{Message: err.Error(), Location: p.Name, Value: v})
+				continue
+			}
+			reqHeader.Set(p.Name, s)
+		case "cookie":
+			s, err := formatArg(v)
+			if err != nil {
+				errs = append(errs, &ErrorDetail{Message: err.Error(), Location: p.Name, Value: v})
+				continue
+			}
+			reqHeader.Add("Cookie", (&http.Cookie{Name: p.Name, Value: s}).String())
+		}
+	}
+
+	var body io.Reader
+	if op.RequestBody != nil {
+		contentType, flatten := invokeContentType(oapi, op)
+		var value any
+		send := false
+		if flatten {
+			value, send = remaining, len(remaining) > 0
+			remaining = nil
+		} else if v, ok := remaining["body"]; ok {
+			value, send = v, true
+			delete(remaining, "body")
+		}
+		if send {
+			b, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(b)
+			reqHeader.Set("Content-Type", contentType)
+		}
+	}
+
+	names := make([]string, 0, len(remaining))
+	for name := range remaining {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errs = append(errs, &ErrorDetail{Message: "unknown argument", Location: name, Value: remaining[name]})
+	}
+	if len(errs) > 0 {
+		return nil, newAPIError(api, http.StatusBadRequest, "invalid arguments", errs...)
+	}
+
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(invokeContext{ctx}, op.Method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = reqHeader
+	req.RequestURI = path
+	if req.Body == nil {
+		// Like server requests, the body is always set.
+		req.Body = http.NoBody
+	}
+
+	w := &invokeWriter{header: http.Header{}}
+	api.Adapter().ServeHTTP(w, req)
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(w.status) + " " + http.StatusText(w.status),
+		StatusCode:    w.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        w.header,
+		Body:          io.NopCloser(&w.body),
+		ContentLength: int64(w.body.Len()),
+		Request:       req,
+	}, nil
+}
+
+// invokeContentType returns the JSON content type of the operation's request
+// body and whether its schema is an object, whose properties are then given
+// as arguments.
+func invokeContentType(oapi *OpenAPI, op *Operation) (string, bool) {
+	body := op.RequestBody
+	if body.Ref != "" && oapi.Components != nil {
+		body = oapi.Components.RequestBodies[strings.TrimPrefix(body.Ref, "#/components/requestBodies/")]
+	}
+	if body == nil {
+		return "application/json", false
+	}
+	types := make([]string, 0, len(body.Content))
+	for ct := range body.Content {
+		types = append(types, ct)
+	}
+	sort.Strings(types)
+	for _, ct := range types {
+		if !strings.HasSuffix(ct, "json") {
+			continue
+		}
+		s := body.Content[ct].Schema
+		for s != nil && s.Ref != "" && oapi.Components != nil {
+			s = oapi.Components.Schemas.SchemaFromRef(s.Ref)
+		}
+		return ct, s != nil && s.Type == TypeObject
+	}
+	return "application/json", false
+}
+
+// formatArg formats a single argument value as a param string.
+func formatArg(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case json.Number:
+		return v.String(), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	}
+	return "", fmt.Errorf("expected a string, number or boolean")
+}
+
+// formatList formats an argument which may be an array, joining its items.
+func formatList(v any, delimiter string) (string, error) {
+	items, ok := v.([]any)
+	if !ok {
+		return formatArg(v)
+	}
+	parts := make([]string, len(items))
+	for i, item := range items {
+		s, err := formatArg(item)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = s
+	}
+	return strings.Join(parts, delimiter), nil
+}
+
+// invokeContext is canceled with its parent context but has none of its
+// values.
+type invokeContext struct {
+	context.Context
+}
+
+func (invokeContext) Value(key any) any {
+	return nil
+}
+
+// invokeWriter buffers the response of an invoked operation.
+type invokeWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *invokeWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *invokeWriter) WriteHeader(status int) {
+	// Interim responses like early hints are not the final status.
+	if w.status == 0 && !isInterim(status) {
+		w.status = status
+	}
+}
+
+func (w *invokeWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+// Flush is a no-op, as the response is returned once complete.
+func (w *invokeWriter) Flush() {}
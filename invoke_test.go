@@ -0,0 +1,151 @@
+package huma
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvoke(t *testing.T) {
+	r := chi.NewRouter()
+	api := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Register(api, Operation{
+		OperationID: "search",
+		Method:      http.MethodPost,
+		Path:        "/search/{index}",
+	}, func(ctx context.Context, input *struct {
+		Index  string            `path:"index" maxLength:"10"`
+		IDs    []string          `query:"ids,explode"`
+		Tags   []string          `query:"tags,pipeDelimited"`
+		Filter map[string]string `query:"filter,deepObject"`
+		Token  string            `header:"X-Token"`
+		Auth   string            `header:"Authorization"`
+		Body   []string
+	}) (*struct {
+		Total int `header:"X-Total"`
+		Body  map[string]any
+	}, error) {
+		return &struct {
+			Total int `header:"X-Total"`
+			Body  map[string]any
+		}{Total: len(input.Body), Body: map[string]any{
+			"index":  input.Index,
+			"ids":    input.IDs,
+			"tags":   input.Tags,
+			"filter": input.Filter,
+			"token":  input.Token,
+			"auth":   input.Auth,
+			"body":   input.Body,
+		}}, nil
+	})
+
+	op := api.OpenAPI().FindOperation("search")
+	resp, err := Invoke(context.Background(), api, op, http.Header{"Authorization": {"Bearer abc"}}, map[string]any{
+		"index":   "things",
+		"ids":     []any{1.0, 2.0},
+		"tags":    []any{"a", "b"},
+		"filter":  map[string]any{"kind": "big"},
+		"X-Token": "secret",
+		"body":    []any{"x", "y"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("X-Total"))
+	b, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{
+		"index": "things",
+		"ids": ["1", "2"],
+		"tags": ["a", "b"],
+		"filter": {"kind": "big"},
+		"token": "secret",
+		"auth": "Bearer abc",
+		"body": ["x", "y"]
+	}`, string(b))
+
+	// Errors from the operation are returned as responses.
+	resp, err = Invoke(context.Background(), api, op, nil, map[string]any{
+		"index": "too-long-for-the-index",
+		"body":  []any{},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	// Arguments which can't be sent are errors.
+	_, err = Invoke(context.Background(), api, op, nil, map[string]any{
+		"tags":  []any{map[string]any{}},
+		"other": true,
+	})
+	assert.Equal(t, http.StatusBadRequest, err.(StatusError).GetStatus())
+	errs := []string{}
+	for _, detail := range err.(*ErrorModel).Errors {
+		errs = append(errs, detail.Error())
+	}
+	assert.Equal(t, []string{
+		"missing argument (index: <nil>)",
+		"expected a string, number or boolean (tags: [map[]])",
+		"unknown argument (other: true)",
+	}, errs)
+}
+
+func TestInvokeObjectBody(t *testing.T) {
+	r := chi.NewRouter()
+	api := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Register(api, Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID   string `path:"id"`
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{}, error) {
+		assert.Equal(t, "abc", input.ID)
+		assert.Equal(t, "Thing", input.Body.Name)
+		return nil, nil
+	})
+
+	resp, err := Invoke(context.Background(), api, api.OpenAPI().FindOperation("put-thing"), nil, map[string]any{
+		"id":   "abc",
+		"name": "Thing",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestInvokeEarlyHints(t *testing.T) {
+	r := chi.NewRouter()
+	api := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Register(api, Operation{
+		OperationID:   "hinted",
+		Method:        http.MethodGet,
+		Path:          "/hinted",
+		DefaultStatus: http.StatusCreated,
+		EarlyHints:    []string{"</style.css>; rel=preload; as=style"},
+		Transformers: []Transformer{func(ctx Context, status string, v any) (any, error) {
+			// Hints sent while the body is marshaled keep the delayed status.
+			WriteEarlyHints(ctx, "</font.woff2>; rel=preload; as=font")
+			return v, nil
+		}},
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: "hello"}, nil
+	})
+
+	// The early hints are not the final status.
+	resp, err := Invoke(context.Background(), api, api.OpenAPI().FindOperation("hinted"), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, []string{
+		"</style.css>; rel=preload; as=style",
+		"</font.woff2>; rel=preload; as=font",
+	}, resp.Header.Values("Link"))
+	b, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `"hello"`+"\n", string(b))
+}
@@ -0,0 +1,139 @@
+package huma
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// jsonField is a struct field as encoded by `encoding/json`.
+type jsonField struct {
+	name      string
+	index     []int
+	tagged    bool
+	omitEmpty bool
+}
+
+var jsonFieldsCache sync.Map
+
+// jsonFields returns the fields of the struct type which `encoding/json`
+// encodes, in order, using the same names and rules for promoting the fields
+// of embedded structs. Like `encoding/json`, a field shadows fields with the
+// same name which are nested more deeply, a tagged field shadows untagged
+// fields at the same depth, and otherwise conflicting fields are dropped.
+// Fields within nil embedded pointers must be skipped by the caller, e.g. via
+// `reflect.Value.FieldByIndexErr`. This is the single place which knows these
+// rules, so field selection and the body decoder see the same fields as
+// `encoding/json`.
+func jsonFields(t reflect.Type) []jsonField {
+	if cached, ok := jsonFieldsCache.Load(t); ok {
+		return cached.([]jsonField)
+	}
+
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	fields := []jsonField{}
+	next := []embedded{{typ: t}}
+	nextCount := map[reflect.Type]int{}
+	visited := map[reflect.Type]bool{}
+	for len(next) > 0 {
+		current := next
+		count := nextCount
+		next = nil
+		nextCount = map[reflect.Type]int{}
+
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+					// Embedded structs of unexported types may have exported fields.
+				} else if !sf.IsExported() {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(append([]int{}, e.index...), i)
+
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					field := jsonField{
+						name:      name,
+						index:     index,
+						tagged:    name != "",
+						omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+					}
+					if field.name == "" {
+						field.name = sf.Name
+					}
+					fields = append(fields, field)
+					if count[e.typ] > 1 {
+						// The same struct is embedded more than once at this depth, so
+						// its fields conflict with each other.
+						fields = append(fields, field)
+					}
+					continue
+				}
+
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, embedded{typ: ft, index: index})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if len(a.index) != len(b.index) {
+			return len(a.index) < len(b.index)
+		}
+		return a.tagged && !b.tagged
+	})
+
+	// Keep the dominant field for each name, if there is one.
+	dominant := make([]jsonField, 0, len(fields))
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if j-i == 1 || len(fields[i].index) != len(fields[i+1].index) || fields[i].tagged != fields[i+1].tagged {
+			dominant = append(dominant, fields[i])
+		}
+		i = j
+	}
+
+	sort.Slice(dominant, func(i, j int) bool {
+		a, b := dominant[i].index, dominant[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	jsonFieldsCache.Store(t, dominant)
+	return dominant
+}
@@ -0,0 +1,217 @@
+// Package jsonrpc serves the operations of a Huma API as JSON-RPC 2.0 methods
+// over a single endpoint, for clients which prefer RPC semantics. See
+// https://www.jsonrpc.org/specification.
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/internal/schemalink"
+)
+
+// Error codes defined by the JSON-RPC 2.0 specification.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Request is a JSON-RPC request or notification, which has no ID.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// Response is a JSON-RPC response with either a result or an error.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// Error is the error of a JSON-RPC response.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// Register serves every operation of the API with an operation ID as a
+// JSON-RPC method named by the ID, via `POST` requests to the path. Call it
+// once all operations are registered:
+//
+//	jsonrpc.Register(api, "/rpc")
+//
+// Params must be given by name, and are the operation's params & body as
+// described by `huma.Invoke`, e.g. `{"thing-id": "abc", "name": "Thing"}`, so
+// they are validated using the operation's schemas. The result is the
+// operation's response body, or `null` if it has none. Batches are called in
+// order, and headers of the request are sent to each call, while headers of
+// their responses are not sent.
+//
+// Error responses of 400 & 422 are sent as invalid params errors, and other
+// error responses use their status as the error code. The data of these
+// errors is the operation's error response body.
+//
+// The endpoint is not documented in the OpenAPI. Panics if the path is
+// empty.
+func Register(api huma.API, path string) {
+	if path == "" {
+		panic("jsonrpc path must not be empty")
+	}
+
+	oapi := api.OpenAPI()
+	methods := map[string]*huma.Operation{}
+	for _, item := range oapi.Paths {
+		for _, op := range []*huma.Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head, item.Patch, item.Trace} {
+			if op != nil && op.OperationID != "" {
+				methods[op.OperationID] = op
+			}
+		}
+	}
+
+	api.Adapter().Handle(&huma.Operation{
+		OperationID: "jsonrpc",
+		Method:      http.MethodPost,
+		Path:        path,
+	}, func(ctx huma.Context) {
+		body, err := io.ReadAll(ctx.BodyReader())
+		if err != nil {
+			writeJSON(ctx, errorResponse(nil, CodeParseError, "unable to read request", nil))
+			return
+		}
+
+		header := http.Header{}
+		ctx.EachHeader(func(name, value string) {
+			switch strings.ToLower(name) {
+			case "content-type", "content-length", "content-encoding", "accept-encoding":
+				return
+			}
+			header.Add(name, value)
+		})
+		header.Set("Accept", "application/json")
+
+		body = bytes.TrimSpace(body)
+		if len(body) > 0 && body[0] == '[' {
+			var batch []json.RawMessage
+			if err := json.Unmarshal(body, &batch); err != nil {
+				writeJSON(ctx, errorResponse(nil, CodeParseError, "parse error", err.Error()))
+				return
+			}
+			if len(batch) == 0 {
+				writeJSON(ctx, errorResponse(nil, CodeInvalidRequest, "empty batch", nil))
+				return
+			}
+			responses := []*Response{}
+			for _, raw := range batch {
+				if resp := call(ctx, api, methods, header, raw); resp != nil {
+					responses = append(responses, resp)
+				}
+			}
+			if len(responses) == 0 {
+				// Only notifications were sent.
+				ctx.SetStatus(http.StatusNoContent)
+				return
+			}
+			writeJSON(ctx, responses)
+			return
+		}
+
+		if !json.Valid(body) {
+			writeJSON(ctx, errorResponse(nil, CodeParseError, "parse error", nil))
+			return
+		}
+		if resp := call(ctx, api, methods, header, body); resp != nil {
+			writeJSON(ctx, resp)
+			return
+		}
+		ctx.SetStatus(http.StatusNoContent)
+	})
+}
+
+// call calls the operation of a single request, returning nil for
+// notifications.
+func call(ctx huma.Context, api huma.API, methods map[string]*huma.Operation, header http.Header, raw json.RawMessage) *Response {
+	var req Request
+	if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, CodeInvalidRequest, "invalid request", nil)
+	}
+	notification := len(req.ID) == 0
+	respond := func(resp *Response) *Response {
+		if notification {
+			return nil
+		}
+		return resp
+	}
+
+	op := methods[req.Method]
+	if op == nil {
+		return respond(errorResponse(req.ID, CodeMethodNotFound, "method not found", req.Method))
+	}
+
+	args := map[string]any{}
+	if len(req.Params) > 0 && !bytes.Equal(req.Params, []byte("null")) {
+		dec := json.NewDecoder(bytes.NewReader(req.Params))
+		dec.UseNumber()
+		if err := dec.Decode(&args); err != nil {
+			return respond(errorResponse(req.ID, CodeInvalidParams, "params must be an object", nil))
+		}
+	}
+
+	resp, err := huma.Invoke(ctx.Context(), api, op, header, args)
+	if err != nil {
+		return respond(errorResponse(req.ID, CodeInvalidParams, "invalid params", err))
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		var data any
+		if json.Unmarshal(body, &data) != nil {
+			data = nil
+		}
+		code := resp.StatusCode
+		if code == http.StatusBadRequest || code == http.StatusUnprocessableEntity {
+			code = CodeInvalidParams
+		}
+		message := http.StatusText(resp.StatusCode)
+		if m, ok := data.(map[string]any); ok {
+			if detail, ok := m["detail"].(string); ok && detail != "" {
+				message = detail
+			}
+		}
+		return respond(errorResponse(req.ID, code, message, data))
+	}
+
+	result := json.RawMessage("null")
+	if len(body) > 0 {
+		result = schemalink.Strip(body)
+	}
+	return respond(&Response{JSONRPC: "2.0", Result: result, ID: req.ID})
+}
+
+func errorResponse(id json.RawMessage, code int, message string, data any) *Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &Response{JSONRPC: "2.0", Error: &Error{Code: code, Message: message, Data: data}, ID: id}
+}
+
+func writeJSON(ctx huma.Context, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Errorf("unable to marshal JSON-RPC response: %w", err))
+	}
+	ctx.SetHeader("Content-Type", "application/json")
+	ctx.SetStatus(http.StatusOK)
+	ctx.BodyWriter().Write(b)
+}
@@ -0,0 +1,133 @@
+package jsonrpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Thing struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func newAPI(t *testing.T) humatest.TestAPI {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{thing-id}",
+	}, func(ctx context.Context, input *struct {
+		ID   string `path:"thing-id" maxLength:"5"`
+		User string `header:"X-User"`
+	}) (*struct{ Body Thing }, error) {
+		if input.ID == "none" {
+			return nil, huma.Error404NotFound("thing not found")
+		}
+		return &struct{ Body Thing }{Body: Thing{ID: input.ID, Name: input.User}}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-thing",
+		Method:      http.MethodPost,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name" minLength:"2"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	Register(api, "/rpc")
+	return api
+}
+
+func TestJSONRPC(t *testing.T) {
+	api := newAPI(t)
+
+	resp := api.Post("/rpc", "X-User: daniel",
+		strings.NewReader(`{"jsonrpc": "2.0", "method": "get-thing", "params": {"thing-id": "abc"}, "id": 1}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "result": {"id": "abc", "name": "daniel"}, "id": 1}`, resp.Body.String())
+
+	resp = api.Post("/rpc",
+		strings.NewReader(`{"jsonrpc": "2.0", "method": "create-thing", "params": {"name": "Thing"}, "id": "a"}`))
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "result": null, "id": "a"}`, resp.Body.String())
+
+	// Notifications get no response.
+	resp = api.Post("/rpc",
+		strings.NewReader(`{"jsonrpc": "2.0", "method": "create-thing", "params": {"name": "Thing"}}`))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Body.String())
+
+	// The endpoint isn't documented.
+	assert.Len(t, api.OpenAPI().Paths, 2)
+}
+
+func TestJSONRPCBatch(t *testing.T) {
+	api := newAPI(t)
+
+	resp := api.Post("/rpc", strings.NewReader(`[
+		{"jsonrpc": "2.0", "method": "get-thing", "params": {"thing-id": "a"}, "id": 1},
+		{"jsonrpc": "2.0", "method": "create-thing", "params": {"name": "Thing"}},
+		{"jsonrpc": "2.0", "method": "missing", "id": 2},
+		{"foo": "bar"},
+		{"jsonrpc": "2.0", "method": "get-thing", "params": {"thing-id": "none"}, "id": 3}
+	]`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[
+		{"jsonrpc": "2.0", "result": {"id": "a", "name": ""}, "id": 1},
+		{"jsonrpc": "2.0", "error": {"code": -32601, "message": "method not found", "data": "missing"}, "id": 2},
+		{"jsonrpc": "2.0", "error": {"code": -32600, "message": "invalid request"}, "id": null},
+		{"jsonrpc": "2.0", "error": {"code": 404, "message": "thing not found", "data": {
+			"$schema": "http:///schemas/ErrorModel.json",
+			"title": "Not Found",
+			"status": 404,
+			"detail": "thing not found"
+		}}, "id": 3}
+	]`, resp.Body.String())
+
+	resp = api.Post("/rpc", strings.NewReader(`[
+		{"jsonrpc": "2.0", "method": "create-thing", "params": {"name": "Thing"}}
+	]`))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = api.Post("/rpc", strings.NewReader(`[]`))
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "error": {"code": -32600, "message": "empty batch"}, "id": null}`, resp.Body.String())
+}
+
+func TestJSONRPCErrors(t *testing.T) {
+	api := newAPI(t)
+
+	resp := api.Post("/rpc", strings.NewReader(`{"jsonrpc": "2.0", "method"`))
+	assert.Contains(t, resp.Body.String(), `"code":-32700`)
+
+	resp = api.Post("/rpc", strings.NewReader(`[{"jsonrpc": "2.0"`))
+	assert.Contains(t, resp.Body.String(), `"code":-32700`)
+
+	resp = api.Post("/rpc",
+		strings.NewReader(`{"jsonrpc": "2.0", "method": "get-thing", "params": ["abc"], "id": 1}`))
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "error": {"code": -32602, "message": "params must be an object"}, "id": 1}`, resp.Body.String())
+
+	// Params are validated by the operation.
+	resp = api.Post("/rpc",
+		strings.NewReader(`{"jsonrpc": "2.0", "method": "get-thing", "params": {"thing-id": "too-long"}, "id": 1}`))
+	assert.Contains(t, resp.Body.String(), `"code":-32602`)
+	assert.Contains(t, resp.Body.String(), `"location":"path.thing-id"`)
+
+	resp = api.Post("/rpc",
+		strings.NewReader(`{"jsonrpc": "2.0", "method": "get-thing", "params": {"thing-id": "abc", "other": 1}, "id": 1}`))
+	assert.Contains(t, resp.Body.String(), `"code":-32602`)
+	assert.Contains(t, resp.Body.String(), `unknown argument`)
+
+	assert.Panics(t, func() {
+		Register(api, "")
+	})
+}
@@ -0,0 +1,66 @@
+package huma
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// jsonSchemaDialect is the JSON Schema version used by OpenAPI 3.1.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns the schema for the type as a standalone JSON Schema
+// (draft 2020-12) document, with the schemas it references in `$defs` rather
+// than in the OpenAPI components. This is useful to validate data outside of
+// requests, like config files or queue messages, or for code generators. The
+// schemas are added to the registry if needed.
+//
+//	b := huma.JSONSchema(api.OpenAPI().Components.Schemas, reflect.TypeOf(Config{}))
+func JSONSchema(r Registry, t reflect.Type) []byte {
+	s := r.Schema(t, true, deref(t).Name())
+	name := ""
+	if s.Ref != "" {
+		name = s.Ref[strings.LastIndexByte(s.Ref, '/')+1:]
+		s = r.SchemaFromRef(s.Ref)
+	}
+	return schemaDocument(r, s, name)
+}
+
+// schemaDocument returns the registry schema as a standalone JSON Schema
+// document, with the schemas it references in `$defs`. References to the
+// schema's own name, if any, point to the document root.
+func schemaDocument(registry Registry, root *Schema, name string) []byte {
+	schemas := registry.Map()
+
+	defs := map[string]json.RawMessage{}
+	queue := []string{}
+	rewrite := func(s *Schema) json.RawMessage {
+		b, _ := json.Marshal(s)
+		return rxSchema.ReplaceAllFunc(b, func(match []byte) []byte {
+			ref := strings.TrimPrefix(string(match), "#/components/schemas/")
+			if name != "" && ref == name {
+				return []byte("#")
+			}
+			if _, ok := defs[ref]; !ok && schemas[ref] != nil {
+				defs[ref] = nil
+				queue = append(queue, ref)
+			}
+			return []byte("#/$defs/" + ref)
+		})
+	}
+
+	doc := map[string]json.RawMessage{}
+	json.Unmarshal(rewrite(root), &doc)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		defs[ref] = rewrite(schemas[ref])
+	}
+
+	doc["$schema"], _ = json.Marshal(jsonSchemaDialect)
+	if len(defs) > 0 {
+		doc["$defs"], _ = json.Marshal(defs)
+	}
+	b, _ := json.Marshal(doc)
+	return b
+}
@@ -0,0 +1,120 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type SchemaDocOwner struct {
+	Name string `json:"name"`
+}
+
+type SchemaDocNode struct {
+	Owner    SchemaDocOwner   `json:"owner"`
+	Children []*SchemaDocNode `json:"children,omitempty"`
+}
+
+func TestSchemaEndpoint(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Register(app, Operation{
+		OperationID: "get-node",
+		Method:      http.MethodGet,
+		Path:        "/node",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body SchemaDocNode }, error) {
+		return nil, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/schemas/SchemaDocNode.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/schema+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["owner"],
+		"properties": {
+			"$schema": {
+				"type": "string",
+				"format": "uri",
+				"description": "A URL to the JSON Schema for this object.",
+				"readOnly": true
+			},
+			"owner": {"$ref": "#/$defs/SchemaDocOwner"},
+			"children": {"type": "array", "items": {"$ref": "#"}}
+		},
+		"$defs": {
+			"SchemaDocOwner": {
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"}
+				}
+			}
+		}
+	}`, w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodGet, "/schemas/Missing.json", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "schema not found")
+}
+
+func TestJSONSchema(t *testing.T) {
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["owner"],
+		"properties": {
+			"owner": {"$ref": "#/$defs/SchemaDocOwner"},
+			"children": {"type": "array", "items": {"$ref": "#"}}
+		},
+		"$defs": {
+			"SchemaDocOwner": {
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"}
+				}
+			}
+		}
+	}`, string(JSONSchema(registry, reflect.TypeOf(SchemaDocNode{}))))
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "array",
+		"items": {"$ref": "#/$defs/SchemaDocOwner"},
+		"$defs": {
+			"SchemaDocOwner": {
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"}
+				}
+			}
+		}
+	}`, string(JSONSchema(registry, reflect.TypeOf([]SchemaDocOwner{}))))
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "integer",
+		"format": "int64"
+	}`, string(JSONSchema(registry, reflect.TypeOf(0))))
+}
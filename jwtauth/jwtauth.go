@@ -0,0 +1,632 @@
+// Package jwtauth provides JWT bearer token verification for Huma APIs. It
+// verifies tokens signed with keys from a JWKS URL or a static key set,
+// checks the issuer, audience, expiration and scopes, and makes the verified
+// claims available to handlers.
+//
+//	verifier := jwtauth.New(jwtauth.Config{
+//		JWKSURL:  "https://example.com/.well-known/jwks.json",
+//		Issuer:   "https://example.com/",
+//		Audience: "my-api",
+//	})
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	verifier.Configure(&config)
+//
+//	router := chi.NewMux()
+//	router.Use(verifier.Middleware)
+//	api := humachi.New(router, config)
+//
+//	huma.Register(api, huma.Operation{
+//		// ...
+//		Security: verifier.Require("items:read"),
+//	}, func(ctx context.Context, input *MyInput) (*MyOutput, error) {
+//		claims := jwtauth.ClaimsFrom(ctx)
+//		// ...
+//	})
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // Register hashes for `crypto.Hash.New`.
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// DefaultSchemeName is the security scheme name used when none is configured.
+const DefaultSchemeName = "bearerAuth"
+
+// Config controls how tokens are verified. At least one of `JWKSURL` or
+// `Keys` must be set.
+type Config struct {
+	// JWKSURL is a URL to fetch a JSON Web Key Set of signing keys from, e.g.
+	// `https://example.com/.well-known/jwks.json`. Keys are cached and
+	// refreshed every `RefreshInterval` or when an unknown key ID is seen.
+	JWKSURL string
+
+	// Keys are static verification keys by key ID. Use an empty key ID to
+	// match tokens without a `kid` header. Values may be `*rsa.PublicKey`,
+	// `*ecdsa.PublicKey`, `ed25519.PublicKey`, or `[]byte` for HMAC secrets.
+	Keys map[string]any
+
+	// Issuer, if set, must match the token's `iss` claim.
+	Issuer string
+
+	// Audience, if set, must be one of the token's `aud` claim values.
+	Audience string
+
+	// ScopeClaim is the claim containing the token's scopes, either as a
+	// space-separated string or a list of strings. Defaults to `scope`.
+	ScopeClaim string
+
+	// Leeway allows for clock skew when checking `exp` and `nbf`.
+	Leeway time.Duration
+
+	// AllowMissingExpiration accepts tokens without an `exp` claim. By default
+	// they are rejected, since such tokens would otherwise be valid forever.
+	AllowMissingExpiration bool
+
+	// RefreshInterval is how often to refresh the JWKS. Defaults to one hour.
+	// Fetches are attempted at most once a minute, whether or not they
+	// succeed.
+	RefreshInterval time.Duration
+
+	// Client is used to fetch the JWKS. Defaults to `http.DefaultClient`.
+	Client *http.Client
+
+	// FetchTimeout limits how long fetching the JWKS may take. Fetches are
+	// shared by concurrent requests, so they don't use any one request's
+	// context. Defaults to 10 seconds.
+	FetchTimeout time.Duration
+
+	// SchemeName is the name of the security scheme in the OpenAPI. Defaults
+	// to `DefaultSchemeName`.
+	SchemeName string
+}
+
+// Claims are the verified claims from a token.
+type Claims map[string]any
+
+// Subject returns the `sub` claim.
+func (c Claims) Subject() string {
+	s, _ := c["sub"].(string)
+	return s
+}
+
+// Verifier verifies JWTs. Create one with `New`.
+type Verifier struct {
+	config Config
+
+	mu        sync.RWMutex
+	jwks      map[string]any
+	fetched   time.Time
+	attempted time.Time
+	fetch     *jwksFetch
+}
+
+// jwksFetch is a JWKS fetch shared by all callers which need it.
+type jwksFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// New creates a new token verifier.
+func New(config Config) *Verifier {
+	if config.JWKSURL == "" && len(config.Keys) == 0 {
+		panic("jwtauth: JWKSURL or Keys must be set")
+	}
+	if config.ScopeClaim == "" {
+		config.ScopeClaim = "scope"
+	}
+	if config.RefreshInterval == 0 {
+		config.RefreshInterval = time.Hour
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	if config.FetchTimeout == 0 {
+		config.FetchTimeout = 10 * time.Second
+	}
+	if config.SchemeName == "" {
+		config.SchemeName = DefaultSchemeName
+	}
+	return &Verifier{config: config}
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+var b64 = base64.RawURLEncoding
+
+// Verify checks the token's signature and standard claims, returning the
+// claims if the token is valid.
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+
+	sig, err := b64.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+
+	key, err := v.key(ctx, h.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(h.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := b64.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (v *Verifier) validate(claims Claims) error {
+	now := time.Now()
+
+	if exp, ok := claims["exp"].(float64); ok {
+		if now.After(time.Unix(int64(exp), 0).Add(v.config.Leeway)) {
+			return errors.New("token is expired")
+		}
+	} else if !v.config.AllowMissingExpiration {
+		return errors.New("token has no expiration")
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok {
+		if now.Add(v.config.Leeway).Before(time.Unix(int64(nbf), 0)) {
+			return errors.New("token is not valid yet")
+		}
+	}
+
+	if v.config.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.config.Issuer {
+			return errors.New("token has invalid issuer")
+		}
+	}
+
+	if v.config.Audience != "" {
+		found := false
+		switch aud := claims["aud"].(type) {
+		case string:
+			found = aud == v.config.Audience
+		case []any:
+			for _, a := range aud {
+				if a == v.config.Audience {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return errors.New("token has invalid audience")
+		}
+	}
+
+	return nil
+}
+
+// Scopes returns the scopes granted to the token.
+func (v *Verifier) Scopes(claims Claims) []string {
+	switch s := claims[v.config.ScopeClaim].(type) {
+	case string:
+		return strings.Fields(s)
+	case []any:
+		scopes := make([]string, 0, len(s))
+		for _, item := range s {
+			if str, ok := item.(string); ok {
+				scopes = append(scopes, str)
+			}
+		}
+		return scopes
+	}
+	return nil
+}
+
+func verifySignature(alg string, key any, signed string, sig []byte) error {
+	if alg == "EdDSA" {
+		k, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("key does not match token algorithm")
+		}
+		if !ed25519.Verify(k, []byte(signed), sig) {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	}
+
+	// Everything else is a family like `RS` plus a hash size like `256`. Note
+	// that the `none` algorithm is never accepted.
+	if len(alg) != 5 {
+		return fmt.Errorf("unsupported token algorithm %s", alg)
+	}
+	var hf crypto.Hash
+	switch alg[2:] {
+	case "256":
+		hf = crypto.SHA256
+	case "384":
+		hf = crypto.SHA384
+	case "512":
+		hf = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported token algorithm %s", alg)
+	}
+	h := hf.New()
+	h.Write([]byte(signed))
+
+	valid := false
+	switch alg[:2] {
+	case "HS":
+		k, ok := key.([]byte)
+		if !ok {
+			return errors.New("key does not match token algorithm")
+		}
+		mac := hmac.New(hf.New, k)
+		mac.Write([]byte(signed))
+		valid = hmac.Equal(mac.Sum(nil), sig)
+	case "RS":
+		k, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key does not match token algorithm")
+		}
+		valid = rsa.VerifyPKCS1v15(k, hf, h.Sum(nil), sig) == nil
+	case "PS":
+		k, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key does not match token algorithm")
+		}
+		valid = rsa.VerifyPSS(k, hf, h.Sum(nil), sig, nil) == nil
+	case "ES":
+		k, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("key does not match token algorithm")
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) == 2*size {
+			r := new(big.Int).SetBytes(sig[:size])
+			s := new(big.Int).SetBytes(sig[size:])
+			valid = ecdsa.Verify(k, h.Sum(nil), r, s)
+		}
+	default:
+		return fmt.Errorf("unsupported token algorithm %s", alg)
+	}
+
+	if !valid {
+		return errors.New("invalid token signature")
+	}
+	return nil
+}
+
+func curveFor(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	}
+	return nil, fmt.Errorf("unsupported curve %s", name)
+}
+
+// key finds the verification key for a key ID, fetching the JWKS if needed.
+func (v *Verifier) key(ctx context.Context, kid string) (any, error) {
+	if k, ok := v.config.Keys[kid]; ok {
+		return k, nil
+	}
+
+	if v.config.JWKSURL == "" {
+		return nil, errors.New("unknown token key")
+	}
+
+	v.mu.RLock()
+	k, ok := v.jwks[kid]
+	stale := time.Since(v.fetched) > v.config.RefreshInterval
+	v.mu.RUnlock()
+
+	// Refresh when stale, or when the key is unknown since keys may have been
+	// rotated. On failure, keep using any cached key.
+	if stale || !ok {
+		if err := v.refresh(ctx); err != nil && !ok {
+			return nil, err
+		}
+		v.mu.RLock()
+		k, ok = v.jwks[kid]
+		v.mu.RUnlock()
+	}
+
+	if !ok {
+		return nil, errors.New("unknown token key")
+	}
+	return k, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// refresh fetches the JWKS. Concurrent callers share a single fetch, and a
+// new fetch is only started if the last attempt was over a minute ago, so
+// unknown key IDs or an unavailable JWKS URL can't cause a flood of requests.
+// Otherwise the result of the last attempt is returned. The fetch runs in the
+// background, so callers which give up don't cancel it for everyone else.
+func (v *Verifier) refresh(ctx context.Context) error {
+	v.mu.Lock()
+	f := v.fetch
+	start := f == nil || (f.finished() && time.Since(v.attempted) > time.Minute)
+	if start {
+		f = &jwksFetch{done: make(chan struct{})}
+		v.fetch = f
+		v.attempted = time.Now()
+	}
+	v.mu.Unlock()
+
+	if start {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), v.config.FetchTimeout)
+			defer cancel()
+			f.err = v.fetchJWKS(ctx)
+			close(f.done)
+		}()
+	}
+
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *jwksFetch) finished() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (v *Verifier) fetchJWKS(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.config.JWKSURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.config.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("unable to parse JWKS: %w", err)
+	}
+
+	keys := map[string]any{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// Unsupported key types, including symmetric keys, are skipped.
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+
+	v.mu.Lock()
+	v.jwks = keys
+	v.fetched = time.Now()
+	v.mu.Unlock()
+	return nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		curve, err := curveFor(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+type contextKey struct{}
+
+type result struct {
+	claims Claims
+	err    error
+}
+
+// bearerToken returns the token from an `Authorization: Bearer ...` header.
+func bearerToken(authorization string) string {
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "bearer ") {
+		return strings.TrimSpace(authorization[7:])
+	}
+	return ""
+}
+
+// Middleware verifies bearer tokens for `net/http` compatible routers and
+// stores the result in the request context, making the claims available to
+// handlers via `ClaimsFrom`. It does not reject any requests, which is left
+// to the `Authenticator` based on each operation's security requirements.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := bearerToken(r.Header.Get("Authorization")); token != "" {
+			claims, err := v.Verify(r.Context(), token)
+			r = r.WithContext(context.WithValue(r.Context(), contextKey{}, &result{claims, err}))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ClaimsFrom returns the verified claims stored by `Middleware`, or nil if
+// the request had no valid token.
+func ClaimsFrom(ctx context.Context) Claims {
+	if r, ok := ctx.Value(contextKey{}).(*result); ok && r.err == nil {
+		return r.claims
+	}
+	return nil
+}
+
+// Authenticator returns a `huma.Authenticator` which requires a valid token
+// with all of the operation's required scopes for this verifier's security
+// scheme. Missing or invalid tokens result in a 401 while missing scopes
+// result in a 403. Results from `Middleware` are reused when available.
+func (v *Verifier) Authenticator() huma.Authenticator {
+	return func(ctx huma.Context, scheme string, scopes []string) error {
+		if scheme != v.config.SchemeName {
+			return fmt.Errorf("unsupported security scheme %s", scheme)
+		}
+
+		r, ok := ctx.Context().Value(contextKey{}).(*result)
+		if !ok {
+			token := bearerToken(ctx.Header("Authorization"))
+			if token == "" {
+				return errors.New("missing bearer token")
+			}
+			claims, err := v.Verify(ctx.Context(), token)
+			r = &result{claims, err}
+		}
+		if r.err != nil {
+			return r.err
+		}
+
+		granted := v.Scopes(r.claims)
+	outer:
+		for _, scope := range scopes {
+			for _, g := range granted {
+				if g == scope {
+					continue outer
+				}
+			}
+			return huma.Error403Forbidden("token is missing required scope " + scope)
+		}
+		return nil
+	}
+}
+
+// Configure documents the bearer token security scheme in the API config and
+// sets up the authenticator. If the config already has an authenticator, it
+// is still used for any other security schemes.
+func (v *Verifier) Configure(config *huma.Config) {
+	if config.OpenAPI == nil {
+		config.OpenAPI = &huma.OpenAPI{}
+	}
+	if config.Components == nil {
+		config.Components = &huma.Components{}
+	}
+	if config.Components.SecuritySchemes == nil {
+		config.Components.SecuritySchemes = map[string]*huma.SecurityScheme{}
+	}
+	config.Components.SecuritySchemes[v.config.SchemeName] = huma.NewBearerAuthScheme("JWT")
+
+	authenticate := v.Authenticator()
+	if existing := config.Authenticator; existing != nil {
+		config.Authenticator = func(ctx huma.Context, scheme string, scopes []string) error {
+			if scheme == v.config.SchemeName {
+				return authenticate(ctx, scheme, scopes)
+			}
+			return existing(ctx, scheme, scopes)
+		}
+	} else {
+		config.Authenticator = authenticate
+	}
+}
+
+// Require returns security requirements for an operation which needs a
+// valid token with all of the given scopes.
+func (v *Verifier) Require(scopes ...string) []map[string][]string {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return []map[string][]string{{v.config.SchemeName: scopes}}
+}
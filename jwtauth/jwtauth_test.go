@@ -0,0 +1,284 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func sign(t *testing.T, alg, kid string, key any, claims map[string]any) string {
+	h, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	signed := b64.EncodeToString(h) + "." + b64.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	var err error
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, digest[:])
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	}
+	assert.NoError(t, err)
+	return signed + "." + b64.EncodeToString(sig)
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	secret := []byte("secret")
+
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": "rsa",
+					"use": "sig",
+					"n":   b64.EncodeToString(rsaKey.N.Bytes()),
+					"e":   b64.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+				},
+				{
+					"kty": "EC",
+					"kid": "ec",
+					"crv": "P-256",
+					"x":   b64.EncodeToString(ecKey.X.Bytes()),
+					"y":   b64.EncodeToString(ecKey.Y.Bytes()),
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	v := New(Config{
+		JWKSURL:  server.URL,
+		Keys:     map[string]any{"hmac": secret},
+		Issuer:   "https://example.com/",
+		Audience: "test",
+	})
+
+	valid := map[string]any{
+		"iss": "https://example.com/",
+		"aud": []any{"other", "test"},
+		"sub": "user1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+
+	for _, item := range []struct {
+		name   string
+		token  string
+		errMsg string
+	}{
+		{"rsa", sign(t, "RS256", "rsa", rsaKey, valid), ""},
+		{"ec", sign(t, "ES256", "ec", ecKey, valid), ""},
+		{"hmac", sign(t, "HS256", "hmac", secret, valid), ""},
+		{"malformed", "abc.def", "malformed token"},
+		{"none", sign(t, "none", "rsa", secret, valid), "unsupported token algorithm none"},
+		{"alg-mismatch", sign(t, "HS256", "rsa", secret, valid), "key does not match token algorithm"},
+		{"unknown-key", sign(t, "RS256", "missing", rsaKey, valid), "unknown token key"},
+		{"bad-signature", sign(t, "RS256", "ec", rsaKey, valid), "key does not match token algorithm"},
+		{"expired", sign(t, "RS256", "rsa", rsaKey, map[string]any{"iss": "https://example.com/", "aud": "test", "exp": time.Now().Add(-time.Hour).Unix()}), "token is expired"},
+		{"no-expiration", sign(t, "RS256", "rsa", rsaKey, map[string]any{"iss": "https://example.com/", "aud": "test"}), "token has no expiration"},
+		{"not-yet-valid", sign(t, "RS256", "rsa", rsaKey, map[string]any{"iss": "https://example.com/", "aud": "test", "exp": valid["exp"], "nbf": time.Now().Add(time.Hour).Unix()}), "token is not valid yet"},
+		{"issuer", sign(t, "RS256", "rsa", rsaKey, map[string]any{"iss": "bad", "aud": "test", "exp": valid["exp"]}), "token has invalid issuer"},
+		{"audience", sign(t, "RS256", "rsa", rsaKey, map[string]any{"iss": "https://example.com/", "aud": "bad", "exp": valid["exp"]}), "token has invalid audience"},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			claims, err := v.Verify(context.Background(), item.token)
+			if item.errMsg != "" {
+				assert.EqualError(t, err, item.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "user1", claims.Subject())
+		})
+	}
+
+	// Keys are cached rather than fetched for every token.
+	assert.Equal(t, 1, fetches)
+
+	// Tampering with the claims invalidates the signature.
+	token := sign(t, "RS256", "rsa", rsaKey, valid)
+	other := sign(t, "RS256", "rsa", rsaKey, map[string]any{"sub": "admin"})
+	_, err = v.Verify(context.Background(), token[:len(token)-len(other)]+other)
+	assert.Error(t, err)
+}
+
+func TestAllowMissingExpiration(t *testing.T) {
+	secret := []byte("secret")
+	v := New(Config{Keys: map[string]any{"": secret}, AllowMissingExpiration: true})
+
+	claims, err := v.Verify(context.Background(), sign(t, "HS256", "", secret, map[string]any{"sub": "user1"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", claims.Subject())
+}
+
+func TestRefreshLimit(t *testing.T) {
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	v := New(Config{JWKSURL: server.URL})
+	token := sign(t, "HS256", "unknown", []byte("secret"), map[string]any{"sub": "user1"})
+
+	// Concurrent requests share a single fetch.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := v.Verify(context.Background(), token)
+			assert.EqualError(t, err, "unable to fetch JWKS: status 500")
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// Failed attempts aren't retried right away.
+	_, err := v.Verify(context.Background(), token)
+	assert.EqualError(t, err, "unable to fetch JWKS: status 500")
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestRefreshCanceled(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				{
+					"kty": "EC",
+					"kid": "ec",
+					"crv": "P-256",
+					"x":   b64.EncodeToString(ecKey.X.Bytes()),
+					"y":   b64.EncodeToString(ecKey.Y.Bytes()),
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	v := New(Config{JWKSURL: server.URL})
+	token := sign(t, "ES256", "ec", ecKey, map[string]any{
+		"sub": "user1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	// The first caller gives up, which must not fail the fetch for others.
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := v.Verify(ctx, token)
+		first <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	second := make(chan error)
+	go func() {
+		_, err := v.Verify(context.Background(), token)
+		second <- err
+	}()
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+	close(release)
+	assert.NoError(t, <-second)
+}
+
+func TestAuthenticator(t *testing.T) {
+	secret := []byte("secret")
+	v := New(Config{Keys: map[string]any{"": secret}, AllowMissingExpiration: true})
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	v.Configure(&config)
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-item",
+		Method:      http.MethodGet,
+		Path:        "/item",
+		Security:    v.Require("items:read"),
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	assert.Equal(t, "bearer", api.OpenAPI().Components.SecuritySchemes[DefaultSchemeName].Scheme)
+	assert.NotNil(t, api.OpenAPI().Paths["/item"].Get.Responses["401"])
+
+	resp := api.Get("/item")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Contains(t, resp.Body.String(), "missing bearer token")
+
+	resp = api.Get("/item", "Authorization: Bearer "+sign(t, "HS256", "", secret, map[string]any{"scope": "items:write"}))
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = api.Get("/item", "Authorization: Bearer "+sign(t, "HS256", "", secret, map[string]any{"scope": "items:read items:write"}))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+}
+
+func TestMiddleware(t *testing.T) {
+	secret := []byte("secret")
+	v := New(Config{Keys: map[string]any{"": secret}, ScopeClaim: "scp", AllowMissingExpiration: true})
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	v.Configure(&config)
+	r := chi.NewRouter()
+	r.Use(v.Middleware)
+	api := humatest.NewTestAPI(t, r, config)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-me",
+		Method:      http.MethodGet,
+		Path:        "/me",
+		Security:    v.Require(),
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: ClaimsFrom(ctx).Subject()}, nil
+	})
+
+	token := sign(t, "HS256", "", secret, map[string]any{"sub": "user1", "scp": []string{"a"}})
+	resp := api.Get("/me", "Authorization: Bearer "+token)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `"user1"`, resp.Body.String()[:7])
+
+	resp = api.Get("/me", "Authorization: Bearer invalid")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Contains(t, resp.Body.String(), "malformed token")
+}
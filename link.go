@@ -0,0 +1,56 @@
+package huma
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AddLink documents a link from the operation's response with the given
+// status to the target operation, e.g. from a newly created user to the
+// operation which gets it, so clients can navigate between operations. Pass
+// the same `Operation` used to register the target, so the link follows any
+// changes to its operation ID.
+//
+// Parameters map the target's parameter names to runtime expressions like
+// `$request.path.user-id` or `$response.body#/id`. Values which are not
+// expressions are JSON pointers into the response body, so `id` is short for
+// `$response.body#/id`.
+//
+//	huma.AddLink(&createUser, http.StatusCreated, "GetUser", getUser, map[string]string{
+//		"user-id": "id",
+//	})
+func AddLink(op *Operation, status int, name string, target Operation, parameters map[string]string) {
+	if target.OperationID == "" {
+		panic("link " + name + " target operation has no ID")
+	}
+
+	params := map[string]any{}
+	for param, value := range parameters {
+		if !strings.HasPrefix(value, "$") {
+			value = "$response.body#/" + strings.TrimPrefix(value, "/")
+		}
+		params[param] = value
+	}
+
+	if op.Responses == nil {
+		op.Responses = map[string]*Response{}
+	}
+	statusStr := strconv.Itoa(status)
+	resp := op.Responses[statusStr]
+	if resp == nil {
+		resp = &Response{}
+		op.Responses[statusStr] = resp
+	}
+	if resp.Description == "" {
+		resp.Description = http.StatusText(status)
+	}
+	if resp.Links == nil {
+		resp.Links = map[string]*Link{}
+	}
+	resp.Links[name] = &Link{
+		OperationID: target.OperationID,
+		Parameters:  params,
+		Description: target.Summary,
+	}
+}
@@ -0,0 +1,67 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type LinkUser struct {
+	ID    string `json:"id"`
+	Owner struct {
+		ID string `json:"id"`
+	} `json:"owner"`
+}
+
+func TestAddLink(t *testing.T) {
+	app := NewTestAdapter(chi.NewRouter(), DefaultConfig("Test API", "1.0.0"))
+
+	getUser := Operation{
+		OperationID: "get-user",
+		Method:      http.MethodGet,
+		Path:        "/users/{user-id}",
+		Summary:     "Get a user",
+	}
+	createUser := Operation{
+		OperationID:   "create-user",
+		Method:        http.MethodPost,
+		Path:          "/users",
+		DefaultStatus: http.StatusCreated,
+	}
+	AddLink(&createUser, http.StatusCreated, "GetUser", getUser, map[string]string{
+		"user-id": "id",
+	})
+	AddLink(&createUser, http.StatusCreated, "GetOwner", getUser, map[string]string{
+		"user-id": "/owner/id",
+	})
+	AddLink(&createUser, http.StatusCreated, "GetCaller", getUser, map[string]string{
+		"user-id": "$request.header.X-User",
+	})
+
+	Register(app, getUser, func(ctx context.Context, input *struct {
+		ID string `path:"user-id"`
+	}) (*struct{ Body LinkUser }, error) {
+		return nil, nil
+	})
+	Register(app, createUser, func(ctx context.Context, input *struct{}) (*struct{ Body LinkUser }, error) {
+		return nil, nil
+	})
+
+	resp := app.OpenAPI().Paths["/users"].Post.Responses["201"]
+	assert.Equal(t, "Created", resp.Description)
+	assert.NotNil(t, resp.Content["application/json"].Schema)
+	assert.Equal(t, &Link{
+		OperationID: "get-user",
+		Parameters:  map[string]any{"user-id": "$response.body#/id"},
+		Description: "Get a user",
+	}, resp.Links["GetUser"])
+	assert.Equal(t, "$response.body#/owner/id", resp.Links["GetOwner"].Parameters["user-id"])
+	assert.Equal(t, "$request.header.X-User", resp.Links["GetCaller"].Parameters["user-id"])
+
+	assert.Panics(t, func() {
+		AddLink(&createUser, http.StatusCreated, "Bad", Operation{}, nil)
+	})
+}
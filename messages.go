@@ -0,0 +1,205 @@
+package huma
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MessageCatalog maps validation message IDs to `fmt` templates for one
+// language. Templates are given the message's parameters in order, e.g. the
+// `minimum` message gets the minimum value. IDs missing from a catalog use the
+// English message from `DefaultMessages`.
+type MessageCatalog map[string]string
+
+// DefaultMessages are the English validation messages, keyed by message ID.
+// They are a complete list of the IDs which can be translated.
+var DefaultMessages = MessageCatalog{
+	"expectedBoolean":       "expected boolean",
+	"expectedNumber":        "expected number",
+	"expectedInteger":       "expected integer",
+	"expectedString":        "expected string",
+	"expectedArray":         "expected array",
+	"expectedObject":        "expected object",
+	"integerRange":          "expected integer to fit in %v",
+	"enum":                  "expected value to be one of \"%v\"",
+	"const":                 "expected value to be \"%v\"",
+	"minimum":               "expected number >= %v",
+	"exclusiveMinimum":      "expected number > %v",
+	"maximum":               "expected number <= %v",
+	"exclusiveMaximum":      "expected number < %v",
+	"multipleOf":            "expected number to be a multiple of %v",
+	"minLength":             "expected length >= %v",
+	"maxLength":             "expected length <= %v",
+	"pattern":               "expected string to match pattern %v",
+	"base64":                "expected string to be base64 encoded",
+	"minItems":              "expected array length >= %v",
+	"maxItems":              "expected array length <= %v",
+	"uniqueItems":           "expected array items to be unique",
+	"minProperties":         "expected object with at least %v properties",
+	"maxProperties":         "expected object with at most %v properties",
+	"required":              "expected required property %v to be present",
+	"dependentRequired":     "expected property %v to be present when %v is present",
+	"eqField":               "expected value to equal %v",
+	"neField":               "expected value to not equal %v",
+	"gtField":               "expected value > %v",
+	"gteField":              "expected value >= %v",
+	"ltField":               "expected value < %v",
+	"lteField":              "expected value <= %v",
+	"unexpectedProperty":    "unexpected property",
+	"writeOnly":             "write only property is non-zero",
+	"date-time":             "expected string to be RFC 3339 date-time",
+	"date":                  "expected string to be RFC 3339 date",
+	"time":                  "expected string to be RFC 3339 time",
+	"duration":              "expected string to be ISO 8601 duration",
+	"email":                 "expected string to be RFC 5322 email",
+	"hostname":              "expected string to be RFC 5890 hostname",
+	"ipv4":                  "expected string to be RFC 2673 ipv4",
+	"ipv6":                  "expected string to be RFC 2373 ipv6",
+	"uri":                   "expected string to be RFC 3986 uri",
+	"uuid":                  "expected string to be RFC 4122 uuid",
+	"uri-template":          "expected string to be RFC 6570 uri-template",
+	"json-pointer":          "expected string to be RFC 6901 json-pointer",
+	"relative-json-pointer": "expected string to be RFC 6901 relative-json-pointer",
+	"regex":                 "expected string to be regex",
+	"invalidInteger":        "invalid integer",
+	"invalidFloat":          "invalid float",
+	"invalidBoolean":        "invalid boolean",
+	"invalidTime":           "invalid time",
+	"invalidDuration":       "invalid duration",
+	"invalidValue":          "invalid value",
+	"requiredPathParam":     "required path parameter is missing",
+}
+
+// MessageCatalogs are the available languages for validation messages, keyed
+// by language tag like `en` or `de`. Validation errors are translated into
+// the best match for a request's `Accept-Language` header, if any, e.g.
+//
+//	huma.MessageCatalogs["de"] = huma.MessageCatalog{
+//		"minimum": "Zahl >= %v erwartet",
+//	}
+var MessageCatalogs = map[string]MessageCatalog{
+	"en": DefaultMessages,
+}
+
+// DefaultLanguage is the language of the precomputed schema messages, which
+// is used when a request's `Accept-Language` doesn't match any catalog and
+// the API has no `Config.Language`. Set it before creating schemas.
+var DefaultLanguage = "en"
+
+// messageKey identifies a message so it can be rendered in another language.
+type messageKey struct {
+	id     string
+	params []any
+}
+
+// messageIndex maps messages rendered in the default language back to their
+// keys, so validation errors can be translated at request time without
+// tracking message IDs on the happy path. Only messages whose parameters come
+// from schemas are indexed, so the index is bounded.
+var messageIndex sync.Map
+
+// message renders the message in the default language and indexes it for
+// translation.
+func message(id string, params ...any) string {
+	msg := renderMessage(DefaultLanguage, id, params)
+	if _, ok := messageIndex.Load(msg); !ok {
+		messageIndex.Store(msg, messageKey{id, params})
+	}
+	return msg
+}
+
+func renderMessage(lang, id string, params []any) string {
+	tmpl, ok := MessageCatalogs[lang][id]
+	if !ok {
+		tmpl = DefaultMessages[id]
+	}
+	if len(params) == 0 {
+		return tmpl
+	}
+	return fmt.Sprintf(tmpl, params...)
+}
+
+// translateMessage renders an indexed message in the given language. Details
+// appended after a `: `, like parse errors, are kept as-is. Other messages
+// are returned unchanged.
+func translateMessage(msg, lang string) string {
+	if key, ok := messageIndex.Load(msg); ok {
+		k := key.(messageKey)
+		return renderMessage(lang, k.id, k.params)
+	}
+	if prefix, detail, ok := strings.Cut(msg, ": "); ok {
+		if key, ok := messageIndex.Load(prefix); ok {
+			k := key.(messageKey)
+			return renderMessage(lang, k.id, k.params) + ": " + detail
+		}
+	}
+	return msg
+}
+
+// translateErrors translates validation error messages into the language
+// best matching the `Accept-Language` header, falling back to the API's
+// language `lang`, if it's not the default.
+func translateErrors(lang, acceptLanguage string, errs []error) {
+	if acceptLanguage != "" && len(MessageCatalogs) > 1 {
+		if selected := selectLanguage(acceptLanguage); selected != "" {
+			lang = selected
+		}
+	}
+	if lang == "" || lang == DefaultLanguage {
+		return
+	}
+	for _, err := range errs {
+		if detail, ok := err.(*ErrorDetail); ok {
+			detail.Message = translateMessage(detail.Message, lang)
+		}
+	}
+}
+
+// selectLanguage returns the message catalog best matching an
+// `Accept-Language` header, falling back from tags like `de-AT` to `de`. It
+// returns an empty string if nothing matches.
+func selectLanguage(header string) string {
+	best := ""
+	bestQ := 0.0
+	for _, entry := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(entry, ";")
+		tag = strings.TrimSpace(tag)
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, _ = strconv.ParseFloat(v, 64)
+		}
+		if q <= bestQ {
+			continue
+		}
+
+		for tag != "" {
+			if name := catalogName(tag); name != "" {
+				best, bestQ = name, q
+				break
+			}
+			i := strings.LastIndexByte(tag, '-')
+			if i == -1 {
+				break
+			}
+			tag = tag[:i]
+		}
+	}
+	return best
+}
+
+// catalogName returns the name of the catalog for a language tag, ignoring
+// case, or an empty string if there is none.
+func catalogName(tag string) string {
+	if _, ok := MessageCatalogs[tag]; ok {
+		return tag
+	}
+	for name := range MessageCatalogs {
+		if strings.EqualFold(name, tag) {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,164 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectLanguage(t *testing.T) {
+	MessageCatalogs["de"] = MessageCatalog{}
+	MessageCatalogs["pt-BR"] = MessageCatalog{}
+	defer delete(MessageCatalogs, "de")
+	defer delete(MessageCatalogs, "pt-BR")
+
+	for header, expected := range map[string]string{
+		"":                        "",
+		"fr":                      "",
+		"de":                      "de",
+		"de-AT":                   "de",
+		"DE-at":                   "de",
+		"pt-br":                   "pt-BR",
+		"pt":                      "",
+		"fr, de;q=0.5":            "de",
+		"de;q=0.5, en;q=0.8":      "en",
+		"en-US,en;q=0.9,de;q=1":   "en",
+		"*, de;q=0.1":             "de",
+		"de;q=0, en;q=0.1, fr-CA": "en",
+	} {
+		assert.Equal(t, expected, selectLanguage(header), header)
+	}
+}
+
+func TestLocalizedMessages(t *testing.T) {
+	MessageCatalogs["de"] = MessageCatalog{
+		"minimum":         "Zahl >= %v erwartet",
+		"required":        "Pflichtfeld %v fehlt",
+		"uuid":            "UUID erwartet",
+		"invalidInteger":  "ungültige Ganzzahl",
+		"expectedInteger": "Ganzzahl erwartet",
+	}
+	defer delete(MessageCatalogs, "de")
+
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Register(app, Operation{
+		OperationID: "create",
+		Method:      http.MethodPost,
+		Path:        "/items",
+	}, func(ctx context.Context, input *struct {
+		Limit int `query:"limit"`
+		Body  struct {
+			ID    string `json:"id" format:"uuid"`
+			Name  string `json:"name" pattern:"^[a-z]+$"`
+			Count int    `json:"count" minimum:"10"`
+			Size  int    `json:"size,omitempty"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	messages := func(lang string) []string {
+		req, _ := http.NewRequest(http.MethodPost, "/items?limit=abc", strings.NewReader(`{"id": "nope", "name": "A", "count": 1, "size": 1.5}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", lang)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+		var model ErrorModel
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &model))
+		msgs := []string{}
+		for _, e := range model.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return msgs
+	}
+
+	en := messages("fr, en;q=0.5")
+	assert.Contains(t, en, "invalid integer")
+	assert.Contains(t, en, "expected number >= 10")
+	assert.Contains(t, en, "expected integer")
+	assert.Contains(t, en, "expected string to match pattern ^[a-z]+$")
+
+	de := messages("de-DE")
+	assert.Contains(t, de, "ungültige Ganzzahl")
+	assert.Contains(t, de, "Zahl >= 10 erwartet")
+	assert.Contains(t, de, "Ganzzahl erwartet")
+	// Messages without a translation use English, and details are kept.
+	assert.Contains(t, de, "expected string to match pattern ^[a-z]+$")
+	for _, msg := range de {
+		if strings.HasPrefix(msg, "UUID") {
+			assert.Equal(t, "UUID erwartet: invalid UUID length: 4", msg)
+		}
+	}
+	assert.Len(t, de, len(en))
+}
+
+func TestConfigLanguage(t *testing.T) {
+	MessageCatalogs["de"] = MessageCatalog{"minimum": "Zahl >= %v erwartet"}
+	defer delete(MessageCatalogs, "de")
+
+	assert.Panics(t, func() {
+		config := DefaultConfig("Test API", "1.0.0")
+		config.Language = "xx"
+		NewTestAdapter(chi.NewRouter(), config)
+	})
+
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Language = "de"
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "list",
+		Method:      http.MethodGet,
+		Path:        "/items",
+	}, func(ctx context.Context, input *struct {
+		Limit int `query:"limit" minimum:"5"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	for lang, expected := range map[string]string{
+		"":   "Zahl >= 5 erwartet",
+		"de": "Zahl >= 5 erwartet",
+		"en": "expected number >= 5",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/items?limit=1", nil)
+		req.Header.Set("Accept-Language", lang)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		var model ErrorModel
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &model))
+		assert.Equal(t, expected, model.Errors[0].Message, lang)
+	}
+
+	// The language only applies to the API created with the config.
+	r = chi.NewRouter()
+	app = NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+	Register(app, Operation{
+		OperationID: "list",
+		Method:      http.MethodGet,
+		Path:        "/items",
+	}, func(ctx context.Context, input *struct {
+		Limit int `query:"limit" minimum:"5"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/items?limit=1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var model ErrorModel
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &model))
+	assert.Equal(t, "expected number >= 5", model.Errors[0].Message)
+}
@@ -0,0 +1,115 @@
+package huma
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/exp/slices"
+)
+
+// allowMethods are routed to `allowAdapter` for every path, so it can answer
+// requests for the methods without an operation. Requests using other
+// methods, like `HEAD`, are left to the router.
+var allowMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// allowPath tracks the operations registered at a single path.
+type allowPath struct {
+	methods  []string
+	allow    string
+	ops      map[string]*Operation
+	handlers map[string]func(Context)
+}
+
+// allowAdapter answers `OPTIONS` requests with an `Allow` header listing the
+// methods of the operations registered at each path, and requests for other
+// methods with a `405 Method Not Allowed` error, consistently for every
+// router. See `Config.AutoAllow`.
+//
+// Routers only see a handler for each of the `allowMethods` at each path,
+// which dispatches to the operation's handler, so operations can be added to
+// a path at any time without conflicting with the automatic responses.
+type allowAdapter struct {
+	Adapter
+	api   API
+	paths map[string]*allowPath
+}
+
+func newAllowAdapter(a Adapter) *allowAdapter {
+	return &allowAdapter{
+		Adapter: a,
+		paths:   map[string]*allowPath{},
+	}
+}
+
+func (a *allowAdapter) Handle(op *Operation, handler func(Context)) {
+	p := a.paths[op.Path]
+	if p == nil {
+		p = &allowPath{
+			ops:      map[string]*Operation{},
+			handlers: map[string]func(Context){},
+		}
+		a.paths[op.Path] = p
+		for _, method := range allowMethods {
+			method := method
+			a.Adapter.Handle(&Operation{
+				Method:   method,
+				Path:     op.Path,
+				Security: []map[string][]string{},
+			}, func(ctx Context) {
+				a.dispatch(ctx, p, method)
+			})
+		}
+	}
+
+	if p.handlers[op.Method] == nil {
+		p.methods = append(p.methods, op.Method)
+		p.allow = joinUnique(append([]string{http.MethodOptions}, p.methods...))
+	}
+	// Like most routers, the last handler registered for a method is used.
+	p.ops[op.Method] = op
+	p.handlers[op.Method] = handler
+	if !slices.Contains(allowMethods, op.Method) {
+		a.Adapter.Handle(op, handler)
+	}
+}
+
+func (a *allowAdapter) dispatch(ctx Context, p *allowPath, method string) {
+	if handler := p.handlers[method]; handler != nil {
+		handler(&allowContext{humaContext: ctx, op: p.ops[method]})
+		return
+	}
+
+	ctx.SetHeader("Allow", p.allow)
+	if method == http.MethodOptions {
+		ctx.SetStatus(http.StatusNoContent)
+		return
+	}
+	WriteErr(a.api, ctx, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", method))
+}
+
+// allowContext provides the operation which handles the request, rather than
+// the one the router was given.
+type allowContext struct {
+	humaContext
+	op *Operation
+}
+
+// Unwrap returns the wrapped context, see `UnwrapContext`.
+func (c *allowContext) Unwrap() Context {
+	return c.humaContext
+}
+
+func (c *allowContext) Operation() *Operation {
+	return c.op
+}
+
+func (c *allowContext) Flush() error {
+	return Flush(c.humaContext)
+}
@@ -0,0 +1,126 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoAllow(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.AutoAllow = true
+	app := NewTestAdapter(r, config)
+
+	type Output struct {
+		Body string
+	}
+
+	Register(app, Operation{
+		OperationID: "get-item",
+		Method:      http.MethodGet,
+		Path:        "/items/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*Output, error) {
+		return &Output{Body: "get " + input.ID}, nil
+	})
+	Register(app, Operation{
+		OperationID: "put-item",
+		Method:      http.MethodPut,
+		Path:        "/items/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*Output, error) {
+		return &Output{Body: "put " + input.ID}, nil
+	})
+
+	// Handlers see their own operation.
+	var op *Operation
+	Register(app, Operation{
+		OperationID: "list-items",
+		Method:      http.MethodGet,
+		Path:        "/items",
+		Middlewares: []Middleware{
+			func(ctx Context, next func(Context)) {
+				op = ctx.Operation()
+				next(ctx)
+			},
+		},
+	}, func(ctx context.Context, input *struct{}) (*Output, error) {
+		return &Output{Body: "list"}, nil
+	})
+
+	for _, item := range []struct {
+		method string
+		path   string
+		status int
+		allow  string
+		body   string
+	}{
+		{http.MethodGet, "/items/123", http.StatusOK, "", `"get 123"`},
+		{http.MethodPut, "/items/123", http.StatusOK, "", `"put 123"`},
+		{http.MethodOptions, "/items/123", http.StatusNoContent, "GET, OPTIONS, PUT", ""},
+		{http.MethodDelete, "/items/123", http.StatusMethodNotAllowed, "GET, OPTIONS, PUT", "method DELETE not allowed"},
+		{http.MethodPost, "/items", http.StatusMethodNotAllowed, "GET, OPTIONS", "method POST not allowed"},
+	} {
+		t.Run(item.method+" "+item.path, func(t *testing.T) {
+			req, _ := http.NewRequest(item.method, item.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, item.status, w.Code, w.Body.String())
+			assert.Equal(t, item.allow, w.Header().Get("Allow"))
+			if item.status == http.StatusMethodNotAllowed {
+				assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			}
+			if item.body != "" {
+				assert.Contains(t, strings.TrimSpace(w.Body.String()), item.body)
+			}
+		})
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if assert.NotNil(t, op) {
+		assert.Equal(t, "list-items", op.OperationID)
+	}
+}
+
+func TestAutoAllowCORS(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.AutoAllow = true
+	config.CORS = &CORSConfig{AllowOrigins: []string{"*"}}
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "delete-item",
+		Method:      http.MethodDelete,
+		Path:        "/items/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	// Preflight requests are still handled by CORS.
+	req, _ := http.NewRequest(http.MethodOptions, "/items/123", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "DELETE", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "DELETE, OPTIONS", w.Header().Get("Allow"))
+
+	req, _ = http.NewRequest(http.MethodGet, "/items/123", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "DELETE, OPTIONS", w.Header().Get("Allow"))
+}
@@ -0,0 +1,69 @@
+package huma
+
+import "context"
+
+// Middleware wraps the handling of a single operation. It is called with the
+// request context and must call `next` to continue processing the request,
+// optionally with a wrapped context, or write a response itself to stop.
+// Middleware is router-agnostic and works with any adapter.
+//
+//	func RateLimit(ctx huma.Context, next func(huma.Context)) {
+//		if !limiter.Allow() {
+//			ctx.SetStatus(http.StatusTooManyRequests)
+//			return
+//		}
+//		next(ctx)
+//	}
+type Middleware func(ctx Context, next func(Context))
+
+// chainMiddlewares wraps the handler so that middlewares run in order, with
+// the first middleware being the outermost.
+func chainMiddlewares(middlewares []Middleware, handler func(Context)) func(Context) {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		mw := middlewares[i]
+		next := handler
+		handler = func(ctx Context) {
+			mw(ctx, next)
+		}
+	}
+	return handler
+}
+
+// WithContext returns a context wrapping `ctx` which uses `override` as its
+// `context.Context`, e.g. to add request-scoped values or a cancellation in
+// middleware. Pass the returned context to `next` so resolvers and the
+// operation handler see the new `context.Context`. All other methods use the
+// wrapped context.
+func WithContext(ctx Context, override context.Context) Context {
+	return &subContext{humaContext: ctx, override: override}
+}
+
+// WithValue returns a context wrapping `ctx` whose `context.Context` has the
+// key set to the value, see `WithContext`.
+//
+//	func Auth(ctx huma.Context, next func(huma.Context)) {
+//		user := lookupUser(ctx.Header("Authorization"))
+//		next(huma.WithValue(ctx, userKey, user))
+//	}
+func WithValue(ctx Context, key, value any) Context {
+	return WithContext(ctx, context.WithValue(ctx.Context(), key, value))
+}
+
+// subContext overrides the `context.Context` of the context it wraps.
+type subContext struct {
+	humaContext
+	override context.Context
+}
+
+// Unwrap returns the wrapped context, see `UnwrapContext`.
+func (c *subContext) Unwrap() Context {
+	return c.humaContext
+}
+
+func (c *subContext) Context() context.Context {
+	return c.override
+}
+
+func (c *subContext) Flush() error {
+	return Flush(c.humaContext)
+}
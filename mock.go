@@ -0,0 +1,320 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// mockFormats are the generated values of string formats.
+var mockFormats = map[string]string{
+	"date-time":             "2006-01-02T15:04:05Z",
+	"date":                  "2006-01-02",
+	"time":                  "15:04:05Z",
+	"duration":              "PT1H",
+	"email":                 "user@example.com",
+	"idn-email":             "user@example.com",
+	"hostname":              "example.com",
+	"idn-hostname":          "example.com",
+	"ipv4":                  "192.0.2.1",
+	"ipv6":                  "2001:db8::1",
+	"uri":                   "https://example.com/",
+	"uri-reference":         "https://example.com/",
+	"iri":                   "https://example.com/",
+	"iri-reference":         "https://example.com/",
+	"uri-template":          "https://example.com/{id}",
+	"uuid":                  "3e4666bf-d5e5-4aa7-b8ce-cefe41c7568a",
+	"json-pointer":          "/id",
+	"relative-json-pointer": "0/id",
+	"regex":                 ".*",
+	"byte":                  "c3RyaW5n",
+}
+
+// MockValue generates a value which is valid for the schema, e.g. to mock
+// responses before handlers are implemented. The schema's const, first
+// example, default or first enum value is used if set, otherwise a value is
+// generated which honors the schema's type, format, length & range. Read-only
+// properties are left out of values written to the server and write-only
+// properties out of values read from the server.
+func MockValue(r Registry, s *Schema, mode ValidateMode) any {
+	return mockValue(r, s, mode, map[*Schema]bool{})
+}
+
+func mockValue(r Registry, s *Schema, mode ValidateMode, visiting map[*Schema]bool) any {
+	for s != nil && s.Ref != "" {
+		s = r.SchemaFromRef(s.Ref)
+	}
+	if s == nil || visiting[s] {
+		// Recursive schemas end with a null value.
+		return nil
+	}
+	visiting[s] = true
+	defer delete(visiting, s)
+
+	switch {
+	case s.Const != nil:
+		return s.Const
+	case len(s.Examples) > 0:
+		return s.Examples[0]
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	}
+
+	switch s.Type {
+	case TypeBoolean:
+		return true
+	case TypeInteger:
+		return int(mockNumber(s, true))
+	case TypeNumber:
+		return mockNumber(s, false)
+	case TypeString:
+		return mockString(s)
+	case TypeArray:
+		count := 1
+		if s.MinItems != nil && *s.MinItems > count {
+			count = *s.MinItems
+		}
+		if s.MaxItems != nil && *s.MaxItems < count {
+			count = *s.MaxItems
+		}
+		items := make([]any, 0, count)
+		for i := 0; i < count; i++ {
+			items = append(items, mockValue(r, s.Items, mode, visiting))
+		}
+		return items
+	case TypeObject:
+		obj := map[string]any{}
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop := s.Properties[name]
+			for prop != nil && prop.Ref != "" {
+				prop = r.SchemaFromRef(prop.Ref)
+			}
+			if prop == nil || (mode == ModeWriteToServer && prop.ReadOnly) || (mode == ModeReadFromServer && prop.WriteOnly) {
+				continue
+			}
+			v := mockValue(r, prop, mode, visiting)
+			if v == nil && !prop.Nullable && !slices.Contains(s.Required, name) {
+				// Leave out optional recursive properties.
+				continue
+			}
+			obj[name] = v
+		}
+		if addl, ok := s.AdditionalProperties.(*Schema); ok {
+			for i := len(obj); s.MinProperties != nil && i < *s.MinProperties; i++ {
+				obj["key"+strconv.Itoa(i)] = mockValue(r, addl, mode, visiting)
+			}
+		}
+		return obj
+	}
+	return nil
+}
+
+// mockNumber returns the number closest to zero within the schema's range.
+func mockNumber(s *Schema, integer bool) float64 {
+	v := 0.0
+	if s.Minimum != nil && v < *s.Minimum {
+		v = *s.Minimum
+	}
+	if s.ExclusiveMinimum != nil && v <= *s.ExclusiveMinimum {
+		v = *s.ExclusiveMinimum + 1
+	}
+	if s.Maximum != nil && v > *s.Maximum {
+		v = *s.Maximum
+	}
+	if s.ExclusiveMaximum != nil && v >= *s.ExclusiveMaximum {
+		v = *s.ExclusiveMaximum - 1
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		m := *s.MultipleOf
+		if up := math.Ceil(v/m) * m; (s.Maximum == nil || up <= *s.Maximum) && (s.ExclusiveMaximum == nil || up < *s.ExclusiveMaximum) {
+			v = up
+		} else {
+			v = math.Floor(v/m) * m
+		}
+	}
+	if integer {
+		v = math.Ceil(v)
+	}
+	return v
+}
+
+// mockString returns a string for the schema's format, padded or truncated
+// to fit its length.
+func mockString(s *Schema) string {
+	if v, ok := mockFormats[s.Format]; ok {
+		return v
+	}
+	v := "string"
+	if s.MinLength != nil && len(v) < *s.MinLength {
+		v += strings.Repeat("x", *s.MinLength-len(v))
+	}
+	if s.MaxLength != nil && len(v) > *s.MaxLength {
+		v = v[:*s.MaxLength]
+	}
+	return v
+}
+
+// mockResponse returns the status & response to mock for the operation,
+// which is the first declared success response.
+func mockResponse(op *Operation) (int, *Response) {
+	status := declaredStatus(op, true)
+	if status == 0 {
+		return http.StatusNoContent, nil
+	}
+	return status, op.Responses[strconv.Itoa(status)]
+}
+
+// mockBody returns the example or generated body of the response, if it has
+// a JSON body.
+func mockBody(r Registry, resp *Response) (any, bool) {
+	if resp == nil {
+		return nil, false
+	}
+	types := make([]string, 0, len(resp.Content))
+	for ct := range resp.Content {
+		types = append(types, ct)
+	}
+	sort.Strings(types)
+	for _, ct := range types {
+		mt := resp.Content[ct]
+		if mt == nil || !strings.HasSuffix(ct, "json") {
+			continue
+		}
+		if mt.Example != nil {
+			return mt.Example, true
+		}
+		names := make([]string, 0, len(mt.Examples))
+		for name := range mt.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if e := mt.Examples[name]; e != nil && e.Value != nil {
+				return e.Value, true
+			}
+		}
+		if mt.Schema != nil {
+			return MockValue(r, mt.Schema, ModeReadFromServer), true
+		}
+	}
+	return nil, false
+}
+
+// Mock serves mock responses for every operation in the API's OpenAPI
+// document which doesn't have a handler yet, e.g. operations declared in a
+// document loaded via `LoadOpenAPI`. This lets clients be built against the
+// API before it is implemented. Call it after registering the implemented
+// operations:
+//
+//	huma.Implement(api, "get-greeting", handler)
+//	huma.Mock(api)
+//
+// Each mock responds with the operation's first success response, using its
+// example if there is one or a value generated by `MockValue` otherwise.
+// Requests are not validated.
+func Mock(api API) {
+	oapi := api.OpenAPI()
+	paths := make([]string, 0, len(oapi.Paths))
+	for path := range oapi.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, op := range oapi.Paths[path].operations() {
+			if op.inputType != nil {
+				// Already implemented.
+				continue
+			}
+			op := op
+			api.Adapter().Handle(op, func(ctx Context) {
+				status, resp := mockResponse(op)
+				if resp != nil {
+					for name, h := range resp.Headers {
+						if h != nil && h.Schema != nil {
+							if v := MockValue(oapi.Components.Schemas, h.Schema, ModeReadFromServer); v != nil {
+								ctx.SetHeader(name, formatMockHeader(v))
+							}
+						}
+					}
+				}
+				body, ok := mockBody(oapi.Components.Schemas, resp)
+				if !ok {
+					ctx.SetStatus(status)
+					return
+				}
+				ct, err := api.Negotiate(ctx.Header("Accept"))
+				if err != nil {
+					WriteErr(api, ctx, http.StatusNotAcceptable, "unable to marshal response", err)
+					return
+				}
+				ctx.SetHeader("Content-Type", ct)
+				marshalWithStatus(api, ctx, status, strconv.Itoa(status), ct, body)
+			})
+		}
+	}
+}
+
+// formatMockHeader formats a generated header value, joining lists with
+// commas.
+func formatMockHeader(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if items, ok := v.([]any); ok {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = formatMockHeader(item)
+		}
+		return strings.Join(parts, ",")
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// RegisterMock registers an operation like `Register` with a handler which
+// responds with generated output until the real handler exists. The output's
+// body is set using `MockValue` with the body's schema, while its headers are
+// left empty.
+//
+//	huma.RegisterMock[GetThingInput, GetThingOutput](api, huma.Operation{
+//		OperationID: "get-thing",
+//		Method:      http.MethodGet,
+//		Path:        "/things/{id}",
+//	})
+func RegisterMock[I, O any](api API, op Operation) {
+	registry := api.OpenAPI().Components.Schemas
+	outputType := reflect.TypeOf((*O)(nil)).Elem()
+
+	// The body's schema is looked up once registered, which creates it.
+	var bodySchema *Schema
+	Register(api, op, func(ctx context.Context, input *I) (*O, error) {
+		out := new(O)
+		if bodySchema != nil {
+			b, err := json.Marshal(MockValue(registry, bodySchema, ModeReadFromServer))
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(b, reflect.ValueOf(out).Elem().FieldByName("Body").Addr().Interface()); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	})
+	if f, ok := outputType.FieldByName("Body"); ok && f.Type.Kind() != reflect.Func {
+		bodySchema = registry.Schema(f.Type, true, getHint(outputType, f.Name, op.OperationID+"Response"))
+	}
+}
@@ -0,0 +1,168 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockThing struct {
+	ID       string     `json:"id" format:"uuid" readOnly:"true"`
+	Name     string     `json:"name" minLength:"8" maxLength:"10"`
+	Kind     string     `json:"kind" enum:"small,large"`
+	Count    int        `json:"count" minimum:"5" multipleOf:"3"`
+	Score    float64    `json:"score" exclusiveMaximum:"-1"`
+	Tags     []string   `json:"tags" minItems:"2" maxItems:"3"`
+	Created  time.Time  `json:"created"`
+	Email    string     `json:"email" format:"email"`
+	Password string     `json:"password,omitempty" writeOnly:"true"`
+	Note     string     `json:"note,omitempty" example:"Hello"`
+	Parent   *MockThing `json:"parent,omitempty"`
+}
+
+func TestMockValue(t *testing.T) {
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	s := registry.Schema(reflect.TypeOf(MockThing{}), true, "")
+
+	for _, mode := range []ValidateMode{ModeReadFromServer, ModeWriteToServer} {
+		v := MockValue(registry, s, mode)
+		pb := NewPathBuffer([]byte{}, 0)
+		res := &ValidateResult{}
+		Validate(registry, s, pb, mode, v, res)
+		assert.Empty(t, res.Errors, mode)
+
+		obj := v.(map[string]any)
+		assert.Equal(t, "stringxx", obj["name"])
+		assert.Equal(t, "small", obj["kind"])
+		assert.Equal(t, 6, obj["count"])
+		assert.Equal(t, -2.0, obj["score"])
+		assert.Equal(t, []any{"string", "string"}, obj["tags"])
+		assert.Equal(t, "2006-01-02T15:04:05Z", obj["created"])
+		assert.Equal(t, "user@example.com", obj["email"])
+		assert.Equal(t, "Hello", obj["note"])
+		assert.NotContains(t, obj, "parent")
+		if mode == ModeReadFromServer {
+			assert.Equal(t, "3e4666bf-d5e5-4aa7-b8ce-cefe41c7568a", obj["id"])
+			assert.NotContains(t, obj, "password")
+		} else {
+			assert.NotContains(t, obj, "id")
+			assert.Contains(t, obj, "password")
+		}
+	}
+}
+
+func TestMock(t *testing.T) {
+	oapi, err := LoadOpenAPI([]byte(`
+openapi: 3.1.0
+info:
+  title: Mocks
+  version: 1.0.0
+paths:
+  /things:
+    get:
+      operationId: list-things
+      responses:
+        "200":
+          description: OK
+          headers:
+            X-Total:
+              schema:
+                type: integer
+                minimum: 1
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Thing"
+    post:
+      operationId: create-thing
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              example:
+                id: example
+        "400":
+          description: Bad Request
+    delete:
+      operationId: delete-things
+      responses:
+        "204":
+          description: No Content
+components:
+  schemas:
+    Thing:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: string
+          format: uuid
+        size:
+          type: string
+          enum: [small, large]
+`))
+	assert.NoError(t, err)
+	config := DefaultConfig(oapi.Info.Title, oapi.Info.Version)
+	config.OpenAPI = oapi
+	r := chi.NewRouter()
+	api := NewTestAdapter(r, config)
+
+	Implement(api, "delete-things", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+	Mock(api)
+
+	req, _ := http.NewRequest(http.MethodGet, "/things", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-Total"))
+	assert.JSONEq(t, `[{"id": "3e4666bf-d5e5-4aa7-b8ce-cefe41c7568a", "size": "small"}]`, w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodPost, "/things", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"id": "example"}`, w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodDelete, "/things", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRegisterMock(t *testing.T) {
+	r := chi.NewRouter()
+	api := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	RegisterMock[struct {
+		ID string `path:"id"`
+	}, struct {
+		ETag string `header:"ETag"`
+		Body MockThing
+	}](api, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/things/abc", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var thing MockThing
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &thing))
+	assert.Equal(t, "stringxx", thing.Name)
+	assert.Equal(t, 6, thing.Count)
+	assert.ElementsMatch(t, []string{"ErrorDetail", "ErrorModel", "MockThing"}, keys(api.OpenAPI().Components.Schemas.Map()))
+}
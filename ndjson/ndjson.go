@@ -0,0 +1,147 @@
+// Package ndjson provides utilities for streaming newline delimited JSON
+// (NDJSON, also known as JSON Lines) responses, where each line is a single
+// JSON value. This suits large exports and tailing logs or other events, as
+// clients can process each item as soon as it arrives.
+package ndjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ContentType is the content type of NDJSON responses.
+const ContentType = "application/x-ndjson"
+
+// WriteTimeout is the timeout for writing each item to the client.
+var WriteTimeout = 5 * time.Second
+
+// Register a new NDJSON operation. The `f` function is called with the
+// context & input and returns a channel of items, which are sent to the
+// client one per line as they are received until the channel is closed.
+// Errors returned by `f` are sent as a normal error response since nothing
+// has been streamed yet. The context is canceled if the client disconnects,
+// so producers should stop sending & close the channel when it's done.
+//
+// Each item is serialized & validated against the schema of `T` on its own.
+// An item which fails is replaced by an `{"error": "..."}` line and ends the
+// stream, as the status has already been sent. Flushing is handled
+// automatically via `huma.Flush` as long as the adapter supports it.
+//
+//	ndjson.Register(api, huma.Operation{
+//		OperationID: "export-orders",
+//		Method:      http.MethodGet,
+//		Path:        "/orders/export",
+//	}, func(ctx context.Context, input *struct{}) (<-chan Order, error) {
+//		orders := make(chan Order)
+//		go func() {
+//			defer close(orders)
+//			for rows.Next() {
+//				select {
+//				case orders <- scan(rows):
+//				case <-ctx.Done():
+//					return
+//				}
+//			}
+//		}()
+//		return orders, nil
+//	})
+func Register[I, T any](api huma.API, op huma.Operation, f func(ctx context.Context, input *I) (<-chan T, error)) {
+	registry := api.OpenAPI().Components.Schemas
+	itemType := reflect.TypeOf((*T)(nil)).Elem()
+	schema := registry.Schema(itemType, true, op.OperationID+"Item")
+
+	if op.Responses == nil {
+		op.Responses = map[string]*huma.Response{}
+	}
+	if op.Responses["200"] == nil {
+		op.Responses["200"] = &huma.Response{}
+	}
+	if op.Responses["200"].Description == "" {
+		op.Responses["200"].Description = "Each line is a JSON value matching the schema."
+	}
+	if op.Responses["200"].Content == nil {
+		op.Responses["200"].Content = map[string]*huma.MediaType{}
+	}
+	op.Responses["200"].Content[ContentType] = &huma.MediaType{
+		Schema: schema,
+	}
+
+	huma.Register(api, op, func(ctx context.Context, input *I) (*huma.StreamResponse, error) {
+		items, err := f(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		return &huma.StreamResponse{
+			Body: func(ctx huma.Context) {
+				ctx.SetHeader("Content-Type", ContentType)
+				ctx.SetStatus(http.StatusOK)
+				bw := ctx.BodyWriter()
+				done := ctx.Context().Done()
+
+				buf := &bytes.Buffer{}
+				encoder := json.NewEncoder(buf)
+				res := &huma.ValidateResult{}
+				pb := huma.NewPathBuffer([]byte{}, 0)
+				for {
+					var item T
+					var ok bool
+					select {
+					case item, ok = <-items:
+					case <-done:
+						return
+					}
+					if !ok {
+						return
+					}
+
+					buf.Reset()
+					err := encoder.Encode(item)
+					if err == nil {
+						err = validate(registry, schema, pb, res, buf.Bytes())
+					}
+					if err != nil {
+						// The status has been sent, so report the error in the stream.
+						msg, _ := json.Marshal(map[string]string{"error": err.Error()})
+						bw.Write(append(msg, '\n'))
+						huma.Flush(ctx)
+						return
+					}
+
+					if err := ctx.SetWriteDeadline(time.Now().Add(WriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+						return
+					}
+					if _, err := bw.Write(buf.Bytes()); err != nil {
+						return
+					}
+					if err := huma.Flush(ctx); err != nil && !errors.Is(err, http.ErrNotSupported) {
+						return
+					}
+				}
+			},
+		}, nil
+	})
+}
+
+// validate a serialized item against its schema.
+func validate(registry huma.Registry, schema *huma.Schema, pb *huma.PathBuffer, res *huma.ValidateResult, b []byte) error {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	pb.Reset()
+	res.Reset()
+	huma.Validate(registry, schema, pb, huma.ModeReadFromServer, v, res)
+	if len(res.Errors) > 0 {
+		return fmt.Errorf("validation failed: %w", errors.Join(res.Errors...))
+	}
+	return nil
+}
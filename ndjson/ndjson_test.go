@@ -0,0 +1,71 @@
+package ndjson
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name" minLength:"1"`
+}
+
+func TestNDJSON(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	Register(api, huma.Operation{
+		OperationID: "export",
+		Method:      http.MethodGet,
+		Path:        "/export/{count}",
+	}, func(ctx context.Context, input *struct {
+		Count int `path:"count"`
+	}) (<-chan Item, error) {
+		if input.Count == 0 {
+			return nil, huma.Error404NotFound("nothing to export")
+		}
+		items := make(chan Item)
+		go func() {
+			defer close(items)
+			for i := 1; i <= input.Count; i++ {
+				name := "item"
+				if i == 3 {
+					// Fails validation.
+					name = ""
+				}
+				select {
+				case items <- Item{ID: i, Name: name}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		return items, nil
+	})
+
+	mt := api.OpenAPI().Paths["/export/{count}"].Get.Responses["200"].Content[ContentType]
+	assert.NotNil(t, mt)
+	assert.Equal(t, "#/components/schemas/Item", mt.Schema.Ref)
+
+	resp := api.Get("/export/2")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, ContentType, resp.Header().Get("Content-Type"))
+	assert.Equal(t, `{"id":1,"name":"item"}
+{"id":2,"name":"item"}
+`, resp.Body.String())
+
+	// Invalid items end the stream with an error.
+	resp = api.Get("/export/5")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	lines := resp.Body.String()
+	assert.Contains(t, lines, `{"id":2,"name":"item"}`+"\n"+`{"error":"validation failed: expected length \u003e= 1 (name: )"}`+"\n")
+	assert.NotContains(t, lines, `"id":4`)
+
+	// Errors before streaming are sent as usual.
+	resp = api.Get("/export/0")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
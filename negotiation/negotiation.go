@@ -8,7 +8,8 @@ import (
 // SelectQValue selects and returns the best value from the allowed set
 // given a header with optional quality values, as you would get for an
 // Accept or Accept-Encoding header. The *first* item in allowed is preferred
-// if there is a tie. If nothing matches, returns an empty string.
+// if there is a tie. Values with `q=0` are not acceptable. If nothing
+// matches, returns an empty string.
 func SelectQValue(header string, allowed []string) string {
 	formats := strings.Split(header, ",")
 	best := ""
@@ -30,13 +31,10 @@ func SelectQValue(header string, allowed []string) string {
 			continue
 		}
 
-		// Default weight to 1 if no value is passed.
-		q := 1.0
-		if len(parts) > 1 {
-			trimmed := strings.Trim(parts[1], " \t")
-			if strings.HasPrefix(trimmed, "q=") {
-				q, _ = strconv.ParseFloat(trimmed[2:], 64)
-			}
+		q := qValue(strings.Join(parts[1:], ";"))
+		if q <= 0 {
+			// Explicitly not acceptable.
+			continue
 		}
 
 		// Prefer the first one if there is a tie.
@@ -55,62 +53,60 @@ func SelectQValueFast(header string, allowed []string) string {
 	best := ""
 	bestQ := 0.0
 
-	name := ""
-	start := -1
-	end := 0
-
-	for pos, char := range header {
+	for header != "" {
 		// Format is like "a; q=0.5, b;q=1.0,c; q=0.3"
-		if char == ';' {
-			name = header[start : end+1]
-			start = -1
-			continue
+		format := header
+		if i := strings.IndexByte(header, ','); i >= 0 {
+			format, header = header[:i], header[i+1:]
+		} else {
+			header = ""
 		}
 
-		if char == ',' || pos == len(header)-1 {
-			q := 1.0
-			if pos == len(header)-1 {
-				// This is the end, so it must be the name.
-				// Example: "application/yaml"
-				name = header[start : pos+1]
-			} else if name == "" {
-				// No name yet means we did not encounter a `;`.
-				// Example: "a, b, c"
-				name = header[start:pos]
-			} else {
-				if parsed, _ := strconv.ParseFloat(header[start+2:end+1], 64); parsed > 0 {
-					q = parsed
-				}
-			}
-			start = -1
-
-			found := false
-			for _, n := range allowed {
-				if n == name {
-					found = true
-					break
-				}
-			}
+		name, params, _ := strings.Cut(format, ";")
+		name = strings.Trim(name, " \t")
 
-			if !found {
-				// Skip formats we don't support.
-				continue
+		found := false
+		for _, n := range allowed {
+			if n == name {
+				found = true
+				break
 			}
+		}
 
-			if q > bestQ || (q == bestQ && name == allowed[0]) {
-				bestQ = q
-				best = name
-			}
+		if !found {
+			// Skip formats we don't support.
 			continue
 		}
 
-		if char != ' ' && char != '\t' {
-			end = pos
-			if start == -1 {
-				start = pos
-			}
+		q := qValue(params)
+		if q <= 0 {
+			// Explicitly not acceptable.
+			continue
+		}
+
+		// Prefer the first one if there is a tie.
+		if q > bestQ || (q == bestQ && name == allowed[0]) {
+			bestQ = q
+			best = name
 		}
 	}
 
 	return best
 }
+
+// qValue returns the quality value from parameters like `v=b3;q=0.7`,
+// defaulting to 1 if there is none or it is invalid.
+func qValue(params string) float64 {
+	for params != "" {
+		var param string
+		param, params, _ = strings.Cut(params, ";")
+		param = strings.Trim(param, " \t")
+		if strings.HasPrefix(param, "q=") {
+			if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+				return q
+			}
+			return 1.0
+		}
+	}
+	return 1.0
+}
@@ -50,6 +50,21 @@ func TestNoMatchFast(t *testing.T) {
 	assert.Equal(t, "", SelectQValueFast("a; q=1.0, b;q=1.0,c; q=0.3", []string{"d", "e"}))
 }
 
+func TestNotAcceptable(t *testing.T) {
+	assert.Equal(t, "", SelectQValue("gzip;q=0, identity", []string{"gzip", "br"}))
+	assert.Equal(t, "br", SelectQValue("gzip;q=0, br;q=0.1", []string{"gzip", "br"}))
+}
+
+func TestNotAcceptableFast(t *testing.T) {
+	assert.Equal(t, "", SelectQValueFast("gzip;q=0, identity", []string{"gzip", "br"}))
+	assert.Equal(t, "", SelectQValueFast("gzip;q=0", []string{"gzip", "br"}))
+	assert.Equal(t, "br", SelectQValueFast("gzip;q=0, br;q=0.1", []string{"gzip", "br"}))
+}
+
+func TestAcceptLastQFast(t *testing.T) {
+	assert.Equal(t, "b", SelectQValueFast("a;q=0.5, b;q=0.8", []string{"a", "b"}))
+}
+
 var BenchResult string
 
 func BenchmarkMatch(b *testing.B) {
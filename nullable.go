@@ -0,0 +1,102 @@
+package huma
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// NullTypes maps nullable wrapper types to the type of the value they wrap.
+// Their schema is the value's schema which also allows `null`, e.g. `type:
+// [string, "null"]`. Only add types which marshal themselves as their value or
+// `null` in every format you serve, e.g. via `MarshalJSON` & `UnmarshalJSON`
+// methods, since the schema must match what is sent. Types like
+// `sql.NullString`, which marshal as `{"String": "", "Valid": false}`, are
+// documented as the structs they are, so wrap them or use `Nullable[T]`:
+//
+//	// NullString is a `sql.NullString` which marshals as a string or `null`.
+//	type NullString struct{ sql.NullString }
+//
+//	func (n NullString) MarshalJSON() ([]byte, error) { ... }
+//	func (n *NullString) UnmarshalJSON(b []byte) error { ... }
+//
+//	huma.NullTypes[reflect.TypeOf(NullString{})] = reflect.TypeOf("")
+var NullTypes = map[reflect.Type]reflect.Type{}
+
+// Nullable is a value which may be an explicit JSON `null`, and which records
+// whether the client sent it at all. This tells apart a missing field, e.g. to
+// leave a value unchanged, from `null`, e.g. to clear it. It is documented
+// with the schema of `T` which also allows `null`, like `type: [T, "null"]`.
+// Nullable fields are never required, and unset or invalid values are
+// marshaled as `null`.
+//
+//	type UpdateUser struct {
+//		Nickname huma.Nullable[string] `json:"nickname" maxLength:"20"`
+//	}
+//
+//	if input.Body.Nickname.Set {
+//		if input.Body.Nickname.Valid {
+//			user.Nickname = &input.Body.Nickname.Value
+//		} else {
+//			user.Nickname = nil
+//		}
+//	}
+type Nullable[T any] struct {
+	// Value is the value sent by the client, or the zero value if unset or
+	// `null`.
+	Value T
+
+	// Valid is true if the value is not `null`.
+	Valid bool
+
+	// Set is true if the client sent a value, including `null`.
+	Set bool
+}
+
+// NewNullable returns a `Nullable` which is set to the given value.
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{Value: v, Valid: true, Set: true}
+}
+
+// Null returns a `Nullable` which is set to `null`.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{Set: true}
+}
+
+// MarshalJSON marshals the value, or `null` if it isn't valid.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// UnmarshalJSON unmarshals the value or `null` and marks it as set.
+func (n *Nullable[T]) UnmarshalJSON(b []byte) error {
+	var zero T
+	n.Value, n.Valid, n.Set = zero, false, true
+	if string(b) == "null" {
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(b, &n.Value)
+}
+
+func (n Nullable[T]) nullableType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// nullableWrapper is implemented by every `Nullable[T]`.
+type nullableWrapper interface {
+	nullableType() reflect.Type
+}
+
+var nullableWrapperType = reflect.TypeOf((*nullableWrapper)(nil)).Elem()
+
+// nullableElem returns the type wrapped by a `Nullable[T]` type, or nil if the
+// type is not a `Nullable`.
+func nullableElem(t reflect.Type) reflect.Type {
+	if t.Kind() != reflect.Struct || !t.Implements(nullableWrapperType) {
+		return nil
+	}
+	return reflect.Zero(t).Interface().(nullableWrapper).nullableType()
+}
@@ -3,6 +3,7 @@ package huma
 import (
 	"fmt"
 	"net/http"
+	"reflect"
 	"time"
 
 	"github.com/goccy/go-yaml"
@@ -141,6 +142,17 @@ type Param struct {
 	Extensions    map[string]any      `yaml:",inline"`
 }
 
+// MarshalYAML marshals params which reference a shared definition as just the
+// reference. Operations keep a copy of the referenced fields, e.g. so that
+// middleware can find the headers used by an operation.
+func (p *Param) MarshalYAML() (any, error) {
+	if p.Ref != "" {
+		return map[string]string{"$ref": p.Ref}, nil
+	}
+	type plain Param
+	return (*plain)(p), nil
+}
+
 type Header = Param
 
 type RequestBody struct {
@@ -182,7 +194,8 @@ type Operation struct {
 
 	// DefaultStatus is the default HTTP status code for this operation. It will
 	// be set to 200 or 204 if not specified, depending on whether the handler
-	// returns a response body.
+	// returns a response body. Set it to e.g. 201 for a POST which creates a
+	// resource. A non-zero `Status` output field overrides it at runtime.
 	DefaultStatus int `yaml:"-"`
 
 	// MaxBodyBytes is the maximum number of bytes to read from the request
@@ -197,10 +210,35 @@ type Operation struct {
 	// of -1 can unset the server's timeout.
 	BodyReadTimeout time.Duration `yaml:"-"`
 
+	// RequestExamples are example request bodies keyed by name, given as Go
+	// values like the input body type. They are documented in the spec for
+	// each request content type, and `huma.Register` panics if one doesn't
+	// match the body schema.
+	RequestExamples map[string]any `yaml:"-"`
+
+	// ResponseExamples are example response bodies keyed by status code &
+	// name, given as Go values like the output body type. See
+	// `RequestExamples`.
+	ResponseExamples map[int]map[string]any `yaml:"-"`
+
 	// Errors is a list of HTTP status codes that the handler may return. If
 	// not specified, then a default error response is added to the OpenAPI.
+	// The errors Huma itself may return are always documented, see
+	// `SkipAutoErrors`.
 	Errors []int `yaml:"-"`
 
+	// SkipAutoErrors disables documenting the errors Huma itself may return
+	// for this operation: 400 & 422 for invalid input, 401 & 403 when there
+	// are security requirements, and 500. Only `Errors` are documented.
+	SkipAutoErrors bool `yaml:"-"`
+
+	// DefaultErrorResponse documents the error model once as the `default`
+	// response, which applies to any status code without its own response,
+	// instead of a response for each of the `Errors` and the errors Huma
+	// itself may return. This keeps large specs smaller. A `default` response
+	// declared in `Responses` is kept as-is.
+	DefaultErrorResponse bool `yaml:"-"`
+
 	// SkipValidateParams disables validation of path, query, and header
 	// parameters. This can speed up request processing if you want to handle
 	// your own validation. Use with caution!
@@ -211,11 +249,94 @@ type Operation struct {
 	// caution!
 	SkipValidateBody bool `yaml:"-"`
 
+	// UnknownFields sets how request body properties which are not allowed by
+	// the schema are handled, e.g. extra metadata sent by clients. The default
+	// is to reject them with a validation error.
+	UnknownFields UnknownFieldsMode `yaml:"-"`
+
+	// StripReadOnly removes read-only properties from request bodies before
+	// validation rather than validating them, so clients can send back a
+	// resource they received, e.g. from a `GET` to a `PUT`. The properties are
+	// not decoded into the input struct. See also `Config.StripReadOnly`.
+	StripReadOnly bool `yaml:"-"`
+
+	// MaxValidationErrors limits the number of validation errors collected
+	// and returned for a request, reducing the work & response size for large
+	// invalid inputs. Zero means no limit. See also `Config.MaxValidationErrors`.
+	MaxValidationErrors int `yaml:"-"`
+
+	// SkipRedaction sends fields tagged `sensitive` as-is rather than removing
+	// or masking them when using `RedactTransform`, e.g. for an operation
+	// which returns a newly created API token to its owner.
+	SkipRedaction bool `yaml:"-"`
+
 	// Hidden will skip documenting this operation in the OpenAPI. This is
 	// useful for operations that are not intended to be used by clients but
 	// you'd still like the benefits of using Huma. Generally not recommended.
 	Hidden bool `yaml:"-"`
 
+	// Middlewares wrap the handling of this operation, e.g. for auth, caching,
+	// or rate limiting. The first middleware is the outermost and runs after
+	// any router middleware.
+	Middlewares []Middleware `yaml:"-"`
+
+	// Cache is the caching policy sent as the `Cache-Control` header with
+	// successful responses, and documented via the `x-cache-control`
+	// extension.
+	Cache *CachePolicy `yaml:"-"`
+
+	// Relations link this operation's successful responses to related
+	// resources by relation, like `self` or `parent`, using URI templates such
+	// as `/users/{user-id}` which are resolved from the request's params. See
+	// `RelationsTransform`.
+	Relations map[string]string `yaml:"-"`
+
+	// Transformers modify this operation's response bodies after the API's
+	// transformers have run.
+	Transformers []Transformer `yaml:"-"`
+
+	// EarlyHints are `Link` header values, e.g. `</style.css>; rel=preload;
+	// as=style`, sent in a `103 Early Hints` interim response once the
+	// operation's middlewares have run, before the request is parsed. See
+	// `huma.WriteEarlyHints`.
+	EarlyHints []string `yaml:"-"`
+
+	// Upgrade is the protocol, like `websocket`, which the operation switches
+	// the connection to via `huma.Upgrade` from a streaming response body. It
+	// is documented as a `101 Switching Protocols` default response with a
+	// `426 Upgrade Required` error & the `x-upgrade` extension.
+	Upgrade string `yaml:"-"`
+
+	// DeprecatedSince, Sunset & SuccessorVersion describe the lifecycle of a
+	// `Deprecated` operation. Every response of a deprecated operation has a
+	// `Deprecation` header with the time it was deprecated, or `true` if
+	// unknown, along with a `Sunset` header with the time it will stop working
+	// and a `Link` header to the URL of its successor with the
+	// `successor-version` relation, if set. The headers are documented for
+	// each response.
+	DeprecatedSince  time.Time `yaml:"-"`
+	Sunset           time.Time `yaml:"-"`
+	SuccessorVersion string    `yaml:"-"`
+
+	// inputType & outputType are the structs of the registered handler, which
+	// are used to generate clients.
+	inputType, outputType reflect.Type
+
+	// registry holds the schemas of the operation's params & bodies.
+	registry Registry
+
+	// declared is set for operations implemented via `Implement`, which are
+	// documented & validated using the loaded OpenAPI document.
+	declared bool
+
+	// responses are the documented responses before `OnAddOperation` hooks
+	// ran, used by `Config.DebugValidateResponses`.
+	responses map[string]*Response
+
+	// trustForwarded is set when the API uses `Config.TrustForwardedHeaders`,
+	// for transformers which only have the operation.
+	trustForwarded bool
+
 	// OpenAPI fields
 
 	Tags         []string              `yaml:"tags,omitempty"`
@@ -226,7 +347,7 @@ type Operation struct {
 	Parameters   []*Param              `yaml:"parameters,omitempty"`
 	RequestBody  *RequestBody          `yaml:"requestBody,omitempty"`
 	Responses    map[string]*Response  `yaml:"responses,omitempty"`
-	Callbacks    map[string]*PathItem  `yaml:"callbacks,omitempty"`
+	Callbacks    map[string]Callback   `yaml:"callbacks,omitempty"`
 	Deprecated   bool                  `yaml:"deprecated,omitempty"`
 	Security     []map[string][]string `yaml:"security,omitempty"`
 	Servers      []*Server             `yaml:"servers,omitempty"`
@@ -250,9 +371,13 @@ type PathItem struct {
 	Extensions  map[string]any `yaml:",inline"`
 }
 
+// Callback maps runtime expressions, like `{$request.body#/callbackUrl}`, to
+// the requests which the service sends to the resulting URLs.
+type Callback map[string]*PathItem
+
 type OAuthFlow struct {
-	AuthorizationURL string            `yaml:"authorizationUrl"`
-	TokenURL         string            `yaml:"tokenUrl"`
+	AuthorizationURL string            `yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `yaml:"tokenUrl,omitempty"`
 	RefreshURL       string            `yaml:"refreshUrl,omitempty"`
 	Scopes           map[string]string `yaml:"scopes"`
 	Extensions       map[string]any    `yaml:",inline"`
@@ -287,11 +412,47 @@ type Components struct {
 	Headers         map[string]*Header         `yaml:"headers,omitempty"`
 	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes,omitempty"`
 	Links           map[string]*Link           `yaml:"links,omitempty"`
-	Callbacks       map[string]*PathItem       `yaml:"callbacks,omitempty"`
+	Callbacks       map[string]Callback        `yaml:"callbacks,omitempty"`
 	PathItems       map[string]*PathItem       `yaml:"pathItems,omitempty"`
 	Extensions      map[string]any             `yaml:",inline"`
 }
 
+// UnmarshalYAML unmarshals the components, loading the schemas into a new map
+// registry so they can be referenced & used for validation.
+func (c *Components) UnmarshalYAML(b []byte) error {
+	var schemas struct {
+		Schemas map[string]*Schema `yaml:"schemas"`
+	}
+	if err := yaml.Unmarshal(b, &schemas); err != nil {
+		return err
+	}
+
+	// The rest is unmarshaled without the schemas, as a registry is an
+	// interface.
+	var m map[string]any
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	delete(m, "schemas")
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	type plain Components
+	var p plain
+	if err := yaml.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*c = Components(p)
+
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	for name, s := range schemas.Schemas {
+		registry.Map()[name] = s
+	}
+	c.Schemas = registry
+	return nil
+}
+
 type ExternalDocs struct {
 	Description string         `yaml:"description,omitempty"`
 	URL         string         `yaml:"url"`
@@ -326,6 +487,19 @@ type OpenAPI struct {
 	OnAddOperation []AddOpFunc `yaml:"-"`
 }
 
+// FindOperation returns the operation with the given ID, or nil if there is
+// none.
+func (o *OpenAPI) FindOperation(operationID string) *Operation {
+	for _, item := range o.Paths {
+		for _, op := range item.operations() {
+			if op.OperationID == operationID {
+				return op
+			}
+		}
+	}
+	return nil
+}
+
 func (o *OpenAPI) AddOperation(op *Operation) {
 	if o.Paths == nil {
 		o.Paths = map[string]*PathItem{}
@@ -337,30 +511,35 @@ func (o *OpenAPI) AddOperation(op *Operation) {
 		o.Paths[op.Path] = item
 	}
 
+	item.setOperation(op)
+
+	for _, f := range o.OnAddOperation {
+		f(o, op)
+	}
+}
+
+// setOperation sets the operation for its method on the path item.
+func (p *PathItem) setOperation(op *Operation) {
 	switch op.Method {
 	case http.MethodGet:
-		item.Get = op
+		p.Get = op
 	case http.MethodPost:
-		item.Post = op
+		p.Post = op
 	case http.MethodPut:
-		item.Put = op
+		p.Put = op
 	case http.MethodPatch:
-		item.Patch = op
+		p.Patch = op
 	case http.MethodDelete:
-		item.Delete = op
+		p.Delete = op
 	case http.MethodHead:
-		item.Head = op
+		p.Head = op
 	case http.MethodOptions:
-		item.Options = op
+		p.Options = op
 	case http.MethodTrace:
-		item.Trace = op
+		p.Trace = op
 	default:
 		panic(fmt.Sprintf("unknown method %s", op.Method))
 	}
-
-	for _, f := range o.OnAddOperation {
-		f(o, op)
-	}
 }
 
 func (o *OpenAPI) MarshalJSON() ([]byte, error) {
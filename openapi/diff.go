@@ -0,0 +1,587 @@
+// Package openapi provides utilities for working with generated OpenAPI
+// documents, like finding the differences between two versions of an API.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// rxPathParam matches a path template param like `{id}`.
+var rxPathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+// Change is a single difference between two versions of an OpenAPI document.
+type Change struct {
+	// Operation is the method & path of the changed operation, e.g.
+	// `GET /things/{id}`.
+	Operation string
+
+	// Location is where in the operation the change was made, e.g.
+	// `query.limit` or `response.200.body.items[].name`. It is empty when
+	// the operation itself was added or removed.
+	Location string
+
+	// Message describes the change.
+	Message string
+
+	// Breaking is set when the change may break existing clients.
+	Breaking bool
+}
+
+// String returns a human-readable description of the change.
+func (c Change) String() string {
+	kind := "compatible"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	if c.Location == "" {
+		return fmt.Sprintf("%s: %s: %s", kind, c.Operation, c.Message)
+	}
+	return fmt.Sprintf("%s: %s %s: %s", kind, c.Operation, c.Location, c.Message)
+}
+
+// Changes is a list of changes between two versions of an OpenAPI document.
+type Changes []Change
+
+// Breaking returns only the changes which may break existing clients.
+func (c Changes) Breaking() Changes {
+	breaking := Changes{}
+	for _, change := range c {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// Diff compares two versions of an OpenAPI document and returns the changes
+// made to their operations, sorted by operation & location. Each change is
+// classified as breaking or compatible for existing clients, so e.g. a CI
+// pipeline can refuse to deploy breaking changes:
+//
+//	if breaking := openapi.Diff(oldAPI, api.OpenAPI()).Breaking(); len(breaking) > 0 {
+//		for _, change := range breaking {
+//			fmt.Println(change)
+//		}
+//		os.Exit(1)
+//	}
+//
+// Removing operations, params, content types or success responses is
+// breaking, as is adding required params or properties. Schemas are compared
+// based on who sends the data: narrowing a request schema (e.g. a lower
+// `maxLength` or removed enum value) rejects requests which used to be valid,
+// while widening a response schema (e.g. a new enum value or nullable field)
+// returns data which clients may not handle.
+//
+// Operations are matched by method & path, ignoring the names of path params
+// so they may be renamed. References to component schemas, params, request
+// bodies and responses are resolved using each document's components.
+func Diff(old, new *huma.OpenAPI) Changes {
+	d := &differ{old: old, new: new, seen: map[[2]*huma.Schema]bool{}}
+
+	oldOps := operations(old)
+	newOps := operations(new)
+	for _, key := range sortedKeys(oldOps) {
+		o := oldOps[key]
+		n, ok := newOps[key]
+		if !ok {
+			d.add(o.name, "", true, "operation removed")
+			continue
+		}
+		d.operation(n.name, o, n)
+	}
+	for _, key := range sortedKeys(newOps) {
+		if _, ok := oldOps[key]; !ok {
+			d.add(newOps[key].name, "", false, "operation added")
+		}
+	}
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		if d.changes[i].Operation != d.changes[j].Operation {
+			return d.changes[i].Operation < d.changes[j].Operation
+		}
+		return d.changes[i].Location < d.changes[j].Location
+	})
+	return d.changes
+}
+
+// direction describes who sends the data described by a schema.
+type direction int
+
+const (
+	// toServer data is sent by clients, so narrowing its schema is breaking.
+	toServer direction = iota
+	// toClient data is sent by the server, so widening its schema is breaking.
+	toClient
+)
+
+// operation is an operation along with the path item containing it.
+type operation struct {
+	name string
+	path string
+	item *huma.PathItem
+	op   *huma.Operation
+}
+
+// operations returns the operations of the document keyed by method & path,
+// with path param names removed.
+func operations(oapi *huma.OpenAPI) map[string]operation {
+	ops := map[string]operation{}
+	for path, item := range oapi.Paths {
+		if item == nil {
+			continue
+		}
+		for method, op := range map[string]*huma.Operation{
+			http.MethodGet:     item.Get,
+			http.MethodPut:     item.Put,
+			http.MethodPost:    item.Post,
+			http.MethodDelete:  item.Delete,
+			http.MethodOptions: item.Options,
+			http.MethodHead:    item.Head,
+			http.MethodPatch:   item.Patch,
+			http.MethodTrace:   item.Trace,
+		} {
+			if op != nil {
+				key := method + " " + rxPathParam.ReplaceAllString(path, "{}")
+				ops[key] = operation{name: method + " " + path, path: path, item: item, op: op}
+			}
+		}
+	}
+	return ops
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type differ struct {
+	old     *huma.OpenAPI
+	new     *huma.OpenAPI
+	changes Changes
+
+	// seen tracks compared schema pairs to stop recursive schemas.
+	seen map[[2]*huma.Schema]bool
+}
+
+func (d *differ) add(op, loc string, breaking bool, format string, args ...any) {
+	d.changes = append(d.changes, Change{
+		Operation: op,
+		Location:  loc,
+		Message:   fmt.Sprintf(format, args...),
+		Breaking:  breaking,
+	})
+}
+
+func (d *differ) operation(name string, o, n operation) {
+	if !o.op.Deprecated && n.op.Deprecated {
+		d.add(name, "", false, "operation deprecated")
+	}
+
+	oldParams := params(d.old, o)
+	newParams := params(d.new, n)
+
+	// Match renamed path params by their position in the path.
+	oldNames := rxPathParam.FindAllString(o.path, -1)
+	newNames := rxPathParam.FindAllString(n.path, -1)
+	for i, oldName := range oldNames {
+		oldKey := "path." + strings.Trim(oldName, "{}")
+		newKey := "path." + strings.Trim(newNames[i], "{}")
+		if p, ok := oldParams[oldKey]; ok && oldKey != newKey {
+			delete(oldParams, oldKey)
+			oldParams[newKey] = p
+		}
+	}
+	for _, key := range sortedKeys(oldParams) {
+		op := oldParams[key]
+		np, ok := newParams[key]
+		if !ok {
+			d.add(name, key, true, "%s param removed", op.In)
+			continue
+		}
+		if !op.Required && np.Required {
+			d.add(name, key, true, "%s param became required", op.In)
+		}
+		if !op.Deprecated && np.Deprecated {
+			d.add(name, key, false, "%s param deprecated", op.In)
+		}
+		d.schema(name, key, toServer, op.Schema, np.Schema)
+	}
+	for _, key := range sortedKeys(newParams) {
+		if _, ok := oldParams[key]; !ok {
+			if np := newParams[key]; np.Required {
+				d.add(name, key, true, "required %s param added", np.In)
+			} else {
+				d.add(name, key, false, "optional %s param added", np.In)
+			}
+		}
+	}
+
+	d.requestBody(name, o.op.RequestBody, n.op.RequestBody)
+
+	for _, status := range sortedKeys(o.op.Responses) {
+		loc := "response." + status
+		or := resolveResponse(d.old, o.op.Responses[status])
+		nr, ok := n.op.Responses[status]
+		if !ok {
+			// Clients may rely on success responses, but generally handle
+			// errors generically.
+			d.add(name, loc, strings.HasPrefix(status, "2"), "response removed")
+			continue
+		}
+		d.response(name, loc, or, resolveResponse(d.new, nr))
+	}
+	for _, status := range sortedKeys(n.op.Responses) {
+		if _, ok := o.op.Responses[status]; !ok {
+			d.add(name, "response."+status, false, "response added")
+		}
+	}
+}
+
+// params returns the resolved params of the operation, including those of
+// its path item, keyed by location & name.
+func params(oapi *huma.OpenAPI, o operation) map[string]*huma.Param {
+	result := map[string]*huma.Param{}
+	for _, p := range append(append([]*huma.Param{}, o.item.Parameters...), o.op.Parameters...) {
+		if p == nil {
+			continue
+		}
+		if p.Name == "" && p.Ref != "" && oapi.Components != nil {
+			p = oapi.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+			if p == nil {
+				continue
+			}
+		}
+		name := p.Name
+		if p.In == "header" {
+			name = http.CanonicalHeaderKey(name)
+		}
+		result[p.In+"."+name] = p
+	}
+	return result
+}
+
+func resolveResponse(oapi *huma.OpenAPI, r *huma.Response) *huma.Response {
+	if r != nil && r.Ref != "" && oapi.Components != nil {
+		if resolved := oapi.Components.Responses[strings.TrimPrefix(r.Ref, "#/components/responses/")]; resolved != nil {
+			return resolved
+		}
+	}
+	return r
+}
+
+func resolveRequestBody(oapi *huma.OpenAPI, b *huma.RequestBody) *huma.RequestBody {
+	if b != nil && b.Ref != "" && oapi.Components != nil {
+		if resolved := oapi.Components.RequestBodies[strings.TrimPrefix(b.Ref, "#/components/requestBodies/")]; resolved != nil {
+			return resolved
+		}
+	}
+	return b
+}
+
+func (d *differ) requestBody(name string, o, n *huma.RequestBody) {
+	o = resolveRequestBody(d.old, o)
+	n = resolveRequestBody(d.new, n)
+	switch {
+	case o == nil && n == nil:
+		return
+	case o == nil:
+		if n.Required {
+			d.add(name, "body", true, "required request body added")
+		} else {
+			d.add(name, "body", false, "optional request body added")
+		}
+		return
+	case n == nil:
+		d.add(name, "body", true, "request body removed")
+		return
+	}
+	if !o.Required && n.Required {
+		d.add(name, "body", true, "request body became required")
+	}
+	d.content(name, "body", toServer, o.Content, n.Content)
+}
+
+func (d *differ) response(name, loc string, o, n *huma.Response) {
+	if o == nil || n == nil {
+		return
+	}
+	for _, header := range sortedKeys(o.Headers) {
+		if _, ok := n.Headers[header]; !ok {
+			d.add(name, loc+".headers."+header, true, "response header removed")
+			continue
+		}
+		if o.Headers[header] != nil && n.Headers[header] != nil {
+			d.schema(name, loc+".headers."+header, toClient, o.Headers[header].Schema, n.Headers[header].Schema)
+		}
+	}
+	for _, header := range sortedKeys(n.Headers) {
+		if _, ok := o.Headers[header]; !ok {
+			d.add(name, loc+".headers."+header, false, "response header added")
+		}
+	}
+	d.content(name, loc+".body", toClient, o.Content, n.Content)
+}
+
+func (d *differ) content(name, loc string, dir direction, o, n map[string]*huma.MediaType) {
+	for _, ct := range sortedKeys(o) {
+		if _, ok := n[ct]; !ok {
+			d.add(name, loc, true, "content type %s removed", ct)
+			continue
+		}
+		if o[ct] != nil && n[ct] != nil {
+			d.schema(name, loc, dir, o[ct].Schema, n[ct].Schema)
+		}
+	}
+	for _, ct := range sortedKeys(n) {
+		if _, ok := o[ct]; !ok {
+			d.add(name, loc, false, "content type %s added", ct)
+		}
+	}
+}
+
+// resolve follows a schema's `$ref` to the document's component schemas.
+func resolve(oapi *huma.OpenAPI, s *huma.Schema) *huma.Schema {
+	for s != nil && s.Ref != "" {
+		if oapi.Components == nil || oapi.Components.Schemas == nil {
+			return nil
+		}
+		s = oapi.Components.Schemas.SchemaFromRef(s.Ref)
+	}
+	return s
+}
+
+// narrowed records a change which allows fewer values than before. This is
+// breaking for data sent by clients.
+func (d *differ) narrowed(name, loc string, dir direction, format string, args ...any) {
+	d.add(name, loc, dir == toServer, format, args...)
+}
+
+// widened records a change which allows more values than before. This is
+// breaking for data sent by the server.
+func (d *differ) widened(name, loc string, dir direction, format string, args ...any) {
+	d.add(name, loc, dir == toClient, format, args...)
+}
+
+func (d *differ) schema(name, loc string, dir direction, o, n *huma.Schema) {
+	o = resolve(d.old, o)
+	n = resolve(d.new, n)
+	if o == nil || n == nil {
+		return
+	}
+	pair := [2]*huma.Schema{o, n}
+	if d.seen[pair] {
+		return
+	}
+	d.seen[pair] = true
+	defer delete(d.seen, pair)
+
+	if o.Type != n.Type {
+		switch {
+		case o.Type == "":
+			d.narrowed(name, loc, dir, "type %s added", n.Type)
+		case n.Type == "":
+			d.widened(name, loc, dir, "type %s removed", o.Type)
+		case o.Type == huma.TypeInteger && n.Type == huma.TypeNumber:
+			d.widened(name, loc, dir, "type changed from %s to %s", o.Type, n.Type)
+		default:
+			d.add(name, loc, true, "type changed from %s to %s", o.Type, n.Type)
+		}
+	}
+	if o.Format != n.Format {
+		switch {
+		case o.Format == "":
+			d.narrowed(name, loc, dir, "format %s added", n.Format)
+		case n.Format == "":
+			d.widened(name, loc, dir, "format %s removed", o.Format)
+		default:
+			d.add(name, loc, true, "format changed from %s to %s", o.Format, n.Format)
+		}
+	}
+	if o.Nullable != n.Nullable {
+		if n.Nullable {
+			d.widened(name, loc, dir, "became nullable")
+		} else {
+			d.narrowed(name, loc, dir, "is no longer nullable")
+		}
+	}
+	if o.Pattern != n.Pattern {
+		switch {
+		case o.Pattern == "":
+			d.narrowed(name, loc, dir, "pattern %s added", n.Pattern)
+		case n.Pattern == "":
+			d.widened(name, loc, dir, "pattern %s removed", o.Pattern)
+		default:
+			d.add(name, loc, true, "pattern changed from %s to %s", o.Pattern, n.Pattern)
+		}
+	}
+	if !o.Deprecated && n.Deprecated {
+		d.add(name, loc, false, "deprecated")
+	}
+
+	d.enum(name, loc, dir, o, n)
+
+	d.lower(name, loc, dir, "minimum", o.Minimum, n.Minimum)
+	d.lower(name, loc, dir, "exclusiveMinimum", o.ExclusiveMinimum, n.ExclusiveMinimum)
+	d.upper(name, loc, dir, "maximum", o.Maximum, n.Maximum)
+	d.upper(name, loc, dir, "exclusiveMaximum", o.ExclusiveMaximum, n.ExclusiveMaximum)
+	d.lower(name, loc, dir, "minLength", intBound(o.MinLength), intBound(n.MinLength))
+	d.upper(name, loc, dir, "maxLength", intBound(o.MaxLength), intBound(n.MaxLength))
+	d.lower(name, loc, dir, "minItems", intBound(o.MinItems), intBound(n.MinItems))
+	d.upper(name, loc, dir, "maxItems", intBound(o.MaxItems), intBound(n.MaxItems))
+	d.lower(name, loc, dir, "minProperties", intBound(o.MinProperties), intBound(n.MinProperties))
+	d.upper(name, loc, dir, "maxProperties", intBound(o.MaxProperties), intBound(n.MaxProperties))
+	if !o.UniqueItems && n.UniqueItems {
+		d.narrowed(name, loc, dir, "items became unique")
+	} else if o.UniqueItems && !n.UniqueItems {
+		d.widened(name, loc, dir, "items are no longer unique")
+	}
+
+	if o.Items != nil && n.Items != nil {
+		d.schema(name, loc+"[]", dir, o.Items, n.Items)
+	}
+	d.properties(name, loc, dir, o, n)
+}
+
+func (d *differ) enum(name, loc string, dir direction, o, n *huma.Schema) {
+	oldValues := enumValues(o)
+	newValues := enumValues(n)
+	switch {
+	case oldValues == nil && newValues == nil:
+		return
+	case oldValues == nil:
+		d.narrowed(name, loc, dir, "enum added")
+		return
+	case newValues == nil:
+		d.widened(name, loc, dir, "enum removed")
+		return
+	}
+	for _, v := range sortedKeys(oldValues) {
+		if !newValues[v] {
+			d.narrowed(name, loc, dir, "enum value %s removed", v)
+		}
+	}
+	for _, v := range sortedKeys(newValues) {
+		if !oldValues[v] {
+			d.widened(name, loc, dir, "enum value %s added", v)
+		}
+	}
+}
+
+// enumValues returns the allowed values of the schema's enum or const, encoded
+// as JSON so they can be compared, or nil if any value is allowed.
+func enumValues(s *huma.Schema) map[string]bool {
+	values := s.Enum
+	if s.Const != nil {
+		values = []any{s.Const}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	result := map[string]bool{}
+	for _, v := range values {
+		b, _ := json.Marshal(v)
+		result[string(b)] = true
+	}
+	return result
+}
+
+func intBound(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+// lower compares a lower bound like `minimum`, where raising it narrows the
+// allowed values.
+func (d *differ) lower(name, loc string, dir direction, keyword string, o, n *float64) {
+	switch {
+	case o == nil && n == nil:
+	case o == nil:
+		d.narrowed(name, loc, dir, "%s %v added", keyword, *n)
+	case n == nil:
+		d.widened(name, loc, dir, "%s %v removed", keyword, *o)
+	case *n > *o:
+		d.narrowed(name, loc, dir, "%s increased from %v to %v", keyword, *o, *n)
+	case *n < *o:
+		d.widened(name, loc, dir, "%s decreased from %v to %v", keyword, *o, *n)
+	}
+}
+
+// upper compares an upper bound like `maximum`, where lowering it narrows the
+// allowed values.
+func (d *differ) upper(name, loc string, dir direction, keyword string, o, n *float64) {
+	switch {
+	case o == nil && n == nil:
+	case o == nil:
+		d.narrowed(name, loc, dir, "%s %v added", keyword, *n)
+	case n == nil:
+		d.widened(name, loc, dir, "%s %v removed", keyword, *o)
+	case *n < *o:
+		d.narrowed(name, loc, dir, "%s decreased from %v to %v", keyword, *o, *n)
+	case *n > *o:
+		d.widened(name, loc, dir, "%s increased from %v to %v", keyword, *o, *n)
+	}
+}
+
+func (d *differ) properties(name, loc string, dir direction, o, n *huma.Schema) {
+	oldRequired := map[string]bool{}
+	for _, p := range o.Required {
+		oldRequired[p] = true
+	}
+	newRequired := map[string]bool{}
+	for _, p := range n.Required {
+		newRequired[p] = true
+	}
+	closed := n.AdditionalProperties == false
+
+	for _, prop := range sortedKeys(o.Properties) {
+		ploc := loc + "." + prop
+		np, ok := n.Properties[prop]
+		if !ok {
+			// Clients may still send removed properties, which is only a
+			// problem when additional properties are forbidden.
+			d.add(name, ploc, dir == toClient || closed, "property removed")
+			continue
+		}
+		if !oldRequired[prop] && newRequired[prop] {
+			// Clients must now send it, and may rely on it being returned.
+			d.add(name, ploc, dir == toServer, "property became required")
+		} else if oldRequired[prop] && !newRequired[prop] {
+			d.add(name, ploc, dir == toClient, "property is no longer required")
+		}
+		d.schema(name, ploc, dir, o.Properties[prop], np)
+	}
+	for _, prop := range sortedKeys(n.Properties) {
+		if _, ok := o.Properties[prop]; !ok {
+			if newRequired[prop] {
+				d.add(name, loc+"."+prop, dir == toServer, "required property added")
+			} else {
+				d.add(name, loc+"."+prop, false, "optional property added")
+			}
+		}
+	}
+
+	if o.AdditionalProperties != false && closed {
+		d.narrowed(name, loc, dir, "additional properties forbidden")
+	} else if o.AdditionalProperties == false && !closed {
+		d.widened(name, loc, dir, "additional properties allowed")
+	}
+	oldAdditional, _ := o.AdditionalProperties.(*huma.Schema)
+	newAdditional, _ := n.AdditionalProperties.(*huma.Schema)
+	if oldAdditional != nil && newAdditional != nil {
+		d.schema(name, loc+".*", dir, oldAdditional, newAdditional)
+	}
+}
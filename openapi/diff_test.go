@@ -0,0 +1,225 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type ThingV1 struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name" maxLength:"20"`
+	Status string   `json:"status" enum:"active,inactive"`
+	Tags   []string `json:"tags,omitempty"`
+	Owner  string   `json:"owner,omitempty"`
+}
+
+type ThingV2 struct {
+	ID     string                `json:"id"`
+	Name   string                `json:"name" maxLength:"10"`
+	Status string                `json:"status" enum:"active,inactive,archived"`
+	Tags   []string              `json:"tags,omitempty"`
+	Note   huma.Nullable[string] `json:"note"`
+	Region string                `json:"region"`
+}
+
+type ListV1Input struct {
+	Limit int `query:"limit" maximum:"100"`
+}
+
+type ListV2Input struct {
+	Limit  int    `query:"limit" maximum:"50"`
+	Cursor string `query:"cursor"`
+	Tenant string `header:"X-Tenant"`
+}
+
+func newDiffAPI(t *testing.T, register func(api huma.API)) *huma.OpenAPI {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	register(api)
+	return api.OpenAPI()
+}
+
+func TestDiff(t *testing.T) {
+	old := newDiffAPI(t, func(api huma.API) {
+		huma.Register(api, huma.Operation{
+			OperationID: "get-thing",
+			Method:      http.MethodGet,
+			Path:        "/things/{id}",
+		}, func(ctx context.Context, input *struct {
+			ID string `path:"id"`
+		}) (*struct{ Body ThingV1 }, error) {
+			return nil, nil
+		})
+		huma.Register(api, huma.Operation{
+			OperationID: "put-thing",
+			Method:      http.MethodPut,
+			Path:        "/things/{id}",
+		}, func(ctx context.Context, input *struct {
+			ID   string `path:"id"`
+			Body ThingV1
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+		huma.Register(api, huma.Operation{
+			OperationID: "list-things",
+			Method:      http.MethodGet,
+			Path:        "/things",
+		}, func(ctx context.Context, input *ListV1Input) (*struct{}, error) {
+			return nil, nil
+		})
+		huma.Register(api, huma.Operation{
+			OperationID: "delete-thing",
+			Method:      http.MethodDelete,
+			Path:        "/things/{id}",
+		}, func(ctx context.Context, input *struct {
+			ID string `path:"id"`
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+
+	new := newDiffAPI(t, func(api huma.API) {
+		huma.Register(api, huma.Operation{
+			OperationID: "get-thing",
+			Method:      http.MethodGet,
+			Path:        "/things/{thing-id}",
+		}, func(ctx context.Context, input *struct {
+			ID string `path:"thing-id"`
+		}) (*struct{ Body ThingV2 }, error) {
+			return nil, nil
+		})
+		huma.Register(api, huma.Operation{
+			OperationID: "put-thing",
+			Method:      http.MethodPut,
+			Path:        "/things/{thing-id}",
+		}, func(ctx context.Context, input *struct {
+			ID   string `path:"thing-id"`
+			Body ThingV2
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+		huma.Register(api, huma.Operation{
+			OperationID: "list-things",
+			Method:      http.MethodGet,
+			Path:        "/things",
+			Deprecated:  true,
+		}, func(ctx context.Context, input *ListV2Input) (*struct{}, error) {
+			return nil, nil
+		})
+		huma.Register(api, huma.Operation{
+			OperationID: "create-thing",
+			Method:      http.MethodPost,
+			Path:        "/things",
+		}, func(ctx context.Context, input *struct {
+			Body ThingV2
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+
+	changes := Diff(old, new)
+	summary := []string{}
+	for _, c := range changes {
+		summary = append(summary, c.String())
+	}
+
+	assert.Equal(t, []string{
+		"breaking: DELETE /things/{id}: operation removed",
+		"compatible: GET /things: operation deprecated",
+		"compatible: GET /things header.X-Tenant: optional header param added",
+		"compatible: GET /things query.cursor: optional query param added",
+		"breaking: GET /things query.limit: maximum decreased from 100 to 50",
+		"compatible: GET /things response.204.headers.Deprecation: response header added",
+		"compatible: GET /things response.422.headers.Deprecation: response header added",
+		"compatible: GET /things response.500.headers.Deprecation: response header added",
+		"compatible: GET /things response.default.headers.Deprecation: response header added",
+		"compatible: GET /things/{thing-id} response.200.body.name: maxLength decreased from 20 to 10",
+		"compatible: GET /things/{thing-id} response.200.body.note: optional property added",
+		"breaking: GET /things/{thing-id} response.200.body.owner: property removed",
+		"compatible: GET /things/{thing-id} response.200.body.region: required property added",
+		"breaking: GET /things/{thing-id} response.200.body.status: enum value \"archived\" added",
+		"compatible: POST /things: operation added",
+		"breaking: PUT /things/{thing-id} body.name: maxLength decreased from 20 to 10",
+		"compatible: PUT /things/{thing-id} body.note: optional property added",
+		"breaking: PUT /things/{thing-id} body.owner: property removed",
+		"breaking: PUT /things/{thing-id} body.region: required property added",
+		"compatible: PUT /things/{thing-id} body.status: enum value \"archived\" added",
+	}, summary)
+
+	for _, c := range changes.Breaking() {
+		assert.True(t, c.Breaking)
+	}
+	assert.Empty(t, Diff(old, old))
+}
+
+func TestDiffSchemas(t *testing.T) {
+	min := 1.0
+	oldSchema := &huma.Schema{Type: huma.TypeInteger}
+	newSchema := &huma.Schema{Type: huma.TypeNumber, Minimum: &min, Nullable: true}
+
+	oapi := func(s *huma.Schema, required bool) *huma.OpenAPI {
+		return &huma.OpenAPI{
+			Paths: map[string]*huma.PathItem{
+				"/items": {
+					Post: &huma.Operation{
+						Parameters: []*huma.Param{{Ref: "#/components/parameters/Count"}},
+						RequestBody: &huma.RequestBody{
+							Required: required,
+							Content: map[string]*huma.MediaType{
+								"application/json": {Schema: &huma.Schema{Ref: "#/components/schemas/Item"}},
+							},
+						},
+						Responses: map[string]*huma.Response{
+							"200": {Content: map[string]*huma.MediaType{
+								"application/json": {Schema: &huma.Schema{Ref: "#/components/schemas/Item"}},
+							}},
+						},
+					},
+				},
+			},
+			Components: &huma.Components{
+				Schemas: schemaRegistry(map[string]*huma.Schema{
+					"Item": {Type: huma.TypeObject, Properties: map[string]*huma.Schema{
+						"count": s,
+						"next":  {Ref: "#/components/schemas/Item"},
+					}},
+				}),
+				Parameters: map[string]*huma.Param{
+					"Count": {Name: "count", In: "query", Required: required, Schema: s},
+				},
+			},
+		}
+	}
+
+	changes := Diff(oapi(oldSchema, false), oapi(newSchema, true))
+	summary := []string{}
+	for _, c := range changes {
+		summary = append(summary, c.String())
+	}
+	assert.Equal(t, []string{
+		"breaking: POST /items body: request body became required",
+		"compatible: POST /items body.count: type changed from integer to number",
+		"compatible: POST /items body.count: became nullable",
+		"breaking: POST /items body.count: minimum 1 added",
+		"breaking: POST /items query.count: query param became required",
+		"compatible: POST /items query.count: type changed from integer to number",
+		"compatible: POST /items query.count: became nullable",
+		"breaking: POST /items query.count: minimum 1 added",
+		"breaking: POST /items response.200.body.count: type changed from integer to number",
+		"breaking: POST /items response.200.body.count: became nullable",
+		"compatible: POST /items response.200.body.count: minimum 1 added",
+	}, summary)
+}
+
+// schemaRegistry returns a registry with fixed schemas.
+func schemaRegistry(schemas map[string]*huma.Schema) huma.Registry {
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	for name, s := range schemas {
+		r.Map()[name] = s
+	}
+	return r
+}
@@ -0,0 +1,72 @@
+package huma
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Optional is a value which records whether the client sent it, which is
+// useful to tell a missing field apart from its zero value, e.g. for `PATCH`
+// style partial updates, without using pointers. It can be used for body
+// fields and parameters, and is documented with the schema of `T`. Optional
+// fields are never required.
+//
+//	type UpdateThing struct {
+//		Name  huma.Optional[string] `json:"name" maxLength:"80"`
+//		Count huma.Optional[int]    `json:"count" minimum:"0"`
+//	}
+//
+//	if input.Body.Count.Set {
+//		thing.Count = input.Body.Count.Value
+//	}
+type Optional[T any] struct {
+	// Value is the value sent by the client, or the zero value if unset.
+	Value T
+
+	// Set is true if the client sent a value.
+	Set bool
+}
+
+// NewOptional returns an `Optional` which is set to the given value.
+func NewOptional[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Set: true}
+}
+
+// Get returns the value and whether it was set.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Set
+}
+
+// MarshalJSON marshals the value, which is the zero value if unset.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON unmarshals the value and marks it as set.
+func (o *Optional[T]) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &o.Value); err != nil {
+		return err
+	}
+	o.Set = true
+	return nil
+}
+
+func (o Optional[T]) optionalType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// optionalWrapper is implemented by every `Optional[T]`.
+type optionalWrapper interface {
+	optionalType() reflect.Type
+}
+
+var optionalWrapperType = reflect.TypeOf((*optionalWrapper)(nil)).Elem()
+
+// optionalElem returns the type wrapped by an `Optional[T]` type, or nil if
+// the type is not optional.
+func optionalElem(t reflect.Type) reflect.Type {
+	if t.Kind() != reflect.Struct || !t.Implements(optionalWrapperType) {
+		return nil
+	}
+	return reflect.Zero(t).Interface().(optionalWrapper).optionalType()
+}
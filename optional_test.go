@@ -0,0 +1,90 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type OptionalPatch struct {
+	Name  Optional[string] `json:"name" maxLength:"5"`
+	Count Optional[int]    `json:"count" minimum:"0"`
+	Tags  []string         `json:"tags,omitempty"`
+}
+
+func TestOptionalSchema(t *testing.T) {
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	s := registry.Schema(reflect.TypeOf(OptionalPatch{}), false, "")
+
+	assert.Empty(t, s.Required)
+	assert.Equal(t, TypeString, s.Properties["name"].Type)
+	assert.Equal(t, 5, *s.Properties["name"].MaxLength)
+	assert.Equal(t, TypeInteger, s.Properties["count"].Type)
+	assert.Equal(t, 0.0, *s.Properties["count"].Minimum)
+	assert.Len(t, registry.Map(), 1, "optional wrappers don't get refs")
+
+	assert.Panics(t, func() {
+		registry.Schema(reflect.TypeOf(struct {
+			Count Optional[int] `json:"count" default:"5"`
+		}{}), false, "")
+	})
+}
+
+func TestOptional(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	var got OptionalPatch
+	var limit Optional[int]
+	Register(app, Operation{
+		OperationID: "patch",
+		Method:      http.MethodPatch,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		Limit Optional[int] `query:"limit" minimum:"1"`
+		Body  OptionalPatch
+	}) (*struct{}, error) {
+		got = input.Body
+		limit = input.Limit
+		return nil, nil
+	})
+
+	for _, item := range []struct {
+		url   string
+		body  string
+		patch OptionalPatch
+		limit Optional[int]
+	}{
+		{"/things", `{}`, OptionalPatch{}, Optional[int]{}},
+		{"/things?limit=5", `{"count": 0}`, OptionalPatch{Count: NewOptional(0)}, NewOptional(5)},
+		{"/things", `{"name": "", "count": 3}`, OptionalPatch{Name: NewOptional(""), Count: NewOptional(3)}, Optional[int]{}},
+	} {
+		req, _ := http.NewRequest(http.MethodPatch, item.url, strings.NewReader(item.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+		assert.Equal(t, item.patch, got, item.body)
+		assert.Equal(t, item.limit, limit, item.url)
+	}
+
+	req, _ := http.NewRequest(http.MethodPatch, "/things?limit=0", strings.NewReader(`{"name": "toolong", "count": -1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var model ErrorModel
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &model))
+	assert.Len(t, model.Errors, 3)
+
+	b, err := json.Marshal(OptionalPatch{Name: NewOptional("foo")})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name": "foo", "count": 0}`, string(b))
+}
@@ -0,0 +1,166 @@
+// Package pagination provides cursor-based pagination helpers: query params
+// to embed in input structs, a standard page output type with RFC 8288 `Link`
+// headers, and a codec for opaque, optionally signed cursors.
+//
+//	type ListThingsInput struct {
+//		pagination.Params
+//	}
+//
+//	huma.Register(api, huma.Operation{
+//		OperationID: "list-things",
+//		Method:      http.MethodGet,
+//		Path:        "/things",
+//	}, func(ctx context.Context, input *ListThingsInput) (*pagination.Page[Thing], error) {
+//		things, next := db.ListThings(input.Cursor, input.Limit)
+//		return pagination.NewPage(input.Params, things, next), nil
+//	})
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ErrInvalidCursor is returned when decoding a cursor which is malformed or
+// has an invalid signature.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Params are the `cursor` & `limit` query params for cursor-based pagination.
+// Embed them in an operation's input struct.
+type Params struct {
+	Cursor string `query:"cursor" doc:"Opaque cursor from a previous page's next link. Omit to get the first page."`
+	Limit  int    `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Maximum number of items to return."`
+
+	// url is the request URL, used to generate links to other pages.
+	url url.URL
+}
+
+func (p *Params) Resolve(ctx huma.Context) []error {
+	p.url = ctx.URL()
+	return nil
+}
+
+// Link returns the relative URL for the page starting at the cursor, keeping
+// the request's other query params. An empty cursor links to the first page.
+func (p Params) Link(cursor string) string {
+	query := p.url.Query()
+	query.Del("cursor")
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	if p.Limit != 0 {
+		query.Set("limit", strconv.Itoa(p.Limit))
+	}
+	link := p.url.Path
+	if encoded := query.Encode(); encoded != "" {
+		link += "?" + encoded
+	}
+	return link
+}
+
+// Items is a page of items with the cursor of the next page.
+type Items[T any] struct {
+	Items []T    `json:"items" doc:"The items on this page."`
+	Next  string `json:"next,omitempty" doc:"Cursor of the next page, if there is one."`
+}
+
+// Page is an output struct for a page of items, with RFC 8288 `Link` headers
+// to the first, current, and next pages.
+type Page[T any] struct {
+	Link string `header:"Link" doc:"Links to the first (rel=first), current (rel=self), and next (rel=next) pages."`
+	Body Items[T]
+}
+
+// NewPage creates a page of items. If there are more items then `next` is the
+// cursor of the next page, otherwise it is empty.
+func NewPage[T any](params Params, items []T, next string) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	links := []string{
+		linkValue(params.Link(params.Cursor), "self"),
+	}
+	if params.Cursor != "" {
+		links = append(links, linkValue(params.Link(""), "first"))
+	}
+	if next != "" {
+		links = append(links, linkValue(params.Link(next), "next"))
+	}
+	return &Page[T]{
+		Link: strings.Join(links, ", "),
+		Body: Items[T]{Items: items, Next: next},
+	}
+}
+
+// linkValue formats a link as described in RFC 8288.
+func linkValue(link, rel string) string {
+	return "<" + link + ">; rel=\"" + rel + "\""
+}
+
+// Codec encodes values like the last item's sort key into opaque cursors.
+// If it has a key, cursors are signed with HMAC-SHA256 so clients can't
+// tamper with them.
+type Codec struct {
+	key []byte
+}
+
+// NewCodec creates a cursor codec which signs cursors with the key, which
+// may be nil to skip signing.
+func NewCodec(key []byte) *Codec {
+	return &Codec{key: key}
+}
+
+// Encode the value as JSON into a URL-safe cursor.
+func (c *Codec) Encode(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	cursor := base64.RawURLEncoding.EncodeToString(b)
+	if c.key != nil {
+		cursor += "." + base64.RawURLEncoding.EncodeToString(c.sign(b))
+	}
+	return cursor, nil
+}
+
+// Decode the cursor into the value, returning `ErrInvalidCursor` if it is
+// malformed or its signature doesn't match.
+//
+//	var after time.Time
+//	if err := codec.Decode(input.Cursor, &after); err != nil {
+//		return nil, huma.Error400BadRequest("invalid cursor", err)
+//	}
+func (c *Codec) Decode(cursor string, v any) error {
+	data, sig, signed := strings.Cut(cursor, ".")
+	if signed != (c.key != nil) {
+		return ErrInvalidCursor
+	}
+	b, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if c.key != nil {
+		s, err := base64.RawURLEncoding.DecodeString(sig)
+		if err != nil || !hmac.Equal(s, c.sign(b)) {
+			return ErrInvalidCursor
+		}
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+func (c *Codec) sign(b []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(b)
+	return mac.Sum(nil)
+}
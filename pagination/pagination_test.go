@@ -0,0 +1,102 @@
+package pagination
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Thing struct {
+	ID int `json:"id"`
+}
+
+func TestPage(t *testing.T) {
+	_, api := humatest.New(t)
+	codec := NewCodec([]byte("secret"))
+
+	things := []Thing{{1}, {2}, {3}, {4}, {5}}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		Params
+		Color string `query:"color"`
+	}) (*Page[Thing], error) {
+		start := 0
+		if input.Cursor != "" {
+			if err := codec.Decode(input.Cursor, &start); err != nil {
+				return nil, huma.Error400BadRequest("invalid cursor", err)
+			}
+		}
+		end := start + input.Limit
+		next := ""
+		if end < len(things) {
+			next, _ = codec.Encode(end)
+		} else {
+			end = len(things)
+		}
+		return NewPage(input.Params, things[start:end], next), nil
+	})
+
+	resp := api.Get("/things?limit=2&color=red")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	page := humatest.Output[Page[Thing]](t, api, resp)
+	assert.Equal(t, []Thing{{1}, {2}}, page.Body.Items)
+	assert.NotEmpty(t, page.Body.Next)
+	next := "/things?color=red&cursor=" + page.Body.Next + "&limit=2"
+	assert.Equal(t, `</things?color=red&limit=2>; rel="self", <`+next+`>; rel="next"`, resp.Header().Get("Link"))
+
+	resp = api.Get(next)
+	page = humatest.Output[Page[Thing]](t, api, resp)
+	assert.Equal(t, []Thing{{3}, {4}}, page.Body.Items)
+	assert.Contains(t, resp.Header().Get("Link"), `</things?color=red&limit=2>; rel="first"`)
+
+	resp = api.Get("/things")
+	page = humatest.Output[Page[Thing]](t, api, resp)
+	assert.Len(t, page.Body.Items, 5)
+	assert.Empty(t, page.Body.Next)
+	assert.Equal(t, `</things?limit=20>; rel="self"`, resp.Header().Get("Link"))
+
+	resp = api.Get("/things?cursor=bad")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = api.Get("/things?limit=1000")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	op := api.OpenAPI().Paths["/things"].Get
+	assert.Equal(t, "cursor", op.Parameters[0].Name)
+	assert.Equal(t, "limit", op.Parameters[1].Name)
+	assert.Contains(t, op.Responses["200"].Headers, "Link")
+	assert.Equal(t, "#/components/schemas/ItemsThing", op.Responses["200"].Content["application/json"].Schema.Ref)
+}
+
+func TestCodec(t *testing.T) {
+	signed := NewCodec([]byte("secret"))
+	cursor, err := signed.Encode(map[string]any{"after": "abc"})
+	assert.NoError(t, err)
+
+	var v map[string]any
+	assert.NoError(t, signed.Decode(cursor, &v))
+	assert.Equal(t, "abc", v["after"])
+
+	// Tampered or differently signed cursors are rejected.
+	assert.ErrorIs(t, signed.Decode("x"+cursor, &v), ErrInvalidCursor)
+	assert.ErrorIs(t, NewCodec([]byte("other")).Decode(cursor, &v), ErrInvalidCursor)
+	assert.ErrorIs(t, NewCodec(nil).Decode(cursor, &v), ErrInvalidCursor)
+
+	unsigned := NewCodec(nil)
+	cursor, err = unsigned.Encode(5)
+	assert.NoError(t, err)
+	assert.NotContains(t, cursor, ".")
+
+	var i int
+	assert.NoError(t, unsigned.Decode(cursor, &i))
+	assert.Equal(t, 5, i)
+	assert.ErrorIs(t, unsigned.Decode("!!!", &i), ErrInvalidCursor)
+}
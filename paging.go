@@ -0,0 +1,60 @@
+package huma
+
+import "strings"
+
+// SortFields is implemented by types listing the fields which a collection
+// can be sorted by, for use with `PagingParams`.
+//
+//	var thingSortFields = []string{"name", "created"}
+//
+//	type ThingSort struct{}
+//
+//	func (ThingSort) SortFields() []string {
+//		return thingSortFields
+//	}
+type SortFields interface {
+	SortFields() []string
+}
+
+// SortBy is a sort param value, which is one of the fields listed by `F`,
+// optionally prefixed by `-` for descending order, like `-created`.
+type SortBy[F SortFields] string
+
+// Field returns the name of the field to sort by.
+func (s SortBy[F]) Field() string {
+	return strings.TrimPrefix(string(s), "-")
+}
+
+// Descending returns whether to sort in descending order.
+func (s SortBy[F]) Descending() bool {
+	return strings.HasPrefix(string(s), "-")
+}
+
+// TransformSchema documents the fields which can be sorted by as an enum.
+func (s *SortBy[F]) TransformSchema(r Registry, schema *Schema) *Schema {
+	var f F
+	for _, field := range f.SortFields() {
+		schema.Enum = append(schema.Enum, field, "-"+field)
+	}
+	schema.PrecomputeMessages()
+	return schema
+}
+
+// PagingParams are the `limit`, `offset`, and `sort` query params for
+// offset-based pagination. Embed them in an operation's input struct, where
+// `F` lists the fields which can be sorted by.
+//
+//	type ListThingsInput struct {
+//		huma.PagingParams[ThingSort]
+//	}
+type PagingParams[F SortFields] struct {
+	Limit  int       `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Maximum number of items to return."`
+	Offset int       `query:"offset" minimum:"0" doc:"Number of items to skip."`
+	Sort   SortBy[F] `query:"sort" doc:"Field to sort by, prefixed with - for descending order."`
+}
+
+// PagingHeaders are response headers for offset-based pagination. Embed them
+// in an operation's output struct.
+type PagingHeaders struct {
+	TotalCount int `header:"X-Total-Count" doc:"Total number of items across all pages."`
+}
@@ -0,0 +1,72 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type PagingThingSort struct{}
+
+func (PagingThingSort) SortFields() []string {
+	return []string{"name", "created"}
+}
+
+func TestPagingParams(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Register(app, Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		PagingParams[PagingThingSort]
+	}) (*struct {
+		PagingHeaders
+		Body []string
+	}, error) {
+		resp := &struct {
+			PagingHeaders
+			Body []string
+		}{}
+		resp.TotalCount = 42
+		resp.Body = []string{input.Sort.Field()}
+		if input.Sort.Descending() {
+			resp.Body = append(resp.Body, "desc")
+		}
+		return resp, nil
+	})
+
+	for _, item := range []struct {
+		url    string
+		status int
+		body   string
+	}{
+		{"/things", http.StatusOK, `[""]`},
+		{"/things?sort=name&limit=5&offset=10", http.StatusOK, `["name"]`},
+		{"/things?sort=-created", http.StatusOK, `["created", "desc"]`},
+		{"/things?sort=color", http.StatusUnprocessableEntity, ""},
+		{"/things?limit=101", http.StatusUnprocessableEntity, ""},
+		{"/things?offset=-1", http.StatusUnprocessableEntity, ""},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, item.url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, item.status, w.Code, item.url)
+		if item.body != "" {
+			assert.JSONEq(t, item.body, w.Body.String(), item.url)
+			assert.Equal(t, "42", w.Header().Get("X-Total-Count"))
+		}
+	}
+
+	op := app.OpenAPI().Paths["/things"].Get
+	assert.Len(t, op.Parameters, 3)
+	assert.Equal(t, []any{"name", "-name", "created", "-created"}, op.Parameters[2].Schema.Enum)
+	assert.Equal(t, 100.0, *op.Parameters[0].Schema.Maximum)
+	assert.Contains(t, op.Responses["200"].Headers, "X-Total-Count")
+}
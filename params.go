@@ -0,0 +1,479 @@
+package huma
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var stringSliceType = reflect.TypeOf([]string{})
+
+// paramParser parses a raw parameter value, sets it on the input struct field
+// and optionally validates it, adding any errors to `res`. Parsers are built
+// once per parameter at registration time so that requests don't need to
+// inspect types, and scalar values are validated without boxing them, which
+// keeps parameter handling allocation-free unless there is an error.
+type paramParser func(f reflect.Value, value string, pb *PathBuffer, res *ValidateResult, validate bool)
+
+// queryParser parses a query parameter which is spread over multiple values or
+// keys, i.e. an exploded array or a `deepObject`, from the parsed query.
+type queryParser func(f reflect.Value, query url.Values, pb *PathBuffer, res *ValidateResult, validate bool)
+
+// listDelimiters are the separators of array values for each query style.
+var listDelimiters = map[string]string{
+	"":               ",",
+	"form":           ",",
+	"simple":         ",",
+	"spaceDelimited": " ",
+	"pipeDelimited":  "|",
+}
+
+// parseQueryOptions parses the options after the name in a `query` tag, like
+// `query:"ids,explode"` or `query:"filter,deepObject"`, into the parameter's
+// OpenAPI style and explode setting. It panics for invalid options or those
+// not supported by the parameter's type.
+func parseQueryOptions(p *paramFieldInfo, options string) {
+	for _, option := range strings.Split(options, ",") {
+		switch option {
+		case "explode":
+			p.Explode = true
+		case "form", "spaceDelimited", "pipeDelimited", "deepObject":
+			if p.Style != "" {
+				panic(fmt.Sprintf("query param %s has multiple styles", p.Name))
+			}
+			p.Style = option
+		default:
+			panic(fmt.Sprintf("unknown option %s for query param %s", option, p.Name))
+		}
+	}
+
+	t := p.Type
+	switch p.Style {
+	case "deepObject":
+		if (t.Kind() != reflect.Struct || t == timeType) && (t.Kind() != reflect.Map || t.Key().Kind() != reflect.String) {
+			panic(fmt.Sprintf("deepObject query param %s must be a struct or map with string keys", p.Name))
+		}
+		// Objects are always exploded into one key per property.
+		p.Explode = true
+	case "spaceDelimited", "pipeDelimited":
+		if p.Explode {
+			panic(fmt.Sprintf("%s query param %s cannot be exploded", p.Style, p.Name))
+		}
+		fallthrough
+	default:
+		if t.Kind() != reflect.Slice {
+			panic(fmt.Sprintf("query param %s must be a slice to set style or explode", p.Name))
+		}
+		if p.Style == "" {
+			p.Style = "form"
+		}
+		if d := listDelimiters[p.Style]; d != "," {
+			// Defaults are comma-separated like other array tags.
+			p.Default = strings.ReplaceAll(p.Default, ",", d)
+		}
+	}
+}
+
+// newParamParser returns the parser for the parameter field `p`. It
+// panics for unsupported types so mistakes are caught at startup.
+func newParamParser(registry Registry, p *paramFieldInfo) paramParser {
+	s := p.Schema
+	for s.Ref != "" {
+		s = registry.SchemaFromRef(s.Ref)
+	}
+
+	t := p.Type
+	if elem := optionalElem(t); elem != nil {
+		// Parse the wrapped value, then record that it was sent.
+		vp := *p
+		vp.Type = elem
+		parse := newParamParser(registry, &vp)
+		return func(f reflect.Value, value string, pb *PathBuffer, res *ValidateResult, validate bool) {
+			parse(f.Field(0), value, pb, res, validate)
+			f.Field(1).SetBool(true)
+		}
+	}
+
+	if t == durationType && s.Format == "duration" {
+		return func(f reflect.Value, value string, pb *PathBuffer, res *ValidateResult, validate bool) {
+			d, err := parseParamDuration(value)
+			if err != nil {
+				res.Add(pb, value, message("invalidDuration"))
+				return
+			}
+			f.SetInt(int64(d))
+		}
+	}
+
+	if t != timeType && reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return func(f reflect.Value, value string, pb *PathBuffer, res *ValidateResult, validate bool) {
+			if validate {
+				// Validate first so e.g. the format error is used if it doesn't parse.
+				count := len(res.Errors)
+				validateString(pb, s, value, value, res)
+				if len(res.Errors) > count {
+					return
+				}
+			}
+			if err := f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+				res.Add(pb, value, message("invalidValue")+": "+err.Error())
+			}
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(f reflect.Value, value string, pb *PathBuffer, res *ValidateResult, validate bool) {
+			f.SetString(value)
+			if validate {
+				validateString(pb, s, value, value, res)
+				if len(s.Enum) > 0 && !enumHas(s.Enum, value) {
+					res.Add(pb, value, s.msgEnum)
+				}
+				if c, ok := s.constValue.(string); ok && c != value {
+					res.Add(pb, value, s.msgConst)
+				}
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		enum := numericEnum(s.Enum)
+		return func(f reflect.Value, value string, pb *PathBuffer, res *ValidateResult, validate bool) {
+			v, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || f.OverflowInt(v) {
+				res.Add(pb, value, message("invalidInteger"))
+				return
+			}
+			f.SetInt(v)
+			if validate {
+				validateParamNumber(pb, s, enum, float64(v), v, res)
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		enum := numericEnum(s.Enum)
+		return func(f reflect.Value, value string, pb *PathBuffer, res *ValidateResult, validate bool) {
+			v, err := strconv.ParseUint(value, 10, 64)
+			if err != nil || f.OverflowUint(v) {
+				res.Add(pb, value, message("invalidInteger"))
+				return
+			}
+			f.SetUint(v)
+			if validate {
+				validateParamNumber(pb, s, enum, float64(v), v, res)
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		enum := numericEnum(s.Enum)
+		return func(f reflect.Value, value string, pb *PathBuffer, res *ValidateResult, validate bool) {
+			v, err := strconv.ParseFloat(value, t.Bits())
+			if err != nil {
+				res.Add(pb, value, message("invalidFloat"))
+				return
+			}
+			f.SetFloat(v)
+			if validate {
+				validateParamNumber(pb, s, enum, v, v, res)
+			}
+		}
+	case reflect.Bool:
+		return func(f reflect.Value, value string, pb *PathBuffer, res *ValidateResult, validate bool) {
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				res.Add(pb, value, message("invalidBoolean"))
+				return
+			}
+			f.SetBool(v)
+			if !validate {
+				return
+			}
+			if len(s.Enum) > 0 && !enumHas(s.Enum, v) {
+				res.Add(pb, v, s.msgEnum)
+			}
+			if c, ok := s.constValue.(bool); ok && c != v {
+				res.Add(pb, v, s.msgConst)
+			}
+		}
+	case reflect.Slice:
+		if !stringSliceType.ConvertibleTo(t) {
+			break
+		}
+		list := newListParser(registry, s, t)
+		delimiter := listDelimiters[p.Style]
+		header := p.Loc == "header"
+		return func(f reflect.Value, value string, pb *PathBuffer, res *ValidateResult, validate bool) {
+			values := strings.Split(value, delimiter)
+			if header {
+				for i := range values {
+					// Header lists may have optional whitespace after the commas.
+					values[i] = strings.TrimSpace(values[i])
+				}
+			}
+			list(f, values, pb, res, validate)
+		}
+	case reflect.Struct:
+		if t != timeType {
+			break
+		}
+		timeFormat := time.RFC3339Nano
+		if p.Loc == "header" {
+			timeFormat = http.TimeFormat
+		}
+		if p.TimeFormat != "" {
+			timeFormat = p.TimeFormat
+		}
+		return func(f reflect.Value, value string, pb *PathBuffer, res *ValidateResult, validate bool) {
+			// Parsing with the expected format is the validation.
+			v, err := time.Parse(timeFormat, value)
+			if err != nil {
+				res.Add(pb, value, message("invalidTime"))
+				return
+			}
+			f.Set(reflect.ValueOf(v))
+		}
+	}
+
+	panic("unsupported param type " + t.String())
+}
+
+// newListParser returns the parser for the values of an array parameter of
+// type `t`, which must be convertible from `[]string`.
+func newListParser(registry Registry, s *Schema, t reflect.Type) func(f reflect.Value, values []string, pb *PathBuffer, res *ValidateResult, validate bool) {
+	return func(f reflect.Value, values []string, pb *PathBuffer, res *ValidateResult, validate bool) {
+		f.Set(reflect.ValueOf(values).Convert(t))
+		if validate {
+			// Validation works on generic `[]any` like decoded JSON arrays.
+			pvs := make([]any, len(values))
+			for i := range values {
+				pvs[i] = values[i]
+			}
+			Validate(registry, s, pb, ModeWriteToServer, pvs, res)
+		}
+	}
+}
+
+// newQueryParser returns the parser for an exploded array or `deepObject`
+// query parameter, or nil if the parameter is a single value.
+func newQueryParser(registry Registry, p *paramFieldInfo) queryParser {
+	if !p.Explode {
+		return nil
+	}
+
+	s := p.Schema
+	for s.Ref != "" {
+		s = registry.SchemaFromRef(s.Ref)
+	}
+
+	t := p.Type
+	if p.Style == "form" {
+		// Exploded arrays repeat the parameter, e.g. `?id=1&id=2`.
+		if !stringSliceType.ConvertibleTo(t) {
+			panic("unsupported param type " + t.String())
+		}
+		list := newListParser(registry, s, t)
+		var defaults []string
+		if p.Default != "" {
+			defaults = strings.Split(p.Default, ",")
+		}
+		return func(f reflect.Value, query url.Values, pb *PathBuffer, res *ValidateResult, validate bool) {
+			values := query[p.Name]
+			if len(values) == 0 {
+				if defaults == nil {
+					return
+				}
+				values = append([]string{}, defaults...)
+			}
+			list(f, values, pb, res, validate)
+		}
+	}
+
+	if t.Kind() == reflect.Map {
+		// Maps accept any keys, e.g. `?labels[env]=prod`.
+		elemSchema, _ := s.AdditionalProperties.(*Schema)
+		elem := newParamParser(registry, &paramFieldInfo{Type: t.Elem(), Loc: p.Loc, Schema: elemSchema})
+		return func(f reflect.Value, query url.Values, pb *PathBuffer, res *ValidateResult, validate bool) {
+			var m reflect.Value
+			for key, values := range query {
+				k, ok := deepObjectKey(p.Name, key)
+				if !ok {
+					continue
+				}
+				if !m.IsValid() {
+					m = reflect.MakeMap(t)
+				}
+				item := reflect.New(t.Elem()).Elem()
+				pb.Push(k)
+				elem(item, values[0], pb, res, validate)
+				pb.Pop()
+				m.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), item)
+			}
+			if m.IsValid() {
+				f.Set(m)
+			}
+		}
+	}
+
+	// Structs set their fields by property name, e.g. `?filter[name]=foo`.
+	type deepField struct {
+		index int
+		key   string
+		name  string
+		parse paramParser
+	}
+	fields := []deepField{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		if j := sf.Tag.Get("json"); j != "" {
+			name = strings.Split(j, ",")[0]
+		}
+		ps := s.Properties[name]
+		if ps == nil {
+			continue
+		}
+		fields = append(fields, deepField{
+			index: i,
+			key:   p.Name + "[" + name + "]",
+			name:  name,
+			parse: newParamParser(registry, &paramFieldInfo{Type: sf.Type, Loc: p.Loc, Schema: ps, TimeFormat: sf.Tag.Get("timeFormat")}),
+		})
+	}
+	return func(f reflect.Value, query url.Values, pb *PathBuffer, res *ValidateResult, validate bool) {
+		found := false
+		for _, df := range fields {
+			if values := query[df.key]; len(values) > 0 {
+				found = true
+				pb.Push(df.name)
+				df.parse(f.Field(df.index), values[0], pb, res, validate)
+				pb.Pop()
+			}
+		}
+		if !found || !validate {
+			return
+		}
+		for _, df := range fields {
+			if s.requiredMap[df.name] && len(query[df.key]) == 0 {
+				res.Add(pb, nil, s.msgRequired[df.name])
+			}
+		}
+		if addl, ok := s.AdditionalProperties.(bool); ok && !addl {
+			for key, values := range query {
+				if k, ok := deepObjectKey(p.Name, key); ok && s.Properties[k] == nil {
+					pb.Push(k)
+					res.Add(pb, values[0], message("unexpectedProperty"))
+					pb.Pop()
+				}
+			}
+		}
+	}
+}
+
+// deepObjectKey returns the property name from a `deepObject` query key like
+// `filter[name]` for the parameter `filter`.
+func deepObjectKey(name, key string) (string, bool) {
+	k, ok := strings.CutPrefix(key, name+"[")
+	if !ok || !strings.HasSuffix(k, "]") {
+		return "", false
+	}
+	return k[:len(k)-1], true
+}
+
+// paramItemConstraints moves string constraints like `pattern` from an array
+// parameter's schema to its items, since they describe the individual values.
+// Enums are already set on the items.
+func paramItemConstraints(s *Schema) {
+	items := s.Items
+	if s.Type != TypeArray || items == nil || items.Type != TypeString {
+		return
+	}
+	if s.Pattern == "" && s.MinLength == nil && s.MaxLength == nil && s.Format == "" {
+		return
+	}
+	if s.Pattern != "" {
+		items.Pattern, s.Pattern = s.Pattern, ""
+	}
+	if s.MinLength != nil {
+		items.MinLength, s.MinLength = s.MinLength, nil
+	}
+	if s.MaxLength != nil {
+		items.MaxLength, s.MaxLength = s.MaxLength, nil
+	}
+	if s.Format != "" {
+		items.Format, s.Format = s.Format, ""
+	}
+	items.PrecomputeMessages()
+}
+
+// headerValues returns all values of a header joined by commas, which is
+// equivalent to sending them as a single comma-separated header.
+func headerValues(ctx Context, name string) string {
+	value := ""
+	ctx.EachHeader(func(n, v string) {
+		if !strings.EqualFold(n, name) {
+			return
+		}
+		if value != "" {
+			value += ","
+		}
+		value += v
+	})
+	return value
+}
+
+// validateParamNumber validates a parsed numeric parameter. Enum values are
+// compared by their float64 value since the tag values use the field's type.
+func validateParamNumber[T int64 | uint64 | float64](pb *PathBuffer, s *Schema, enum []float64, num float64, v T, res *ValidateResult) {
+	validateNumber(pb, s, num, v, res)
+	if len(enum) > 0 {
+		found := false
+		for _, e := range enum {
+			if e == num {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res.Add(pb, v, s.msgEnum)
+		}
+	}
+	if c, ok := s.constValue.(float64); ok && c != num {
+		res.Add(pb, v, s.msgConst)
+	}
+}
+
+// numericEnum converts the enum values of a numeric schema to float64 for
+// comparison with parsed parameter values.
+func numericEnum(enum []any) []float64 {
+	if len(enum) == 0 {
+		return nil
+	}
+	nums := make([]float64, 0, len(enum))
+	for _, e := range enum {
+		v := reflect.ValueOf(e)
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			nums = append(nums, float64(v.Int()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			nums = append(nums, float64(v.Uint()))
+		case reflect.Float32, reflect.Float64:
+			nums = append(nums, v.Float())
+		}
+	}
+	return nums
+}
+
+// enumHas returns whether the enum contains `v` without boxing it.
+func enumHas[T string | bool](enum []any, v T) bool {
+	for _, e := range enum {
+		if ev, ok := e.(T); ok && ev == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,493 @@
+// Package protoschema bridges protobuf messages and Huma schemas, so services
+// exposing both gRPC and REST APIs have a single source of truth for their
+// payloads. Schemas describe messages as marshaled by `protojson`.
+package protoschema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/danielgtaylor/casing"
+	"github.com/danielgtaylor/huma/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	// Well-known types used by generated descriptors.
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// FieldNumberExtension sets the field number of a property when generating a
+// message descriptor via `Descriptor`, e.g. in a schema transformer.
+const FieldNumberExtension = "x-proto-field"
+
+var messageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// wrappers maps the well-known wrapper messages to their value's kind.
+var wrappers = map[protoreflect.FullName]descriptorpb.FieldDescriptorProto_Type{
+	"google.protobuf.DoubleValue": descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	"google.protobuf.FloatValue":  descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+	"google.protobuf.Int64Value":  descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	"google.protobuf.UInt64Value": descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	"google.protobuf.Int32Value":  descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	"google.protobuf.UInt32Value": descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	"google.protobuf.BoolValue":   descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"google.protobuf.StringValue": descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"google.protobuf.BytesValue":  descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+}
+
+// TypeSchema generates schemas for generated protobuf message types. Add it
+// to `huma.TypeSchemas` so operations can use messages as their bodies:
+//
+//	huma.TypeSchemas = append(huma.TypeSchemas, protoschema.TypeSchema)
+//
+// Messages must be marshaled via `protojson` to match their schemas, so use
+// formats based on it for the API.
+func TypeSchema(r huma.Registry, t reflect.Type) *huma.Schema {
+	if !reflect.PointerTo(t).Implements(messageType) {
+		return nil
+	}
+	msg := reflect.New(t).Interface().(proto.Message)
+	return Schema(r, msg.ProtoReflect().Descriptor())
+}
+
+// Schema returns the schema of a protobuf message. Fields use their JSON
+// names, and values are described as marshaled by `protojson`, e.g. 64-bit
+// integers are strings and enums are their value names. Nested messages with
+// a generated Go type are added to the registry, while other nested messages
+// are inlined. Well-known types like `google.protobuf.Timestamp` use their
+// JSON representation.
+func Schema(r huma.Registry, md protoreflect.MessageDescriptor) *huma.Schema {
+	return messageSchema(r, md, map[protoreflect.FullName]bool{}, false)
+}
+
+// messageSchema returns the schema of a message, which is a ref if the message
+// has a generated Go type and `allowRef` is set.
+func messageSchema(r huma.Registry, md protoreflect.MessageDescriptor, visiting map[protoreflect.FullName]bool, allowRef bool) *huma.Schema {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return &huma.Schema{Type: huma.TypeString, Format: "date-time"}
+	case "google.protobuf.Duration":
+		return &huma.Schema{Type: huma.TypeString, Pattern: `^-?[0-9]+(\.[0-9]+)?s$`}
+	case "google.protobuf.FieldMask":
+		return &huma.Schema{Type: huma.TypeString}
+	case "google.protobuf.Struct":
+		return &huma.Schema{Type: huma.TypeObject, AdditionalProperties: true}
+	case "google.protobuf.Value":
+		return &huma.Schema{}
+	case "google.protobuf.ListValue":
+		return &huma.Schema{Type: huma.TypeArray, Items: &huma.Schema{}}
+	case "google.protobuf.Any":
+		s := &huma.Schema{
+			Type:                 huma.TypeObject,
+			Properties:           map[string]*huma.Schema{"@type": {Type: huma.TypeString}},
+			Required:             []string{"@type"},
+			AdditionalProperties: true,
+		}
+		s.PrecomputeMessages()
+		return s
+	}
+	if _, ok := wrappers[md.FullName()]; ok {
+		s := valueSchema(r, md.Fields().ByName("value"), visiting)
+		s.Nullable = true
+		return s
+	}
+
+	if allowRef {
+		if mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName()); err == nil {
+			return r.Schema(reflect.TypeOf(mt.Zero().Interface()), true, string(md.Name()))
+		}
+	}
+	if visiting[md.FullName()] {
+		// Recursive messages without a Go type can't be referenced.
+		return &huma.Schema{Type: huma.TypeObject}
+	}
+	visiting[md.FullName()] = true
+	defer delete(visiting, md.FullName())
+
+	s := &huma.Schema{
+		Type:                 huma.TypeObject,
+		Properties:           map[string]*huma.Schema{},
+		AdditionalProperties: false,
+	}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		s.Properties[fd.JSONName()] = fieldSchema(r, fd, visiting)
+		if fd.Cardinality() == protoreflect.Required {
+			s.Required = append(s.Required, fd.JSONName())
+		}
+	}
+	s.PrecomputeMessages()
+	return s
+}
+
+// fieldSchema returns the schema of a field, including lists & maps.
+func fieldSchema(r huma.Registry, fd protoreflect.FieldDescriptor, visiting map[protoreflect.FullName]bool) *huma.Schema {
+	var s *huma.Schema
+	switch {
+	case fd.IsMap():
+		s = &huma.Schema{
+			Type:                 huma.TypeObject,
+			AdditionalProperties: valueSchema(r, fd.MapValue(), visiting),
+		}
+	case fd.IsList():
+		s = &huma.Schema{Type: huma.TypeArray, Items: valueSchema(r, fd, visiting)}
+	default:
+		return valueSchema(r, fd, visiting)
+	}
+	s.PrecomputeMessages()
+	return s
+}
+
+// valueSchema returns the schema of a single value of the field.
+func valueSchema(r huma.Registry, fd protoreflect.FieldDescriptor, visiting map[protoreflect.FullName]bool) *huma.Schema {
+	var s *huma.Schema
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		s = &huma.Schema{Type: huma.TypeBoolean}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		s = &huma.Schema{Type: huma.TypeInteger, Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		min, max := 0.0, float64(math.MaxUint32)
+		s = &huma.Schema{Type: huma.TypeInteger, Format: "int64", Minimum: &min, Maximum: &max}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		s = &huma.Schema{Type: huma.TypeString, Format: "int64", Pattern: `^-?[0-9]+$`}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		s = &huma.Schema{Type: huma.TypeString, Format: "uint64", Pattern: `^[0-9]+$`}
+	case protoreflect.FloatKind:
+		s = &huma.Schema{Type: huma.TypeNumber, Format: "float"}
+	case protoreflect.DoubleKind:
+		s = &huma.Schema{Type: huma.TypeNumber, Format: "double"}
+	case protoreflect.StringKind:
+		s = &huma.Schema{Type: huma.TypeString}
+	case protoreflect.BytesKind:
+		s = &huma.Schema{Type: huma.TypeString, ContentEncoding: "base64"}
+	case protoreflect.EnumKind:
+		if fd.Enum().FullName() == "google.protobuf.NullValue" {
+			return &huma.Schema{Nullable: true}
+		}
+		s = &huma.Schema{Type: huma.TypeString}
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			s.Enum = append(s.Enum, string(values.Get(i).Name()))
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageSchema(r, fd.Message(), visiting, true)
+	}
+	s.PrecomputeMessages()
+	return s
+}
+
+// Descriptor returns a proto3 message descriptor for an object schema, so a
+// Huma API can also be served via gRPC or its messages stored as protobuf,
+// e.g. via `dynamicpb`. The message and any others it needs are created in a
+// new file in the name's package. Referenced object schemas become messages
+// named after the schema, while inline objects become messages named after
+// their parent and property.
+//
+// Fields use the property name as their JSON name. Field numbers are set by
+// the `x-proto-field` extension of each property, otherwise properties are
+// numbered in alphabetical order after the highest set field number. Set
+// field numbers for APIs whose messages are persisted or sent between
+// services, as adding properties may change the generated numbers.
+//
+// Types are mapped to their closest protobuf type:
+//
+//   - Nullable scalars use wrappers like `google.protobuf.StringValue`.
+//   - Strings with the `date-time` format use `google.protobuf.Timestamp`.
+//   - Objects with only additional properties are maps, and other objects
+//     without properties use `google.protobuf.Struct`.
+//   - Schemas without a type use `google.protobuf.Value`.
+//
+// An error is returned for schemas which can't be described by protobuf,
+// such as nested arrays.
+func Descriptor(r huma.Registry, s *huma.Schema, name protoreflect.FullName) (protoreflect.MessageDescriptor, error) {
+	if !name.IsValid() || name.Parent() == "" {
+		return nil, fmt.Errorf("invalid message name %q, expected a package like pkg.Message", name)
+	}
+	b := &builder{
+		r: r,
+		file: &descriptorpb.FileDescriptorProto{
+			Name:    proto.String(strings.ReplaceAll(string(name), ".", "/") + ".proto"),
+			Package: proto.String(string(name.Parent())),
+			Syntax:  proto.String("proto3"),
+		},
+		pkg:  string(name.Parent()),
+		refs: map[string]string{},
+		deps: map[string]bool{},
+	}
+	if s != nil && s.Ref != "" {
+		b.refs[s.Ref] = string(name.Name())
+	}
+	s = b.resolve(s)
+	if s == nil || s.Type != huma.TypeObject {
+		return nil, fmt.Errorf("%s: only object schemas can be messages", name)
+	}
+	if err := b.message(s, string(name.Name())); err != nil {
+		return nil, err
+	}
+	for dep := range b.deps {
+		b.file.Dependency = append(b.file.Dependency, dep)
+	}
+	sort.Strings(b.file.Dependency)
+
+	fd, err := protodesc.NewFile(b.file, protoregistry.GlobalFiles)
+	if err != nil {
+		return nil, err
+	}
+	return fd.Messages().ByName(name.Name()), nil
+}
+
+// builder creates the messages of a file descriptor from schemas.
+type builder struct {
+	r    huma.Registry
+	file *descriptorpb.FileDescriptorProto
+	pkg  string
+
+	// refs maps schema refs to their message names.
+	refs map[string]string
+
+	// deps are the imported files of well-known types.
+	deps map[string]bool
+}
+
+func (b *builder) resolve(s *huma.Schema) *huma.Schema {
+	for s != nil && s.Ref != "" {
+		s = b.r.SchemaFromRef(s.Ref)
+	}
+	return s
+}
+
+// wellKnown returns the type name of a well-known message, importing its file.
+func (b *builder) wellKnown(file string, name string) *string {
+	b.deps["google/protobuf/"+file+".proto"] = true
+	return proto.String(".google.protobuf." + name)
+}
+
+// message adds a top-level message for the object schema.
+func (b *builder) message(s *huma.Schema, name string) error {
+	msg := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+	b.file.MessageType = append(b.file.MessageType, msg)
+
+	props := make([]string, 0, len(s.Properties))
+	for prop := range s.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	numbers := map[string]int32{}
+	next := int32(1)
+	for _, prop := range props {
+		if n, ok := fieldNumber(s.Properties[prop]); ok {
+			numbers[prop] = n
+			if n >= next {
+				next = n + 1
+			}
+		}
+	}
+
+	for _, prop := range props {
+		number, ok := numbers[prop]
+		if !ok {
+			number = next
+			next++
+		}
+		field := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(fieldName(prop)),
+			JsonName: proto.String(prop),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+		if err := b.field(msg, field, s.Properties[prop], name+casing.Camel(fieldName(prop))); err != nil {
+			return fmt.Errorf("%s.%s: %w", name, prop, err)
+		}
+		msg.Field = append(msg.Field, field)
+	}
+	return nil
+}
+
+// field sets the type of the field for the property's schema. Inline objects
+// become messages with the given name.
+func (b *builder) field(msg *descriptorpb.DescriptorProto, field *descriptorpb.FieldDescriptorProto, prop *huma.Schema, name string) error {
+	s := b.resolve(prop)
+	if s == nil {
+		return fmt.Errorf("unknown schema ref %s", prop.Ref)
+	}
+	switch {
+	case s.Type == huma.TypeArray:
+		items := b.resolve(s.Items)
+		if items != nil && (items.Type == huma.TypeArray || isMap(items)) {
+			return fmt.Errorf("nested arrays & maps are not supported")
+		}
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		if s.Items == nil {
+			return b.value(field, &huma.Schema{}, name, false)
+		}
+		return b.value(field, s.Items, name, false)
+	case isMap(s):
+		value := b.resolve(s.AdditionalProperties.(*huma.Schema))
+		if value == nil || value.Type == huma.TypeArray || isMap(value) {
+			return fmt.Errorf("nested arrays & maps are not supported")
+		}
+		entry := &descriptorpb.DescriptorProto{
+			Name: proto.String(casing.Camel(field.GetName()) + "Entry"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("key"),
+				JsonName: proto.String("key"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			}, {
+				Name:     proto.String("value"),
+				JsonName: proto.String("value"),
+				Number:   proto.Int32(2),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			}},
+			Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+		}
+		if err := b.value(entry.Field[1], s.AdditionalProperties.(*huma.Schema), name+"Value", false); err != nil {
+			return err
+		}
+		msg.NestedType = append(msg.NestedType, entry)
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+		field.TypeName = proto.String("." + b.pkg + "." + msg.GetName() + "." + entry.GetName())
+		return nil
+	}
+	return b.value(field, prop, name, true)
+}
+
+// value sets the type of a single value of the field. Nullable scalars use
+// wrapper messages if `allowWrapper` is set.
+func (b *builder) value(field *descriptorpb.FieldDescriptorProto, prop *huma.Schema, name string, allowWrapper bool) error {
+	s := b.resolve(prop)
+	if s == nil {
+		return fmt.Errorf("unknown schema ref %s", prop.Ref)
+	}
+
+	var typ descriptorpb.FieldDescriptorProto_Type
+	switch s.Type {
+	case huma.TypeBoolean:
+		typ = descriptorpb.FieldDescriptorProto_TYPE_BOOL
+	case huma.TypeInteger:
+		switch s.Format {
+		case "int32":
+			typ = descriptorpb.FieldDescriptorProto_TYPE_INT32
+		case "uint32":
+			typ = descriptorpb.FieldDescriptorProto_TYPE_UINT32
+		case "uint64":
+			typ = descriptorpb.FieldDescriptorProto_TYPE_UINT64
+		default:
+			typ = descriptorpb.FieldDescriptorProto_TYPE_INT64
+		}
+	case huma.TypeNumber:
+		typ = descriptorpb.FieldDescriptorProto_TYPE_DOUBLE
+		if s.Format == "float" {
+			typ = descriptorpb.FieldDescriptorProto_TYPE_FLOAT
+		}
+	case huma.TypeString:
+		switch {
+		case s.Format == "date-time":
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+			field.TypeName = b.wellKnown("timestamp", "Timestamp")
+			return nil
+		case s.Format == "int64":
+			typ = descriptorpb.FieldDescriptorProto_TYPE_INT64
+		case s.Format == "uint64":
+			typ = descriptorpb.FieldDescriptorProto_TYPE_UINT64
+		case s.Format == "byte" || s.ContentEncoding == "base64":
+			typ = descriptorpb.FieldDescriptorProto_TYPE_BYTES
+		default:
+			typ = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		}
+	case huma.TypeObject:
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+		if len(s.Properties) == 0 {
+			if s.AdditionalProperties == false {
+				field.TypeName = b.wellKnown("empty", "Empty")
+			} else {
+				field.TypeName = b.wellKnown("struct", "Struct")
+			}
+			return nil
+		}
+		msgName := name
+		if prop.Ref != "" {
+			// Referenced schemas are shared by every field using them.
+			if existing, ok := b.refs[prop.Ref]; ok {
+				field.TypeName = proto.String("." + b.pkg + "." + existing)
+				return nil
+			}
+			msgName = prop.Ref[strings.LastIndex(prop.Ref, "/")+1:]
+			b.refs[prop.Ref] = msgName
+		}
+		field.TypeName = proto.String("." + b.pkg + "." + msgName)
+		return b.message(s, msgName)
+	case "":
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+		field.TypeName = b.wellKnown("struct", "Value")
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %s", s.Type)
+	}
+
+	if allowWrapper && s.Nullable {
+		for wrapper, t := range wrappers {
+			if t == typ {
+				field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+				field.TypeName = b.wellKnown("wrappers", string(wrapper.Name()))
+				return nil
+			}
+		}
+	}
+	field.Type = typ.Enum()
+	return nil
+}
+
+// isMap returns whether the schema is an object with only additional
+// properties, which is described by a protobuf map.
+func isMap(s *huma.Schema) bool {
+	_, ok := s.AdditionalProperties.(*huma.Schema)
+	return s.Type == huma.TypeObject && len(s.Properties) == 0 && ok
+}
+
+// fieldNumber returns the number set by the property's `x-proto-field`
+// extension.
+func fieldNumber(s *huma.Schema) (int32, bool) {
+	switch n := s.Extensions[FieldNumberExtension].(type) {
+	case int:
+		return int32(n), true
+	case int32:
+		return n, true
+	case int64:
+		return int32(n), true
+	case uint64:
+		return int32(n), true
+	case float64:
+		return int32(n), true
+	}
+	return 0, false
+}
+
+// fieldName returns the snake case field name for a property.
+func fieldName(prop string) string {
+	name := []rune(casing.Snake(prop))
+	for i, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || unicode.IsDigit(name[0]) {
+		name = append([]rune{'_'}, name...)
+	}
+	return string(name)
+}
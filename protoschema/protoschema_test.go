@@ -0,0 +1,180 @@
+package protoschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/examples/protodemo/protodemo"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func validate(t *testing.T, r huma.Registry, s *huma.Schema, data []byte) []string {
+	t.Helper()
+	var v any
+	assert.NoError(t, json.Unmarshal(data, &v))
+	res := &huma.ValidateResult{}
+	huma.Validate(r, s, huma.NewPathBuffer([]byte{}, 0), huma.ModeReadFromServer, v, res)
+	errs := []string{}
+	for _, err := range res.Errors {
+		errs = append(errs, err.Error())
+	}
+	return errs
+}
+
+func TestTypeSchema(t *testing.T) {
+	huma.TypeSchemas = append(huma.TypeSchemas, TypeSchema)
+	defer func() { huma.TypeSchemas = huma.TypeSchemas[:len(huma.TypeSchemas)-1] }()
+
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	ref := r.Schema(reflect.TypeOf(&protodemo.User{}), true, "")
+	assert.Equal(t, "#/components/schemas/User", ref.Ref)
+
+	b, _ := json.Marshal(r.Map()["User"])
+	assert.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"id": {"type": "string"},
+			"name": {"type": "string"},
+			"email": {"type": "string"},
+			"password": {"type": "string"},
+			"updated": {"type": "string", "format": "date-time"},
+			"roles": {"type": "array", "items": {"type": "string"}}
+		}
+	}`, string(b))
+
+	// Messages marshaled via protojson match their schema.
+	data, _ := protojson.Marshal(&protodemo.User{Id: "abc", Roles: []string{"admin"}, Updated: timestamppb.Now()})
+	assert.Empty(t, validate(t, r, ref, data))
+	assert.NotEmpty(t, validate(t, r, ref, []byte(`{"unknown": true}`)))
+
+	// Types which aren't messages are left to the default behavior.
+	assert.Nil(t, TypeSchema(r, reflect.TypeOf(struct{}{})))
+}
+
+func TestSchema(t *testing.T) {
+	huma.TypeSchemas = append(huma.TypeSchemas, TypeSchema)
+	defer func() { huma.TypeSchemas = huma.TypeSchemas[:len(huma.TypeSchemas)-1] }()
+
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	s := Schema(r, (&typepb.Type{}).ProtoReflect().Descriptor())
+
+	assert.Equal(t, huma.TypeObject, s.Type)
+	assert.Equal(t, []any{"SYNTAX_PROTO2", "SYNTAX_PROTO3"}, s.Properties["syntax"].Enum)
+	assert.Equal(t, huma.TypeArray, s.Properties["oneofs"].Type)
+	assert.Equal(t, "#/components/schemas/Field", s.Properties["fields"].Items.Ref)
+	assert.Equal(t, "int32", r.Map()["Field"].Properties["number"].Format)
+	assert.Equal(t, "#/components/schemas/Option", r.Map()["Field"].Properties["options"].Items.Ref)
+
+	// The `Any` value of options uses its JSON representation.
+	assert.Equal(t, []string{"@type"}, r.Map()["Option"].Properties["value"].Required)
+
+	data, err := protojson.Marshal(&typepb.Type{
+		Name:   "Thing",
+		Fields: []*typepb.Field{{Name: "id", Number: 1, Kind: typepb.Field_TYPE_STRING}},
+		Syntax: typepb.Syntax_SYNTAX_PROTO3,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, validate(t, r, s, data))
+}
+
+func TestDescriptor(t *testing.T) {
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	r.Map()["Tag"] = &huma.Schema{
+		Type: huma.TypeObject,
+		Properties: map[string]*huma.Schema{
+			"name":   {Type: huma.TypeString},
+			"parent": {Ref: "#/components/schemas/Tag"},
+		},
+	}
+	s := &huma.Schema{
+		Type: huma.TypeObject,
+		Properties: map[string]*huma.Schema{
+			"id":       {Type: huma.TypeString, Extensions: map[string]any{FieldNumberExtension: 5}},
+			"count":    {Type: huma.TypeInteger, Format: "int32"},
+			"total":    {Type: huma.TypeString, Format: "int64"},
+			"score":    {Type: huma.TypeNumber, Nullable: true},
+			"created":  {Type: huma.TypeString, Format: "date-time"},
+			"data":     {Type: huma.TypeString, ContentEncoding: "base64"},
+			"tags":     {Type: huma.TypeArray, Items: &huma.Schema{Ref: "#/components/schemas/Tag"}},
+			"primary":  {Ref: "#/components/schemas/Tag"},
+			"labels":   {Type: huma.TypeObject, AdditionalProperties: &huma.Schema{Type: huma.TypeString}},
+			"metadata": {Type: huma.TypeObject, AdditionalProperties: true},
+			"extra":    {},
+			"owner-info": {
+				Type:       huma.TypeObject,
+				Properties: map[string]*huma.Schema{"name": {Type: huma.TypeString}},
+			},
+		},
+	}
+
+	md, err := Descriptor(r, s, "things.v1.Thing")
+	assert.NoError(t, err)
+	assert.Equal(t, protoreflect.FullName("things.v1.Thing"), md.FullName())
+
+	fields := md.Fields()
+	assert.Equal(t, protoreflect.FieldNumber(5), fields.ByJSONName("id").Number())
+	assert.Equal(t, protoreflect.FieldNumber(6), fields.ByJSONName("count").Number())
+	assert.Equal(t, protoreflect.Int32Kind, fields.ByJSONName("count").Kind())
+	assert.Equal(t, protoreflect.Int64Kind, fields.ByJSONName("total").Kind())
+	assert.Equal(t, protoreflect.FullName("google.protobuf.DoubleValue"), fields.ByJSONName("score").Message().FullName())
+	assert.Equal(t, protoreflect.FullName("google.protobuf.Timestamp"), fields.ByJSONName("created").Message().FullName())
+	assert.Equal(t, protoreflect.BytesKind, fields.ByJSONName("data").Kind())
+	assert.True(t, fields.ByJSONName("tags").IsList())
+	assert.Equal(t, protoreflect.FullName("things.v1.Tag"), fields.ByJSONName("tags").Message().FullName())
+	assert.Equal(t, protoreflect.FullName("things.v1.Tag"), fields.ByJSONName("primary").Message().FullName())
+	assert.Equal(t, protoreflect.FullName("things.v1.Tag"), fields.ByJSONName("primary").Message().Fields().ByName("parent").Message().FullName())
+	assert.True(t, fields.ByJSONName("labels").IsMap())
+	assert.Equal(t, protoreflect.FullName("google.protobuf.Struct"), fields.ByJSONName("metadata").Message().FullName())
+	assert.Equal(t, protoreflect.FullName("google.protobuf.Value"), fields.ByJSONName("extra").Message().FullName())
+	assert.Equal(t, protoreflect.Name("owner_info"), fields.ByJSONName("owner-info").Name())
+	assert.Equal(t, protoreflect.FullName("things.v1.ThingOwnerInfo"), fields.ByJSONName("owner-info").Message().FullName())
+
+	// Messages use the same JSON as the schema.
+	data := `{"id": "abc", "count": 1, "total": "2", "score": null, "created": "2023-01-01T00:00:00Z", "tags": [{"name": "a"}], "labels": {"k": "v"}, "metadata": {"a": 1}, "owner-info": {"name": "me"}}`
+	msg := dynamicpb.NewMessage(md)
+	assert.NoError(t, protojson.Unmarshal([]byte(data), msg))
+	out, err := protojson.Marshal(msg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": "abc", "count": 1, "total": "2", "created": "2023-01-01T00:00:00Z", "tags": [{"name": "a"}], "labels": {"k": "v"}, "metadata": {"a": 1}, "owner-info": {"name": "me"}}`, string(out))
+
+	// The schema round-trips through the descriptor.
+	back := Schema(r, md)
+	assert.Equal(t, huma.TypeString, back.Properties["total"].Type)
+	assert.True(t, back.Properties["score"].Nullable)
+	assert.Equal(t, huma.TypeString, back.Properties["labels"].AdditionalProperties.(*huma.Schema).Type)
+}
+
+func TestDescriptorErrors(t *testing.T) {
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+
+	_, err := Descriptor(r, &huma.Schema{Type: huma.TypeObject}, "Thing")
+	assert.ErrorContains(t, err, "invalid message name")
+
+	_, err = Descriptor(r, &huma.Schema{Type: huma.TypeString}, "pkg.Thing")
+	assert.ErrorContains(t, err, "only object schemas")
+
+	_, err = Descriptor(r, &huma.Schema{
+		Type: huma.TypeObject,
+		Properties: map[string]*huma.Schema{
+			"matrix": {Type: huma.TypeArray, Items: &huma.Schema{Type: huma.TypeArray}},
+		},
+	}, "pkg.Thing")
+	assert.ErrorContains(t, err, "Thing.matrix: nested arrays")
+
+	_, err = Descriptor(r, &huma.Schema{
+		Type: huma.TypeObject,
+		Properties: map[string]*huma.Schema{
+			"a": {Type: huma.TypeString, Extensions: map[string]any{FieldNumberExtension: 1}},
+			"b": {Type: huma.TypeString, Extensions: map[string]any{FieldNumberExtension: 1}},
+		},
+	}, "pkg.Dupe")
+	assert.ErrorContains(t, err, "conflicting fields")
+}
@@ -0,0 +1,79 @@
+package huma
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicHandler is called with the recovered value and stack trace when an
+// operation handler panics, e.g. to log or report it. See `Config.Recover`.
+type PanicHandler func(ctx Context, v any, stack []byte)
+
+// DefaultPanicHandler logs the panic and its stack trace using the standard
+// library logger.
+func DefaultPanicHandler(ctx Context, v any, stack []byte) {
+	op := ctx.Operation()
+	log.Printf("panic in %s %s: %v\n%s", op.Method, op.Path, v, stack)
+}
+
+// recoverAdapter converts panics in operation handlers into error responses.
+type recoverAdapter struct {
+	Adapter
+	api     API
+	onPanic PanicHandler
+}
+
+func (a *recoverAdapter) Handle(op *Operation, handler func(Context)) {
+	a.Adapter.Handle(op, func(ctx Context) {
+		rc := &recoverContext{humaContext: ctx}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				// The handler deliberately aborted the response.
+				panic(v)
+			}
+			a.onPanic(ctx, v, debug.Stack())
+			if rc.started {
+				// The status is already sent, so abort the connection rather
+				// than let the client mistake a truncated body for a response.
+				panic(http.ErrAbortHandler)
+			}
+			WriteErr(a.api, ctx, http.StatusInternalServerError, "internal server error")
+		}()
+		handler(rc)
+	})
+}
+
+// recoverContext tracks whether the response has been started, since an error
+// can't be sent after part of a response has been written.
+type recoverContext struct {
+	humaContext
+	started bool
+}
+
+// Unwrap returns the wrapped context, see `UnwrapContext`.
+func (c *recoverContext) Unwrap() Context {
+	return c.humaContext
+}
+
+func (c *recoverContext) SetStatus(code int) {
+	if !isInterim(code) {
+		c.started = true
+	}
+	c.humaContext.SetStatus(code)
+}
+
+func (c *recoverContext) BodyWriter() io.Writer {
+	c.started = true
+	return c.humaContext.BodyWriter()
+}
+
+func (c *recoverContext) Flush() error {
+	c.started = true
+	return Flush(c.humaContext)
+}
@@ -0,0 +1,185 @@
+package huma
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// RedactedMask replaces the values of string fields tagged
+// `sensitive:"mask"` when redacting.
+var RedactedMask = "********"
+
+// RedactTransform is a transform that removes fields tagged
+// `sensitive:"true"` from response bodies, like tokens or personal data, and
+// replaces string fields tagged `sensitive:"mask"` with `RedactedMask`.
+// Fields tagged `sensitive:"true"` are documented as `writeOnly`, so they may
+// still be sent in requests. Operations can opt out via
+// `Operation.SkipRedaction`.
+//
+//	type User struct {
+//		Name     string `json:"name"`
+//		Password string `json:"password" sensitive:"true"`
+//		SSN      string `json:"ssn" sensitive:"mask"`
+//	}
+func RedactTransform(ctx Context, status string, v any) (any, error) {
+	if op := ctx.Operation(); op != nil && op.SkipRedaction {
+		return v, nil
+	}
+	return Redact(v), nil
+}
+
+// Redact returns the value with fields tagged `sensitive` removed or masked,
+// e.g. to log request or response payloads. The result marshals to the same
+// JSON as the value otherwise. Values without sensitive fields are returned
+// as-is. Values with interface types are redacted based on their dynamic
+// values.
+func Redact(v any) any {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if !hasSensitive(rv.Type()) {
+		return v
+	}
+	return redactValue(rv)
+}
+
+// sensitiveTypes caches whether types contain sensitive fields.
+var sensitiveTypes sync.Map
+
+// hasSensitive returns whether the type has fields tagged `sensitive`, either
+// directly or within nested types.
+func hasSensitive(t reflect.Type) bool {
+	if cached, ok := sensitiveTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	result := findSensitive(t, map[reflect.Type]bool{})
+	sensitiveTypes.Store(t, result)
+	return result
+}
+
+func findSensitive(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	if pt := reflect.PointerTo(t); pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType) {
+		// Custom marshaling can't be redacted.
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		// The dynamic value may have sensitive fields, so it must be checked.
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return findSensitive(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Tag.Get("json") == "-" || (!f.IsExported() && !f.Anonymous) {
+				continue
+			}
+			if f.Tag.Get("sensitive") != "" || findSensitive(f.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactValue returns the value as maps & slices with sensitive fields
+// removed or masked. Values without sensitive fields are kept as-is so they
+// are marshalled normally.
+func redactValue(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !hasSensitive(v.Type()) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		out := map[string]any{}
+		redactStructFields(v, out)
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := map[string]any{}
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = redactValue(iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// redactStructFields adds the struct's fields to `out`, using the same names
+// and embedding rules as `encoding/json`.
+func redactStructFields(v reflect.Value, out map[string]any) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := v.Field(i)
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// Fields of embedded structs are promoted.
+				if fv.Kind() == reflect.Pointer {
+					if fv.IsNil() {
+						continue
+					}
+					fv = fv.Elem()
+				}
+				redactStructFields(fv, out)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && isEmptyValue(fv) {
+			continue
+		}
+		switch f.Tag.Get("sensitive") {
+		case "":
+			out[name] = redactValue(fv)
+		case "mask":
+			if fv.Kind() == reflect.String {
+				out[name] = RedactedMask
+			}
+		}
+	}
+}
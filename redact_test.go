@@ -0,0 +1,147 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type RedactCredentials struct {
+	Token string `json:"token" sensitive:"true"`
+}
+
+type RedactUser struct {
+	Name        string             `json:"name"`
+	Password    string             `json:"password" sensitive:"true"`
+	SSN         string             `json:"ssn,omitempty" sensitive:"mask"`
+	Credentials *RedactCredentials `json:"credentials,omitempty"`
+}
+
+func TestRedact(t *testing.T) {
+	user := RedactUser{
+		Name:        "alice",
+		Password:    "hunter2",
+		SSN:         "123-45-6789",
+		Credentials: &RedactCredentials{Token: "abc123"},
+	}
+
+	for _, item := range []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"struct", user, `{"name": "alice", "ssn": "********", "credentials": {}}`},
+		{"pointer", &RedactUser{Name: "bob"}, `{"name": "bob"}`},
+		{"slice", []RedactUser{{Name: "bob", Password: "x"}}, `[{"name": "bob"}]`},
+		{"map", map[string]*RedactCredentials{"a": {Token: "x"}, "b": nil}, `{"a": {}, "b": null}`},
+		{"interface", map[string]any{"user": RedactUser{Name: "bob", Password: "x"}, "n": 1}, `{"user": {"name": "bob"}, "n": 1}`},
+		{"interface field", struct{ Body any }{&RedactCredentials{Token: "x"}}, `{"Body": {}}`},
+	} {
+		b, err := json.Marshal(Redact(item.value))
+		assert.NoError(t, err, item.name)
+		assert.JSONEq(t, item.expected, string(b), item.name)
+	}
+
+	// Values without sensitive fields are returned as-is.
+	plain := &FieldSelectOwner{Name: "alice"}
+	assert.Same(t, plain, Redact(plain))
+	assert.Nil(t, Redact(nil))
+}
+
+func TestRedactTransform(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Transformers = append(config.Transformers, RedactTransform)
+	app := NewTestAdapter(r, config)
+
+	user := RedactUser{Name: "alice", Password: "hunter2", SSN: "123-45-6789"}
+
+	Register(app, Operation{
+		OperationID: "get-user",
+		Method:      http.MethodGet,
+		Path:        "/user",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body RedactUser }, error) {
+		return &struct{ Body RedactUser }{user}, nil
+	})
+	Register(app, Operation{
+		OperationID:   "create-token",
+		Method:        http.MethodPost,
+		Path:          "/tokens",
+		SkipRedaction: true,
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body RedactCredentials }, error) {
+		return &struct{ Body RedactCredentials }{RedactCredentials{Token: "abc123"}}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/user", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"$schema": "http:///schemas/RedactUser.json", "name": "alice", "ssn": "********"}`, w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodPost, "/tokens", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), "abc123")
+
+	// Sensitive fields are documented as write-only, so they can be sent but
+	// are never required in responses.
+	schema := app.OpenAPI().Components.Schemas.Map()["RedactUser"]
+	assert.True(t, schema.Properties["password"].WriteOnly)
+	assert.False(t, schema.Properties["ssn"].WriteOnly)
+}
+
+func TestRedactAfterFieldSelect(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Transformers = []Transformer{FieldSelectTransform, RedactTransform}
+	app := NewTestAdapter(r, config)
+
+	type Secret struct {
+		Name string `json:"name"`
+		PW   string `json:"pw" sensitive:"true"`
+	}
+
+	Register(app, Operation{
+		OperationID: "get-typed",
+		Method:      http.MethodGet,
+		Path:        "/typed",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body Secret }, error) {
+		return &struct{ Body Secret }{Secret{Name: "a", PW: "secret"}}, nil
+	})
+	Register(app, Operation{
+		OperationID: "get-any",
+		Method:      http.MethodGet,
+		Path:        "/any",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body any }, error) {
+		return &struct{ Body any }{Secret{Name: "a", PW: "secret"}}, nil
+	})
+
+	for _, item := range []struct {
+		name   string
+		path   string
+		fields string
+	}{
+		{"typed", "/typed", ""},
+		{"typed selected", "/typed", "name,pw"},
+		{"typed query", "/typed", "{name, pw}"},
+		{"any", "/any", ""},
+		{"any selected", "/any", "name,pw"},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, item.path, nil)
+			if item.fields != "" {
+				req.Header.Set("Fields", item.fields)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), `"name":"a"`)
+			assert.NotContains(t, w.Body.String(), "secret")
+		})
+	}
+}
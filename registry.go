@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 )
 
@@ -19,6 +20,9 @@ type Registry interface {
 	Map() map[string]*Schema
 }
 
+// rxTypeArgPackage matches the package path & name before a type argument.
+var rxTypeArgPackage = regexp.MustCompile(`[\w./-]+\.`)
+
 // DefaultSchemaNamer provides schema names for types. It uses the type name
 // when possible, ignoring the package name. If the type is generic, e.g.
 // `MyType[SubType]`, then the brackets are removed like `MyTypeSubType`.
@@ -29,7 +33,10 @@ type Registry interface {
 func DefaultSchemaNamer(t reflect.Type, hint string) string {
 	name := deref(t).Name()
 
-	// Fix up generics, if used, for nicer refs & URLs.
+	// Fix up generics, if used, for nicer refs & URLs. Type arguments include
+	// their package path, e.g. `Page[github.com/foo/bar.Thing]`, which is
+	// removed as well.
+	name = rxTypeArgPackage.ReplaceAllString(name, "")
 	name = strings.ReplaceAll(name, "[", "")
 	name = strings.ReplaceAll(name, "]", "")
 
@@ -54,6 +61,14 @@ func (r *mapRegistry) Schema(t reflect.Type, allowRef bool, hint string) *Schema
 		// Special case: time.Time is always a string.
 		getsRef = false
 	}
+	if _, ok := NullTypes[t]; ok {
+		// Special case: nullable wrappers use the schema of their value.
+		getsRef = false
+	}
+	if optionalElem(t) != nil || nullableElem(t) != nil {
+		// Special case: optional & nullable values use the schema of their value.
+		getsRef = false
+	}
 
 	name := r.namer(t, hint)
 
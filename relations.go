@@ -0,0 +1,195 @@
+package huma
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Relation is an embedded link to a related resource in a `_links` object.
+type Relation struct {
+	Href string `json:"href"`
+}
+
+// RelationsProvider is implemented by response bodies which link to related
+// resources only known at runtime, like the `next` page. The values are URI
+// templates like those in `Operation.Relations`.
+//
+//	func (l ThingList) Relations() map[string]string {
+//		return map[string]string{"next": "/things?cursor=" + l.Cursor}
+//	}
+type RelationsProvider interface {
+	Relations() map[string]string
+}
+
+var relationsProviderType = reflect.TypeOf((*RelationsProvider)(nil)).Elem()
+
+// RelationsTransform is a transform which links successful responses to
+// related resources, e.g. `self`, `next`, or `parent`, from the operation's
+// `Relations` and bodies implementing `RelationsProvider`. URI templates like
+// `/users/{user-id}` are resolved using the request's path & query params,
+// skipping relations with missing values. Links are sent as `Link` headers
+// and, for object bodies, embedded as a `_links` object. Use
+// `DocumentRelations` to document them. It must run after transformers which
+// replace the body, like `FieldSelectTransform`, so that it can find the
+// body's relations.
+func RelationsTransform(ctx Context, status string, v any) (any, error) {
+	if !strings.HasPrefix(status, "2") {
+		return v, nil
+	}
+
+	templates := map[string]string{}
+	if op := ctx.Operation(); op != nil {
+		for rel, tmpl := range op.Relations {
+			templates[rel] = tmpl
+		}
+	}
+	body := v
+	if sl, ok := v.(schemaLinked); ok {
+		body = sl.value
+	}
+	if p, ok := body.(RelationsProvider); ok {
+		for rel, tmpl := range p.Relations() {
+			templates[rel] = tmpl
+		}
+	}
+	if len(templates) == 0 {
+		return v, nil
+	}
+
+	rels := make([]string, 0, len(templates))
+	for rel := range templates {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	links := map[string]Relation{}
+	for _, rel := range rels {
+		href, ok := expandTemplate(ctx, templates[rel])
+		if !ok {
+			continue
+		}
+		links[rel] = Relation{Href: href}
+		ctx.AppendHeader("Link", "<"+href+">; rel=\""+rel+"\"")
+	}
+	if len(links) == 0 || v == nil {
+		return v, nil
+	}
+	return relationsLinked{links: links, value: v}, nil
+}
+
+// expandTemplate replaces `{name}` in the URI template with the escaped value
+// of the request's path or query param, returning false if one is missing.
+func expandTemplate(ctx Context, tmpl string) (string, bool) {
+	var sb strings.Builder
+	for {
+		before, rest, found := strings.Cut(tmpl, "{")
+		sb.WriteString(before)
+		if !found {
+			return sb.String(), true
+		}
+		name, after, found := strings.Cut(rest, "}")
+		if !found {
+			sb.WriteString("{" + rest)
+			return sb.String(), true
+		}
+		value := ctx.Param(name)
+		if value == "" {
+			value = ctx.Query(name)
+		}
+		if value == "" {
+			return "", false
+		}
+		sb.WriteString(url.PathEscape(value))
+		tmpl = after
+	}
+}
+
+// relationsLinked adds the `_links` member to a value, which is left as-is if
+// it doesn't marshal to an object.
+type relationsLinked struct {
+	links map[string]Relation
+	value any
+}
+
+func (r relationsLinked) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(r.value)
+	if err != nil || len(b) < 2 || b[0] != '{' {
+		return b, err
+	}
+	links, err := json.Marshal(r.links)
+	if err != nil {
+		return nil, err
+	}
+	field := append([]byte(`{"_links":`), links...)
+	if len(b) > 2 {
+		field = append(field, ',')
+	}
+	return append(field, b[1:]...), nil
+}
+
+func (r relationsLinked) MarshalCBOR() ([]byte, error) {
+	b, err := cborEncMode.Marshal(r.value)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := cbor.Unmarshal(b, &m); err != nil {
+		// Not a map, so there is nowhere to put the links.
+		return b, nil
+	}
+	m["_links"] = r.links
+	return cborEncMode.Marshal(m)
+}
+
+// DocumentRelations documents the `Link` header & `_links` property sent by
+// `RelationsTransform` for operations with `Relations`. Add it to the
+// OpenAPI's `OnAddOperation` hooks before registering operations.
+func DocumentRelations(oapi *OpenAPI, op *Operation) {
+	if len(op.Relations) == 0 {
+		return
+	}
+	registry := oapi.Components.Schemas
+	for status, resp := range op.Responses {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		if resp.Headers == nil {
+			resp.Headers = map[string]*Header{}
+		}
+		if resp.Headers["Link"] == nil {
+			resp.Headers["Link"] = &Header{
+				Description: "Links to related resources.",
+				Schema:      &Schema{Type: TypeString},
+			}
+		}
+		for _, content := range resp.Content {
+			if content == nil || content.Schema == nil {
+				continue
+			}
+			schema := content.Schema
+			if schema.Ref != "" {
+				schema = registry.SchemaFromRef(schema.Ref)
+			}
+			if schema == nil || schema.Type != TypeObject || schema.Properties == nil || schema.Properties["_links"] != nil {
+				continue
+			}
+			schema.Properties["_links"] = &Schema{
+				Type:        TypeObject,
+				Description: "Links to related resources by relation.",
+				ReadOnly:    true,
+				AdditionalProperties: &Schema{
+					Type: TypeObject,
+					Properties: map[string]*Schema{
+						"href": {Type: TypeString, Format: "uri-reference"},
+					},
+					Required: []string{"href"},
+				},
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type RelationsThing struct {
+	ID string `json:"id"`
+}
+
+type RelationsThingList struct {
+	Items  []RelationsThing `json:"items"`
+	Cursor string           `json:"-"`
+}
+
+func (l RelationsThingList) Relations() map[string]string {
+	if l.Cursor == "" {
+		return nil
+	}
+	return map[string]string{"next": "/users/{user-id}/things?cursor=" + l.Cursor}
+}
+
+func TestRelationsTransform(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.OnAddOperation = append(config.OnAddOperation, DocumentRelations)
+	config.Transformers = append(config.Transformers, RelationsTransform)
+	app := NewTestAdapter(r, config)
+
+	Register(app, Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/users/{user-id}/things/{id}",
+		Relations: map[string]string{
+			"self":    "/users/{user-id}/things/{id}",
+			"parent":  "/users/{user-id}",
+			"version": "/users/{user-id}/things/{id}?version={version}",
+		},
+	}, func(ctx context.Context, input *struct {
+		UserID string `path:"user-id"`
+		ID     string `path:"id"`
+	}) (*struct{ Body RelationsThing }, error) {
+		if input.ID == "missing" {
+			return nil, Error404NotFound("no thing")
+		}
+		return &struct{ Body RelationsThing }{RelationsThing{ID: input.ID}}, nil
+	})
+	Register(app, Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/users/{user-id}/things",
+	}, func(ctx context.Context, input *struct {
+		UserID string `path:"user-id"`
+	}) (*struct{ Body RelationsThingList }, error) {
+		return &struct{ Body RelationsThingList }{RelationsThingList{Items: []RelationsThing{{ID: "a"}}, Cursor: "abc"}}, nil
+	})
+
+	for _, item := range []struct {
+		url   string
+		links []string
+		body  string
+	}{
+		{"/users/u%201/things/a", []string{
+			`</schemas/RelationsThing.json>; rel="describedBy"`,
+			`</users/u%201>; rel="parent"`,
+			`</users/u%201/things/a>; rel="self"`,
+		}, `{
+			"$schema": "http:///schemas/RelationsThing.json",
+			"_links": {"parent": {"href": "/users/u%201"}, "self": {"href": "/users/u%201/things/a"}},
+			"id": "a"
+		}`},
+		{"/users/u1/things/a?version=2", []string{
+			`</schemas/RelationsThing.json>; rel="describedBy"`,
+			`</users/u1>; rel="parent"`,
+			`</users/u1/things/a>; rel="self"`,
+			`</users/u1/things/a?version=2>; rel="version"`,
+		}, `{
+			"$schema": "http:///schemas/RelationsThing.json",
+			"_links": {
+				"parent": {"href": "/users/u1"},
+				"self": {"href": "/users/u1/things/a"},
+				"version": {"href": "/users/u1/things/a?version=2"}
+			},
+			"id": "a"
+		}`},
+		{"/users/u1/things/missing", []string{
+			`</schemas/ErrorModel.json>; rel="describedBy"`,
+		}, `{"$schema": "http:///schemas/ErrorModel.json", "title": "Not Found", "status": 404, "detail": "no thing"}`},
+		{"/users/u1/things", []string{
+			`</schemas/RelationsThingList.json>; rel="describedBy"`,
+			`</users/u1/things?cursor=abc>; rel="next"`,
+		}, `{
+			"$schema": "http:///schemas/RelationsThingList.json",
+			"_links": {"next": {"href": "/users/u1/things?cursor=abc"}},
+			"items": [{"id": "a"}]
+		}`},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, item.url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		// Check the headers as sent with the status, not modified afterward.
+		assert.Equal(t, item.links, w.Result().Header.Values("Link"), item.url)
+		assert.JSONEq(t, item.body, w.Body.String(), item.url)
+	}
+
+	resp := app.OpenAPI().Paths["/users/{user-id}/things/{id}"].Get.Responses["200"]
+	assert.NotNil(t, resp.Headers["Link"])
+	thing := app.OpenAPI().Components.Schemas.Map()["RelationsThing"]
+	assert.True(t, thing.Properties["_links"].ReadOnly)
+	list := app.OpenAPI().Components.Schemas.Map()["RelationsThingList"]
+	assert.NotContains(t, list.Properties, "_links", "only static relations are documented")
+}
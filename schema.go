@@ -1,6 +1,7 @@
 package huma
 
 import (
+	"encoding"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,11 +10,13 @@ import (
 	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/goccy/go-yaml"
+	"github.com/google/uuid"
 )
 
 // ErrSchemaInvalid is sent when there is a problem building the schema.
@@ -30,11 +33,38 @@ const (
 )
 
 var (
-	timeType = reflect.TypeOf(time.Time{})
-	ipType   = reflect.TypeOf(net.IP{})
-	urlType  = reflect.TypeOf(url.URL{})
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	ipType            = reflect.TypeOf(net.IP{})
+	urlType           = reflect.TypeOf(url.URL{})
 )
 
+// TextFormats maps types which marshal as text to the string format used in
+// their schema. Add your own types to document their format, e.g.
+// `huma.TextFormats[reflect.TypeOf(MyID{})] = "my-id"`.
+var TextFormats = map[reflect.Type]string{
+	reflect.TypeOf(uuid.UUID{}): "uuid",
+}
+
+// TypeSchemas generate schemas for types which can't describe themselves by
+// implementing an interface, e.g. generated code. Each is called in turn with
+// the type before the default behavior, and the first non-nil schema is used.
+// See the `protoschema` package for protobuf messages.
+var TypeSchemas []func(r Registry, t reflect.Type) *Schema
+
+// isTextType returns whether the type is sent as a string by `encoding/json`
+// because it implements `encoding.TextMarshaler` without a custom JSON
+// marshaler, e.g. `uuid.UUID` or `netip.Addr`.
+func isTextType(t reflect.Type) bool {
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return false
+	}
+	return t.Implements(textMarshalerType)
+}
+
 func deref(t reflect.Type) reflect.Type {
 	for t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -47,44 +77,63 @@ func deref(t reflect.Type) reflect.Type {
 // spec, designed specifically for use with Go structs and to enable fast zero
 // or near-zero allocation happy-path validation for incoming requests.
 type Schema struct {
-	Type                 string             `yaml:"type,omitempty"`
-	Title                string             `yaml:"title,omitempty"`
-	Description          string             `yaml:"description,omitempty"`
-	Ref                  string             `yaml:"$ref,omitempty"`
-	Format               string             `yaml:"format,omitempty"`
-	ContentEncoding      string             `yaml:"contentEncoding,omitempty"`
-	Default              any                `yaml:"default,omitempty"`
-	Examples             []any              `yaml:"examples,omitempty"`
-	Items                *Schema            `yaml:"items,omitempty"`
-	AdditionalProperties any                `yaml:"additionalProperties,omitempty"`
-	Properties           map[string]*Schema `yaml:"properties,omitempty"`
-	Enum                 []any              `yaml:"enum,omitempty"`
-	Minimum              *float64           `yaml:"minimum,omitempty"`
-	ExclusiveMinimum     *float64           `yaml:"exclusiveMinimum,omitempty"`
-	Maximum              *float64           `yaml:"maximum,omitempty"`
-	ExclusiveMaximum     *float64           `yaml:"exclusiveMaximum,omitempty"`
-	MultipleOf           *float64           `yaml:"multipleOf,omitempty"`
-	MinLength            *int               `yaml:"minLength,omitempty"`
-	MaxLength            *int               `yaml:"maxLength,omitempty"`
-	Pattern              string             `yaml:"pattern,omitempty"`
-	MinItems             *int               `yaml:"minItems,omitempty"`
-	MaxItems             *int               `yaml:"maxItems,omitempty"`
-	UniqueItems          bool               `yaml:"uniqueItems,omitempty"`
-	Required             []string           `yaml:"required,omitempty"`
-	MinProperties        *int               `yaml:"minProperties,omitempty"`
-	MaxProperties        *int               `yaml:"maxProperties,omitempty"`
-	ReadOnly             bool               `yaml:"readOnly,omitempty"`
-	WriteOnly            bool               `yaml:"writeOnly,omitempty"`
-	Deprecated           bool               `yaml:"deprecated,omitempty"`
-	Extensions           map[string]any     `yaml:",inline"`
-
-	patternRe     *regexp.Regexp  `yaml:"-"`
-	requiredMap   map[string]bool `yaml:"-"`
-	propertyNames []string        `yaml:"-"`
+	Type                 string              `yaml:"type,omitempty"`
+	Title                string              `yaml:"title,omitempty"`
+	Description          string              `yaml:"description,omitempty"`
+	Ref                  string              `yaml:"$ref,omitempty"`
+	Format               string              `yaml:"format,omitempty"`
+	ContentEncoding      string              `yaml:"contentEncoding,omitempty"`
+	Default              any                 `yaml:"default,omitempty"`
+	Examples             []any               `yaml:"examples,omitempty"`
+	Items                *Schema             `yaml:"items,omitempty"`
+	AdditionalProperties any                 `yaml:"additionalProperties,omitempty"`
+	Properties           map[string]*Schema  `yaml:"properties,omitempty"`
+	PatternProperties    map[string]*Schema  `yaml:"patternProperties,omitempty"`
+	PropertyNames        *Schema             `yaml:"propertyNames,omitempty"`
+	Enum                 []any               `yaml:"enum,omitempty"`
+	Const                any                 `yaml:"const,omitempty"`
+	Minimum              *float64            `yaml:"minimum,omitempty"`
+	ExclusiveMinimum     *float64            `yaml:"exclusiveMinimum,omitempty"`
+	Maximum              *float64            `yaml:"maximum,omitempty"`
+	ExclusiveMaximum     *float64            `yaml:"exclusiveMaximum,omitempty"`
+	MultipleOf           *float64            `yaml:"multipleOf,omitempty"`
+	MinLength            *int                `yaml:"minLength,omitempty"`
+	MaxLength            *int                `yaml:"maxLength,omitempty"`
+	Pattern              string              `yaml:"pattern,omitempty"`
+	MinItems             *int                `yaml:"minItems,omitempty"`
+	MaxItems             *int                `yaml:"maxItems,omitempty"`
+	UniqueItems          bool                `yaml:"uniqueItems,omitempty"`
+	Required             []string            `yaml:"required,omitempty"`
+	DependentRequired    map[string][]string `yaml:"dependentRequired,omitempty"`
+	MinProperties        *int                `yaml:"minProperties,omitempty"`
+	MaxProperties        *int                `yaml:"maxProperties,omitempty"`
+	ReadOnly             bool                `yaml:"readOnly,omitempty"`
+	WriteOnly            bool                `yaml:"writeOnly,omitempty"`
+	Deprecated           bool                `yaml:"deprecated,omitempty"`
+	Extensions           map[string]any      `yaml:",inline"`
+
+	// Nullable allows `null` in addition to the schema's type, which is
+	// marshaled as a type list like `["string", "null"]`.
+	Nullable bool `yaml:"-"`
+
+	patternRe     *regexp.Regexp    `yaml:"-"`
+	requiredMap   map[string]bool   `yaml:"-"`
+	propertyNames []string          `yaml:"-"`
+	requiredOnly  []string          `yaml:"-"`
+	patternProps  []patternProp     `yaml:"-"`
+	dependents    []dependent       `yaml:"-"`
+	comparisons   []fieldComparison `yaml:"-"`
+	constValue    any               `yaml:"-"`
+
+	// goInt is the Go integer type the schema was created for, like `uint32`,
+	// and goIntFormat the format it documented for it.
+	goInt       string `yaml:"-"`
+	goIntFormat string `yaml:"-"`
 
 	// Precomputed validation messages. These prevent allocations during
 	// validation and are known at schema creation time.
 	msgEnum             string            `yaml:"-"`
+	msgConst            string            `yaml:"-"`
 	msgMinimum          string            `yaml:"-"`
 	msgExclusiveMinimum string            `yaml:"-"`
 	msgMaximum          string            `yaml:"-"`
@@ -100,46 +149,157 @@ type Schema struct {
 	msgRequired         map[string]string `yaml:"-"`
 }
 
+// patternProp is a compiled `patternProperties` entry.
+type patternProp struct {
+	re     *regexp.Regexp
+	schema *Schema
+}
+
+// matchesPatternProp returns whether the property name matches any of the
+// schema's `patternProperties`, in which case it is not an additional property.
+func (s *Schema) matchesPatternProp(name string) bool {
+	for _, pp := range s.patternProps {
+		if pp.re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// dependent is a precomputed `dependentRequired` entry with the messages to
+// use for each missing dependency.
+type dependent struct {
+	name     string
+	required []string
+	msgs     []string
+}
+
+// fieldComparison is a cross-field comparison from a tag like `gtField`, which
+// compares the value of the property `name` to its sibling property `other`.
+type fieldComparison struct {
+	name  string
+	other string
+	op    string
+	msg   string
+}
+
+// fieldComparisonNotes are the description notes documenting each cross-field
+// comparison tag, since JSON Schema has no keyword for them.
+var fieldComparisonNotes = map[string]string{
+	"eqField":  "Must equal `%s`.",
+	"neField":  "Must not equal `%s`.",
+	"gtField":  "Must be greater than `%s`.",
+	"gteField": "Must be greater than or equal to `%s`.",
+	"ltField":  "Must be less than `%s`.",
+	"lteField": "Must be less than or equal to `%s`.",
+}
+
+// fieldComparisonTags are the cross-field comparison tags in a stable order.
+var fieldComparisonTags = []string{"eqField", "neField", "gtField", "gteField", "ltField", "lteField"}
+
 func (s *Schema) PrecomputeMessages() {
-	s.msgEnum = "expected value to be one of \"" + strings.Join(mapTo(s.Enum, func(v any) string {
+	s.msgEnum = message("enum", strings.Join(mapTo(s.Enum, func(v any) string {
 		return fmt.Sprintf("%v", v)
-	}), ", ") + "\""
+	}), ", "))
+	if s.Const != nil {
+		// Compare against the value as it would be decoded from JSON, so e.g.
+		// an `int` constant matches the `float64` from a request body.
+		s.constValue = s.Const
+		if b, err := json.Marshal(s.Const); err == nil {
+			json.Unmarshal(b, &s.constValue)
+		}
+		s.msgConst = message("const", s.Const)
+	}
 	if s.Minimum != nil {
-		s.msgMinimum = fmt.Sprintf("expected number >= %v", *s.Minimum)
+		s.msgMinimum = message("minimum", *s.Minimum)
 	}
 	if s.ExclusiveMinimum != nil {
-		s.msgExclusiveMinimum = fmt.Sprintf("expected number > %v", *s.ExclusiveMinimum)
+		s.msgExclusiveMinimum = message("exclusiveMinimum", *s.ExclusiveMinimum)
 	}
 	if s.Maximum != nil {
-		s.msgMaximum = fmt.Sprintf("expected number <= %v", *s.Maximum)
+		s.msgMaximum = message("maximum", *s.Maximum)
 	}
 	if s.ExclusiveMaximum != nil {
-		s.msgExclusiveMaximum = fmt.Sprintf("expected number < %v", *s.ExclusiveMaximum)
+		s.msgExclusiveMaximum = message("exclusiveMaximum", *s.ExclusiveMaximum)
 	}
 	if s.MultipleOf != nil {
-		s.msgMultipleOf = fmt.Sprintf("expected number to be a multiple of %v", *s.MultipleOf)
+		s.msgMultipleOf = message("multipleOf", *s.MultipleOf)
 	}
 	if s.MinLength != nil {
-		s.msgMinLength = fmt.Sprintf("expected length >= %d", *s.MinLength)
+		s.msgMinLength = message("minLength", *s.MinLength)
 	}
 	if s.MaxLength != nil {
-		s.msgMaxLength = fmt.Sprintf("expected length <= %d", *s.MaxLength)
+		s.msgMaxLength = message("maxLength", *s.MaxLength)
 	}
 	if s.Pattern != "" {
 		s.patternRe = regexp.MustCompile(s.Pattern)
-		s.msgPattern = "expected string to match pattern " + s.Pattern
+		s.msgPattern = message("pattern", s.Pattern)
+	}
+	if s.PatternProperties != nil {
+		patterns := make([]string, 0, len(s.PatternProperties))
+		for pattern := range s.PatternProperties {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		s.patternProps = make([]patternProp, 0, len(patterns))
+		for _, pattern := range patterns {
+			s.patternProps = append(s.patternProps, patternProp{regexp.MustCompile(pattern), s.PatternProperties[pattern]})
+		}
 	}
 	if s.MinItems != nil {
-		s.msgMinItems = fmt.Sprintf("expected array length >= %d", *s.MinItems)
+		s.msgMinItems = message("minItems", *s.MinItems)
 	}
 	if s.MaxItems != nil {
-		s.msgMaxItems = fmt.Sprintf("expected array length <= %d", *s.MaxItems)
+		s.msgMaxItems = message("maxItems", *s.MaxItems)
 	}
 	if s.MinProperties != nil {
-		s.msgMinProperties = fmt.Sprintf("expected object with at least %d properties", *s.MinProperties)
+		s.msgMinProperties = message("minProperties", *s.MinProperties)
 	}
 	if s.MaxProperties != nil {
-		s.msgMaxProperties = fmt.Sprintf("expected object with at most %d properties", *s.MaxProperties)
+		s.msgMaxProperties = message("maxProperties", *s.MaxProperties)
+	}
+
+	if s.DependentRequired != nil {
+		names := make([]string, 0, len(s.DependentRequired))
+		for name := range s.DependentRequired {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		s.dependents = make([]dependent, 0, len(names))
+		for _, name := range names {
+			d := dependent{name: name, required: s.DependentRequired[name]}
+			for _, dep := range d.required {
+				d.msgs = append(d.msgs, message("dependentRequired", dep, name))
+			}
+			s.dependents = append(s.dependents, d)
+		}
+	}
+	for i := range s.comparisons {
+		c := &s.comparisons[i]
+		c.msg = message(c.op, c.other)
+	}
+
+	if s.propertyNames == nil && s.Properties != nil {
+		// Custom schemas validate properties in a stable order.
+		s.propertyNames = make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			s.propertyNames = append(s.propertyNames, name)
+		}
+		sort.Strings(s.propertyNames)
+	}
+	if s.requiredMap == nil && s.Required != nil {
+		s.requiredMap = map[string]bool{}
+		for _, name := range s.Required {
+			s.requiredMap[name] = true
+		}
+	}
+	s.requiredOnly = nil
+	for _, name := range s.Required {
+		if s.Properties[name] == nil {
+			// Required without a schema for the value, which is possible in
+			// custom schemas.
+			s.requiredOnly = append(s.requiredOnly, name)
+		}
 	}
 
 	if s.Required != nil {
@@ -147,7 +307,7 @@ func (s *Schema) PrecomputeMessages() {
 			s.msgRequired = map[string]string{}
 		}
 		for _, name := range s.Required {
-			s.msgRequired[name] = "expected required property " + name + " to be present"
+			s.msgRequired[name] = message("required", name)
 		}
 	}
 }
@@ -156,6 +316,119 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 	return yaml.MarshalWithOptions(s, yaml.JSON())
 }
 
+// MarshalYAML marshals the schema, writing the type as a list including
+// `null` for nullable schemas.
+func (s *Schema) MarshalYAML() (any, error) {
+	type plain Schema
+	if !s.Nullable || s.Type == "" {
+		return (*plain)(s), nil
+	}
+	p := plain(*s)
+	p.Type = ""
+	return struct {
+		Type   []any `yaml:"type"`
+		*plain `yaml:",inline"`
+	}{[]any{s.Type, yamlNull}, &p}, nil
+}
+
+// yamlString is a string which is always quoted in YAML, since otherwise
+// e.g. the `null` type name is written as a YAML null.
+type yamlString string
+
+const yamlNull yamlString = "null"
+
+func (y yamlString) MarshalYAML() ([]byte, error) {
+	return []byte(strconv.Quote(string(y))), nil
+}
+
+// schemaKeywords are the marshaled names of the schema's fields, which are
+// used to tell known keywords apart from extensions when unmarshaling.
+var schemaKeywords = func() map[string]bool {
+	keywords := map[string]bool{}
+	t := reflect.TypeOf(Schema{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if name != "" && name != "-" {
+			keywords[name] = true
+		}
+	}
+	return keywords
+}()
+
+// UnmarshalYAML unmarshals the schema, collecting unknown keywords such as
+// `x-` extensions into `Extensions` so they survive a round-trip. Messages are
+// precomputed so the result can be used for validation.
+func (s *Schema) UnmarshalYAML(b []byte) error {
+	var typ struct {
+		Type any `yaml:"type"`
+	}
+	if err := yaml.Unmarshal(b, &typ); err != nil {
+		return err
+	}
+	types, _ := typ.Type.([]any)
+	if types != nil {
+		// The list is removed so the rest can be unmarshaled into the schema.
+		var m map[string]any
+		if err := yaml.Unmarshal(b, &m); err != nil {
+			return err
+		}
+		delete(m, "type")
+		var err error
+		if b, err = yaml.Marshal(m); err != nil {
+			return err
+		}
+	}
+
+	type plain Schema
+	var p plain
+	if err := yaml.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*s = Schema(p)
+
+	for _, item := range types {
+		// A type list like `[T, "null"]` is a nullable schema.
+		if name, _ := item.(string); item == nil || name == "null" {
+			s.Nullable = true
+		} else if s.Type == "" {
+			s.Type = name
+		} else {
+			return fmt.Errorf("%w: multiple types are not supported: %v", ErrSchemaInvalid, types)
+		}
+	}
+
+	for k := range s.Extensions {
+		if schemaKeywords[k] {
+			delete(s.Extensions, k)
+		}
+	}
+	if len(s.Extensions) == 0 {
+		s.Extensions = nil
+	}
+
+	if m, ok := s.AdditionalProperties.(map[string]any); ok {
+		// Additional properties may be a boolean or a schema.
+		b, err := yaml.Marshal(m)
+		if err != nil {
+			return err
+		}
+		addl := &Schema{}
+		if err := addl.UnmarshalYAML(b); err != nil {
+			return err
+		}
+		s.AdditionalProperties = addl
+	}
+
+	s.PrecomputeMessages()
+	return nil
+}
+
+// UnmarshalJSON unmarshals the schema like `UnmarshalYAML`, as JSON is a
+// subset of YAML.
+func (s *Schema) UnmarshalJSON(b []byte) error {
+	return s.UnmarshalYAML(b)
+}
+
 func boolTag(f reflect.StructField, tag string) bool {
 	if v := f.Tag.Get(tag); v != "" {
 		if v == "true" {
@@ -191,6 +464,32 @@ func floatTag(f reflect.StructField, tag string) *float64 {
 	return nil
 }
 
+// extensionsTag parses an `extensions` tag, which is either a JSON object or
+// a comma-separated list of `name=value` pairs. Values which aren't valid
+// JSON are used as strings.
+func extensionsTag(f reflect.StructField, value string) map[string]any {
+	extensions := map[string]any{}
+	if strings.HasPrefix(strings.TrimSpace(value), "{") {
+		if err := json.Unmarshal([]byte(value), &extensions); err != nil {
+			panic("invalid extensions tag for field '" + f.Name + "': " + err.Error())
+		}
+		return extensions
+	}
+	for _, pair := range strings.Split(value, ",") {
+		name, v, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			panic("invalid extensions tag for field '" + f.Name + "': " + value)
+		}
+		var parsed any
+		if err := json.Unmarshal([]byte(v), &parsed); err != nil {
+			parsed = strings.TrimSpace(v)
+		}
+		extensions[name] = parsed
+	}
+	return extensions
+}
+
 func jsonTagValue(f reflect.StructField, t reflect.Type, value string) any {
 	// Special case: strings don't need quotes.
 	if t.Kind() == reflect.String {
@@ -252,10 +551,32 @@ func SchemaFromField(registry Registry, parent reflect.Type, f reflect.StructFie
 	if parent != nil {
 		parentName = parent.Name()
 	}
-	fs := registry.Schema(f.Type, true, parentName+f.Name+"Struct")
+	if elem := optionalElem(f.Type); elem != nil {
+		// Optional values use the schema & tags of the value they wrap.
+		if f.Tag.Get("default") != "" {
+			panic("optional field '" + f.Name + "' cannot have a default")
+		}
+		f.Type = elem
+	}
+	nullable := false
+	if elem := nullableElem(f.Type); elem != nil {
+		if f.Tag.Get("default") != "" {
+			panic("nullable field '" + f.Name + "' cannot have a default")
+		}
+		f.Type = elem
+		nullable = true
+	}
+	fs := registry.Schema(f.Type, !nullable, parentName+f.Name+"Struct")
 	if fs == nil {
 		return fs
 	}
+	if nullable {
+		// The value's schema is inlined since a `$ref` can't also allow `null`,
+		// and copied so the registry's schema isn't modified.
+		c := *fs
+		c.Nullable = true
+		fs = &c
+	}
 	fs.Description = f.Tag.Get("doc")
 	if fmt := f.Tag.Get("format"); fmt != "" {
 		fs.Format = fmt
@@ -263,10 +584,28 @@ func SchemaFromField(registry Registry, parent reflect.Type, f reflect.StructFie
 	if enc := f.Tag.Get("encoding"); enc != "" {
 		fs.ContentEncoding = enc
 	}
-	fs.Default = jsonTag(f, "default", false)
+	if isDurationString(f) {
+		// Only parameters are parsed by Huma, so bodies would still be sent as
+		// integer nanoseconds by `encoding/json`.
+		if f.Tag.Get("path") == "" && f.Tag.Get("query") == "" && f.Tag.Get("header") == "" {
+			panic("format duration for field '" + f.Name + "' is only supported for time.Duration parameters, use a string field with huma.ParseDuration instead")
+		}
+		fs.Type = TypeString
+		if d := f.Tag.Get("default"); d != "" {
+			fs.Default = d
+		}
+		if e := f.Tag.Get("example"); e != "" {
+			fs.Examples = []any{e}
+		}
+	} else {
+		fs.Default = jsonTag(f, "default", false)
+		if c := jsonTag(f, "const", false); c != nil {
+			fs.Const = c
+		}
 
-	if e := jsonTag(f, "example", false); e != nil {
-		fs.Examples = []any{e}
+		if e := jsonTag(f, "example", false); e != nil {
+			fs.Examples = []any{e}
+		}
 	}
 
 	if enum := f.Tag.Get("enum"); enum != "" {
@@ -280,6 +619,7 @@ func SchemaFromField(registry Registry, parent reflect.Type, f reflect.StructFie
 		}
 		if fs.Type == TypeArray {
 			fs.Items.Enum = enumValues
+			fs.Items.PrecomputeMessages()
 		} else {
 			fs.Enum = enumValues
 		}
@@ -297,14 +637,43 @@ func SchemaFromField(registry Registry, parent reflect.Type, f reflect.StructFie
 	fs.UniqueItems = boolTag(f, "uniqueItems")
 	fs.MinProperties = intTag(f, "minProperties")
 	fs.MaxProperties = intTag(f, "maxProperties")
+	if fs.Type == TypeObject {
+		if pattern := f.Tag.Get("patternProperties"); pattern != "" {
+			// Map values are only allowed for keys matching the pattern.
+			if addl, ok := fs.AdditionalProperties.(*Schema); ok {
+				fs.PatternProperties = map[string]*Schema{pattern: addl}
+				fs.AdditionalProperties = false
+			}
+		}
+		keyPattern := f.Tag.Get("keyPattern")
+		keyFormat := f.Tag.Get("keyFormat")
+		if keyPattern != "" || keyFormat != "" {
+			fs.PropertyNames = &Schema{Type: TypeString, Pattern: keyPattern, Format: keyFormat}
+			fs.PropertyNames.PrecomputeMessages()
+		}
+	}
 	fs.ReadOnly = boolTag(f, "readOnly")
-	fs.WriteOnly = boolTag(f, "writeOnly")
+	fs.WriteOnly = boolTag(f, "writeOnly") || f.Tag.Get("sensitive") == "true"
 	fs.Deprecated = boolTag(f, "deprecated")
+	if ext := f.Tag.Get("extensions"); ext != "" {
+		if fs.Extensions == nil {
+			fs.Extensions = map[string]any{}
+		}
+		for k, v := range extensionsTag(f, ext) {
+			fs.Extensions[k] = v
+		}
+	}
 	fs.PrecomputeMessages()
 
 	return fs
 }
 
+// isDurationString returns whether the field is a `time.Duration` which is
+// represented as an ISO 8601 duration string via `format:"duration"`.
+func isDurationString(f reflect.StructField) bool {
+	return deref(f.Type) == durationType && f.Tag.Get("format") == "duration"
+}
+
 // fieldInfo stores information about a field, which may come from an
 // embedded type. The `Parent` stores the field's direct parent.
 type fieldInfo struct {
@@ -346,15 +715,60 @@ func getFields(typ reflect.Type) []fieldInfo {
 	return fields
 }
 
+// SchemaTransformer is an interface that can be implemented by types to modify
+// the schema generated for them, e.g. to document additional properties.
+type SchemaTransformer interface {
+	TransformSchema(r Registry, s *Schema) *Schema
+}
+
 func SchemaFromType(r Registry, t reflect.Type) *Schema {
 	s := Schema{}
 	t = deref(t)
 
+	for _, f := range TypeSchemas {
+		if s := f(r, t); s != nil {
+			return s
+		}
+	}
+
+	if vt, ok := NullTypes[t]; ok {
+		// Special case: nullable wrappers registered in `NullTypes`.
+		s := *SchemaFromType(r, vt)
+		s.Nullable = true
+		return &s
+	}
+
+	if elem := optionalElem(t); elem != nil {
+		// Special case: optional values use the schema of their value.
+		return r.Schema(elem, true, "")
+	}
+
+	if elem := nullableElem(t); elem != nil {
+		// Special case: nullable values use the schema of their value.
+		s := *r.Schema(elem, false, "")
+		s.Nullable = true
+		return &s
+	}
+
 	if t == ipType {
 		// Special case: IP address.
 		return &Schema{Type: TypeString, Format: "ipv4"}
 	}
 
+	if t == rawMessageType {
+		// Special case: raw JSON can be anything.
+		return &Schema{}
+	}
+
+	if isTextType(t) {
+		// Special case: types like `uuid.UUID` which marshal as text.
+		s := &Schema{Type: TypeString, Format: TextFormats[t]}
+		if st, ok := reflect.New(t).Interface().(SchemaTransformer); ok {
+			return st.TransformSchema(r, s)
+		}
+		return s
+	}
+
 	minZero := 0.0
 	switch t.Kind() {
 	case reflect.Bool:
@@ -373,6 +787,7 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 		s.Type = TypeInteger
 		s.Format = "int64"
 	case reflect.Uint:
+		// Unsigned integers can't be negative.
 		s.Type = TypeInteger
 		if bits.UintSize == 32 {
 			s.Format = "int32"
@@ -429,9 +844,29 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 		requiredMap := map[string]bool{}
 		propNames := []string{}
 		props := map[string]*Schema{}
+		var dependentRequired map[string][]string
+		var comparisons []fieldComparison
+		goNames := map[string]string{}
 		for _, info := range getFields(t) {
 			f := info.Field
 
+			if dr := f.Tag.Get("dependentRequired"); dr != "" {
+				// Format: `name:dep1,dep2;other:dep3`
+				if dependentRequired == nil {
+					dependentRequired = map[string][]string{}
+				}
+				for _, entry := range strings.Split(dr, ";") {
+					name, deps, ok := strings.Cut(entry, ":")
+					if !ok || strings.TrimSpace(deps) == "" {
+						panic("invalid dependentRequired tag for field '" + f.Name + "': " + dr)
+					}
+					name = strings.TrimSpace(name)
+					for _, dep := range strings.Split(deps, ",") {
+						dependentRequired[name] = append(dependentRequired[name], strings.TrimSpace(dep))
+					}
+				}
+			}
+
 			name := f.Name
 			omit := false
 			if j := f.Tag.Get("json"); j != "" {
@@ -454,15 +889,53 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 			if fs != nil {
 				props[name] = fs
 				propNames = append(propNames, name)
-				if !omit {
+				goNames[f.Name] = name
+				if !omit && optionalElem(f.Type) == nil && nullableElem(f.Type) == nil {
 					required = append(required, name)
 					requiredMap[name] = true
 				}
+				for _, op := range fieldComparisonTags {
+					if other := f.Tag.Get(op); other != "" {
+						comparisons = append(comparisons, fieldComparison{name: name, other: other, op: op})
+					}
+				}
+			}
+		}
+		for i := range comparisons {
+			// Siblings are referenced by their Go field name or property name.
+			c := &comparisons[i]
+			if name, ok := goNames[c.other]; ok {
+				c.other = name
+			} else if props[c.other] == nil {
+				panic(fmt.Errorf("%s property %s not found in %s: %w", c.op, c.other, t, ErrSchemaInvalid))
+			}
+			if fs := props[c.name]; fs.Ref == "" {
+				note := fmt.Sprintf(fieldComparisonNotes[c.op], c.other)
+				if fs.Description != "" {
+					note = fs.Description + "\n\n" + note
+				}
+				fs.Description = note
+			}
+		}
+		for name, deps := range dependentRequired {
+			for _, n := range append([]string{name}, deps...) {
+				if props[n] == nil {
+					panic(fmt.Errorf("dependentRequired property %s not found in %s: %w", n, t, ErrSchemaInvalid))
+				}
 			}
 		}
+
 		s.Type = TypeObject
 		s.AdditionalProperties = false
+		for i := 0; i < t.NumField(); i++ {
+			// A `_` field can set tags for the struct itself.
+			if f := t.Field(i); f.Name == "_" && boolTag(f, "additionalProperties") {
+				s.AdditionalProperties = true
+			}
+		}
 		s.Properties = props
+		s.DependentRequired = dependentRequired
+		s.comparisons = comparisons
 		s.propertyNames = propNames
 		s.Required = required
 		s.requiredMap = requiredMap
@@ -473,5 +946,15 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 		return nil
 	}
 
+	if s.Type == TypeInteger {
+		// Values are range checked against the Go type, since the documented
+		// format may not match it exactly, e.g. `uint32` uses `int32`.
+		s.goInt, s.goIntFormat = t.Kind().String(), s.Format
+	}
+
+	if st, ok := reflect.New(t).Interface().(SchemaTransformer); ok {
+		return st.TransformSchema(r, &s)
+	}
+
 	return &s
 }
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"math/bits"
 	"net"
+	"net/netip"
 	"net/url"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/goccy/go-yaml"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -97,6 +100,30 @@ func TestSchema(t *testing.T) {
 			input:    net.IPv4(127, 0, 0, 1),
 			expected: `{"type": "string", "format": "ipv4"}`,
 		},
+		{
+			name: "field-raw-json",
+			input: struct {
+				Value json.RawMessage `json:"value" doc:"Anything"`
+			}{},
+			expected: `{
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["value"],
+				"properties": {
+					"value": {"description": "Anything"}
+				}
+			}`,
+		},
+		{
+			name:     "uuid",
+			input:    uuid.UUID{},
+			expected: `{"type": "string", "format": "uuid"}`,
+		},
+		{
+			name:     "text-marshaler",
+			input:    netip.Addr{},
+			expected: `{"type": "string"}`,
+		},
 		{
 			name:     "bytes",
 			input:    []byte("test"),
@@ -256,6 +283,29 @@ func TestSchema(t *testing.T) {
 				"required": ["value"]
 			}`,
 		},
+		{
+			name: "field-comparison",
+			input: struct {
+				Min int `json:"min"`
+				Max int `json:"max" doc:"Upper bound" gteField:"Min"`
+			}{},
+			expected: `{
+				"type": "object",
+				"properties": {
+					"min": {
+						"type": "integer",
+						"format": "int64"
+					},
+					"max": {
+						"type": "integer",
+						"format": "int64",
+						"description": "Upper bound\n\nMust be greater than or equal to ` + "`min`" + `."
+					}
+				},
+				"additionalProperties": false,
+				"required": ["min", "max"]
+			}`,
+		},
 		{
 			name: "field-default-string",
 			input: struct {
@@ -368,6 +418,42 @@ func TestSchema(t *testing.T) {
 				}
 			}`,
 		},
+		{
+			name: "field-extensions",
+			input: struct {
+				Value string `json:"value" extensions:"x-internal=true, x-order=3, x-owner=billing"`
+			}{},
+			expected: `{
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["value"],
+				"properties": {
+					"value": {
+						"type": "string",
+						"x-internal": true,
+						"x-order": 3,
+						"x-owner": "billing"
+					}
+				}
+			}`,
+		},
+		{
+			name: "field-extensions-json",
+			input: struct {
+				Value string `json:"value" extensions:"{\"x-display\": {\"widget\": \"textarea\", \"rows\": 5}}"`
+			}{},
+			expected: `{
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["value"],
+				"properties": {
+					"value": {
+						"type": "string",
+						"x-display": {"widget": "textarea", "rows": 5}
+					}
+				}
+			}`,
+		},
 		{
 			name: "panic-bool",
 			input: struct {
@@ -389,6 +475,20 @@ func TestSchema(t *testing.T) {
 			}{},
 			panics: "invalid float tag 'minimum' for field 'Value': bad (strconv.ParseFloat: parsing \"bad\": invalid syntax)",
 		},
+		{
+			name: "panic-extensions",
+			input: struct {
+				Value string `json:"value" extensions:"x-internal"`
+			}{},
+			panics: "invalid extensions tag for field 'Value': x-internal",
+		},
+		{
+			name: "panic-duration",
+			input: struct {
+				Value time.Duration `json:"value" format:"duration"`
+			}{},
+			panics: "format duration for field 'Value' is only supported for time.Duration parameters, use a string field with huma.ParseDuration instead",
+		},
 		{
 			name: "panic-json",
 			input: struct {
@@ -415,6 +515,50 @@ func TestSchema(t *testing.T) {
 	}
 }
 
+func TestSchemaUnmarshal(t *testing.T) {
+	input := `{
+		"type": "object",
+		"x-internal": true,
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "x-order": 1},
+			"nickname": {"type": ["string", "null"]}
+		},
+		"additionalProperties": {"type": "integer", "x-order": 2}
+	}`
+
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			var s Schema
+			if format == "json" {
+				assert.NoError(t, json.Unmarshal([]byte(input), &s))
+			} else {
+				b, err := yaml.JSONToYAML([]byte(input))
+				assert.NoError(t, err)
+				assert.NoError(t, yaml.Unmarshal(b, &s))
+			}
+
+			assert.Equal(t, map[string]any{"x-internal": true}, s.Extensions)
+			assert.EqualValues(t, 1, s.Properties["name"].Extensions["x-order"])
+			assert.IsType(t, &Schema{}, s.AdditionalProperties)
+			assert.Equal(t, TypeString, s.Properties["nickname"].Type)
+			assert.True(t, s.Properties["nickname"].Nullable)
+
+			// Round-trips without losing or duplicating keywords.
+			b, err := json.Marshal(&s)
+			assert.NoError(t, err)
+			assert.JSONEq(t, input, string(b))
+
+			// The schema is ready to use for validation.
+			r := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+			pb := NewPathBuffer([]byte{}, 0)
+			res := &ValidateResult{}
+			Validate(r, &s, pb, ModeWriteToServer, map[string]any{"name": "", "nickname": nil}, res)
+			assert.Len(t, res.Errors, 1)
+		})
+	}
+}
+
 type GreetingInput struct {
 	ID string `path:"id"`
 }
@@ -470,6 +614,9 @@ func TestSchemaGenericNaming(t *testing.T) {
 	assert.JSONEq(t, `{
 		"$ref": "#/components/schemas/SchemaGenericint"
 	}`, string(b))
+
+	// Package paths of type arguments are removed.
+	assert.Equal(t, "SchemaGenericURL", DefaultSchemaNamer(reflect.TypeOf(SchemaGeneric[url.URL]{}), ""))
 }
 
 type BenchSub struct {
@@ -0,0 +1,230 @@
+package huma
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NewBearerAuthScheme creates an HTTP bearer token security scheme. The
+// `bearerFormat` is an optional hint like `JWT`.
+func NewBearerAuthScheme(bearerFormat string) *SecurityScheme {
+	return &SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: bearerFormat,
+	}
+}
+
+// NewBasicAuthScheme creates an HTTP basic auth security scheme.
+func NewBasicAuthScheme() *SecurityScheme {
+	return &SecurityScheme{
+		Type:   "http",
+		Scheme: "basic",
+	}
+}
+
+// NewAPIKeyScheme creates an API key security scheme, where `in` is one of
+// `query`, `header`, or `cookie` and `name` is the parameter name, e.g.
+// `X-API-Key`.
+func NewAPIKeyScheme(in, name string) *SecurityScheme {
+	switch in {
+	case "query", "header", "cookie":
+	default:
+		panic("api key location must be query, header, or cookie, got " + in)
+	}
+	return &SecurityScheme{
+		Type: "apiKey",
+		In:   in,
+		Name: name,
+	}
+}
+
+// NewOAuth2Scheme creates an OAuth 2.0 security scheme using the given flows.
+func NewOAuth2Scheme(flows *OAuthFlows) *SecurityScheme {
+	return &SecurityScheme{
+		Type:  "oauth2",
+		Flows: flows,
+	}
+}
+
+// NewOpenIDConnectScheme creates an OpenID Connect security scheme using the
+// given discovery URL, e.g. `https://example.com/.well-known/openid-configuration`.
+func NewOpenIDConnectScheme(url string) *SecurityScheme {
+	return &SecurityScheme{
+		Type:             "openIdConnect",
+		OpenIDConnectURL: url,
+	}
+}
+
+// hasScope returns whether any of the OAuth 2.0 flows defines the scope.
+func (f *OAuthFlows) hasScope(scope string) bool {
+	for _, flow := range []*OAuthFlow{f.Implicit, f.Password, f.ClientCredentials, f.AuthorizationCode} {
+		if flow != nil {
+			if _, ok := flow.Scopes[scope]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateSecurity ensures every security requirement references a declared
+// security scheme, and OAuth 2.0 scopes are declared by one of its flows.
+func validateSecurity(oapi *OpenAPI, security []map[string][]string) error {
+	for _, requirement := range security {
+		for name, scopes := range requirement {
+			var scheme *SecurityScheme
+			if oapi.Components != nil {
+				scheme = oapi.Components.SecuritySchemes[name]
+			}
+			if scheme == nil {
+				return fmt.Errorf("unknown security scheme %s", name)
+			}
+			if scheme.Type == "oauth2" && scheme.Flows != nil {
+				for _, scope := range scopes {
+					if !scheme.Flows.hasScope(scope) {
+						return fmt.Errorf("unknown scope %s for security scheme %s", scope, name)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Authenticator enforces a security requirement declared on an operation or
+// API. It is called with the name of a scheme from
+// `Components.SecuritySchemes` and the scopes required by the operation, and
+// returns nil if the request satisfies it. Returning a `StatusError` (e.g.
+// `huma.Error403Forbidden(...)`) sends that error, while any other error
+// results in a 401 Unauthorized. See `Config.Authenticator`.
+type Authenticator func(ctx Context, scheme string, scopes []string) error
+
+type authAdapter struct {
+	Adapter
+	api          API
+	authenticate Authenticator
+}
+
+func (a *authAdapter) Handle(op *Operation, handler func(Context)) {
+	a.Adapter.Handle(op, func(ctx Context) {
+		oapi := a.api.OpenAPI()
+		security := op.Security
+		if security == nil {
+			security = oapi.Security
+		}
+		if err := a.check(ctx, security); err != nil {
+			if err.GetStatus() == http.StatusUnauthorized {
+				challenge(ctx, oapi, security)
+			}
+			writeStatusErr(a.api, ctx, err)
+			return
+		}
+		handler(ctx)
+	})
+}
+
+// check returns nil if any of the security requirements is met. Each
+// requirement is met if all of its schemes are. Otherwise the error from the
+// first requirement is returned.
+func (a *authAdapter) check(ctx Context, security []map[string][]string) StatusError {
+	var first StatusError
+	for _, requirement := range security {
+		var err error
+		if len(requirement) == 1 {
+			for name, scopes := range requirement {
+				err = a.authenticate(ctx, name, scopes)
+			}
+		} else {
+			// Use a stable order so the same error is returned each time.
+			names := make([]string, 0, len(requirement))
+			for name := range requirement {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if err = a.authenticate(ctx, name, requirement[name]); err != nil {
+					break
+				}
+			}
+		}
+		if err == nil {
+			return nil
+		}
+		if first == nil {
+			var se StatusError
+			if errors.As(err, &se) {
+				first = se
+			} else {
+				first = newAPIError(a.api, http.StatusUnauthorized, err.Error())
+			}
+		}
+	}
+	return first
+}
+
+// challenge tells the client how to authenticate via `WWW-Authenticate`
+// headers for any HTTP auth schemes like `Bearer` or `Basic`.
+func challenge(ctx Context, oapi *OpenAPI, security []map[string][]string) {
+	seen := map[string]bool{}
+	for _, requirement := range security {
+		for name := range requirement {
+			scheme := oapi.Components.SecuritySchemes[name]
+			if scheme == nil || scheme.Type != "http" || scheme.Scheme == "" || seen[scheme.Scheme] {
+				continue
+			}
+			seen[scheme.Scheme] = true
+			ctx.AppendHeader("WWW-Authenticate", strings.ToUpper(scheme.Scheme[:1])+scheme.Scheme[1:])
+		}
+	}
+}
+
+// documentErrors adds 401 & 403 responses to operations with security
+// requirements when an authenticator is enforcing them.
+func (a *authAdapter) documentErrors(oapi *OpenAPI, op *Operation) {
+	security := op.Security
+	if security == nil {
+		security = oapi.Security
+	}
+	if len(security) == 0 {
+		return
+	}
+	documentErrors(oapi, op, newAPIError(a.api, 0, ""), http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// documentErrors adds responses using the error model for the status codes
+// which the operation doesn't already document, unless they are covered by
+// its default error response. The `exampleErr` determines the error type.
+func documentErrors(oapi *OpenAPI, op *Operation, exampleErr StatusError, codes ...int) {
+	if op.DefaultErrorResponse && op.Responses["default"] != nil {
+		return
+	}
+	errContentType := "application/json"
+	if ctf, ok := exampleErr.(ContentTypeFilter); ok {
+		errContentType = ctf.ContentType(errContentType)
+	}
+	errType := reflect.TypeOf(exampleErr)
+	errSchema := oapi.Components.Schemas.Schema(errType, true, getHint(errType, "", "Error"))
+	for _, code := range codes {
+		status := strconv.Itoa(code)
+		if op.Responses[status] != nil {
+			continue
+		}
+		if op.Responses == nil {
+			op.Responses = map[string]*Response{}
+		}
+		op.Responses[status] = &Response{
+			Description: http.StatusText(code),
+			Content: map[string]*MediaType{
+				errContentType: {
+					Schema: errSchema,
+				},
+			},
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package huma
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+// NewServer creates a server for the OpenAPI `Servers` list. The URL may
+// contain variables like `https://{region}.example.com:{port}`, which are
+// described using `Var`.
+//
+//	config.Servers = []*huma.Server{
+//		huma.NewServer("https://{region}.example.com", "Production").
+//			Var("region", "us", "us", "eu"),
+//		huma.NewServer("http://localhost:{port}", "Local development").
+//			Var("port", "8888"),
+//	}
+func NewServer(url, description string) *Server {
+	return &Server{URL: url, Description: description}
+}
+
+// Var describes a variable in the server URL with its default value and the
+// allowed values, if limited. It panics if the URL has no such variable or
+// the default isn't allowed, and returns the server for chaining.
+func (s *Server) Var(name, defaultValue string, enum ...string) *Server {
+	if !strings.Contains(s.URL, "{"+name+"}") {
+		panic(fmt.Sprintf("server %s has no variable %s", s.URL, name))
+	}
+	if len(enum) > 0 && !slices.Contains(enum, defaultValue) {
+		panic(fmt.Sprintf("server %s variable %s default %s must be one of %v", s.URL, name, defaultValue, enum))
+	}
+	if s.Variables == nil {
+		s.Variables = map[string]*ServerVariable{}
+	}
+	s.Variables[name] = &ServerVariable{Default: defaultValue, Enum: enum}
+	return s
+}
+
+// requestServerURL returns the URL of the server the request was sent to. See
+// `requestOrigin`.
+func requestServerURL(ctx Context, trustForwarded bool) string {
+	scheme, host := requestOrigin(ctx, trustForwarded)
+	return scheme + "://" + host
+}
+
+// requestOrigin returns the scheme & host the request was sent to. If the
+// proxy headers are trusted, the `Forwarded` or `X-Forwarded-Proto` &
+// `X-Forwarded-Host` headers are used when set. Otherwise the scheme is
+// `https` if the request was made over TLS. See
+// `Config.TrustForwardedHeaders`.
+func requestOrigin(ctx Context, trustForwarded bool) (scheme, host string) {
+	if !trustForwarded {
+		return requestScheme(ctx), ctx.Host()
+	}
+	if forwarded := ctx.Header("Forwarded"); forwarded != "" {
+		// Only the first proxy's element matters, e.g.
+		// `for=1.2.3.4;proto=https;host=example.com, for=5.6.7.8`.
+		first, _, _ := strings.Cut(forwarded, ",")
+		for _, pair := range strings.Split(first, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			v = strings.Trim(v, `"`)
+			switch strings.ToLower(k) {
+			case "proto":
+				scheme = v
+			case "host":
+				host = v
+			}
+		}
+	}
+	if scheme == "" {
+		scheme, _, _ = strings.Cut(ctx.Header("X-Forwarded-Proto"), ",")
+	}
+	if host == "" {
+		host, _, _ = strings.Cut(ctx.Header("X-Forwarded-Host"), ",")
+	}
+	scheme, host = strings.TrimSpace(scheme), strings.TrimSpace(host)
+	if scheme == "" {
+		scheme = requestScheme(ctx)
+	}
+	if host == "" {
+		host = ctx.Host()
+	}
+	return scheme, host
+}
+
+// requestScheme returns the scheme of the connection the request was sent on.
+func requestScheme(ctx Context) string {
+	if scheme := ctx.URL().Scheme; scheme != "" {
+		return scheme
+	}
+	if ctx.TLS() != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// withServer returns a copy of the spec with the server URL listed first, if
+// it isn't listed already.
+func withServer(o *OpenAPI, url string) *OpenAPI {
+	for _, s := range o.Servers {
+		if s.URL == url {
+			return o
+		}
+	}
+	c := *o
+	c.Servers = append([]*Server{{URL: url}}, o.Servers...)
+	return &c
+}
+
+// maxServerSpecs limits the number of marshalled specs cached by server URL,
+// since the URL comes from the request.
+const maxServerSpecs = 64
+
+// serverSpecs caches a marshalled spec for each request server URL.
+type serverSpecs struct {
+	mu    sync.Mutex
+	specs map[string][]byte
+}
+
+func (c *serverSpecs) get(url string, marshal func() []byte) []byte {
+	c.mu.Lock()
+	b, ok := c.specs[url]
+	c.mu.Unlock()
+	if ok {
+		return b
+	}
+
+	b = marshal()
+	c.mu.Lock()
+	if c.specs == nil {
+		c.specs = map[string][]byte{}
+	}
+	if len(c.specs) < maxServerSpecs {
+		c.specs[url] = b
+	}
+	c.mu.Unlock()
+	return b
+}
@@ -0,0 +1,92 @@
+package huma
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServer(t *testing.T) {
+	s := NewServer("https://{region}.example.com:{port}", "Production").
+		Var("region", "us", "us", "eu").
+		Var("port", "443")
+
+	assert.Equal(t, "Production", s.Description)
+	assert.Equal(t, &ServerVariable{Default: "us", Enum: []string{"us", "eu"}}, s.Variables["region"])
+	assert.Equal(t, &ServerVariable{Default: "443"}, s.Variables["port"])
+
+	assert.PanicsWithValue(t, "server https://example.com has no variable region", func() {
+		NewServer("https://example.com", "").Var("region", "us")
+	})
+	assert.Panics(t, func() {
+		NewServer("https://{region}.example.com", "").Var("region", "ap", "us", "eu")
+	})
+}
+
+func TestOpenAPIRequestServer(t *testing.T) {
+	var r *chi.Mux
+	newRouter := func(trustForwarded bool) {
+		r = chi.NewRouter()
+		config := DefaultConfig("Test API", "1.0.0")
+		config.Servers = []*Server{NewServer("https://api.example.com", "Production")}
+		config.OpenAPIRequestServer = true
+		config.TrustForwardedHeaders = trustForwarded
+		NewTestAdapter(r, config)
+	}
+
+	servers := func(path string, headers map[string]string) []string {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		req.Host = "localhost:8888"
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var spec struct {
+			Servers []struct {
+				URL string `json:"url"`
+			} `json:"servers"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+		urls := []string{}
+		for _, s := range spec.Servers {
+			urls = append(urls, s.URL)
+		}
+		return urls
+	}
+
+	newRouter(false)
+	assert.Equal(t, []string{"http://localhost:8888", "https://api.example.com"}, servers("/openapi.json", nil))
+	assert.Equal(t, []string{"http://localhost:8888", "https://api.example.com"}, servers("/openapi-3.0.json", nil))
+
+	// Proxy headers are ignored unless trusted.
+	assert.Equal(t, []string{"http://localhost:8888", "https://api.example.com"}, servers("/openapi.json", map[string]string{
+		"X-Forwarded-Proto": "https",
+		"X-Forwarded-Host":  "proxy.example.com",
+	}))
+
+	// TLS connections use HTTPS.
+	req, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	req.Host = "api.example.com"
+	req.TLS = &tls.ConnectionState{}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), `"servers":[{"url":"https://api.example.com","description":"Production"}]`)
+
+	newRouter(true)
+	assert.Equal(t, []string{"https://proxy.example.com", "https://api.example.com"}, servers("/openapi.json", map[string]string{
+		"X-Forwarded-Proto": "https",
+		"X-Forwarded-Host":  "proxy.example.com, internal",
+	}))
+	assert.Equal(t, []string{"https://api.example.com"}, servers("/openapi.json", map[string]string{
+		"X-Forwarded-Proto": "https",
+		"X-Forwarded-Host":  "api.example.com",
+	}), "servers already listed aren't repeated")
+}
@@ -0,0 +1,93 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/danielgtaylor/casing"
+)
+
+// GenerateOperationID generates an operation ID from the method & path, like
+// `get-things-by-thing-id` for `GET /things/{thing-id}`. A `GET` operation
+// whose response body is a slice is a `list`, e.g. `list-things`. The response
+// is the output struct or a pointer to it, and may be nil.
+func GenerateOperationID(method, path string, response any) string {
+	action := strings.ToLower(method)
+	if method == http.MethodGet && response != nil {
+		if f, ok := deref(reflect.TypeOf(response)).FieldByName("Body"); ok && deref(f.Type).Kind() == reflect.Slice {
+			action = "list"
+		}
+	}
+
+	words := []string{action}
+	for _, part := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(part, "{"); ok {
+			words = append(words, "by", strings.TrimSuffix(name, "}"))
+			continue
+		}
+		words = append(words, part)
+	}
+	return casing.Kebab(strings.Join(words, " "))
+}
+
+// GenerateSummary generates a summary from the method & path, like
+// `Get things by thing id` for `GET /things/{thing-id}`. See
+// `GenerateOperationID` for details.
+func GenerateSummary(method, path string, response any) string {
+	summary := strings.ReplaceAll(GenerateOperationID(method, path, response), "-", " ")
+	return strings.ToUpper(summary[:1]) + summary[1:]
+}
+
+// convenience registers an operation for the method & path, generating its
+// ID & summary, then applying the operation handlers.
+func convenience[I, O any](api API, method, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	var o *O
+	op := Operation{
+		OperationID: GenerateOperationID(method, path, o),
+		Summary:     GenerateSummary(method, path, o),
+		Method:      method,
+		Path:        path,
+	}
+	for _, oh := range operationHandlers {
+		oh(&op)
+	}
+	Register(api, op, handler)
+}
+
+// Get registers a `GET` operation handler with a generated operation ID &
+// summary. Operation handlers can modify the operation before registration,
+// e.g. to add tags or override the generated values.
+//
+//	// Registers `list-things` with the summary `List things`.
+//	huma.Get(api, "/things", func(ctx context.Context, input *struct{}) (*ListThingsOutput, error) {
+//		// ...
+//	})
+func Get[I, O any](api API, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	convenience(api, http.MethodGet, path, handler, operationHandlers...)
+}
+
+// Post registers a `POST` operation handler with a generated operation ID &
+// summary. See `Get` for details.
+func Post[I, O any](api API, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	convenience(api, http.MethodPost, path, handler, operationHandlers...)
+}
+
+// Put registers a `PUT` operation handler with a generated operation ID &
+// summary. See `Get` for details.
+func Put[I, O any](api API, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	convenience(api, http.MethodPut, path, handler, operationHandlers...)
+}
+
+// Patch registers a `PATCH` operation handler with a generated operation ID &
+// summary. See `Get` for details.
+func Patch[I, O any](api API, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	convenience(api, http.MethodPatch, path, handler, operationHandlers...)
+}
+
+// Delete registers a `DELETE` operation handler with a generated operation ID
+// & summary. See `Get` for details.
+func Delete[I, O any](api API, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	convenience(api, http.MethodDelete, path, handler, operationHandlers...)
+}
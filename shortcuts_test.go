@@ -0,0 +1,105 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type ShortcutItemsOutput struct {
+	Body []string
+}
+
+type ShortcutItemOutput struct {
+	Body string
+}
+
+func TestGenerateOperationID(t *testing.T) {
+	for _, item := range []struct {
+		method   string
+		path     string
+		response any
+		id       string
+		summary  string
+	}{
+		{http.MethodGet, "/items", &ShortcutItemsOutput{}, "list-items", "List items"},
+		{http.MethodGet, "/items/{item-id}", ShortcutItemOutput{}, "get-items-by-item-id", "Get items by item id"},
+		{http.MethodPost, "/items", nil, "post-items", "Post items"},
+		{http.MethodPut, "/users/{userID}/items/{item-id}", nil, "put-users-by-user-id-items-by-item-id", "Put users by user id items by item id"},
+		{http.MethodDelete, "/", nil, "delete", "Delete"},
+	} {
+		assert.Equal(t, item.id, GenerateOperationID(item.method, item.path, item.response), item.path)
+		assert.Equal(t, item.summary, GenerateSummary(item.method, item.path, item.response), item.path)
+	}
+}
+
+func TestShortcuts(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	Get(app, "/items", func(ctx context.Context, input *struct{}) (*ShortcutItemsOutput, error) {
+		return &ShortcutItemsOutput{Body: []string{"a"}}, nil
+	})
+	Get(app, "/items/{item-id}", func(ctx context.Context, input *struct {
+		ID string `path:"item-id"`
+	}) (*ShortcutItemOutput, error) {
+		return &ShortcutItemOutput{Body: input.ID}, nil
+	})
+	Post(app, "/items", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}, func(o *Operation) {
+		o.OperationID = "create-item"
+		o.DefaultStatus = http.StatusCreated
+	})
+	Put(app, "/items/{item-id}", func(ctx context.Context, input *struct {
+		ID string `path:"item-id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+	Patch(app, "/items/{item-id}", func(ctx context.Context, input *struct {
+		ID string `path:"item-id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+	Delete(app, "/items/{item-id}", func(ctx context.Context, input *struct {
+		ID string `path:"item-id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	paths := app.OpenAPI().Paths
+	assert.Equal(t, "list-items", paths["/items"].Get.OperationID)
+	assert.Equal(t, "List items", paths["/items"].Get.Summary)
+	assert.Equal(t, "create-item", paths["/items"].Post.OperationID)
+	assert.Equal(t, "Post items", paths["/items"].Post.Summary)
+	assert.Equal(t, "get-items-by-item-id", paths["/items/{item-id}"].Get.OperationID)
+	assert.Equal(t, "put-items-by-item-id", paths["/items/{item-id}"].Put.OperationID)
+	assert.Equal(t, "patch-items-by-item-id", paths["/items/{item-id}"].Patch.OperationID)
+	assert.Equal(t, "delete-items-by-item-id", paths["/items/{item-id}"].Delete.OperationID)
+
+	for _, item := range []struct {
+		method string
+		url    string
+		status int
+		body   string
+	}{
+		{http.MethodGet, "/items", http.StatusOK, `["a"]`},
+		{http.MethodGet, "/items/foo", http.StatusOK, `"foo"`},
+		{http.MethodPost, "/items", http.StatusCreated, ""},
+		{http.MethodPut, "/items/foo", http.StatusNoContent, ""},
+		{http.MethodPatch, "/items/foo", http.StatusNoContent, ""},
+		{http.MethodDelete, "/items/foo", http.StatusNoContent, ""},
+	} {
+		req, _ := http.NewRequest(item.method, item.url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, item.status, w.Code, item.method+" "+item.url)
+		if item.body != "" {
+			assert.JSONEq(t, item.body, w.Body.String())
+		}
+	}
+}
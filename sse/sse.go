@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"reflect"
 	"runtime/debug"
 	"time"
@@ -46,8 +45,8 @@ func (s Sender) Data(data any) error {
 // Register a new SSE operation. The `eventTypeMap` maps from event name to
 // the type of the data that will be sent. The `f` function is called with
 // the context, input, and a `send` function that can be used to send messages
-// to the client. Flushing is handled automatically as long as the adapter's
-// `BodyWriter` implements `http.Flusher`.
+// to the client. Flushing is handled automatically via `huma.Flush` as long as the
+// adapter supports it.
 func Register[I any](api huma.API, op huma.Operation, eventTypeMap map[string]any, f func(ctx context.Context, input *I, send Sender)) {
 	// Start by defining the SSE schema & operation response.
 	if op.Responses == nil {
@@ -120,9 +119,7 @@ func Register[I any](api huma.API, op huma.Operation, eventTypeMap map[string]an
 				bw := ctx.BodyWriter()
 				encoder := json.NewEncoder(bw)
 				send := func(msg Message) error {
-					if d, ok := bw.(interface{ SetWriteDeadline(time.Time) error }); ok {
-						d.SetWriteDeadline(time.Now().Add(WriteTimeout))
-					} else {
+					if err := ctx.SetWriteDeadline(time.Now().Add(WriteTimeout)); err != nil {
 						fmt.Println("warning: unable to set write deadline")
 					}
 
@@ -155,11 +152,9 @@ func Register[I any](api huma.API, op huma.Operation, eventTypeMap map[string]an
 						return err
 					}
 					bw.Write([]byte("\n"))
-					if f, ok := bw.(http.Flusher); ok {
-						f.Flush()
-					} else {
+					if err := huma.Flush(ctx); err != nil {
 						fmt.Println("error: unable to flush")
-						return fmt.Errorf("unable to flush: %w", http.ErrNotSupported)
+						return fmt.Errorf("unable to flush: %w", err)
 					}
 					return nil
 				}
@@ -0,0 +1,43 @@
+package huma
+
+import "fmt"
+
+// NewTag creates a tag for the OpenAPI `Tags` list, which documents the tag
+// used to group operations. Docs renderers generally list tags in the order
+// they are declared.
+//
+//	config.Tags = []*huma.Tag{
+//		huma.NewTag("users", "Manage user accounts"),
+//		huma.NewTag("billing", "Invoices & payments").
+//			Docs("https://example.com/billing", "Billing guide"),
+//	}
+func NewTag(name, description string) *Tag {
+	return &Tag{Name: name, Description: description}
+}
+
+// Docs links the tag to external documentation, and returns the tag for
+// chaining.
+func (t *Tag) Docs(url, description string) *Tag {
+	t.ExternalDocs = &ExternalDocs{URL: url, Description: description}
+	return t
+}
+
+// AddTag declares a tag after any already declared, e.g. from a package
+// which registers its own operations. It panics if the tag is already
+// declared.
+func (o *OpenAPI) AddTag(tag *Tag) {
+	if o.tag(tag.Name) != nil {
+		panic(fmt.Sprintf("duplicate tag %s", tag.Name))
+	}
+	o.Tags = append(o.Tags, tag)
+}
+
+// tag returns the declared tag with the given name, if any.
+func (o *OpenAPI) tag(name string) *Tag {
+	for _, t := range o.Tags {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
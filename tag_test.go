@@ -0,0 +1,59 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Tags = []*Tag{
+		NewTag("users", "Manage users"),
+		NewTag("billing", "Invoices").Docs("https://example.com/billing", "Billing guide"),
+	}
+	config.StrictTags = true
+	app := NewTestAdapter(chi.NewRouter(), config)
+
+	app.OpenAPI().AddTag(NewTag("admin", ""))
+	assert.PanicsWithValue(t, "duplicate tag users", func() {
+		app.OpenAPI().AddTag(NewTag("users", ""))
+	})
+
+	tags := app.OpenAPI().Tags
+	assert.Equal(t, []string{"users", "billing", "admin"}, []string{tags[0].Name, tags[1].Name, tags[2].Name})
+	assert.Equal(t, &ExternalDocs{URL: "https://example.com/billing", Description: "Billing guide"}, tags[1].ExternalDocs)
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}
+	admin := NewGroup(app, "/admin")
+	admin.Tags = []string{"admin"}
+	Register(admin, Operation{
+		OperationID: "list-users",
+		Method:      http.MethodGet,
+		Path:        "/users",
+		Tags:        []string{"users"},
+	}, handler)
+
+	assert.PanicsWithValue(t, "operation list-invoices: undeclared tag invoices", func() {
+		Register(app, Operation{
+			OperationID: "list-invoices",
+			Method:      http.MethodGet,
+			Path:        "/invoices",
+			Tags:        []string{"billing", "invoices"},
+		}, handler)
+	})
+	assert.PanicsWithValue(t, "operation list-groups: undeclared tag groups", func() {
+		group := NewGroup(app, "/v1")
+		group.Tags = []string{"groups"}
+		Register(group, Operation{
+			OperationID: "list-groups",
+			Method:      http.MethodGet,
+			Path:        "/groups",
+		}, handler)
+	})
+}
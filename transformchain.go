@@ -0,0 +1,182 @@
+package huma
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+// TransformerEntry is a transformer added with `AddTransformer`, with a
+// priority and an optional scope.
+type TransformerEntry struct {
+	// Name identifies the transformer in `TransformerChain`. Defaults to the
+	// name of the function.
+	Name string
+
+	// Priority orders the transformers, with higher priorities running first.
+	// Transformers with the same priority run in the order they were added.
+	// The `Config.Transformers` have a priority of zero.
+	Priority int
+
+	// Tags limits the transformer to operations with any of these tags. If
+	// empty, it runs for every operation.
+	Tags []string
+
+	// Transform is the transformer to run.
+	Transform Transformer
+}
+
+// transformerRegistry is implemented by APIs which support `AddTransformer`.
+type transformerRegistry interface {
+	addTransformer(entry TransformerEntry)
+	transformerChain(op *Operation) []TransformerEntry
+}
+
+// transformersOf returns the transformer registry for an API, unwrapping
+// APIs like the ones from `humatest.New` if needed.
+func transformersOf(api API) transformerRegistry {
+	if g, ok := api.(*Group); ok {
+		return g
+	}
+	if r := baseAPI(api); r != nil {
+		return r
+	}
+	return nil
+}
+
+// AddTransformer adds a transformer to the API with a priority and an
+// optional tag scope. Transformers for a single operation can instead be set
+// via `Operation.Transformers`, which run after the API's transformers.
+//
+//	// Redact sensitive fields before any other transformers run.
+//	huma.AddTransformer(api, huma.TransformerEntry{
+//		Name:      "redact",
+//		Priority:  100,
+//		Transform: huma.RedactTransform,
+//	})
+//
+//	// Only select fields for operations tagged `search`.
+//	huma.AddTransformer(api, huma.TransformerEntry{
+//		Tags:      []string{"search"},
+//		Transform: huma.FieldSelectTransform,
+//	})
+func AddTransformer(api API, entry TransformerEntry) {
+	r := transformersOf(api)
+	if r == nil {
+		panic("API does not support adding transformers")
+	}
+	if entry.Transform == nil {
+		panic("transformer " + entry.Name + " is nil")
+	}
+	if entry.Name == "" {
+		entry.Name = funcName(entry.Transform)
+	}
+	r.addTransformer(entry)
+}
+
+// TransformerChain returns the names of the transformers which run for the
+// operation's responses, in order, for debugging.
+func TransformerChain(api API, op *Operation) []string {
+	r := transformersOf(api)
+	if r == nil {
+		return nil
+	}
+	return mapTo(r.transformerChain(op), func(e TransformerEntry) string {
+		return e.Name
+	})
+}
+
+// funcName returns the full name of a function, like
+// `github.com/danielgtaylor/huma/v2.FieldSelectTransform`.
+func funcName(f any) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer())
+	if fn == nil {
+		return fmt.Sprintf("%p", f)
+	}
+	return strings.TrimSuffix(fn.Name(), "-fm")
+}
+
+// transformers holds the API's transformers and caches the chain for each
+// operation.
+type transformers struct {
+	mu      sync.Mutex
+	entries []TransformerEntry
+	chains  sync.Map
+}
+
+func newTransformers(ts []Transformer) *transformers {
+	t := &transformers{}
+	for _, tf := range ts {
+		t.entries = append(t.entries, TransformerEntry{Name: funcName(tf), Transform: tf})
+	}
+	return t
+}
+
+func (t *transformers) add(entry TransformerEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, entry)
+	sort.SliceStable(t.entries, func(i, j int) bool {
+		return t.entries[i].Priority > t.entries[j].Priority
+	})
+	t.chains.Range(func(key, value any) bool {
+		t.chains.Delete(key)
+		return true
+	})
+}
+
+// chain returns the transformers which run for the operation, which may be
+// nil for responses outside of an operation.
+func (t *transformers) chain(op *Operation) []TransformerEntry {
+	if cached, ok := t.chains.Load(op); ok {
+		return cached.([]TransformerEntry)
+	}
+
+	t.mu.Lock()
+	chain := []TransformerEntry{}
+	for _, e := range t.entries {
+		if len(e.Tags) > 0 && (op == nil || !slices.ContainsFunc(e.Tags, func(tag string) bool {
+			return slices.Contains(op.Tags, tag)
+		})) {
+			continue
+		}
+		chain = append(chain, e)
+	}
+	t.mu.Unlock()
+
+	if op != nil {
+		for _, tf := range op.Transformers {
+			chain = append(chain, TransformerEntry{Name: funcName(tf), Transform: tf})
+		}
+	}
+	t.chains.Store(op, chain)
+	return chain
+}
+
+func (r *api) addTransformer(entry TransformerEntry) {
+	r.transformers.add(entry)
+}
+
+func (r *api) transformerChain(op *Operation) []TransformerEntry {
+	return r.transformers.chain(op)
+}
+
+func (g *Group) addTransformer(entry TransformerEntry) {
+	r := transformersOf(g.API)
+	if r == nil {
+		panic("API does not support adding transformers")
+	}
+	r.addTransformer(entry)
+}
+
+func (g *Group) transformerChain(op *Operation) []TransformerEntry {
+	if r := transformersOf(g.API); r != nil {
+		return r.transformerChain(op)
+	}
+	return nil
+}
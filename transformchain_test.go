@@ -0,0 +1,74 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+// appendTransformer returns a transformer which appends to string bodies.
+func appendTransformer(suffix string) Transformer {
+	return func(ctx Context, status string, v any) (any, error) {
+		if s, ok := v.(string); ok {
+			return s + suffix, nil
+		}
+		return v, nil
+	}
+}
+
+func TestTransformerChain(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Transformers = append(config.Transformers, appendTransformer("-config"))
+	app := NewTestAdapter(r, config)
+
+	AddTransformer(app, TransformerEntry{Name: "low", Priority: -1, Transform: appendTransformer("-low")})
+	AddTransformer(app, TransformerEntry{Name: "high", Priority: 10, Transform: appendTransformer("-high")})
+	AddTransformer(app, TransformerEntry{Name: "admin", Tags: []string{"admin"}, Transform: appendTransformer("-admin")})
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{"body"}, nil
+	}
+
+	Register(app, Operation{
+		OperationID: "get-public",
+		Method:      http.MethodGet,
+		Path:        "/public",
+	}, handler)
+
+	admin := NewGroup(app, "/admin")
+	admin.Tags = []string{"admin"}
+	admin.Transformers = []Transformer{appendTransformer("-group")}
+	Register(admin, Operation{
+		OperationID:  "get-admin",
+		Method:       http.MethodGet,
+		Path:         "/thing",
+		Transformers: []Transformer{appendTransformer("-op")},
+	}, handler)
+
+	for url, expected := range map[string]string{
+		"/public":      `"body-high-config-low"`,
+		"/admin/thing": `"body-high-config-admin-low-group-op"`,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, url)
+		assert.JSONEq(t, expected, w.Body.String(), url)
+	}
+
+	chain := TransformerChain(admin, app.OpenAPI().Paths["/admin/thing"].Get)
+	assert.Len(t, chain, 7)
+	assert.Equal(t, "high", chain[0])
+	assert.Equal(t, "github.com/danielgtaylor/huma/v2.(*SchemaLinkTransformer).Transform", chain[1])
+	assert.Equal(t, "admin", chain[3])
+	assert.Equal(t, "low", chain[4])
+
+	assert.Panics(t, func() {
+		AddTransformer(app, TransformerEntry{Name: "nil"})
+	})
+}
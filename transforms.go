@@ -5,32 +5,117 @@ import (
 	"encoding/json"
 	"path"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 
-	"github.com/danielgtaylor/shorthand/v2"
+	"github.com/fxamacker/cbor/v2"
 )
 
 type schemaField struct {
 	Schema string `json:"$schema"`
 }
 
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// schemaLinked adds the `$schema` member to a value which marshals itself, so
+// that members which are not struct fields, like `ErrorModel.Extensions`, are
+// kept.
+type schemaLinked struct {
+	schema string
+	value  any
+}
+
+func (s schemaLinked) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(s.value)
+	if err != nil || len(b) < 2 || b[0] != '{' {
+		return b, err
+	}
+	field, err := json.Marshal(schemaField{Schema: s.schema})
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > 2 {
+		field[len(field)-1] = ','
+	} else {
+		field = field[:len(field)-1]
+	}
+	return append(field, b[1:]...), nil
+}
+
+func (s schemaLinked) MarshalCBOR() ([]byte, error) {
+	b, err := cborEncMode.Marshal(s.value)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := cbor.Unmarshal(b, &m); err != nil {
+		// Not a map, so there is nowhere to put the link.
+		return b, nil
+	}
+	m["$schema"] = s.schema
+	return cborEncMode.Marshal(m)
+}
+
+// linkedTypes maps the types created by `SchemaLinkTransformer` to add the
+// `$schema` field back to their original types.
+var linkedTypes sync.Map
+
+// unlinkValue returns the original value for values transformed by
+// `SchemaLinkTransformer`, for formats like CSV & HTML where the `$schema`
+// link doesn't apply.
+func unlinkValue(v any) any {
+	if s, ok := v.(schemaLinked); ok {
+		return s.value
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer {
+		return v
+	}
+	orig, ok := linkedTypes.Load(rv.Type().Elem())
+	if !ok {
+		return v
+	}
+	rv = rv.Elem()
+	out := reflect.New(orig.(reflect.Type)).Elem()
+	for i := 1; i < rv.NumField(); i++ {
+		out.FieldByName(rv.Type().Field(i).Name).Set(rv.Field(i))
+	}
+	return out.Addr().Interface()
+}
+
+// SchemaLinkTransformer adds a `describedBy` link header and a `$schema`
+// property to object responses, linking them to their JSON Schema. See
+// `NewSchemaLinkTransformer`.
 type SchemaLinkTransformer struct {
+	// BaseURL is the scheme & host used for `$schema` links, like
+	// `https://api.example.com`. If empty, it is taken from the request. See
+	// `Config.TrustForwardedHeaders`.
+	BaseURL string
+
 	prefix      string
 	schemasPath string
 	types       map[any]struct {
-		t      reflect.Type
-		ref    string
-		header string
+		t         reflect.Type
+		ref       string
+		header    string
+		marshaler bool
 	}
 }
 
+// NewSchemaLinkTransformer creates a transformer for schemas with the given
+// ref prefix, which are served at `schemasPath`. Its `OnAddOperation` method
+// must be added to the OpenAPI's `OnAddOperation` hooks, and its `Transform`
+// method to the API's transformers, as done by `DefaultConfig`.
 func NewSchemaLinkTransformer(prefix, schemasPath string) *SchemaLinkTransformer {
 	return &SchemaLinkTransformer{
 		prefix:      prefix,
 		schemasPath: schemasPath,
 		types: map[any]struct {
-			t      reflect.Type
-			ref    string
-			header string
+			t         reflect.Type
+			ref       string
+			header    string
+			marshaler bool
 		}{},
 	}
 }
@@ -65,9 +150,22 @@ func (t *SchemaLinkTransformer) OnAddOperation(oapi *OpenAPI, op *Operation) {
 				Schema: t.schemasPath + "/" + path.Base(content.Schema.Ref) + ".json",
 			}
 
-			fields := []reflect.StructField{
-				reflect.TypeOf(extra).Field(0),
+			if pt := reflect.PointerTo(typ); pt.Implements(jsonMarshalerType) || pt.Implements(relationsProviderType) {
+				// Copying the fields would lose the custom marshaling or
+				// relations, so wrap the value itself instead.
+				info := t.types[typ]
+				info.marshaler = true
+				info.ref = extra.Schema
+				info.header = "<" + extra.Schema + ">; rel=\"describedBy\""
+				t.types[typ] = info
+				continue
 			}
+
+			// Types with the same fields would share the same new type, so the
+			// original type is added to the tag to tell them apart.
+			linkField := reflect.TypeOf(extra).Field(0)
+			linkField.Tag += reflect.StructTag(` link:` + strconv.Quote(typ.PkgPath()+" "+typ.String()))
+			fields := []reflect.StructField{linkField}
 			for i := 0; i < typ.NumField(); i++ {
 				f := typ.Field(i)
 				if !f.IsExported() {
@@ -77,6 +175,7 @@ func (t *SchemaLinkTransformer) OnAddOperation(oapi *OpenAPI, op *Operation) {
 			}
 
 			newType := reflect.StructOf(fields)
+			linkedTypes.Store(newType, typ)
 			info := t.types[typ]
 			info.t = newType
 			info.ref = extra.Schema
@@ -98,13 +197,31 @@ func (t *SchemaLinkTransformer) Transform(ctx Context, status string, v any) (an
 	}
 
 	info := t.types[typ]
-	if info.t == nil {
+	if info.t == nil && !info.marshaler {
 		return v, nil
 	}
 
-	host := ctx.Header("Host")
 	ctx.AppendHeader("Link", info.header)
 
+	buf := bufPool.Get().(*bytes.Buffer)
+	if t.BaseURL != "" {
+		buf.WriteString(strings.TrimSuffix(t.BaseURL, "/"))
+	} else {
+		op := ctx.Operation()
+		scheme, host := requestOrigin(ctx, op != nil && op.trustForwarded)
+		buf.WriteString(scheme)
+		buf.WriteString("://")
+		buf.WriteString(host)
+	}
+	buf.WriteString(info.ref)
+	link := buf.String()
+	buf.Reset()
+	bufPool.Put(buf)
+
+	if info.marshaler {
+		return schemaLinked{schema: link, value: v}, nil
+	}
+
 	vv := reflect.Indirect(reflect.ValueOf(v))
 	tmp := reflect.New(info.t).Elem()
 	for i := 0; i < tmp.NumField(); i++ {
@@ -113,17 +230,7 @@ func (t *SchemaLinkTransformer) Transform(ctx Context, status string, v any) (an
 			continue
 		}
 		if i == 0 {
-			buf := bufPool.Get().(*bytes.Buffer)
-			if len(host) >= 9 && host[:9] == "localhost" {
-				buf.WriteString("http://")
-			} else {
-				buf.WriteString("https://")
-			}
-			buf.WriteString(host)
-			buf.WriteString(info.ref)
-			tmp.Field(i).SetString(buf.String())
-			buf.Reset()
-			bufPool.Put(buf)
+			tmp.Field(i).SetString(link)
 		} else {
 			tmp.Field(i).Set(vv.Field(i - 1))
 		}
@@ -131,18 +238,3 @@ func (t *SchemaLinkTransformer) Transform(ctx Context, status string, v any) (an
 
 	return tmp.Addr().Interface(), nil
 }
-
-// FieldSelectTransform is an example of a transform that can use an input
-// header value to modify the response on the server, providing a GraphQL-like
-// way to send only the fields that the client wants over the wire.
-func FieldSelectTransform(ctx Context, status string, v any) (any, error) {
-	if fields := ctx.Header("Fields"); fields != "" {
-		// Ugh this is inefficient... consider other ways of doing this :-(
-		var tmp any
-		b, _ := json.Marshal(v)
-		json.Unmarshal(b, &tmp)
-		result, _, err := shorthand.GetPath(fields, tmp, shorthand.GetOptions{})
-		return result, err
-	}
-	return v, nil
-}
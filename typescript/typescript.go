@@ -0,0 +1,483 @@
+// Package typescript generates TypeScript types from an API's schemas, and
+// optionally a `fetch`-based client for its operations, so frontends can use
+// types which come straight from the Go structs without a separate OpenAPI
+// code generation toolchain.
+//
+//	src := typescript.Generate(api.OpenAPI(), typescript.Options{Client: true})
+//	os.WriteFile("web/src/api.ts", []byte(src), 0o644)
+package typescript
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/danielgtaylor/casing"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+var rxIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Options configure the generated TypeScript.
+type Options struct {
+	// Client generates a `fetch`-based client class with a method per
+	// operation in addition to the types.
+	Client bool
+
+	// ClientName is the name of the client class. Defaults to `Client`.
+	ClientName string
+}
+
+// Generate returns the TypeScript source for the spec. Each schema in the
+// registry becomes an exported interface, or a type alias for non-object
+// schemas, with the schema's description as its doc comment. Required
+// properties are non-optional, read-only properties are `readonly`, and
+// enums become unions of their values.
+//
+// With `Options.Client`, a client class is generated with an `async` method
+// per operation, named after its operation ID, which takes the operation's
+// params & body as a single input object and returns its successful response
+// body. Error responses throw an `ApiError` with the status & decoded body.
+func Generate(oapi *huma.OpenAPI, opts Options) string {
+	g := &generator{oapi: oapi, buf: &strings.Builder{}}
+	g.buf.WriteString("// Code generated by huma. DO NOT EDIT.\n")
+
+	if oapi.Components != nil && oapi.Components.Schemas != nil {
+		schemas := oapi.Components.Schemas.Map()
+		names := make([]string, 0, len(schemas))
+		for name := range schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			g.declare(name, schemas[name])
+		}
+	}
+
+	if opts.Client {
+		name := opts.ClientName
+		if name == "" {
+			name = "Client"
+		}
+		g.client(name)
+	}
+	return g.buf.String()
+}
+
+type generator struct {
+	oapi *huma.OpenAPI
+	buf  *strings.Builder
+}
+
+// declare writes an exported declaration for a named schema.
+func (g *generator) declare(name string, s *huma.Schema) {
+	g.buf.WriteString("\n")
+	g.doc("", s.Description, s.Deprecated)
+	if s.Type == huma.TypeObject && s.Properties != nil && !s.Nullable {
+		fmt.Fprintf(g.buf, "export interface %s %s\n", name, g.object(s, ""))
+		return
+	}
+	fmt.Fprintf(g.buf, "export type %s = %s;\n", name, g.typeExpr(s, ""))
+}
+
+// doc writes a JSDoc comment at the indent, if there is anything to say.
+func (g *generator) doc(indent, description string, deprecated bool) {
+	lines := []string{}
+	if description != "" {
+		lines = append(lines, strings.Split(strings.ReplaceAll(description, "*/", "*\\/"), "\n")...)
+	}
+	if deprecated {
+		lines = append(lines, "@deprecated")
+	}
+	switch len(lines) {
+	case 0:
+	case 1:
+		fmt.Fprintf(g.buf, "%s/** %s */\n", indent, lines[0])
+	default:
+		fmt.Fprintf(g.buf, "%s/**\n", indent)
+		for _, line := range lines {
+			fmt.Fprintf(g.buf, "%s * %s\n", indent, line)
+		}
+		fmt.Fprintf(g.buf, "%s */\n", indent)
+	}
+}
+
+// typeExpr returns the TypeScript type for the schema.
+func (g *generator) typeExpr(s *huma.Schema, indent string) string {
+	if s == nil {
+		return "unknown"
+	}
+	t := g.baseType(s, indent)
+	if s.Nullable && t != "unknown" {
+		t += " | null"
+	}
+	return t
+}
+
+func (g *generator) baseType(s *huma.Schema, indent string) string {
+	if s.Ref != "" {
+		return refName(s.Ref)
+	}
+	if s.Const != nil {
+		return literal(s.Const)
+	}
+	if len(s.Enum) > 0 {
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			values[i] = literal(v)
+		}
+		return strings.Join(values, " | ")
+	}
+
+	switch s.Type {
+	case huma.TypeString:
+		return "string"
+	case huma.TypeInteger, huma.TypeNumber:
+		return "number"
+	case huma.TypeBoolean:
+		return "boolean"
+	case huma.TypeArray:
+		item := g.typeExpr(s.Items, indent)
+		if strings.Contains(item, " ") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case huma.TypeObject:
+		if s.Properties == nil {
+			if addl, ok := s.AdditionalProperties.(*huma.Schema); ok {
+				return "Record<string, " + g.typeExpr(addl, indent) + ">"
+			}
+			return "Record<string, unknown>"
+		}
+		return g.object(s, indent)
+	}
+	return "unknown"
+}
+
+// object returns an object type literal with the schema's properties.
+func (g *generator) object(s *huma.Schema, indent string) string {
+	required := map[string]bool{}
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Properties are written to a separate buffer as nested objects also write
+	// their doc comments.
+	outer := g.buf
+	g.buf = &strings.Builder{}
+	g.buf.WriteString("{\n")
+	inner := indent + "  "
+	for _, name := range names {
+		prop := s.Properties[name]
+		g.doc(inner, prop.Description, prop.Deprecated)
+		modifier := ""
+		if prop.ReadOnly {
+			modifier = "readonly "
+		}
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		fmt.Fprintf(g.buf, "%s%s%s%s: %s;\n", inner, modifier, propName(name), optional, g.typeExpr(prop, inner))
+	}
+	if addl, ok := s.AdditionalProperties.(*huma.Schema); ok {
+		fmt.Fprintf(g.buf, "%s[key: string]: %s;\n", inner, g.typeExpr(addl, inner))
+	}
+	g.buf.WriteString(indent + "}")
+	obj := g.buf.String()
+	g.buf = outer
+	return obj
+}
+
+// operation is an operation to generate a client method for.
+type operation struct {
+	path string
+	op   *huma.Operation
+}
+
+// client writes the client class and its helpers.
+func (g *generator) client(name string) {
+	paths := make([]string, 0, len(g.oapi.Paths))
+	for path := range g.oapi.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	ops := []operation{}
+	for _, path := range paths {
+		item := g.oapi.Paths[path]
+		for _, op := range []*huma.Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head, item.Patch, item.Trace} {
+			if op != nil && op.OperationID != "" {
+				ops = append(ops, operation{path, op})
+			}
+		}
+	}
+
+	g.buf.WriteString(clientRuntime)
+	for _, o := range ops {
+		g.inputType(o)
+	}
+
+	fmt.Fprintf(g.buf, "\n/** %s calls the operations of %s. */\n", name, strings.ReplaceAll(g.oapi.Info.Title, "*/", "*\\/"))
+	fmt.Fprintf(g.buf, "export class %s {\n", name)
+	g.buf.WriteString("  constructor(public baseURL: string, public init: RequestInit = {}) {}\n")
+	for _, o := range ops {
+		g.method(o)
+	}
+	g.buf.WriteString("}\n")
+}
+
+// params returns the operation's params, resolving shared params.
+func (g *generator) params(op *huma.Operation) []*huma.Param {
+	params := []*huma.Param{}
+	for _, p := range op.Parameters {
+		if p.Name == "" && p.Ref != "" && g.oapi.Components != nil {
+			if shared := g.oapi.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]; shared != nil {
+				p = shared
+			}
+		}
+		if p.In == "path" || p.In == "query" || p.In == "header" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// inputType writes the input interface for the operation, if it has any
+// params or a body.
+func (g *generator) inputType(o operation) {
+	params := g.params(o.op)
+	body := requestSchema(o.op)
+	if len(params) == 0 && body == nil {
+		return
+	}
+	fmt.Fprintf(g.buf, "\nexport interface %sInput {\n", casing.Camel(o.op.OperationID))
+	for _, p := range params {
+		g.doc("  ", p.Description, p.Deprecated)
+		optional := "?"
+		if p.Required || p.In == "path" {
+			optional = ""
+		}
+		fmt.Fprintf(g.buf, "  %s%s: %s;\n", propName(p.Name), optional, g.typeExpr(p.Schema, "  "))
+	}
+	if body != nil {
+		optional := "?"
+		if o.op.RequestBody.Required {
+			optional = ""
+		}
+		fmt.Fprintf(g.buf, "  body%s: %s;\n", optional, g.typeExpr(body, "  "))
+	}
+	g.buf.WriteString("}\n")
+}
+
+// method writes the client method for the operation.
+func (g *generator) method(o operation) {
+	op := o.op
+	params := g.params(op)
+	body := requestSchema(op)
+	hasInput := len(params) > 0 || body != nil
+
+	description := op.Summary
+	if op.Description != "" {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += op.Description
+	}
+	g.buf.WriteString("\n")
+	g.doc("  ", description, op.Deprecated)
+
+	result := "void"
+	if s := responseSchema(op); s != nil {
+		result = g.typeExpr(s, "  ")
+	}
+	arg := ""
+	if hasInput {
+		optional := ""
+		if !hasRequired(params) && (body == nil || !op.RequestBody.Required) {
+			optional = " = {}"
+		}
+		arg = fmt.Sprintf("input: %sInput%s, ", casing.Camel(op.OperationID), optional)
+	}
+	fmt.Fprintf(g.buf, "  async %s(%sinit?: RequestInit): Promise<%s> {\n", casing.LowerCamel(op.OperationID), arg, result)
+
+	path := o.path
+	query := []string{}
+	headers := []string{}
+	for _, p := range params {
+		value := "input" + propAccess(p.Name)
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", "${encodeURIComponent(String("+value+"))}")
+		case "query":
+			query = append(query, fmt.Sprintf("[%s, %s, %s]", literal(p.Name), value, literal(queryStyle(p))))
+		case "header":
+			headers = append(headers, fmt.Sprintf("[%s, %s]", literal(p.Name), value))
+		}
+	}
+	bodyArg := "undefined"
+	if body != nil {
+		bodyArg = "input.body"
+	}
+	fmt.Fprintf(g.buf, "    return request<%s>(this, init, %s, `%s`, [%s], [%s], %s);\n",
+		result, literal(op.Method), strings.ReplaceAll(path, "`", "\\`"), strings.Join(query, ", "), strings.Join(headers, ", "), bodyArg)
+	g.buf.WriteString("  }\n")
+}
+
+func hasRequired(params []*huma.Param) bool {
+	for _, p := range params {
+		if p.Required || p.In == "path" {
+			return true
+		}
+	}
+	return false
+}
+
+// queryStyle returns how the client runtime serializes a query param.
+func queryStyle(p *huma.Param) string {
+	switch {
+	case p.Style == "deepObject":
+		return "deepObject"
+	case p.Explode != nil && *p.Explode:
+		return "explode"
+	case p.Style == "spaceDelimited":
+		return " "
+	case p.Style == "pipeDelimited":
+		return "|"
+	}
+	return ","
+}
+
+// requestSchema returns the schema of the operation's JSON request body.
+func requestSchema(op *huma.Operation) *huma.Schema {
+	if op.RequestBody == nil {
+		return nil
+	}
+	return jsonSchema(op.RequestBody.Content)
+}
+
+// responseSchema returns the schema of the operation's first successful JSON
+// response body.
+func responseSchema(op *huma.Operation) *huma.Schema {
+	statuses := []string{}
+	for status := range op.Responses {
+		if strings.HasPrefix(status, "2") {
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		if s := jsonSchema(op.Responses[status].Content); s != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// jsonSchema returns the schema of the JSON media type, if any.
+func jsonSchema(content map[string]*huma.MediaType) *huma.Schema {
+	types := make([]string, 0, len(content))
+	for ct := range content {
+		types = append(types, ct)
+	}
+	sort.Strings(types)
+	for _, ct := range types {
+		if (ct == "application/json" || strings.HasSuffix(ct, "+json")) && content[ct].Schema != nil {
+			return content[ct].Schema
+		}
+	}
+	return nil
+}
+
+// refName returns the type name for a schema ref like
+// `#/components/schemas/Thing`.
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// literal returns a value as a TypeScript literal.
+func literal(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// propName returns a property name, quoted if it isn't an identifier.
+func propName(name string) string {
+	if rxIdentifier.MatchString(name) {
+		return name
+	}
+	return literal(name)
+}
+
+// propAccess returns the property accessor for a name, like `.id` or
+// `["X-Tenant"]`.
+func propAccess(name string) string {
+	if rxIdentifier.MatchString(name) {
+		return "." + name
+	}
+	return "[" + literal(name) + "]"
+}
+
+// clientRuntime is the error type & request helper used by the client.
+const clientRuntime = `
+/** ApiError is thrown for error responses, with the decoded response body. */
+export class ApiError extends Error {
+  constructor(public status: number, public body: unknown) {
+    super(` + "`request failed with status ${status}`" + `);
+  }
+}
+
+function request<T>(
+  client: { baseURL: string; init: RequestInit },
+  init: RequestInit | undefined,
+  method: string,
+  path: string,
+  query: [string, unknown, string][],
+  headers: [string, unknown][],
+  body: unknown,
+): Promise<T> {
+  const url = new URL(client.baseURL.replace(/\/$/, "") + path);
+  for (const [name, value, style] of query) {
+    if (value === undefined || value === null) continue;
+    if (style === "deepObject") {
+      for (const [k, v] of Object.entries(value as Record<string, unknown>)) {
+        if (v !== undefined && v !== null) url.searchParams.set(` + "`${name}[${k}]`" + `, String(v));
+      }
+    } else if (Array.isArray(value)) {
+      if (style === "explode") {
+        for (const v of value) url.searchParams.append(name, String(v));
+      } else {
+        url.searchParams.set(name, value.join(style));
+      }
+    } else {
+      url.searchParams.set(name, String(value));
+    }
+  }
+  const h = new Headers(client.init.headers);
+  new Headers(init?.headers).forEach((value, name) => h.set(name, value));
+  h.set("Accept", "application/json");
+  for (const [name, value] of headers) {
+    if (value !== undefined && value !== null) h.set(name, Array.isArray(value) ? value.join(",") : String(value));
+  }
+  if (body !== undefined) h.set("Content-Type", "application/json");
+  return fetch(url, {
+    ...client.init,
+    ...init,
+    method,
+    headers: h,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  }).then(async (res) => {
+    const text = await res.text();
+    let data: unknown = text;
+    if (text && (res.headers.get("Content-Type") ?? "").includes("json")) data = JSON.parse(text);
+    if (!res.ok) throw new ApiError(res.status, data);
+    return (text ? data : undefined) as T;
+  });
+}
+`
@@ -0,0 +1,91 @@
+package typescript
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Owner struct {
+	Name  string `json:"name" doc:"Full name"`
+	Email string `json:"email,omitempty" format:"email"`
+}
+
+type Thing struct {
+	ID      string                `json:"id" readOnly:"true"`
+	Kind    string                `json:"kind" enum:"small,large"`
+	Tags    []string              `json:"tags,omitempty"`
+	Note    huma.Nullable[string] `json:"note"`
+	Owner   *Owner                `json:"owner,omitempty"`
+	Labels  map[string]string     `json:"labels,omitempty"`
+	Score   float64               `json:"score"`
+	Visible bool                  `json:"visible"`
+	Legacy  string                `json:"legacy,omitempty" deprecated:"true"`
+}
+
+func TestGenerate(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{thing-id}",
+		Summary:     "Get a thing",
+	}, func(ctx context.Context, input *struct {
+		ThingID string   `path:"thing-id"`
+		Fields  []string `query:"fields"`
+		Tenant  string   `header:"X-Tenant"`
+	}) (*struct{ Body Thing }, error) {
+		return nil, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "create-thing",
+		Method:      http.MethodPost,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		Body Thing
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		Colors []string `query:"color,explode"`
+	}) (*struct{ Body []Thing }, error) {
+		return nil, nil
+	})
+
+	types := Generate(api.OpenAPI(), Options{})
+	assert.Contains(t, types, "// Code generated by huma. DO NOT EDIT.")
+	assert.Contains(t, types, "export interface Thing {")
+	assert.Contains(t, types, "  readonly id: string;")
+	assert.Contains(t, types, `  kind: "small" | "large";`)
+	assert.Contains(t, types, "  tags?: string[];")
+	assert.Contains(t, types, "  note?: string | null;")
+	assert.Contains(t, types, "  owner?: Owner;")
+	assert.Contains(t, types, "  labels?: Record<string, string>;")
+	assert.Contains(t, types, "  score: number;")
+	assert.Contains(t, types, "  visible: boolean;")
+	assert.Contains(t, types, "  /** @deprecated */\n  legacy?: string;")
+	assert.Contains(t, types, "  /** Full name */\n  name: string;")
+	assert.Contains(t, types, "export interface ErrorModel {")
+	assert.NotContains(t, types, "class Client")
+
+	src := Generate(api.OpenAPI(), Options{Client: true, ClientName: "ThingsClient"})
+	assert.Contains(t, src, "export class ApiError extends Error {")
+	assert.Contains(t, src, "export class ThingsClient {")
+	assert.Contains(t, src, "export interface GetThingInput {\n  \"thing-id\": string;\n  fields?: string[];\n  \"X-Tenant\"?: string;\n}")
+	assert.Contains(t, src, "  /** Get a thing */\n  async getThing(input: GetThingInput, init?: RequestInit): Promise<Thing> {")
+	assert.Contains(t, src, "return request<Thing>(this, init, \"GET\", `/things/${encodeURIComponent(String(input[\"thing-id\"]))}`, [[\"fields\", input.fields, \",\"]], [[\"X-Tenant\", input[\"X-Tenant\"]]], undefined);")
+	assert.Contains(t, src, "export interface CreateThingInput {\n  body?: Thing;\n}")
+	assert.Contains(t, src, "async createThing(input: CreateThingInput = {}, init?: RequestInit): Promise<void> {")
+	assert.Contains(t, src, "input.body);")
+	assert.Contains(t, src, "async listThings(input: ListThingsInput = {}, init?: RequestInit): Promise<Thing[]> {")
+	assert.Contains(t, src, `[["color", input.color, "explode"]]`)
+}
@@ -0,0 +1,18 @@
+package huma
+
+// UnwrapContext returns the context created by the adapter for the request,
+// removing any contexts which wrap it, e.g. to compress the response. Wrapping
+// contexts provide the one they wrap via an `Unwrap() huma.Context` method.
+// Adapters use this to provide their router's request & response types, see
+// e.g. `humachi.Unwrap`, so libraries which need them can be used from
+// middleware, resolvers or streaming responses. Writing to the response
+// directly bypasses the wrapping contexts, e.g. it won't be compressed.
+func UnwrapContext(ctx Context) Context {
+	for {
+		u, ok := ctx.(interface{ Unwrap() Context })
+		if !ok {
+			return ctx
+		}
+		ctx = u.Unwrap()
+	}
+}
@@ -0,0 +1,40 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnwrapContext(t *testing.T) {
+	r := chi.NewRouter()
+	config := DefaultConfig("Test API", "1.0.0")
+	config.Compression = &CompressionConfig{}
+	config.Recover = DefaultPanicHandler
+	app := NewTestAdapter(r, config)
+
+	var wrapped, unwrapped Context
+	Register(app, Operation{
+		OperationID: "unwrap",
+		Method:      http.MethodGet,
+		Path:        "/unwrap",
+		Middlewares: []Middleware{func(ctx Context, next func(Context)) {
+			wrapped = ctx
+			unwrapped = UnwrapContext(ctx)
+			next(ctx)
+		}},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/unwrap", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.IsType(t, &recoverContext{}, wrapped)
+	assert.IsType(t, &testContext{}, unwrapped)
+	assert.Same(t, req.URL, unwrapped.(*testContext).r.URL)
+}
@@ -0,0 +1,170 @@
+package huma
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ErrUpgradeUnsupported is returned by `Hijack` & `Upgrade` when the adapter's
+// response writer can't take over the connection, e.g. for HTTP/2 requests or
+// routers which aren't built on `net/http`. It wraps `http.ErrNotSupported`.
+var ErrUpgradeUnsupported = fmt.Errorf("connection upgrade: %w", http.ErrNotSupported)
+
+// websocketGUID is used to compute the `Sec-WebSocket-Accept` header, see
+// RFC 6455 section 1.3.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Hijack takes over the request's connection from the adapter, if possible,
+// so the caller can speak another protocol over it. The caller is then
+// responsible for closing the connection. Returns `ErrUpgradeUnsupported` if
+// neither the context nor its body writer (or a writer it wraps via
+// `Unwrap() http.ResponseWriter`) implements `http.Hijacker`.
+func Hijack(ctx Context) (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := ctx.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	w := ctx.BodyWriter()
+	for {
+		switch t := w.(type) {
+		case http.Hijacker:
+			return t.Hijack()
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return nil, nil, ErrUpgradeUnsupported
+		}
+	}
+}
+
+// IsUpgrade returns whether the request asks to switch the connection to the
+// protocol, e.g. `websocket`, via the `Connection` & `Upgrade` headers.
+func IsUpgrade(ctx Context, protocol string) bool {
+	return headerHasToken(ctx.Header("Connection"), "upgrade") &&
+		headerHasToken(ctx.Header("Upgrade"), protocol)
+}
+
+// headerHasToken returns whether a comma-separated header value contains the
+// token, ignoring case & any `/version` suffix.
+func headerHasToken(value, token string) bool {
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if i := strings.IndexByte(part, '/'); i != -1 {
+			part = part[:i]
+		}
+		if strings.EqualFold(part, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebSocketAccept returns the `Sec-WebSocket-Accept` header value for the
+// `Sec-WebSocket-Key` sent by a client.
+func WebSocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Upgrade switches the request's connection to the protocol by hijacking it &
+// sending a `101 Switching Protocols` response with the given extra headers.
+// Call it from a streaming response body, which is run before any status is
+// written:
+//
+//	huma.Register(api, huma.Operation{
+//		OperationID: "chat",
+//		Method:      http.MethodGet,
+//		Path:        "/chat",
+//		Upgrade:     "websocket",
+//	}, func(ctx context.Context, input *struct{}) (*huma.StreamResponse, error) {
+//		return &huma.StreamResponse{
+//			Body: func(ctx huma.Context) {
+//				conn, rw, err := huma.Upgrade(api, ctx, "websocket", nil)
+//				if err != nil {
+//					return
+//				}
+//				defer conn.Close()
+//				// ... read & write frames via rw ...
+//			},
+//		}, nil
+//	})
+//
+// For `websocket` the `Sec-WebSocket-Accept` header is computed from the
+// request's `Sec-WebSocket-Key`; subprotocol & extension negotiation is left
+// to the caller via `header`. If the request doesn't ask for the protocol a
+// `426 Upgrade Required` error is written, and if the adapter can't hijack the
+// connection a `501 Not Implemented` error is written. In both cases the
+// error is returned & the caller should just return.
+func Upgrade(api API, ctx Context, protocol string, header http.Header) (net.Conn, *bufio.ReadWriter, error) {
+	if !IsUpgrade(ctx, protocol) {
+		ctx.SetHeader("Connection", "Upgrade")
+		ctx.SetHeader("Upgrade", protocol)
+		err := fmt.Errorf("expected a request to upgrade to %s", protocol)
+		WriteErr(api, ctx, http.StatusUpgradeRequired, "upgrade required", err)
+		return nil, nil, err
+	}
+
+	accept := ""
+	if strings.EqualFold(protocol, "websocket") {
+		key := ctx.Header("Sec-WebSocket-Key")
+		if key == "" {
+			err := &ErrorDetail{Message: "missing Sec-WebSocket-Key", Location: "header.Sec-WebSocket-Key"}
+			WriteErr(api, ctx, http.StatusBadRequest, "invalid websocket handshake", err)
+			return nil, nil, err
+		}
+		accept = WebSocketAccept(key)
+	}
+
+	conn, rw, err := Hijack(ctx)
+	if err != nil {
+		WriteErr(api, ctx, http.StatusNotImplemented, "unable to upgrade connection", err)
+		return nil, nil, err
+	}
+
+	resp := http.Header{}
+	for name, values := range header {
+		resp[http.CanonicalHeaderKey(name)] = values
+	}
+	resp.Set("Connection", "Upgrade")
+	resp.Set("Upgrade", protocol)
+	if accept != "" {
+		resp.Set("Sec-WebSocket-Accept", accept)
+	}
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	resp.Write(rw)
+	rw.WriteString("\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, rw, nil
+}
+
+// documentUpgrade documents the switching protocols response of an operation
+// with an `Upgrade`, along with the `x-upgrade` extension.
+func documentUpgrade(op *Operation) {
+	resp := op.Responses[fmt.Sprintf("%d", http.StatusSwitchingProtocols)]
+	if resp == nil {
+		resp = &Response{Description: http.StatusText(http.StatusSwitchingProtocols)}
+		op.Responses[fmt.Sprintf("%d", http.StatusSwitchingProtocols)] = resp
+	}
+	if resp.Headers == nil {
+		resp.Headers = map[string]*Param{}
+	}
+	resp.Headers["Connection"] = &Header{
+		Schema: &Schema{Type: TypeString, Enum: []any{"Upgrade"}},
+	}
+	resp.Headers["Upgrade"] = &Header{
+		Description: "Protocol the connection is switched to.",
+		Schema:      &Schema{Type: TypeString, Enum: []any{op.Upgrade}},
+	}
+	if op.Extensions == nil {
+		op.Extensions = map[string]any{}
+	}
+	op.Extensions["x-upgrade"] = op.Upgrade
+}
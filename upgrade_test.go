@@ -0,0 +1,112 @@
+package huma
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func registerEcho(api API) {
+	Register(api, Operation{
+		OperationID: "echo",
+		Method:      http.MethodGet,
+		Path:        "/echo",
+		Upgrade:     "websocket",
+	}, func(ctx context.Context, input *struct{}) (*StreamResponse, error) {
+		return &StreamResponse{
+			Body: func(ctx Context) {
+				conn, rw, err := Upgrade(api, ctx, "websocket", http.Header{"Sec-WebSocket-Protocol": {"echo"}})
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				line, _ := rw.ReadString('\n')
+				rw.WriteString("echo: " + line)
+				rw.Flush()
+			},
+		}, nil
+	})
+}
+
+func TestUpgrade(t *testing.T) {
+	r := chi.NewRouter()
+	api := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+	registerEcho(api)
+
+	op := api.OpenAPI().Paths["/echo"].Get
+	assert.Equal(t, "websocket", op.Extensions["x-upgrade"])
+	assert.Contains(t, op.Responses, "101")
+	assert.Contains(t, op.Responses["101"].Headers, "Upgrade")
+	assert.Contains(t, op.Responses, "426")
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	conn.Write([]byte("GET /echo HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"Connection: keep-alive, Upgrade\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"))
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.Equal(t, "websocket", resp.Header.Get("Upgrade"))
+	assert.Equal(t, "echo", resp.Header.Get("Sec-WebSocket-Protocol"))
+	// Example from RFC 6455 section 1.3.
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+
+	conn.Write([]byte("hello\n"))
+	line, err := br.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "echo: hello\n", line)
+}
+
+func TestUpgradeErrors(t *testing.T) {
+	r := chi.NewRouter()
+	api := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+	registerEcho(api)
+
+	req, _ := http.NewRequest(http.MethodGet, "/echo", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUpgradeRequired, w.Code)
+	assert.Equal(t, "websocket", w.Header().Get("Upgrade"))
+
+	req, _ = http.NewRequest(http.MethodGet, "/echo", nil)
+	req.Header.Set("Connection", "upgrade")
+	req.Header.Set("Upgrade", "WebSocket")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The recorder can't be hijacked.
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	assert.Panics(t, func() {
+		Register(api, Operation{
+			OperationID: "bad",
+			Method:      http.MethodGet,
+			Path:        "/bad",
+			Upgrade:     "websocket",
+		}, func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+			return nil, nil
+		})
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 	"unsafe"
 
@@ -34,6 +35,7 @@ var rxHostname = regexp.MustCompile(`^([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\-]{0,61
 var rxURITemplate = regexp.MustCompile("^([^{]*({[^}]*})?)*$")
 var rxJSONPointer = regexp.MustCompile("^(?:/(?:[^~/]|~0|~1)*)*$")
 var rxRelJSONPointer = regexp.MustCompile("^(?:0|[1-9][0-9]*)(?:#|(?:/(?:[^~/]|~0|~1)*)*)$")
+var rxDuration = regexp.MustCompile(`^-?P(?:\d+(?:[.,]\d+)?W|(?:\d+(?:[.,]\d+)?Y)?(?:\d+(?:[.,]\d+)?M)?(?:\d+(?:[.,]\d+)?D)?(?:T(?:\d+(?:[.,]\d+)?H)?(?:\d+(?:[.,]\d+)?M)?(?:\d+(?:[.,]\d+)?S)?)?)$`)
 var rxBase64 = regexp.MustCompile(`^[a-zA-Z0-9+/_-]+=*$`)
 
 func mapTo[A, B any](s []A, f func(A) B) []B {
@@ -147,12 +149,45 @@ func NewPathBuffer(buf []byte, offset int) *PathBuffer {
 	return &PathBuffer{buf: buf, off: offset}
 }
 
+// UnknownFieldsMode controls how request body object properties which are
+// not allowed by the schema, i.e. `additionalProperties: false`, are handled.
+type UnknownFieldsMode int
+
+const (
+	// UnknownFieldsReject fails validation when unknown properties are present.
+	// This is the default.
+	UnknownFieldsReject UnknownFieldsMode = iota
+
+	// UnknownFieldsIgnore accepts unknown properties without validating them.
+	// They are not decoded into the input struct, but are still present in
+	// `RawBody`.
+	UnknownFieldsIgnore
+
+	// UnknownFieldsStrip removes unknown properties before validation, and
+	// also from `RawBody` for JSON request bodies.
+	UnknownFieldsStrip
+)
+
 // ValidateResult tracks validation errors.
 type ValidateResult struct {
 	Errors []error
+
+	// Limit is the maximum number of errors to collect, or zero for no limit.
+	// Once it is reached further errors are dropped and `Validate` stops
+	// early, which caps the work done for large invalid inputs. Use a limit of
+	// one to fail fast.
+	Limit int
+}
+
+// full returns whether the error limit has been reached.
+func (r *ValidateResult) full() bool {
+	return r.Limit > 0 && len(r.Errors) >= r.Limit
 }
 
 func (r *ValidateResult) Add(path *PathBuffer, v any, msg string) {
+	if r.full() {
+		return
+	}
 	r.Errors = append(r.Errors, &ErrorDetail{
 		Message:  msg,
 		Location: path.String(),
@@ -161,6 +196,9 @@ func (r *ValidateResult) Add(path *PathBuffer, v any, msg string) {
 }
 
 func (r *ValidateResult) Addf(path *PathBuffer, v any, format string, args ...any) {
+	if r.full() {
+		return
+	}
 	r.Errors = append(r.Errors, &ErrorDetail{
 		Message:  fmt.Sprintf(format, args...),
 		Location: path.String(),
@@ -168,7 +206,12 @@ func (r *ValidateResult) Addf(path *PathBuffer, v any, format string, args ...an
 	})
 }
 
+// Reset the result so it can be reused, e.g. via `sync.Pool`, without keeping
+// the previous errors alive.
 func (r *ValidateResult) Reset() {
+	for i := range r.Errors {
+		r.Errors[i] = nil
+	}
 	r.Errors = r.Errors[:0]
 }
 
@@ -183,68 +226,174 @@ func validateFormat(path *PathBuffer, str string, s *Schema, res *ValidateResult
 			}
 		}
 		if !found {
-			res.Add(path, str, "expected string to be RFC 3339 date-time")
+			res.Add(path, str, message("date-time"))
 		}
 	case "date":
 		if _, err := time.Parse("2006-01-02", str); err != nil {
-			res.Add(path, str, "expected string to be RFC 3339 date")
+			res.Add(path, str, message("date"))
 		}
 	case "time":
 		if _, err := time.Parse("15:04:05", str); err != nil {
 			if _, err := time.Parse("15:04:05Z07:00", str); err != nil {
-				res.Add(path, str, "expected string to be RFC 3339 time")
+				res.Add(path, str, message("time"))
 			}
 		}
-		// TODO: duration
+	case "duration":
+		if !rxDuration.MatchString(str) || str[len(str)-1] == 'P' || str[len(str)-1] == 'T' {
+			res.Add(path, str, message("duration"))
+		}
 	case "email", "idn-email":
 		if _, err := mail.ParseAddress(str); err != nil {
-			res.Addf(path, str, "expected string to be RFC 5322 email: %v", err)
+			res.Add(path, str, message("email")+": "+err.Error())
 		}
 	case "hostname":
 		if !(rxHostname.MatchString(str) && len(str) < 256) {
-			res.Add(path, str, "expected string to be RFC 5890 hostname")
+			res.Add(path, str, message("hostname"))
 		}
 	case "idn-hostname":
 		if _, err := idna.ToASCII(str); err != nil {
-			res.Addf(path, str, "expected string to be RFC 5890 hostname: %v", err)
+			res.Add(path, str, message("hostname")+": "+err.Error())
 		}
 	case "ipv4":
 		if ip := net.ParseIP(str); ip == nil || ip.To4() == nil {
-			res.Add(path, str, "expected string to be RFC 2673 ipv4")
+			res.Add(path, str, message("ipv4"))
 		}
 	case "ipv6":
 		if ip := net.ParseIP(str); ip == nil || ip.To16() == nil {
-			res.Add(path, str, "expected string to be RFC 2373 ipv6")
+			res.Add(path, str, message("ipv6"))
 		}
 	case "uri", "uri-reference", "iri", "iri-reference":
 		if _, err := url.Parse(str); err != nil {
-			res.Addf(path, str, "expected string to be RFC 3986 uri: %v", err)
+			res.Add(path, str, message("uri")+": "+err.Error())
 		}
 		// TODO: check if it's actually a reference?
 	case "uuid":
 		if _, err := uuid.Parse(str); err != nil {
-			res.Addf(path, str, "expected string to be RFC 4122 uuid: %v", err)
+			res.Add(path, str, message("uuid")+": "+err.Error())
 		}
 	case "uri-template":
 		u, err := url.Parse(str)
 		if err != nil {
-			res.Addf(path, str, "expected string to be RFC 3986 uri: %v", err)
+			res.Add(path, str, message("uri")+": "+err.Error())
 			return
 		}
 		if !rxURITemplate.MatchString(u.Path) {
-			res.Add(path, str, "expected string to be RFC 6570 uri-template")
+			res.Add(path, str, message("uri-template"))
 		}
 	case "json-pointer":
 		if !rxJSONPointer.MatchString(str) {
-			res.Add(path, str, "expected string to be RFC 6901 json-pointer")
+			res.Add(path, str, message("json-pointer"))
 		}
 	case "relative-json-pointer":
 		if !rxRelJSONPointer.MatchString(str) {
-			res.Add(path, str, "expected string to be RFC 6901 relative-json-pointer")
+			res.Add(path, str, message("relative-json-pointer"))
 		}
 	case "regex":
 		if _, err := regexp.Compile(str); err != nil {
-			res.Addf(path, str, "expected string to be regex: %v", err)
+			res.Add(path, str, message("regex")+": "+err.Error())
+		}
+	}
+}
+
+// integerRange is the range of values, with an exclusive maximum, which fit
+// into an integer type.
+type integerRange struct {
+	min, max float64
+}
+
+// integerRanges are the checked integer formats and Go integer types. Since
+// JSON numbers are float64, values outside these ranges would otherwise
+// overflow silently.
+var integerRanges = map[string]integerRange{
+	"int":    {math.MinInt, -math.MinInt},
+	"int8":   {math.MinInt8, math.MaxInt8 + 1},
+	"int16":  {math.MinInt16, math.MaxInt16 + 1},
+	"int32":  {math.MinInt32, math.MaxInt32 + 1},
+	"int64":  {math.MinInt64, 1 << 63},
+	"uint":   {0, -2 * math.MinInt},
+	"uint8":  {0, math.MaxUint8 + 1},
+	"uint16": {0, math.MaxUint16 + 1},
+	"uint32": {0, math.MaxUint32 + 1},
+	"uint64": {0, 1 << 64},
+}
+
+// integerRangeMsg returns an error message if the integer doesn't fit into
+// the schema's Go type, or into its format if that was set explicitly, e.g.
+// via a `format` tag. Otherwise it returns an empty string.
+func (s *Schema) integerRangeMsg(num float64) string {
+	if r, ok := integerRanges[s.goInt]; ok && (num < r.min || num >= r.max) {
+		return message("integerRange", s.goInt)
+	}
+	if s.Format != "" && s.Format != s.goIntFormat {
+		if r, ok := integerRanges[s.Format]; ok && (num < r.min || num >= r.max) {
+			return message("integerRange", s.Format)
+		}
+	}
+	return ""
+}
+
+// validateNumber checks the numeric constraints of the schema. The original
+// value `v` is only used for error reporting, so callers with a concrete type
+// don't need to box it unless validation fails.
+func validateNumber[T any](path *PathBuffer, s *Schema, num float64, v T, res *ValidateResult) {
+	if s.Type == TypeInteger {
+		if msg := s.integerRangeMsg(num); msg != "" {
+			res.Add(path, v, msg)
+		}
+	}
+	if s.Minimum != nil {
+		if num < *s.Minimum {
+			res.Add(path, v, s.msgMinimum)
+		}
+	}
+	if s.ExclusiveMinimum != nil {
+		if num <= *s.ExclusiveMinimum {
+			res.Add(path, v, s.msgExclusiveMinimum)
+		}
+	}
+	if s.Maximum != nil {
+		if num > *s.Maximum {
+			res.Add(path, v, s.msgMaximum)
+		}
+	}
+	if s.ExclusiveMaximum != nil {
+		if num >= *s.ExclusiveMaximum {
+			res.Add(path, v, s.msgExclusiveMaximum)
+		}
+	}
+	if s.MultipleOf != nil {
+		if math.Mod(num, *s.MultipleOf) != 0 {
+			res.Add(path, v, s.msgMultipleOf)
+		}
+	}
+}
+
+// validateString checks the string constraints of the schema. Like
+// `validateNumber`, `v` is only used for error reporting.
+func validateString[T any](path *PathBuffer, s *Schema, str string, v T, res *ValidateResult) {
+	if s.MinLength != nil {
+		if len(str) < *s.MinLength {
+			res.Add(path, str, s.msgMinLength)
+		}
+	}
+	if s.MaxLength != nil {
+		if len(str) > *s.MaxLength {
+			res.Add(path, str, s.msgMaxLength)
+		}
+	}
+	if s.patternRe != nil {
+		if !s.patternRe.MatchString(str) {
+			res.Add(path, v, s.msgPattern)
+		}
+	}
+
+	if s.Format != "" {
+		validateFormat(path, str, s, res)
+	}
+
+	if s.ContentEncoding == "base64" {
+		if !rxBase64.MatchString(str) {
+			res.Add(path, str, message("base64"))
 		}
 	}
 }
@@ -254,15 +403,23 @@ func validateFormat(path *PathBuffer, str string, s *Schema, res *ValidateResult
 // to use a `sync.Pool` to reuse the PathBuffer and ValidateResult objects,
 // making sure to call `Reset()` on them before returning them to the pool.
 func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any, res *ValidateResult) {
+	if res.full() {
+		return
+	}
+
 	// Get the actual schema if this is a reference.
 	for s.Ref != "" {
 		s = r.SchemaFromRef(s.Ref)
 	}
 
+	if v == nil && s.Nullable {
+		return
+	}
+
 	switch s.Type {
 	case TypeBoolean:
 		if _, ok := v.(bool); !ok {
-			res.Add(path, v, "expected boolean")
+			res.Add(path, v, message("expectedBoolean"))
 			return
 		}
 	case TypeNumber, TypeInteger:
@@ -276,86 +433,44 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 		case int64:
 			num = float64(v)
 		default:
-			res.Add(path, v, "expected number")
+			res.Add(path, v, message("expectedNumber"))
 			return
 		}
 
-		if s.Minimum != nil {
-			if num < *s.Minimum {
-				res.Addf(path, v, s.msgMinimum)
-			}
-		}
-		if s.ExclusiveMinimum != nil {
-			if num <= *s.ExclusiveMinimum {
-				res.Addf(path, v, s.msgExclusiveMinimum)
-			}
-		}
-		if s.Maximum != nil {
-			if num > *s.Maximum {
-				res.Add(path, v, s.msgMaximum)
-			}
-		}
-		if s.ExclusiveMaximum != nil {
-			if num >= *s.ExclusiveMaximum {
-				res.Addf(path, v, s.msgExclusiveMaximum)
-			}
-		}
-		if s.MultipleOf != nil {
-			if math.Mod(num, *s.MultipleOf) != 0 {
-				res.Addf(path, v, s.msgMultipleOf)
-			}
+		if s.Type == TypeInteger && num != math.Trunc(num) {
+			// JSON has no separate integer type, so check for a fraction.
+			res.Add(path, v, message("expectedInteger"))
+			return
 		}
+
+		validateNumber(path, s, num, v, res)
 	case TypeString:
 		str, ok := v.(string)
 		if !ok {
 			if b, ok := v.([]byte); ok {
 				str = *(*string)(unsafe.Pointer(&b))
 			} else {
-				res.Add(path, v, "expected string")
+				res.Add(path, v, message("expectedString"))
 				return
 			}
 		}
 
-		if s.MinLength != nil {
-			if len(str) < *s.MinLength {
-				res.Addf(path, str, s.msgMinLength)
-			}
-		}
-		if s.MaxLength != nil {
-			if len(str) > *s.MaxLength {
-				res.Add(path, str, s.msgMaxLength)
-			}
-		}
-		if s.patternRe != nil {
-			if !s.patternRe.MatchString(str) {
-				res.Add(path, v, s.msgPattern)
-			}
-		}
-
-		if s.Format != "" {
-			validateFormat(path, str, s, res)
-		}
-
-		if s.ContentEncoding == "base64" {
-			if !rxBase64.MatchString(str) {
-				res.Add(path, str, "expected string to be base64 encoded")
-			}
-		}
+		validateString(path, s, str, v, res)
 	case TypeArray:
 		arr, ok := v.([]any)
 		if !ok {
-			res.Add(path, v, "expected array")
+			res.Add(path, v, message("expectedArray"))
 			return
 		}
 
 		if s.MinItems != nil {
 			if len(arr) < *s.MinItems {
-				res.Addf(path, v, s.msgMinItems)
+				res.Add(path, v, s.msgMinItems)
 			}
 		}
 		if s.MaxItems != nil {
 			if len(arr) > *s.MaxItems {
-				res.Addf(path, v, s.msgMaxItems)
+				res.Add(path, v, s.msgMaxItems)
 			}
 		}
 
@@ -363,7 +478,7 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 			seen := make(map[any]struct{}, len(arr))
 			for _, item := range arr {
 				if _, ok := seen[item]; ok {
-					res.Add(path, v, "expected array items to be unique")
+					res.Add(path, v, message("uniqueItems"))
 				}
 				seen[item] = struct{}{}
 			}
@@ -379,7 +494,7 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 			handleMapString(r, s, path, mode, vv, res)
 			// TODO: handle map[any]any
 		} else {
-			res.Add(path, v, "expected object")
+			res.Add(path, v, message("expectedObject"))
 			return
 		}
 	}
@@ -396,6 +511,108 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 			res.Add(path, v, s.msgEnum)
 		}
 	}
+
+	if s.constValue != nil && !constMatches(s.constValue, v) {
+		res.Add(path, v, s.msgConst)
+	}
+}
+
+// constMatches returns whether the value equals the schema's normalized
+// constant. Numbers are compared by value since they may be decoded as
+// different types.
+func constMatches(c, v any) bool {
+	if cf, ok := c.(float64); ok {
+		switch n := v.(type) {
+		case float64:
+			return n == cf
+		case int:
+			return float64(n) == cf
+		case int64:
+			return float64(n) == cf
+		}
+		return false
+	}
+	return reflect.DeepEqual(c, v)
+}
+
+// compareFields returns whether the values of two sibling properties satisfy
+// a cross-field comparison like `gtField`. Numbers and strings are ordered,
+// with `date-time` strings compared as times. Other values can only be
+// compared for equality.
+func compareFields(op string, a, b any, format string) bool {
+	cmp, ok := compareValues(a, b, format)
+	if !ok {
+		switch op {
+		case "eqField":
+			return reflect.DeepEqual(a, b)
+		case "neField":
+			return !reflect.DeepEqual(a, b)
+		}
+		// Values which can't be ordered are left to the other validation.
+		return true
+	}
+	switch op {
+	case "eqField":
+		return cmp == 0
+	case "neField":
+		return cmp != 0
+	case "gtField":
+		return cmp > 0
+	case "gteField":
+		return cmp >= 0
+	case "ltField":
+		return cmp < 0
+	case "lteField":
+		return cmp <= 0
+	}
+	return true
+}
+
+// compareValues orders two numbers or strings, returning false if they can't
+// be compared.
+func compareValues(a, b any, format string) (int, bool) {
+	if as, ok := a.(string); ok {
+		bs, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		if format == "date-time" {
+			at, aErr := time.Parse(time.RFC3339Nano, as)
+			bt, bErr := time.Parse(time.RFC3339Nano, bs)
+			if aErr == nil && bErr == nil {
+				return at.Compare(bt), true
+			}
+		}
+		return strings.Compare(as, bs), true
+	}
+	an, ok := toFloat(a)
+	if !ok {
+		return 0, false
+	}
+	bn, ok := toFloat(b)
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case an < bn:
+		return -1, true
+	case an > bn:
+		return 1, true
+	}
+	return 0, true
+}
+
+// toFloat returns the value of a number as decoded from JSON or set in code.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
 }
 
 func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m map[string]any, res *ValidateResult) {
@@ -422,11 +639,15 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 
 		// Be stricter for responses, enabling validation of the server if desired.
 		if mode == ModeReadFromServer && v.WriteOnly && m[k] != nil && !reflect.ValueOf(m[k]).IsZero() {
-			res.Add(path, m[k], "write only property is non-zero")
+			res.Add(path, m[k], message("writeOnly"))
 			continue
 		}
 
 		if m[k] == nil {
+			if _, ok := m[k]; ok && v.Nullable {
+				// Present with an allowed `null` value.
+				continue
+			}
 			if !s.requiredMap[k] {
 				continue
 			}
@@ -444,12 +665,65 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 		path.Pop()
 	}
 
+	for _, d := range s.dependents {
+		if m[d.name] == nil {
+			continue
+		}
+		for i, dep := range d.required {
+			if m[dep] == nil {
+				res.Add(path, m, d.msgs[i])
+			}
+		}
+	}
+
+	for _, c := range s.comparisons {
+		a, b := m[c.name], m[c.other]
+		if a == nil || b == nil {
+			// Missing values are handled by `required`.
+			continue
+		}
+		ps := s.Properties[c.name]
+		for ps.Ref != "" {
+			ps = r.SchemaFromRef(ps.Ref)
+		}
+		if !compareFields(c.op, a, b, ps.Format) {
+			path.Push(c.name)
+			res.Add(path, a, c.msg)
+			path.Pop()
+		}
+	}
+
+	if s.PropertyNames != nil {
+		for k := range m {
+			path.Push(k)
+			Validate(r, s.PropertyNames, path, mode, k, res)
+			path.Pop()
+		}
+	}
+
+	for _, pp := range s.patternProps {
+		for k, v := range m {
+			if !pp.re.MatchString(k) {
+				continue
+			}
+			path.Push(k)
+			Validate(r, pp.schema, path, mode, v, res)
+			path.Pop()
+		}
+	}
+
+	for _, k := range s.requiredOnly {
+		if m[k] == nil {
+			res.Add(path, m, s.msgRequired[k])
+		}
+	}
+
 	if addl, ok := s.AdditionalProperties.(bool); ok && !addl {
 		for k := range m {
 			// No additional properties allowed.
-			if _, ok := s.Properties[k]; !ok {
+			if _, ok := s.Properties[k]; !ok && !s.matchesPatternProp(k) {
 				path.Push(k)
-				res.Add(path, m, "unexpected property")
+				res.Add(path, m, message("unexpectedProperty"))
 				path.Pop()
 			}
 		}
@@ -458,7 +732,7 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 	if addl, ok := s.AdditionalProperties.(*Schema); ok {
 		// Additional properties are allowed, but must match the given schema.
 		for k, v := range m {
-			if _, ok := s.Properties[k]; ok {
+			if _, ok := s.Properties[k]; ok || s.matchesPatternProp(k) {
 				continue
 			}
 
@@ -468,3 +742,55 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 		}
 	}
 }
+
+// stripProperties removes properties from the objects in `v` which are not
+// allowed by the schema if `unknown` is set, and read-only properties if
+// `readOnly` is set. It returns whether anything was removed.
+func stripProperties(r Registry, s *Schema, v any, unknown, readOnly bool) bool {
+	for s.Ref != "" {
+		s = r.SchemaFromRef(s.Ref)
+	}
+
+	removed := false
+	switch tv := v.(type) {
+	case map[string]any:
+		for k, item := range tv {
+			if ps := s.Properties[k]; ps != nil {
+				for ps.Ref != "" {
+					ps = r.SchemaFromRef(ps.Ref)
+				}
+				if readOnly && ps.ReadOnly {
+					delete(tv, k)
+					removed = true
+					continue
+				}
+				removed = stripProperties(r, ps, item, unknown, readOnly) || removed
+				continue
+			}
+			if s.matchesPatternProp(k) {
+				for _, pp := range s.patternProps {
+					if pp.re.MatchString(k) {
+						removed = stripProperties(r, pp.schema, item, unknown, readOnly) || removed
+					}
+				}
+				continue
+			}
+			switch addl := s.AdditionalProperties.(type) {
+			case *Schema:
+				removed = stripProperties(r, addl, item, unknown, readOnly) || removed
+			case bool:
+				if unknown && !addl {
+					delete(tv, k)
+					removed = true
+				}
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for _, item := range tv {
+				removed = stripProperties(r, s.Items, item, unknown, readOnly) || removed
+			}
+		}
+	}
+	return removed
+}
@@ -2,6 +2,7 @@ package huma
 
 import (
 	"encoding/json"
+	"math"
 	"reflect"
 	"strings"
 	"testing"
@@ -10,6 +11,21 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// PatternPropsStruct allows extra `x-` properties via a custom schema.
+type PatternPropsStruct struct {
+	Name string `json:"name"`
+}
+
+func (p *PatternPropsStruct) TransformSchema(r Registry, s *Schema) *Schema {
+	minLength := 1
+	s.PatternProperties = map[string]*Schema{
+		"^x-": {Type: TypeString, MinLength: &minLength},
+	}
+	s.PrecomputeMessages()
+	s.PatternProperties["^x-"].PrecomputeMessages()
+	return s
+}
+
 var validateTests = []struct {
 	name  string
 	typ   reflect.Type
@@ -56,6 +72,55 @@ var validateTests = []struct {
 		input: "",
 		errs:  []string{"expected number"},
 	},
+	{
+		name:  "expected integer",
+		typ:   reflect.TypeOf(0),
+		input: 1.5,
+		errs:  []string{"expected integer"},
+	},
+	{
+		name:  "int32 overflow",
+		typ:   reflect.TypeOf(int32(0)),
+		input: float64(math.MaxInt32 + 1),
+		errs:  []string{"expected integer to fit in int32"},
+	},
+	{
+		name:  "int32 underflow",
+		typ:   reflect.TypeOf(int32(0)),
+		input: float64(math.MinInt32 - 1),
+		errs:  []string{"expected integer to fit in int32"},
+	},
+	{
+		name:  "int64 overflow",
+		typ:   reflect.TypeOf(int64(0)),
+		input: 1e19,
+		errs:  []string{"expected integer to fit in int64"},
+	},
+	{
+		name:  "int8 overflow",
+		typ:   reflect.TypeOf(int8(0)),
+		input: 128.0,
+		errs:  []string{"expected integer to fit in int8"},
+	},
+	{
+		name:  "uint32 max success",
+		typ:   reflect.TypeOf(uint32(0)),
+		input: float64(math.MaxUint32),
+	},
+	{
+		name:  "uint64 overflow",
+		typ:   reflect.TypeOf(uint64(0)),
+		input: 1e20,
+		errs:  []string{"expected integer to fit in uint64"},
+	},
+	{
+		name: "uint negative",
+		typ: reflect.TypeOf(struct {
+			Value uint64 `json:"value"`
+		}{}),
+		input: map[string]any{"value": -1.0},
+		errs:  []string{"expected integer to fit in uint64"},
+	},
 	{
 		name: "minimum success",
 		typ: reflect.TypeOf(struct {
@@ -547,6 +612,90 @@ var validateTests = []struct {
 		},
 		errs: []string{"expected object with at most 1 properties"},
 	},
+	{
+		name: "map patternProperties success",
+		typ: reflect.TypeOf(struct {
+			Value map[string]int `json:"value" patternProperties:"^x-"`
+		}{}),
+		input: map[string]any{
+			"value": map[string]any{"x-one": 1},
+		},
+	},
+	{
+		name: "expected map patternProperties",
+		typ: reflect.TypeOf(struct {
+			Value map[string]int `json:"value" patternProperties:"^x-"`
+		}{}),
+		input: map[string]any{
+			"value": map[string]any{"one": 1},
+		},
+		errs: []string{"unexpected property"},
+	},
+	{
+		name: "expected map patternProperties value",
+		typ: reflect.TypeOf(struct {
+			Value map[string]int `json:"value" patternProperties:"^x-"`
+		}{}),
+		input: map[string]any{
+			"value": map[string]any{"x-one": "one"},
+		},
+		errs: []string{"expected number"},
+	},
+	{
+		name: "map keyPattern success",
+		typ: reflect.TypeOf(struct {
+			Value map[string]int `json:"value" keyPattern:"^[a-z]+$"`
+		}{}),
+		input: map[string]any{
+			"value": map[string]any{"one": 1},
+		},
+	},
+	{
+		name: "expected map keyPattern",
+		typ: reflect.TypeOf(struct {
+			Value map[string]int `json:"value" keyPattern:"^[a-z]+$"`
+		}{}),
+		input: map[string]any{
+			"value": map[string]any{"One": 1},
+		},
+		errs: []string{"expected string to match pattern ^[a-z]+$"},
+	},
+	{
+		name: "map keyFormat success",
+		typ: reflect.TypeOf(struct {
+			Value map[string]int `json:"value" keyFormat:"uuid"`
+		}{}),
+		input: map[string]any{
+			"value": map[string]any{"a1b2c3d4-0000-4000-8000-000000000000": 1},
+		},
+	},
+	{
+		name: "expected map keyFormat",
+		typ: reflect.TypeOf(struct {
+			Value map[string]int `json:"value" keyFormat:"uuid"`
+		}{}),
+		input: map[string]any{
+			"value": map[string]any{"one": 1},
+		},
+		errs: []string{"expected string to be RFC 4122 uuid: invalid UUID length: 3"},
+	},
+	{
+		name: "object patternProperties success",
+		typ:  reflect.TypeOf(PatternPropsStruct{}),
+		input: map[string]any{
+			"name":  "foo",
+			"x-one": "bar",
+		},
+	},
+	{
+		name: "expected object patternProperties",
+		typ:  reflect.TypeOf(PatternPropsStruct{}),
+		input: map[string]any{
+			"name":  "foo",
+			"x-one": "",
+		},
+		errs: []string{"expected length >= 1"},
+	},
 	{
 		name:  "object struct success",
 		typ:   reflect.TypeOf(struct{}{}),
@@ -599,6 +748,118 @@ var validateTests = []struct {
 		input: map[string]any{"value": "should not be set"},
 		errs:  []string{"write only property is non-zero"},
 	},
+	{
+		name: "dependentRequired success",
+		typ: reflect.TypeOf(struct {
+			Name       string `json:"name" dependentRequired:"card_number:cvv,expiry"`
+			CardNumber string `json:"card_number,omitempty"`
+			CVV        string `json:"cvv,omitempty"`
+			Expiry     string `json:"expiry,omitempty"`
+		}{}),
+		input: map[string]any{"name": "foo", "card_number": "123", "cvv": "456", "expiry": "01/30"},
+	},
+	{
+		name: "dependentRequired absent success",
+		typ: reflect.TypeOf(struct {
+			Name       string `json:"name" dependentRequired:"card_number:cvv,expiry"`
+			CardNumber string `json:"card_number,omitempty"`
+			CVV        string `json:"cvv,omitempty"`
+			Expiry     string `json:"expiry,omitempty"`
+		}{}),
+		input: map[string]any{"name": "foo"},
+	},
+	{
+		name: "expected dependentRequired",
+		typ: reflect.TypeOf(struct {
+			Name       string `json:"name" dependentRequired:"card_number:cvv,expiry"`
+			CardNumber string `json:"card_number,omitempty"`
+			CVV        string `json:"cvv,omitempty"`
+			Expiry     string `json:"expiry,omitempty"`
+		}{}),
+		input: map[string]any{"name": "foo", "card_number": "123", "cvv": "456"},
+		errs:  []string{"expected property expiry to be present when card_number is present"},
+	},
+	{
+		name: "dependentRequired unknown property",
+		typ: reflect.TypeOf(struct {
+			Name string `json:"name" dependentRequired:"name:missing"`
+		}{}),
+		panic: "dependentRequired property missing not found",
+	},
+	{
+		name: "dependentRequired invalid tag",
+		typ: reflect.TypeOf(struct {
+			Name string `json:"name" dependentRequired:"name"`
+		}{}),
+		panic: "invalid dependentRequired tag",
+	},
+	{
+		name: "gtField success",
+		typ: reflect.TypeOf(struct {
+			Start time.Time `json:"start"`
+			End   time.Time `json:"end" gtField:"Start"`
+		}{}),
+		input: map[string]any{"start": "2023-01-01T12:00:00+02:00", "end": "2023-01-01T11:00:00Z"},
+	},
+	{
+		name: "expected gtField",
+		typ: reflect.TypeOf(struct {
+			Start time.Time `json:"start"`
+			End   time.Time `json:"end" gtField:"Start"`
+		}{}),
+		input: map[string]any{"start": "2023-01-01T12:00:00Z", "end": "2023-01-01T12:00:00Z"},
+		errs:  []string{"expected value > start"},
+	},
+	{
+		name: "expected lteField",
+		typ: reflect.TypeOf(struct {
+			Min int `json:"min" lteField:"max"`
+			Max int `json:"max"`
+		}{}),
+		input: map[string]any{"min": 5.0, "max": 3.0},
+		errs:  []string{"expected value <= max"},
+	},
+	{
+		name: "comparison absent success",
+		typ: reflect.TypeOf(struct {
+			Min int `json:"min,omitempty" ltField:"Max"`
+			Max int `json:"max,omitempty"`
+		}{}),
+		input: map[string]any{"min": 5.0},
+	},
+	{
+		name: "eqField success",
+		typ: reflect.TypeOf(struct {
+			Password        string `json:"password"`
+			PasswordConfirm string `json:"password_confirm" eqField:"Password"`
+		}{}),
+		input: map[string]any{"password": "abc123", "password_confirm": "abc123"},
+	},
+	{
+		name: "expected eqField",
+		typ: reflect.TypeOf(struct {
+			Password        string `json:"password"`
+			PasswordConfirm string `json:"password_confirm" eqField:"Password"`
+		}{}),
+		input: map[string]any{"password": "abc123", "password_confirm": "abc124"},
+		errs:  []string{"expected value to equal password"},
+	},
+	{
+		name: "expected neField",
+		typ: reflect.TypeOf(struct {
+			Old string `json:"old"`
+			New string `json:"new" neField:"Old"`
+		}{}),
+		input: map[string]any{"old": "abc", "new": "abc"},
+		errs:  []string{"expected value to not equal old"},
+	},
+	{
+		name: "comparison unknown property",
+		typ: reflect.TypeOf(struct {
+			End int `json:"end" gtField:"Start"`
+		}{}),
+		panic: "gtField property Start not found",
+	},
 	{
 		name: "unexpected property",
 		typ: reflect.TypeOf(struct {
@@ -641,6 +902,59 @@ var validateTests = []struct {
 		input: map[string]any{"value": "three"},
 		errs:  []string{"expected value to be one of \"one, two\""},
 	},
+	{
+		name: "duration success",
+		typ: reflect.TypeOf(struct {
+			Value string `json:"value" format:"duration"`
+		}{}),
+		input: map[string]any{"value": "P1DT2H30.5S"},
+	},
+	{
+		name: "expected duration",
+		typ: reflect.TypeOf(struct {
+			Value string `json:"value" format:"duration"`
+		}{}),
+		input: map[string]any{"value": "PT"},
+		errs:  []string{"expected string to be ISO 8601 duration"},
+	},
+	{
+		name: "const success",
+		typ: reflect.TypeOf(struct {
+			Value string `json:"value" const:"v1"`
+		}{}),
+		input: map[string]any{"value": "v1"},
+	},
+	{
+		name: "expected const",
+		typ: reflect.TypeOf(struct {
+			Value string `json:"value" const:"v1"`
+		}{}),
+		input: map[string]any{"value": "v2"},
+		errs:  []string{"expected value to be \"v1\""},
+	},
+	{
+		name: "const int success",
+		typ: reflect.TypeOf(struct {
+			Value int `json:"value" const:"2"`
+		}{}),
+		input: map[string]any{"value": 2.0},
+	},
+	{
+		name: "expected const int",
+		typ: reflect.TypeOf(struct {
+			Value int `json:"value" const:"2"`
+		}{}),
+		input: map[string]any{"value": 3.0},
+		errs:  []string{"expected value to be \"2\""},
+	},
+	{
+		name: "expected const false",
+		typ: reflect.TypeOf(struct {
+			Value bool `json:"value" const:"false"`
+		}{}),
+		input: map[string]any{"value": true},
+		errs:  []string{"expected value to be \"false\""},
+	},
 	{
 		name: "optional success",
 		typ: reflect.TypeOf(struct {
@@ -696,6 +1010,31 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateLimit(t *testing.T) {
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	s := registry.Schema(reflect.TypeOf([]struct {
+		Name string `json:"name" minLength:"1"`
+	}{}), false, "")
+
+	items := make([]any, 100)
+	for i := range items {
+		items[i] = map[string]any{"name": ""}
+	}
+
+	pb := NewPathBuffer([]byte{}, 0)
+	res := &ValidateResult{}
+	Validate(registry, s, pb, ModeWriteToServer, items, res)
+	assert.Len(t, res.Errors, 100)
+
+	for _, limit := range []int{1, 3} {
+		pb.Reset()
+		res = &ValidateResult{Limit: limit}
+		Validate(registry, s, pb, ModeWriteToServer, items, res)
+		assert.Len(t, res.Errors, limit)
+		assert.Equal(t, "[0].name", res.Errors[0].(*ErrorDetail).Location)
+	}
+}
+
 var BenchValidatePB *PathBuffer
 var BenchValidateRes *ValidateResult
 
@@ -0,0 +1,271 @@
+package huma
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// validateResponseAdapter checks response bodies against the documented
+// response schemas. See `Config.DebugValidateResponses`.
+type validateResponseAdapter struct {
+	Adapter
+	api API
+}
+
+func (a *validateResponseAdapter) Handle(op *Operation, handler func(Context)) {
+	a.Adapter.Handle(op, func(ctx Context) {
+		vc := &validateResponseContext{humaContext: ctx, op: op}
+		handler(vc)
+		vc.finish(a.api)
+	})
+}
+
+// responseSchema returns the documented schema for the response status and
+// content type, or nil if there is none.
+func responseSchema(responses map[string]*Response, status int, ct string) *Schema {
+	resp := responses[strconv.Itoa(status)]
+	if resp == nil {
+		resp = responses["default"]
+	}
+	if resp == nil {
+		return nil
+	}
+	if i := strings.IndexRune(ct, ';'); i != -1 {
+		ct = ct[:i]
+	}
+	ct = strings.TrimSpace(ct)
+	if mt := resp.Content[ct]; mt != nil {
+		return mt.Schema
+	}
+	if strings.HasSuffix(ct, "+json") {
+		// Errors and other structured JSON types are documented this way.
+		if mt := resp.Content["application/json"]; mt != nil {
+			return mt.Schema
+		}
+	}
+	return nil
+}
+
+// snapshotResponses copies the responses so that changes to their schemas by
+// `OnAddOperation` hooks, like wrapping them in an envelope, don't affect the
+// copy. Bodies are validated against it before transformers run.
+func snapshotResponses(responses map[string]*Response) map[string]*Response {
+	out := make(map[string]*Response, len(responses))
+	for status, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		r := *resp
+		r.Content = make(map[string]*MediaType, len(resp.Content))
+		for ct, mt := range resp.Content {
+			if mt != nil {
+				m := *mt
+				r.Content[ct] = &m
+			}
+		}
+		out[status] = &r
+	}
+	return out
+}
+
+// headerOp is a response header change recorded by `validateResponseContext`.
+type headerOp struct {
+	name, value string
+	append      bool
+}
+
+// validateResponseContext buffers response bodies which have a documented
+// schema so they can be validated before anything is sent to the client.
+// Headers are buffered too, so that they aren't sent along with an error.
+// Other responses, like streams, are passed through as-is.
+type validateResponseContext struct {
+	humaContext
+	op          *Operation
+	status      int
+	ct          string
+	headers     []headerOp
+	schema      *Schema
+	buf         *bytes.Buffer
+	passthrough bool
+
+	// body is the response body before transformers ran, if it was
+	// marshaled by the API. Transformers may reshape the body in ways which
+	// aren't documented, like selecting fields, so it's validated instead.
+	body    any
+	hasBody bool
+}
+
+// validateResponseContextOf returns the `validateResponseContext` wrapped by
+// the context, or nil if there is none.
+func validateResponseContextOf(ctx Context) *validateResponseContext {
+	for {
+		if vc, ok := ctx.(*validateResponseContext); ok {
+			return vc
+		}
+		u, ok := ctx.(interface{ Unwrap() Context })
+		if !ok {
+			return nil
+		}
+		ctx = u.Unwrap()
+	}
+}
+
+// Unwrap returns the wrapped context, see `UnwrapContext`.
+func (c *validateResponseContext) Unwrap() Context {
+	return c.humaContext
+}
+
+func (c *validateResponseContext) SetStatus(code int) {
+	if c.passthrough || isInterim(code) {
+		// Interim responses are sent with the headers so far.
+		c.writeHeaders()
+		c.humaContext.SetStatus(code)
+		return
+	}
+	c.status = code
+}
+
+func (c *validateResponseContext) SetHeader(name, value string) {
+	if strings.EqualFold(name, "Content-Type") {
+		c.ct = value
+	}
+	if c.passthrough {
+		c.humaContext.SetHeader(name, value)
+		return
+	}
+	c.headers = append(c.headers, headerOp{name: name, value: value})
+}
+
+func (c *validateResponseContext) AppendHeader(name, value string) {
+	if c.passthrough {
+		c.humaContext.AppendHeader(name, value)
+		return
+	}
+	c.headers = append(c.headers, headerOp{name: name, value: value, append: true})
+}
+
+// writeHeaders writes the buffered headers to the wrapped context.
+func (c *validateResponseContext) writeHeaders() {
+	for _, h := range c.headers {
+		if h.append {
+			c.humaContext.AppendHeader(h.name, h.value)
+		} else {
+			c.humaContext.SetHeader(h.name, h.value)
+		}
+	}
+	c.headers = nil
+}
+
+// setBody records the response body before transformers run.
+func (c *validateResponseContext) setBody(v any) {
+	if !c.passthrough {
+		c.body = v
+		c.hasBody = true
+	}
+}
+
+func (c *validateResponseContext) BodyWriter() io.Writer {
+	if c.buf != nil {
+		return c.buf
+	}
+	if !c.passthrough {
+		status := c.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if c.schema = responseSchema(c.op.Responses, status, c.ct); c.schema != nil {
+			c.buf = &bytes.Buffer{}
+			return c.buf
+		}
+		c.passthrough = true
+		c.writeHeaders()
+		if c.status != 0 {
+			c.humaContext.SetStatus(c.status)
+		}
+	}
+	return c.humaContext.BodyWriter()
+}
+
+// Flush is a no-op for buffered responses as they are only sent once
+// validated, otherwise it flushes the wrapped context.
+func (c *validateResponseContext) Flush() error {
+	c.BodyWriter()
+	if c.buf != nil {
+		return nil
+	}
+	return Flush(c.humaContext)
+}
+
+// finish validates any buffered body and writes the response, or a 500 error
+// without any of the response's headers if it does not match its schema.
+func (c *validateResponseContext) finish(api API) {
+	if c.buf == nil {
+		if !c.passthrough {
+			c.writeHeaders()
+			if c.status != 0 {
+				c.humaContext.SetStatus(c.status)
+			}
+		}
+		return
+	}
+
+	if errs := c.validate(api); len(errs) > 0 {
+		c.headers = nil
+		WriteErr(api, c.humaContext, http.StatusInternalServerError, "response validation failed", errs...)
+		return
+	}
+
+	c.writeHeaders()
+	if c.status != 0 {
+		c.humaContext.SetStatus(c.status)
+	}
+	c.humaContext.BodyWriter().Write(c.buf.Bytes())
+}
+
+// validate returns the errors from validating the response body. Bodies
+// marshaled by the API are validated as they were before transformers ran,
+// using the schema documented before any `OnAddOperation` hooks.
+func (c *validateResponseContext) validate(api API) []error {
+	schema := c.schema
+	data := c.buf.Bytes()
+	if c.hasBody && c.op.responses != nil {
+		status := c.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if schema = responseSchema(c.op.responses, status, c.ct); schema == nil {
+			return nil
+		}
+		b := &bytes.Buffer{}
+		if err := baseAPI(api).marshalFormat(b, c.ct, c.body); err != nil {
+			return []error{err}
+		}
+		data = b.Bytes()
+	}
+
+	deps := validatePool.Get().(*validateDeps)
+	defer func() {
+		deps.reset()
+		validatePool.Put(deps)
+	}()
+	pb := deps.pb
+	res := deps.res
+
+	var v any
+	if err := api.Unmarshal(c.ct, data, &v); err != nil {
+		if schema.Type != TypeString {
+			return []error{err}
+		}
+		// Text & binary bodies, e.g. using the `contentType` tag, are sent as-is
+		// without an encoding.
+		v = string(data)
+	}
+
+	pb.Push("body")
+	Validate(api.OpenAPI().Components.Schemas, schema, pb, ModeReadFromServer, v, res)
+	// The result is reused once returned to the pool.
+	return append([]error(nil), res.Errors...)
+}
@@ -0,0 +1,100 @@
+package huma
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// RegisterWebhook documents a webhook, which is a request this service sends
+// to its clients when something happens, e.g. an `item-created` event. The
+// request body schema is generated from `B`. No route is registered since the
+// service sends rather than receives these requests. The method defaults to
+// `POST`, and a `200` response is documented if the operation has none.
+//
+//	huma.RegisterWebhook[ItemCreatedEvent](api, "item-created", huma.Operation{
+//		Summary: "Item created",
+//	})
+func RegisterWebhook[B any](api API, name string, op Operation) {
+	oapi := api.OpenAPI()
+	outgoingOperation[B](oapi, name, &op)
+
+	if op.Hidden {
+		return
+	}
+	if oapi.Webhooks == nil {
+		oapi.Webhooks = map[string]*PathItem{}
+	}
+	item := oapi.Webhooks[name]
+	if item == nil {
+		item = &PathItem{}
+		oapi.Webhooks[name] = item
+	}
+	item.setOperation(&op)
+}
+
+// AddCallback documents a callback on the operation, which is a request the
+// service sends in response to the operation, e.g. to notify the client that
+// a long-running job is done. The callback URL is given by a runtime
+// expression like `{$request.body#/callbackUrl}`. The request body schema is
+// generated from `B`. See `RegisterWebhook` for the callback defaults.
+//
+//	op := huma.Operation{
+//		OperationID: "create-job",
+//		Method:      http.MethodPost,
+//		Path:        "/jobs",
+//	}
+//	huma.AddCallback[JobDoneEvent](api, &op, "job-done", "{$request.body#/callbackUrl}", huma.Operation{
+//		Summary: "Job done",
+//	})
+//	huma.Register(api, op, createJob)
+func AddCallback[B any](api API, op *Operation, name, expression string, callback Operation) {
+	outgoingOperation[B](api.OpenAPI(), op.OperationID+"-"+name, &callback)
+
+	if op.Callbacks == nil {
+		op.Callbacks = map[string]Callback{}
+	}
+	if op.Callbacks[name] == nil {
+		op.Callbacks[name] = Callback{}
+	}
+	item := op.Callbacks[name][expression]
+	if item == nil {
+		item = &PathItem{}
+		op.Callbacks[name][expression] = item
+	}
+	item.setOperation(&callback)
+}
+
+// outgoingOperation documents the request body & default response of a
+// request sent by the service, like a webhook or callback. The name is used
+// to name the body schema if the operation has no ID.
+func outgoingOperation[B any](oapi *OpenAPI, name string, op *Operation) {
+	if op.Method == "" {
+		op.Method = http.MethodPost
+	}
+
+	hint := op.OperationID + "Request"
+	if op.OperationID == "" {
+		hint = name + "Request"
+	}
+	contentType := "application/json"
+	t := reflect.TypeOf((*B)(nil)).Elem()
+	if ctf, ok := reflect.New(t).Interface().(ContentTypeFilter); ok {
+		contentType = ctf.ContentType(contentType)
+	}
+	op.RequestBody = &RequestBody{
+		Required: true,
+		Content: map[string]*MediaType{
+			contentType: {
+				Schema: oapi.Components.Schemas.Schema(t, true, hint),
+			},
+		},
+	}
+
+	if len(op.Responses) == 0 {
+		op.Responses = map[string]*Response{
+			"200": {
+				Description: "Return a 200 status to indicate that the data was received successfully",
+			},
+		}
+	}
+}
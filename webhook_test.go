@@ -0,0 +1,111 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type WebhookItemCreated struct {
+	ID   string `json:"id" format:"uuid"`
+	Name string `json:"name" maxLength:"80"`
+}
+
+func TestRegisterWebhook(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	RegisterWebhook[WebhookItemCreated](app, "item-created", Operation{
+		Summary: "Item created",
+		Tags:    []string{"items"},
+	})
+	RegisterWebhook[struct {
+		ID string `json:"id"`
+	}](app, "item-deleted", Operation{
+		Method:      http.MethodPut,
+		OperationID: "item-deleted",
+		Responses: map[string]*Response{
+			"204": {Description: "Received"},
+		},
+	})
+	RegisterWebhook[WebhookItemCreated](app, "hidden", Operation{Hidden: true})
+
+	oapi := app.OpenAPI()
+	assert.Len(t, oapi.Webhooks, 2)
+	assert.Empty(t, oapi.Paths, "no routes are registered")
+
+	created := oapi.Webhooks["item-created"].Post
+	assert.Equal(t, "Item created", created.Summary)
+	assert.True(t, created.RequestBody.Required)
+	assert.Equal(t, "#/components/schemas/WebhookItemCreated", created.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Contains(t, created.Responses, "200")
+
+	deleted := oapi.Webhooks["item-deleted"].Put
+	assert.NotNil(t, deleted)
+	assert.Equal(t, "#/components/schemas/item-deletedRequest", deleted.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Len(t, deleted.Responses, 1)
+	assert.Contains(t, deleted.Responses, "204")
+
+	req, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var spec struct {
+		Webhooks map[string]map[string]any `json:"webhooks"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Contains(t, spec.Webhooks["item-created"], "post")
+	assert.Contains(t, spec.Webhooks["item-deleted"], "put")
+}
+
+type CallbackJobDone struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status" enum:"done,failed"`
+}
+
+func TestAddCallback(t *testing.T) {
+	r := chi.NewRouter()
+	app := NewTestAdapter(r, DefaultConfig("Test API", "1.0.0"))
+
+	op := Operation{
+		OperationID: "create-job",
+		Method:      http.MethodPost,
+		Path:        "/jobs",
+	}
+	AddCallback[CallbackJobDone](app, &op, "job-done", "{$request.body#/callback_url}", Operation{
+		Summary: "Job done",
+	})
+	AddCallback[struct {
+		Progress int `json:"progress"`
+	}](app, &op, "job-progress", "{$request.body#/callback_url}", Operation{
+		Method: http.MethodPut,
+	})
+	Register(app, op, func(ctx context.Context, input *struct {
+		Body struct {
+			CallbackURL string `json:"callback_url" format:"uri"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	callbacks := app.OpenAPI().Paths["/jobs"].Post.Callbacks
+	assert.Len(t, callbacks, 2)
+
+	done := callbacks["job-done"]["{$request.body#/callback_url}"].Post
+	assert.Equal(t, "Job done", done.Summary)
+	assert.Equal(t, "#/components/schemas/CallbackJobDone", done.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Contains(t, done.Responses, "200")
+	assert.Contains(t, app.OpenAPI().Components.Schemas.Map(), "CallbackJobDone")
+
+	progress := callbacks["job-progress"]["{$request.body#/callback_url}"].Put
+	assert.Equal(t, "#/components/schemas/create-job-job-progressRequest", progress.RequestBody.Content["application/json"].Schema.Ref)
+
+	req, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), `"callbacks":{"job-done":{"{$request.body#/callback_url}":{"post":`)
+}